@@ -3,11 +3,15 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // AuthorizationKind is returned by (*Authorization).Kind().
 const AuthorizationKind = "authorization"
 
+// ErrAuthorizationExpired is the error message for expired authorizations.
+const ErrAuthorizationExpired = "token has expired"
+
 // ErrUnableToCreateToken sanitized error message for all errors when a user cannot create a token
 var ErrUnableToCreateToken = &Error{
 	Msg:  "unable to create token",
@@ -23,6 +27,9 @@ type Authorization struct {
 	OrgID       ID           `json:"orgID"`
 	UserID      ID           `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions"`
+	// ExpiresAt is the time the authorization stops being usable.
+	// Authorizations without an expiry never expire.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 	CRUDLog
 }
 
@@ -55,6 +62,10 @@ func (a *Authorization) PermissionSet() (PermissionSet, error) {
 		}
 	}
 
+	if err := a.Expired(); err != nil {
+		return nil, err
+	}
+
 	return a.Permissions, nil
 }
 
@@ -68,6 +79,23 @@ func (a *Authorization) IsActive() bool {
 	return a.Status == Active
 }
 
+// IsExpired returns true if the authorization has expired at time t.
+func (a *Authorization) IsExpired(t time.Time) bool {
+	return a.ExpiresAt != nil && !t.Before(*a.ExpiresAt)
+}
+
+// Expired returns an error if the authorization has expired.
+func (a *Authorization) Expired() error {
+	if a.IsExpired(time.Now()) {
+		return &Error{
+			Code: EUnauthorized,
+			Msg:  ErrAuthorizationExpired,
+		}
+	}
+
+	return nil
+}
+
 // GetUserID returns the user id.
 func (a *Authorization) GetUserID() ID {
 	return a.UserID
@@ -89,6 +89,7 @@ func (s *Service) FindAuthorizationByID(ctx context.Context, id influxdb.ID) (*i
 }
 
 // FindAuthorizationByToken returns a authorization by token for a particular authorization.
+// An error is returned if the authorization has expired.
 func (s *Service) FindAuthorizationByToken(ctx context.Context, n string) (*influxdb.Authorization, error) {
 	var a *influxdb.Authorization
 	err := s.store.View(ctx, func(tx kv.Tx) error {
@@ -106,6 +107,10 @@ func (s *Service) FindAuthorizationByToken(ctx context.Context, n string) (*infl
 		return nil, err
 	}
 
+	if err := a.Expired(); err != nil {
+		return nil, err
+	}
+
 	return a, nil
 }
 
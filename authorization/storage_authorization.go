@@ -4,14 +4,14 @@ import (
 	"context"
 	"encoding/json"
 
-	"github.com/buger/jsonparser"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kv"
 	jsonp "github.com/influxdata/influxdb/v2/pkg/jsonparser"
 )
 
-func authIndexKey(n string) []byte {
-	return []byte(n)
+// authIndexKey returns the key of token in the token index.
+func authIndexKey(token string) []byte {
+	return []byte(kv.TokenPrefix(token))
 }
 
 func authIndexBucket(tx kv.Tx) (kv.Bucket, error) {
@@ -23,7 +23,9 @@ func authIndexBucket(tx kv.Tx) (kv.Bucket, error) {
 	return b, nil
 }
 
-func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
+// encodeAuthorization encodes a with the hash of its token in place of the
+// token itself.
+func encodeAuthorization(a *influxdb.Authorization, token kv.HashedToken) ([]byte, error) {
 	switch a.Status {
 	case influxdb.Active, influxdb.Inactive:
 	case "":
@@ -35,7 +37,12 @@ func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(a)
+	stored := *a
+	stored.Token = ""
+	return json.Marshal(struct {
+		*influxdb.Authorization
+		kv.HashedToken
+	}{&stored, token})
 }
 
 func decodeAuthorization(b []byte, a *influxdb.Authorization) error {
@@ -70,7 +77,12 @@ func (s *Store) CreateAuthorization(ctx context.Context, tx kv.Tx, a *influxdb.A
 		return ErrTokenAlreadyExistsError
 	}
 
-	v, err := encodeAuthorization(a)
+	token, err := kv.HashToken(a.Token)
+	if err != nil {
+		return ErrInternalServiceError(err)
+	}
+
+	v, err := encodeAuthorization(a, token)
 	if err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -88,7 +100,7 @@ func (s *Store) CreateAuthorization(ctx context.Context, tx kv.Tx, a *influxdb.A
 		return err
 	}
 
-	if err := idx.Put(authIndexKey(a.Token), encodedID); err != nil {
+	if err := idx.Put([]byte(token.Prefix), encodedID); err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInternal,
 			Err:  err,
@@ -156,6 +168,20 @@ func (s *Store) GetAuthorizationByToken(ctx context.Context, tx kv.Tx, token str
 		}
 	}
 
+	hashed, err := getHashedToken(tx, idKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// the index is keyed by the token prefix, the salted hash tells whether
+	// the authorization found is the one of the token.
+	if !hashed.Matches(token) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "authorization not found",
+		}
+	}
+
 	var id influxdb.ID
 	if err := id.Decode(idKey); err != nil {
 		return nil, &influxdb.Error{
@@ -164,7 +190,41 @@ func (s *Store) GetAuthorizationByToken(ctx context.Context, tx kv.Tx, token str
 		}
 	}
 
-	return s.GetAuthorizationByID(ctx, tx, id)
+	a, err := s.GetAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the token is not stored, but the caller already knows it
+	a.Token = token
+	return a, nil
+}
+
+// getHashedToken returns the hashed token of the authorization with the
+// encoded ID.
+func getHashedToken(tx kv.Tx, encodedID []byte) (kv.HashedToken, error) {
+	var token kv.HashedToken
+
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return token, ErrInternalServiceError(err)
+	}
+
+	v, err := b.Get(encodedID)
+	if kv.IsNotFound(err) {
+		return token, ErrAuthNotFound
+	}
+	if err != nil {
+		return token, ErrInternalServiceError(err)
+	}
+
+	if err := json.Unmarshal(v, &token); err != nil {
+		return token, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return token, nil
 }
 
 // ListAuthorizations returns all the authorizations matching a set of FindOptions. This function is used for
@@ -220,16 +280,9 @@ func (s *Store) forEachAuthorization(ctx context.Context, tx kv.Tx, pred kv.Curs
 	return nil
 }
 
-// UpdateAuthorization updates the status and description only of an authorization
+// UpdateAuthorization updates the status and description only of an authorization.
+// The token of the stored authorization is kept.
 func (s *Store) UpdateAuthorization(ctx context.Context, tx kv.Tx, id influxdb.ID, a *influxdb.Authorization) (*influxdb.Authorization, error) {
-	v, err := encodeAuthorization(a)
-	if err != nil {
-		return nil, &influxdb.Error{
-			Code: influxdb.EInvalid,
-			Err:  err,
-		}
-	}
-
 	encodedID, err := a.ID.Encode()
 	if err != nil {
 		return nil, &influxdb.Error{
@@ -238,14 +291,15 @@ func (s *Store) UpdateAuthorization(ctx context.Context, tx kv.Tx, id influxdb.I
 		}
 	}
 
-	idx, err := authIndexBucket(tx)
+	token, err := getHashedToken(tx, encodedID)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := idx.Put(authIndexKey(a.Token), encodedID); err != nil {
+	v, err := encodeAuthorization(a, token)
+	if err != nil {
 		return nil, &influxdb.Error{
-			Code: influxdb.EInternal,
+			Code: influxdb.EInvalid,
 			Err:  err,
 		}
 	}
@@ -267,14 +321,14 @@ func (s *Store) UpdateAuthorization(ctx context.Context, tx kv.Tx, id influxdb.I
 
 // DeleteAuthorization removes an authorization from storage
 func (s *Store) DeleteAuthorization(ctx context.Context, tx kv.Tx, id influxdb.ID) error {
-	a, err := s.GetAuthorizationByID(ctx, tx, id)
+	encodedID, err := id.Encode()
 	if err != nil {
-		return err
+		return ErrInvalidAuthID
 	}
 
-	encodedID, err := id.Encode()
+	token, err := getHashedToken(tx, encodedID)
 	if err != nil {
-		return ErrInvalidAuthID
+		return err
 	}
 
 	idx, err := authIndexBucket(tx)
@@ -287,7 +341,7 @@ func (s *Store) DeleteAuthorization(ctx context.Context, tx kv.Tx, id influxdb.I
 		return err
 	}
 
-	if err := idx.Delete([]byte(a.Token)); err != nil {
+	if err := idx.Delete([]byte(token.Prefix)); err != nil {
 		return ErrInternalServiceError(err)
 	}
 
@@ -372,18 +426,6 @@ func authorizationsPredicateFn(f influxdb.AuthorizationFilter) kv.CursorPredicat
 		}
 	}
 
-	if f.Token != nil {
-		exp := *f.Token
-		return func(_, value []byte) bool {
-			// it is assumed that token never has escaped string data
-			got, _, _, err := jsonparser.Get(value, "token")
-			if err != nil {
-				return true
-			}
-			return string(got) == exp
-		}
-	}
-
 	var pred kv.CursorPredicateFunc
 	if f.OrgID != nil {
 		exp := *f.OrgID
@@ -417,12 +459,6 @@ func filterAuthorizationsFn(filter influxdb.AuthorizationFilter) func(a *influxd
 		}
 	}
 
-	if filter.Token != nil {
-		return func(a *influxdb.Authorization) bool {
-			return a.Token == *filter.Token
-		}
-	}
-
 	// Filter by org and user
 	if filter.OrgID != nil && filter.UserID != nil {
 		return func(a *influxdb.Authorization) bool {
@@ -0,0 +1,88 @@
+package authorization
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"go.uber.org/zap"
+)
+
+// DefaultSweepInterval is the default period between two sweeps of
+// expired authorizations.
+const DefaultSweepInterval = time.Minute
+
+// ExpirationSweeper periodically marks expired authorizations as inactive.
+// Expired authorizations are rejected on lookup whether or not they have been
+// swept, sweeping makes their state visible when listing authorizations.
+type ExpirationSweeper struct {
+	log      *zap.Logger
+	svc      influxdb.AuthorizationService
+	interval time.Duration
+}
+
+// NewExpirationSweeper returns a sweeper that checks the authorizations of svc
+// for expiry every interval.
+func NewExpirationSweeper(log *zap.Logger, svc influxdb.AuthorizationService, interval time.Duration) *ExpirationSweeper {
+	if interval <= 0 {
+		interval = DefaultSweepInterval
+	}
+	return &ExpirationSweeper{
+		log:      log,
+		svc:      svc,
+		interval: interval,
+	}
+}
+
+// Run sweeps expired authorizations every interval until ctx is done.
+func (s *ExpirationSweeper) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			n, err := s.Sweep(ctx)
+			if err != nil {
+				s.log.Error("Failed to sweep expired authorizations", zap.Error(err))
+				continue
+			}
+			if n > 0 {
+				s.log.Info("Deactivated expired authorizations", zap.Int("count", n))
+			}
+		}
+	}
+}
+
+// Sweep marks all active authorizations that have expired as inactive and
+// returns how many were updated.
+func (s *ExpirationSweeper) Sweep(ctx context.Context) (int, error) {
+	auths, _, err := s.svc.FindAuthorizations(ctx, influxdb.AuthorizationFilter{})
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		now      = time.Now()
+		inactive = influxdb.Inactive
+		n        int
+	)
+	for _, a := range auths {
+		if !a.IsActive() || !a.IsExpired(now) {
+			continue
+		}
+
+		upd := &influxdb.AuthorizationUpdate{Status: &inactive}
+		if _, err := s.svc.UpdateAuthorization(ctx, a.ID, upd); err != nil {
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				// deleted since it was found
+				continue
+			}
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
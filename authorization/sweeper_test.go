@@ -0,0 +1,52 @@
+package authorization_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorization"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestExpirationSweeper_Sweep(t *testing.T) {
+	var (
+		past   = time.Now().Add(-time.Hour)
+		future = time.Now().Add(time.Hour)
+	)
+
+	auths := []*influxdb.Authorization{
+		{ID: 1, Status: influxdb.Active},
+		{ID: 2, Status: influxdb.Active, ExpiresAt: &past},
+		{ID: 3, Status: influxdb.Active, ExpiresAt: &future},
+		{ID: 4, Status: influxdb.Inactive, ExpiresAt: &past},
+	}
+
+	var updated []influxdb.ID
+	svc := mock.NewAuthorizationService()
+	svc.FindAuthorizationsFn = func(context.Context, influxdb.AuthorizationFilter, ...influxdb.FindOptions) ([]*influxdb.Authorization, int, error) {
+		return auths, len(auths), nil
+	}
+	svc.UpdateAuthorizationFn = func(ctx context.Context, id influxdb.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
+		if upd.Status == nil || *upd.Status != influxdb.Inactive {
+			t.Errorf("expected authorization %s to be set inactive", id)
+		}
+		updated = append(updated, id)
+		return nil, nil
+	}
+
+	s := authorization.NewExpirationSweeper(zaptest.NewLogger(t), svc, time.Minute)
+	n, err := s.Sweep(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n != 1 {
+		t.Errorf("expected 1 authorization to be swept, got %d", n)
+	}
+	if len(updated) != 1 || updated[0] != 2 {
+		t.Errorf("expected only authorization 2 to be updated, got %v", updated)
+	}
+}
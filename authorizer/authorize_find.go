@@ -24,6 +24,60 @@ func AuthorizeFindDBRPs(ctx context.Context, rs []*influxdb.DBRPMappingV2) ([]*i
 	return rrs, len(rrs), nil
 }
 
+// AuthorizeFindReplications takes the given items and returns only the ones whose local bucket the user is authorized to read.
+func AuthorizeFindReplications(ctx context.Context, rs []*influxdb.Replication) ([]*influxdb.Replication, int, error) {
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rrs := rs[:0]
+	for _, r := range rs {
+		_, _, err := AuthorizeRead(ctx, influxdb.BucketsResourceType, r.LocalBucketID, r.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		rrs = append(rrs, r)
+	}
+	return rrs, len(rrs), nil
+}
+
+// AuthorizeFindSilences takes the given items and returns only the ones whose org notification rules the user is authorized to read.
+func AuthorizeFindSilences(ctx context.Context, rs []*influxdb.Silence) ([]*influxdb.Silence, int, error) {
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rrs := rs[:0]
+	for _, r := range rs {
+		_, _, err := AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, r.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		rrs = append(rrs, r)
+	}
+	return rrs, len(rrs), nil
+}
+
+// AuthorizeFindIncidents takes the given items and returns only the ones whose org checks the user is authorized to read.
+func AuthorizeFindIncidents(ctx context.Context, rs []*influxdb.Incident) ([]*influxdb.Incident, int, error) {
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rrs := rs[:0]
+	for _, r := range rs {
+		_, _, err := AuthorizeOrgReadResource(ctx, influxdb.ChecksResourceType, r.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		rrs = append(rrs, r)
+	}
+	return rrs, len(rrs), nil
+}
+
 // AuthorizeFindAuthorizations takes the given items and returns only the ones that the user is authorized to read.
 func AuthorizeFindAuthorizations(ctx context.Context, rs []*influxdb.Authorization) ([]*influxdb.Authorization, int, error) {
 	// This filters without allocating
@@ -300,3 +354,21 @@ func AuthorizeFindUserResourceMappings(ctx context.Context, os OrganizationServi
 	}
 	return rrs, len(rrs), nil
 }
+
+// AuthorizeFindRunningQueries takes the given items and returns only the ones whose org the user is authorized to read.
+func AuthorizeFindRunningQueries(ctx context.Context, rs []*influxdb.RunningQuery) ([]*influxdb.RunningQuery, int, error) {
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rrs := rs[:0]
+	for _, r := range rs {
+		_, _, err := AuthorizeReadOrg(ctx, r.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		rrs = append(rrs, r)
+	}
+	return rrs, len(rrs), nil
+}
@@ -0,0 +1,40 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.CardinalityService = (*CardinalityService)(nil)
+
+// CardinalityService wraps a influxdb.CardinalityService and authorizes actions
+// against it appropriately.
+type CardinalityService struct {
+	s influxdb.CardinalityService
+}
+
+// NewCardinalityService constructs an instance of an authorizing cardinality
+// service.
+func NewCardinalityService(s influxdb.CardinalityService) *CardinalityService {
+	return &CardinalityService{
+		s: s,
+	}
+}
+
+// CardinalityUsage checks to see if the authorizer on context has read access
+// to the bucket, or to all the buckets of the org when no bucket is given.
+func (s CardinalityService) CardinalityUsage(ctx context.Context, orgID, bucketID influxdb.ID) (*influxdb.CardinalityUsage, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if bucketID.Valid() {
+		if _, _, err := AuthorizeRead(ctx, influxdb.BucketsResourceType, bucketID, orgID); err != nil {
+			return nil, err
+		}
+	} else if _, _, err := AuthorizeOrgReadResource(ctx, influxdb.BucketsResourceType, orgID); err != nil {
+		return nil, err
+	}
+	return s.s.CardinalityUsage(ctx, orgID, bucketID)
+}
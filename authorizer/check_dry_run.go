@@ -0,0 +1,38 @@
+package authorizer
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.CheckDryRunService = (*CheckDryRunService)(nil)
+
+// CheckDryRunService wraps a influxdb.CheckDryRunService and authorizes
+// actions against it appropriately.
+type CheckDryRunService struct {
+	s influxdb.CheckDryRunService
+}
+
+// NewCheckDryRunService constructs an instance of an authorizing check dry run
+// service.
+func NewCheckDryRunService(s influxdb.CheckDryRunService) *CheckDryRunService {
+	return &CheckDryRunService{
+		s: s,
+	}
+}
+
+// DryRunCheck checks to see if the authorizer on context has read access to
+// the checks of the org of the check. Access to the data of the check is
+// authorized by the query it runs.
+func (s *CheckDryRunService) DryRunCheck(ctx context.Context, c influxdb.Check, start, stop time.Time) ([]*influxdb.CheckStatus, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeOrgReadResource(ctx, influxdb.ChecksResourceType, c.GetOrgID()); err != nil {
+		return nil, err
+	}
+	return s.s.DryRunCheck(ctx, c, start, stop)
+}
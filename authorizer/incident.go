@@ -0,0 +1,71 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.IncidentService = (*IncidentService)(nil)
+
+// IncidentService wraps a influxdb.IncidentService and authorizes actions
+// against it appropriately. Incidents are made of the statuses of the checks
+// of their organization, so they require the permissions of checks.
+type IncidentService struct {
+	s influxdb.IncidentService
+}
+
+// NewIncidentService constructs an instance of an authorizing incident
+// service.
+func NewIncidentService(s influxdb.IncidentService) *IncidentService {
+	return &IncidentService{
+		s: s,
+	}
+}
+
+// FindIncidentByID checks to see if the authorizer on context has read access
+// to the checks of the org of the incident.
+func (s *IncidentService) FindIncidentByID(ctx context.Context, id influxdb.ID) (*influxdb.Incident, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	i, err := s.s.FindIncidentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeOrgReadResource(ctx, influxdb.ChecksResourceType, i.OrgID); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// FindIncidents retrieves all incidents that match the provided filter and
+// then filters the list down to only the incidents that are authorized.
+func (s *IncidentService) FindIncidents(ctx context.Context, filter influxdb.IncidentFilter) ([]*influxdb.Incident, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	is, err := s.s.FindIncidents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	is, _, err = AuthorizeFindIncidents(ctx, is)
+	return is, err
+}
+
+// UpdateIncident checks to see if the authorizer on context has write access
+// to the checks of the org of the incident.
+func (s *IncidentService) UpdateIncident(ctx context.Context, id influxdb.ID, upd influxdb.IncidentUpdate, userID influxdb.ID) (*influxdb.Incident, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	i, err := s.s.FindIncidentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeOrgWriteResource(ctx, influxdb.ChecksResourceType, i.OrgID); err != nil {
+		return nil, err
+	}
+	return s.s.UpdateIncident(ctx, id, upd, userID)
+}
@@ -0,0 +1,48 @@
+package authorizer
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.PromQLService = (*PromQLService)(nil)
+
+// PromQLService wraps a influxdb.PromQLService and authorizes actions against
+// it appropriately.
+type PromQLService struct {
+	s influxdb.PromQLService
+}
+
+// NewPromQLService constructs an instance of an authorizing PromQL service.
+func NewPromQLService(s influxdb.PromQLService) *PromQLService {
+	return &PromQLService{
+		s: s,
+	}
+}
+
+// QueryPromQL checks to see if the authorizer on context has read access to
+// the bucket.
+func (s *PromQLService) QueryPromQL(ctx context.Context, orgID, bucketID influxdb.ID, q influxdb.PromQLQuery) (*influxdb.PromQLResult, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeRead(ctx, influxdb.BucketsResourceType, bucketID, orgID); err != nil {
+		return nil, err
+	}
+	return s.s.QueryPromQL(ctx, orgID, bucketID, q)
+}
+
+// ReadPromSeries checks to see if the authorizer on context has read access to
+// the bucket.
+func (s *PromQLService) ReadPromSeries(ctx context.Context, orgID, bucketID influxdb.ID, matchers []influxdb.PromLabelMatcher, start, stop time.Time) ([]*influxdb.PromSeries, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeRead(ctx, influxdb.BucketsResourceType, bucketID, orgID); err != nil {
+		return nil, err
+	}
+	return s.s.ReadPromSeries(ctx, orgID, bucketID, matchers, start, stop)
+}
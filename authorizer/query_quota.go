@@ -0,0 +1,60 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.QueryQuotaService = (*QueryQuotaService)(nil)
+
+// QueryQuotaService wraps a influxdb.QueryQuotaService and authorizes actions
+// against it appropriately.
+type QueryQuotaService struct {
+	s influxdb.QueryQuotaService
+}
+
+// NewQueryQuotaService constructs an instance of an authorizing query quota
+// service.
+func NewQueryQuotaService(s influxdb.QueryQuotaService) *QueryQuotaService {
+	return &QueryQuotaService{
+		s: s,
+	}
+}
+
+// FindQueryQuota checks to see if the authorizer on context has read access
+// to the org.
+func (s *QueryQuotaService) FindQueryQuota(ctx context.Context, orgID influxdb.ID) (*influxdb.QueryQuota, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeReadOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.s.FindQueryQuota(ctx, orgID)
+}
+
+// SetQueryQuota checks to see if the authorizer on context has write access
+// to all the orgs. Orgs may not lift their own quotas.
+func (s *QueryQuotaService) SetQueryQuota(ctx context.Context, q *influxdb.QueryQuota) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return err
+	}
+	return s.s.SetQueryQuota(ctx, q)
+}
+
+// DeleteQueryQuota checks to see if the authorizer on context has write
+// access to all the orgs.
+func (s *QueryQuotaService) DeleteQueryQuota(ctx context.Context, orgID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return err
+	}
+	return s.s.DeleteQueryQuota(ctx, orgID)
+}
@@ -0,0 +1,98 @@
+package authorizer_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreService_RestoreBucket(t *testing.T) {
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+	req := influxdb.RestoreBucketRequest{
+		SourceOrgID:    3,
+		SourceBucketID: 4,
+		OrgID:          orgID,
+		BucketID:       bucketID,
+	}
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantErr    bool
+	}{
+		{
+			name: "authorized to write to the target bucket",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					ID:    &bucketID,
+					OrgID: &orgID,
+				},
+			},
+		},
+		{
+			name: "authorized to write to every bucket of the org",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: &orgID,
+				},
+			},
+		},
+		{
+			name: "read only access to the target bucket",
+			permission: influxdb.Permission{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					ID:    &bucketID,
+					OrgID: &orgID,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "write access to the source bucket only",
+			permission: influxdb.Permission{
+				Action: influxdb.WriteAction,
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   &req.SourceBucketID,
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			fakeSVC := mock.NewRestoreService()
+			fakeSVC.RestoreBucketFn = func(context.Context, influxdb.RestoreBucketRequest, io.Reader, io.Reader) error {
+				called = true
+				return nil
+			}
+			s := authorizer.NewRestoreService(fakeSVC)
+
+			ctx := icontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, []influxdb.Permission{tt.permission}))
+
+			err := s.RestoreBucket(ctx, req, &bytes.Buffer{}, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.False(t, called)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, called)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.RunningQueryService = (*RunningQueryService)(nil)
+
+// RunningQueryService wraps a influxdb.RunningQueryService and authorizes
+// actions against it appropriately. Running queries belong to the org they
+// were submitted for.
+type RunningQueryService struct {
+	s influxdb.RunningQueryService
+}
+
+// NewRunningQueryService constructs an instance of an authorizing running
+// query service.
+func NewRunningQueryService(s influxdb.RunningQueryService) *RunningQueryService {
+	return &RunningQueryService{
+		s: s,
+	}
+}
+
+// FindRunningQueries retrieves all running queries that match the provided
+// filter and then filters the list down to only the queries of the orgs the
+// authorizer on context has read access to.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	rqs, err := s.s.FindRunningQueries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	rqs, _, err = AuthorizeFindRunningQueries(ctx, rqs)
+	return rqs, err
+}
+
+// FindRunningQueryByID checks to see if the authorizer on context has read
+// access to the org of the query.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	rq, err := s.s.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeReadOrg(ctx, rq.OrgID); err != nil {
+		return nil, err
+	}
+	return rq, nil
+}
+
+// CancelRunningQuery checks to see if the authorizer on context has write
+// access to the org of the query.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	rq, err := s.s.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, _, err := AuthorizeWriteOrg(ctx, rq.OrgID); err != nil {
+		return err
+	}
+	return s.s.CancelRunningQuery(ctx, id)
+}
@@ -0,0 +1,151 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxdbtesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+func newRunningQueryService() *mock.RunningQueryService {
+	queries := []*influxdb.RunningQuery{
+		{ID: 1, OrgID: 10, State: "executing"},
+		{ID: 2, OrgID: 20, State: "queueing"},
+	}
+	svc := mock.NewRunningQueryService()
+	svc.FindRunningQueriesFn = func(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+		return append([]*influxdb.RunningQuery(nil), queries...), nil
+	}
+	svc.FindRunningQueryByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+		for _, q := range queries {
+			if q.ID == id {
+				return q, nil
+			}
+		}
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "running query not found"}
+	}
+	return svc
+}
+
+func TestRunningQueryService_FindRunningQueries(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wantIDs    []influxdb.ID
+	}{
+		{
+			name: "authorized to read the queries of all orgs",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.OrgsResourceType},
+			},
+			wantIDs: []influxdb.ID{1, 2},
+		},
+		{
+			name: "authorized to read the queries of an org",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: influxdbtesting.IDPtr(20)},
+			},
+			wantIDs: []influxdb.ID{2},
+		},
+		{
+			name: "unauthorized to read the queries of any org",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewRunningQueryService(newRunningQueryService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, []influxdb.Permission{tt.permission}))
+			rqs, err := s.FindRunningQueries(ctx, influxdb.RunningQueryFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []influxdb.ID
+			for _, rq := range rqs {
+				ids = append(ids, rq.ID)
+			}
+			if diff := cmp.Diff(tt.wantIDs, ids); diff != "" {
+				t.Errorf("unexpected queries -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunningQueryService_CancelRunningQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		id         influxdb.ID
+		wantCode   string
+	}{
+		{
+			name: "authorized to cancel the queries of the org",
+			permission: influxdb.Permission{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: influxdbtesting.IDPtr(10)},
+			},
+			id: 1,
+		},
+		{
+			name: "unauthorized to cancel the queries of another org",
+			permission: influxdb.Permission{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: influxdbtesting.IDPtr(10)},
+			},
+			id:       2,
+			wantCode: influxdb.EUnauthorized,
+		},
+		{
+			name: "unauthorized to cancel with read access to the org",
+			permission: influxdb.Permission{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: influxdbtesting.IDPtr(10)},
+			},
+			id:       1,
+			wantCode: influxdb.EUnauthorized,
+		},
+		{
+			name: "query not running",
+			permission: influxdb.Permission{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.OrgsResourceType},
+			},
+			id:       3,
+			wantCode: influxdb.ENotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newRunningQueryService()
+			var canceled bool
+			svc.CancelRunningQueryFn = func(ctx context.Context, id influxdb.ID) error {
+				canceled = true
+				return nil
+			}
+			s := authorizer.NewRunningQueryService(svc)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, []influxdb.Permission{tt.permission}))
+			err := s.CancelRunningQuery(ctx, tt.id)
+			if code := influxdb.ErrorCode(err); code != tt.wantCode {
+				t.Fatalf("unexpected error code: got %q want %q: %v", code, tt.wantCode, err)
+			}
+			if canceled != (tt.wantCode == "") {
+				t.Errorf("unexpected cancellation: got %v", canceled)
+			}
+		})
+	}
+}
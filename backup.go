@@ -3,19 +3,63 @@ package influxdb
 import (
 	"context"
 	"io"
+	"time"
 )
 
+// BackupManifestFilename is the name of the file describing the contents of a backup.
+const BackupManifestFilename = "manifest.json"
+
 // BackupService represents the data backup functions of InfluxDB.
 type BackupService interface {
-	// CreateBackup creates a local copy (hard links) of the TSM data for all orgs and buckets.
+	// CreateBackup creates a local copy (hard links) of the TSM data matching the filter.
+	// An empty filter includes the data for all orgs and buckets.
 	// The return values are used to download each backup file.
-	CreateBackup(context.Context) (backupID int, backupFiles []string, err error)
+	CreateBackup(ctx context.Context, filter BackupFilter) (backupID int, backupFiles []string, err error)
 	// FetchBackupFile downloads one backup file, data or metadata.
 	FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error
 	// InternalBackupPath is a utility to determine the on-disk location of a backup fileset.
 	InternalBackupPath(backupID int) string
 }
 
+// BackupFilter restricts the TSM data included in a backup.
+type BackupFilter struct {
+	// OrgID restricts the backup to the data of an organization.
+	OrgID *ID
+	// BucketID restricts the backup to the data of a bucket. OrgID is required
+	// when BucketID is set.
+	BucketID *ID
+	// Since restricts the backup to the files modified after this time,
+	// producing an incremental backup of a previous one.
+	Since time.Time
+}
+
+// Validate returns an error if the filter is invalid.
+func (f BackupFilter) Validate() error {
+	if f.BucketID != nil && f.OrgID == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required when backing up a single bucket",
+		}
+	}
+	return nil
+}
+
+// BackupManifest describes the files of a backup. Incremental backups form a
+// chain where each manifest references the manifest of the backup it was
+// created since.
+type BackupManifest struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Since is set for incremental backups and holds the creation time
+	// of the parent backup.
+	Since *time.Time `json:"since,omitempty"`
+	// Parent is the manifest of the backup an incremental backup was created since.
+	Parent   string   `json:"parent,omitempty"`
+	OrgID    *ID      `json:"orgID,omitempty"`
+	BucketID *ID      `json:"bucketID,omitempty"`
+	Files    []string `json:"files"`
+}
+
 // KVBackupService represents the meta data backup functions of InfluxDB.
 type KVBackupService interface {
 	// Backup creates a live backup copy of the metadata database.
@@ -34,16 +34,40 @@ const InfiniteRetention = 0
 
 // Bucket is a bucket. 🎉
 type Bucket struct {
-	ID                  ID            `json:"id,omitempty"`
-	OrgID               ID            `json:"orgID,omitempty"`
-	Type                BucketType    `json:"type"`
-	Name                string        `json:"name"`
-	Description         string        `json:"description"`
-	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
-	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	ID                  ID              `json:"id,omitempty"`
+	OrgID               ID              `json:"orgID,omitempty"`
+	Type                BucketType      `json:"type"`
+	Name                string          `json:"name"`
+	Description         string          `json:"description"`
+	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
+	RetentionPeriod     time.Duration   `json:"retentionPeriod"`
+	RetentionTiers      []RetentionTier `json:"retentionTiers,omitempty"`
+	ShardGroupDuration  time.Duration   `json:"shardGroupDuration,omitempty"` // Duration of the storage time partitions, 0 disables partitioning
 	CRUDLog
 }
 
+// MinShardGroupDuration is the shortest duration of the storage time
+// partitions of a bucket.
+const MinShardGroupDuration = time.Hour
+
+// ValidateShardGroupDuration checks that the shard group duration of the
+// bucket either disables partitioning or is at least MinShardGroupDuration.
+func (b *Bucket) ValidateShardGroupDuration() error {
+	if b.ShardGroupDuration < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "shard group duration must not be negative",
+		}
+	}
+	if b.ShardGroupDuration > 0 && b.ShardGroupDuration < MinShardGroupDuration {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("shard group duration must be 0 or at least %s", MinShardGroupDuration),
+		}
+	}
+	return nil
+}
+
 // BucketType differentiates system buckets from user buckets.
 type BucketType int
 
@@ -101,9 +125,11 @@ type BucketService interface {
 // BucketUpdate represents updates to a bucket.
 // Only fields which are set are updated.
 type BucketUpdate struct {
-	Name            *string        `json:"name,omitempty"`
-	Description     *string        `json:"description,omitempty"`
-	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	Name               *string          `json:"name,omitempty"`
+	Description        *string          `json:"description,omitempty"`
+	RetentionPeriod    *time.Duration   `json:"retentionPeriod,omitempty"`
+	RetentionTiers     *[]RetentionTier `json:"retentionTiers,omitempty"`
+	ShardGroupDuration *time.Duration   `json:"shardGroupDuration,omitempty"`
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
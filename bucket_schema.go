@@ -0,0 +1,183 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// SchemaFieldType is the type of the values of a field declared by a bucket
+// schema.
+type SchemaFieldType string
+
+// Field types a bucket schema can declare.
+const (
+	SchemaFieldTypeFloat    SchemaFieldType = "float"
+	SchemaFieldTypeInteger  SchemaFieldType = "integer"
+	SchemaFieldTypeUnsigned SchemaFieldType = "unsigned"
+	SchemaFieldTypeString   SchemaFieldType = "string"
+	SchemaFieldTypeBoolean  SchemaFieldType = "boolean"
+)
+
+// Valid returns an error if t is not a known field type.
+func (t SchemaFieldType) Valid() error {
+	switch t {
+	case SchemaFieldTypeFloat, SchemaFieldTypeInteger, SchemaFieldTypeUnsigned,
+		SchemaFieldTypeString, SchemaFieldTypeBoolean:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("unknown field type %q", t),
+	}
+}
+
+// BucketSchema is the explicit schema of a bucket. Once a bucket has a schema,
+// writes are only accepted for the measurements it declares, with the tag
+// keys and the fields, of the declared types, of their measurement.
+type BucketSchema struct {
+	BucketID     ID                  `json:"bucketID,omitempty"`
+	OrgID        ID                  `json:"orgID,omitempty"`
+	Measurements []MeasurementSchema `json:"measurements"`
+	CRUDLog
+}
+
+// MeasurementSchema declares the tag keys and the fields a measurement
+// accepts.
+type MeasurementSchema struct {
+	Name   string        `json:"name"`
+	Tags   []string      `json:"tags,omitempty"`
+	Fields []FieldSchema `json:"fields"`
+}
+
+// FieldSchema declares the type of a field.
+type FieldSchema struct {
+	Name string          `json:"name"`
+	Type SchemaFieldType `json:"type"`
+}
+
+// Valid returns an error if the schema declares a measurement, tag key or
+// field without a name or more than once, a field of an unknown type, or a
+// name as both a tag key and a field of a measurement.
+func (s *BucketSchema) Valid() error {
+	if !s.BucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucket schema requires a valid bucket ID",
+		}
+	}
+
+	measurements := make(map[string]bool, len(s.Measurements))
+	for _, m := range s.Measurements {
+		if m.Name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "measurement name is required",
+			}
+		}
+		if measurements[m.Name] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("measurement %q is declared more than once", m.Name),
+			}
+		}
+		measurements[m.Name] = true
+
+		if len(m.Fields) == 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("measurement %q requires at least one field", m.Name),
+			}
+		}
+
+		names := make(map[string]bool, len(m.Tags)+len(m.Fields))
+		for _, tag := range m.Tags {
+			if tag == "" {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("measurement %q: tag key is required", m.Name),
+				}
+			}
+			if names[tag] {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("measurement %q: tag key %q is declared more than once", m.Name, tag),
+				}
+			}
+			names[tag] = true
+		}
+
+		for _, f := range m.Fields {
+			if f.Name == "" {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("measurement %q: field name is required", m.Name),
+				}
+			}
+			if names[f.Name] {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("measurement %q: %q is declared more than once", m.Name, f.Name),
+				}
+			}
+			names[f.Name] = true
+
+			if err := f.Type.Valid(); err != nil {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("measurement %q: field %q", m.Name, f.Name),
+					Err:  err,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Measurement returns the schema of the measurement name, or nil if the
+// schema does not declare it.
+func (s *BucketSchema) Measurement(name string) *MeasurementSchema {
+	for i := range s.Measurements {
+		if s.Measurements[i].Name == name {
+			return &s.Measurements[i]
+		}
+	}
+	return nil
+}
+
+// HasTag returns true if the measurement accepts the tag key.
+func (m *MeasurementSchema) HasTag(key string) bool {
+	for _, tag := range m.Tags {
+		if tag == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Field returns the schema of the field name, or nil if the measurement does
+// not declare it.
+func (m *MeasurementSchema) Field(name string) *FieldSchema {
+	for i := range m.Fields {
+		if m.Fields[i].Name == name {
+			return &m.Fields[i]
+		}
+	}
+	return nil
+}
+
+// BucketSchemaService represents a service for managing the schemas of
+// buckets.
+type BucketSchemaService interface {
+	// FindBucketSchema returns the schema of a bucket. It returns an error
+	// with code ENotFound if the bucket has no schema.
+	FindBucketSchema(ctx context.Context, bucketID ID) (*BucketSchema, error)
+
+	// PutBucketSchema sets the schema of a bucket, replacing any previous
+	// schema.
+	PutBucketSchema(ctx context.Context, s *BucketSchema) error
+
+	// DeleteBucketSchema removes the schema of a bucket, so that it accepts
+	// writes of any shape again.
+	DeleteBucketSchema(ctx context.Context, bucketID ID) error
+}
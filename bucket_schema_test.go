@@ -0,0 +1,115 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestBucketSchema_Valid(t *testing.T) {
+	const bucketID = influxdb.ID(1)
+
+	tests := []struct {
+		name         string
+		measurements []influxdb.MeasurementSchema
+		wantErr      bool
+	}{
+		{
+			name: "no measurements",
+		},
+		{
+			name: "valid schema",
+			measurements: []influxdb.MeasurementSchema{
+				{
+					Name: "cpu",
+					Tags: []string{"host", "region"},
+					Fields: []influxdb.FieldSchema{
+						{Name: "usage_user", Type: influxdb.SchemaFieldTypeFloat},
+						{Name: "cores", Type: influxdb.SchemaFieldTypeUnsigned},
+					},
+				},
+				{
+					Name: "mem",
+					Fields: []influxdb.FieldSchema{
+						{Name: "used", Type: influxdb.SchemaFieldTypeInteger},
+					},
+				},
+			},
+		},
+		{
+			name: "measurement name is required",
+			measurements: []influxdb.MeasurementSchema{
+				{Fields: []influxdb.FieldSchema{{Name: "used", Type: influxdb.SchemaFieldTypeInteger}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate measurement",
+			measurements: []influxdb.MeasurementSchema{
+				{Name: "mem", Fields: []influxdb.FieldSchema{{Name: "used", Type: influxdb.SchemaFieldTypeInteger}}},
+				{Name: "mem", Fields: []influxdb.FieldSchema{{Name: "free", Type: influxdb.SchemaFieldTypeInteger}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "measurement requires fields",
+			measurements: []influxdb.MeasurementSchema{
+				{Name: "mem", Tags: []string{"host"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate tag key",
+			measurements: []influxdb.MeasurementSchema{
+				{
+					Name:   "mem",
+					Tags:   []string{"host", "host"},
+					Fields: []influxdb.FieldSchema{{Name: "used", Type: influxdb.SchemaFieldTypeInteger}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate field",
+			measurements: []influxdb.MeasurementSchema{
+				{
+					Name: "mem",
+					Fields: []influxdb.FieldSchema{
+						{Name: "used", Type: influxdb.SchemaFieldTypeInteger},
+						{Name: "used", Type: influxdb.SchemaFieldTypeFloat},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tag key and field share a name",
+			measurements: []influxdb.MeasurementSchema{
+				{
+					Name:   "mem",
+					Tags:   []string{"used"},
+					Fields: []influxdb.FieldSchema{{Name: "used", Type: influxdb.SchemaFieldTypeInteger}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown field type",
+			measurements: []influxdb.MeasurementSchema{
+				{Name: "mem", Fields: []influxdb.FieldSchema{{Name: "used", Type: "decimal"}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := influxdb.BucketSchema{
+				BucketID:     bucketID,
+				Measurements: tt.measurements,
+			}
+			if err := s.Valid(); (err != nil) != tt.wantErr {
+				t.Errorf("BucketSchema.Valid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestBucket_ValidateShardGroupDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		sgd     time.Duration
+		wantErr bool
+	}{
+		{
+			name: "partitioning disabled",
+		},
+		{
+			name: "minimum duration",
+			sgd:  influxdb.MinShardGroupDuration,
+		},
+		{
+			name: "one day",
+			sgd:  24 * time.Hour,
+		},
+		{
+			name:    "negative duration",
+			sgd:     -time.Hour,
+			wantErr: true,
+		},
+		{
+			name:    "below the minimum duration",
+			sgd:     30 * time.Minute,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := influxdb.Bucket{ShardGroupDuration: tt.sgd}
+			if err := b.ValidateShardGroupDuration(); (err != nil) != tt.wantErr {
+				t.Errorf("Bucket.ValidateShardGroupDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package influxdb
+
+import "context"
+
+// CardinalityUsage is the estimated cardinality of a bucket, or of all the
+// buckets of an organization, and the limits it is held to. A limit of 0 means
+// the cardinality is unlimited.
+type CardinalityUsage struct {
+	OrgID    ID `json:"orgID"`
+	BucketID ID `json:"bucketID,omitempty"`
+
+	// Series is the estimated number of series.
+	Series    uint64 `json:"series"`
+	MaxSeries int    `json:"maxSeries"`
+
+	// TagValues is the estimated number of values of each tag key of the
+	// bucket. It is only reported for a bucket.
+	TagValues       map[string]uint64 `json:"tagValues,omitempty"`
+	MaxValuesPerTag int               `json:"maxValuesPerTag"`
+}
+
+// CardinalityService reports the series cardinality of buckets and
+// organizations.
+type CardinalityService interface {
+	// CardinalityUsage returns the cardinality of a bucket of an
+	// organization, or of the whole organization if bucketID is not valid.
+	CardinalityUsage(ctx context.Context, orgID, bucketID ID) (*CardinalityUsage, error)
+}
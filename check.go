@@ -3,6 +3,7 @@ package influxdb
 import (
 	"context"
 	"encoding/json"
+	"time"
 )
 
 // consts for checks config.
@@ -73,6 +74,13 @@ type CheckService interface {
 	DeleteCheck(ctx context.Context, id ID) error
 }
 
+// CheckDryRunService evaluates checks against past data without saving them.
+type CheckDryRunService interface {
+	// DryRunCheck runs the check at each of its scheduled times between start
+	// and stop, and returns the statuses it would have written.
+	DryRunCheck(ctx context.Context, c Check, start, stop time.Time) ([]*CheckStatus, error)
+}
+
 // CheckUpdate are properties than can be updated on a check
 type CheckUpdate struct {
 	Name        *string `json:"name,omitempty"`
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"io"
+	"time"
 
 	platform "github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/cmd/influx/internal"
@@ -18,6 +19,7 @@ type token struct {
 	UserName    string      `json:"userName"`
 	UserID      platform.ID `json:"userID"`
 	Permissions []string    `json:"permissions"`
+	ExpiresAt   *time.Time  `json:"expiresAt,omitempty"`
 }
 
 func cmdAuth(f *globalFlags, opt genericCLIOpts) *cobra.Command {
@@ -47,6 +49,7 @@ var authCreateFlags struct {
 	user        string
 	description string
 	org         organization
+	expiresIn   time.Duration
 
 	writeUserPermission bool
 	readUserPermission  bool
@@ -94,6 +97,7 @@ func authCreateCmd(f *globalFlags) *cobra.Command {
 
 	cmd.Flags().StringVarP(&authCreateFlags.description, "description", "d", "", "Token description")
 	cmd.Flags().StringVarP(&authCreateFlags.user, "user", "u", "", "The user name")
+	cmd.Flags().DurationVar(&authCreateFlags.expiresIn, "expires-in", 0, "Duration until the token expires, e.g. 24h; tokens without one never expire")
 	registerPrintOptions(cmd, &authCRUDFlags.hideHeaders, &authCRUDFlags.json)
 
 	cmd.Flags().BoolVarP(&authCreateFlags.writeUserPermission, "write-user", "", false, "Grants the permission to perform mutative actions against organization users")
@@ -258,6 +262,11 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		OrgID:       orgID,
 	}
 
+	if authCreateFlags.expiresIn > 0 {
+		expiresAt := time.Now().Add(authCreateFlags.expiresIn)
+		authorization.ExpiresAt = &expiresAt
+	}
+
 	if userName := authCreateFlags.user; userName != "" {
 		user, err := userSvc.FindUser(context.Background(), platform.UserFilter{
 			Name: &userName,
@@ -298,6 +307,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 			UserName:    user.Name,
 			UserID:      user.ID,
 			Permissions: ps,
+			ExpiresAt:   authorization.ExpiresAt,
 		},
 	})
 }
@@ -392,6 +402,7 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 			UserName:    user.Name,
 			UserID:      a.UserID,
 			Permissions: permissions,
+			ExpiresAt:   a.ExpiresAt,
 		})
 	}
 
@@ -465,6 +476,7 @@ func authorizationDeleteF(cmd *cobra.Command, args []string) error {
 			UserName:    user.Name,
 			UserID:      user.ID,
 			Permissions: ps,
+			ExpiresAt:   a.ExpiresAt,
 		},
 	})
 }
@@ -533,6 +545,7 @@ func authorizationActiveF(cmd *cobra.Command, args []string) error {
 			UserName:    user.Name,
 			UserID:      user.ID,
 			Permissions: ps,
+			ExpiresAt:   a.ExpiresAt,
 		},
 	})
 }
@@ -601,6 +614,7 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 			UserName:    user.Name,
 			UserID:      user.ID,
 			Permissions: ps,
+			ExpiresAt:   a.ExpiresAt,
 		},
 	})
 }
@@ -634,6 +648,7 @@ func writeTokens(w io.Writer, printOpts tokenPrintOpt) error {
 		"User Name",
 		"User ID",
 		"Permissions",
+		"Expires At",
 	}
 	if printOpts.deleted {
 		headers = append(headers, "Deleted")
@@ -652,6 +667,10 @@ func writeTokens(w io.Writer, printOpts tokenPrintOpt) error {
 			"User Name":   t.UserName,
 			"User ID":     t.UserID.String(),
 			"Permissions": t.Permissions,
+			"Expires At":  "",
+		}
+		if t.ExpiresAt != nil {
+			m["Expires At"] = t.ExpiresAt.Format(time.RFC3339)
 		}
 		if printOpts.deleted {
 			m["Deleted"] = true
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/bolt"
@@ -20,10 +24,16 @@ func cmdBackup(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 		`Backs up data and meta data for the running InfluxDB instance.
 Downloaded files are written to the directory indicated by --path.
 The target directory, and any parent directories, are created automatically.
-Data file have extension .tsm; meta data is written to %s in the same directory.`,
-		bolt.DefaultFilename)
+Data file have extension .tsm; meta data is written to %s in the same directory.
+
+Each backup writes a manifest named <timestamp>%s describing its files.
+With --incremental, only the data files modified since the most recent
+manifest in --path are downloaded and the new manifest references it as
+its parent, forming a chain of backups.`,
+		bolt.DefaultFilename, backupManifestExt)
 
 	f.registerFlags(cmd)
+	backupFlags.org.register(cmd, false)
 
 	opts := flagOpts{
 		{
@@ -34,14 +44,38 @@ Data file have extension .tsm; meta data is written to %s in the same directory.
 			Desc:     "directory path to write backup files to",
 			Required: true,
 		},
+		{
+			DestP: &backupFlags.BucketID,
+			Flag:  "bucket-id",
+			Desc:  "The ID of the bucket to backup",
+		},
+		{
+			DestP: &backupFlags.Bucket,
+			Flag:  "bucket",
+			Short: 'b',
+			Desc:  "The name of the bucket to backup",
+		},
+		{
+			DestP: &backupFlags.Since,
+			Flag:  "since",
+			Desc:  "only backup data files modified after this time, in RFC3339 format",
+		},
 	}
 	opts.mustRegister(cmd)
+	cmd.Flags().BoolVar(&backupFlags.Incremental, "incremental", false, "only backup data files modified since the most recent backup in --path")
 
 	return cmd
 }
 
+const backupManifestExt = ".manifest"
+
 var backupFlags struct {
-	Path string
+	Path        string
+	org         organization
+	BucketID    string
+	Bucket      string
+	Since       string
+	Incremental bool
 }
 
 func newBackupService() (influxdb.BackupService, error) {
@@ -58,25 +92,54 @@ func backupF(cmd *cobra.Command, args []string) error {
 	if backupFlags.Path == "" {
 		return fmt.Errorf("must specify path")
 	}
+	if backupFlags.Since != "" && backupFlags.Incremental {
+		return fmt.Errorf("must specify only one of since or incremental")
+	}
 
 	err := os.MkdirAll(backupFlags.Path, 0777)
 	if err != nil && !os.IsExist(err) {
 		return err
 	}
 
+	filter, err := backupFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	var parent string
+	if backupFlags.Incremental {
+		var prev *influxdb.BackupManifest
+		parent, prev, err = latestBackupManifest(backupFlags.Path)
+		if err != nil {
+			return err
+		}
+		if prev == nil {
+			return fmt.Errorf("no previous backup manifest found in %s", backupFlags.Path)
+		}
+		filter.Since = prev.CreatedAt
+	}
+
 	backupService, err := newBackupService()
 	if err != nil {
 		return err
 	}
 
-	id, backupFilenames, err := backupService.CreateBackup(ctx)
+	id, backupFilenames, err := backupService.CreateBackup(ctx, filter)
 	if err != nil {
 		return err
 	}
 
 	fmt.Printf("Backup ID %d contains %d files\n", id, len(backupFilenames))
 
+	var manifest *influxdb.BackupManifest
 	for _, backupFilename := range backupFilenames {
+		if backupFilename == influxdb.BackupManifestFilename {
+			if manifest, err = fetchBackupManifest(ctx, backupService, id); err != nil {
+				return err
+			}
+			continue
+		}
+
 		dest := filepath.Join(backupFlags.Path, backupFilename)
 		w, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
 		if err != nil {
@@ -91,7 +154,153 @@ func backupF(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if manifest != nil {
+		manifest.Parent = parent
+		if err := writeBackupManifest(backupFlags.Path, manifest); err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("Backup complete")
 
 	return nil
 }
+
+func backupFilter(ctx context.Context) (influxdb.BackupFilter, error) {
+	var filter influxdb.BackupFilter
+	if backupFlags.Since != "" {
+		since, err := time.Parse(time.RFC3339Nano, backupFlags.Since)
+		if err != nil {
+			return filter, fmt.Errorf("invalid since time %q: %v", backupFlags.Since, err)
+		}
+		filter.Since = since
+	}
+
+	if backupFlags.org.id == "" && backupFlags.org.name == "" {
+		if backupFlags.Bucket != "" || backupFlags.BucketID != "" {
+			return filter, fmt.Errorf("must specify org-id, or org name when backing up a bucket")
+		}
+		return filter, nil
+	}
+
+	orgSvc, err := newOrganizationService()
+	if err != nil {
+		return filter, err
+	}
+	orgID, err := backupFlags.org.getID(orgSvc)
+	if err != nil {
+		return filter, err
+	}
+	filter.OrgID = &orgID
+
+	switch {
+	case backupFlags.BucketID != "":
+		bucketID, err := influxdb.IDFromString(backupFlags.BucketID)
+		if err != nil {
+			return filter, fmt.Errorf("invalid bucket ID provided: %s", err.Error())
+		}
+		filter.BucketID = bucketID
+	case backupFlags.Bucket != "":
+		bucketSvc, err := newBucketService()
+		if err != nil {
+			return filter, err
+		}
+		bucket, err := bucketSvc.FindBucket(ctx, influxdb.BucketFilter{
+			OrganizationID: &orgID,
+			Name:           &backupFlags.Bucket,
+		})
+		if err != nil {
+			return filter, err
+		}
+		filter.BucketID = &bucket.ID
+	}
+	return filter, nil
+}
+
+func fetchBackupManifest(ctx context.Context, backupService influxdb.BackupService, id int) (*influxdb.BackupManifest, error) {
+	var buf bytes.Buffer
+	if err := backupService.FetchBackupFile(ctx, id, influxdb.BackupManifestFilename, &buf); err != nil {
+		return nil, fmt.Errorf("error fetching file %s: %v", influxdb.BackupManifestFilename, err)
+	}
+
+	var manifest influxdb.BackupManifest
+	if err := json.NewDecoder(&buf).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("error decoding backup manifest: %v", err)
+	}
+	return &manifest, nil
+}
+
+func writeBackupManifest(path string, manifest *influxdb.BackupManifest) error {
+	b, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+	name := manifest.CreatedAt.UTC().Format("20060102T150405.000000000Z") + backupManifestExt
+	return ioutil.WriteFile(filepath.Join(path, name), b, 0666)
+}
+
+// latestBackupManifest returns the most recently created manifest in path
+// along with its file name. It returns a nil manifest if path holds none.
+func latestBackupManifest(path string) (string, *influxdb.BackupManifest, error) {
+	matches, err := filepath.Glob(filepath.Join(path, "*"+backupManifestExt))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		latestName string
+		latest     *influxdb.BackupManifest
+	)
+	for _, match := range matches {
+		manifest, err := readBackupManifest(match)
+		if err != nil {
+			return "", nil, err
+		}
+		if latest == nil || manifest.CreatedAt.After(latest.CreatedAt) {
+			latestName, latest = filepath.Base(match), manifest
+		}
+	}
+	return latestName, latest, nil
+}
+
+// backupManifestChain returns the chain of manifests ending with the most
+// recently created manifest in path, from the base backup of the chain to
+// the most recent incremental backup, or no manifest if path holds none.
+func backupManifestChain(path string) ([]*influxdb.BackupManifest, error) {
+	name, manifest, err := latestBackupManifest(path)
+	if err != nil || manifest == nil {
+		return nil, err
+	}
+
+	chain := []*influxdb.BackupManifest{manifest}
+	seen := map[string]bool{name: true}
+	for manifest.Parent != "" {
+		name = filepath.Base(manifest.Parent)
+		if seen[name] {
+			return nil, fmt.Errorf("backup manifest %s is its own ancestor", name)
+		}
+		seen[name] = true
+
+		if manifest, err = readBackupManifest(filepath.Join(path, name)); err != nil {
+			return nil, err
+		}
+		chain = append(chain, manifest)
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+func readBackupManifest(path string) (*influxdb.BackupManifest, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest influxdb.BackupManifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return nil, fmt.Errorf("error decoding backup manifest %s: %v", path, err)
+	}
+	return &manifest, nil
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/http"
@@ -29,6 +31,8 @@ type cmdBucketBuilder struct {
 	description string
 	org         organization
 	retention   string
+	tiers       []string
+	sgDuration  string
 }
 
 func newCmdBucketBuilder(svcsFn bucketSVCsFn, f *globalFlags, opts genericCLIOpts) *cmdBucketBuilder {
@@ -49,6 +53,7 @@ func (b *cmdBucketBuilder) cmd() *cobra.Command {
 		b.cmdDelete(),
 		b.cmdList(),
 		b.cmdUpdate(),
+		newCmdBucketSchemaBuilder(newBucketSchemaSVCs, b.globalFlags, b.genericCLIOpts).cmd(),
 	)
 
 	return cmd
@@ -72,6 +77,8 @@ func (b *cmdBucketBuilder) cmdCreate() *cobra.Command {
 
 	cmd.Flags().StringVarP(&b.description, "description", "d", "", "Description of bucket that will be created")
 	cmd.Flags().StringVarP(&b.retention, "retention", "r", "", "Duration bucket will retain data. 0 is infinite. Default is 0.")
+	b.registerRetentionTierFlag(cmd)
+	b.registerShardGroupDurationFlag(cmd)
 	b.org.register(cmd, false)
 	b.registerPrintFlags(cmd)
 
@@ -93,10 +100,22 @@ func (b *cmdBucketBuilder) cmdCreateRunEFn(*cobra.Command, []string) error {
 		return err
 	}
 
+	tiers, err := parseRetentionTiers(b.tiers)
+	if err != nil {
+		return err
+	}
+
+	sgd, err := rawDurationToTimeDuration(b.sgDuration)
+	if err != nil {
+		return err
+	}
+
 	bkt := &influxdb.Bucket{
-		Name:            b.name,
-		Description:     b.description,
-		RetentionPeriod: dur,
+		Name:               b.name,
+		Description:        b.description,
+		RetentionPeriod:    dur,
+		RetentionTiers:     tiers,
+		ShardGroupDuration: sgd,
 	}
 	bkt.OrgID, err = b.org.getID(orgSVC)
 	if err != nil {
@@ -250,6 +269,8 @@ func (b *cmdBucketBuilder) cmdUpdate() *cobra.Command {
 	cmd.Flags().StringVarP(&b.description, "description", "d", "", "Description of bucket that will be created")
 	cmd.MarkFlagRequired("id")
 	cmd.Flags().StringVarP(&b.retention, "retention", "r", "", "Duration bucket will retain data. 0 is infinite. Default is 0.")
+	b.registerRetentionTierFlag(cmd)
+	b.registerShardGroupDurationFlag(cmd)
 
 	return cmd
 }
@@ -281,6 +302,25 @@ func (b *cmdBucketBuilder) cmdUpdateRunEFn(cmd *cobra.Command, args []string) er
 		update.RetentionPeriod = &dur
 	}
 
+	if cmd.Flags().Changed("shard-group-duration") {
+		sgd, err := rawDurationToTimeDuration(b.sgDuration)
+		if err != nil {
+			return err
+		}
+		if dur == 0 {
+			return errors.New("--shard-group-duration requires --retention to be set")
+		}
+		update.ShardGroupDuration = &sgd
+	}
+
+	if cmd.Flags().Changed("retention-tier") {
+		tiers, err := parseRetentionTiers(b.tiers)
+		if err != nil {
+			return err
+		}
+		update.RetentionTiers = &tiers
+	}
+
 	bkt, err := bktSVC.UpdateBucket(context.Background(), id, update)
 	if err != nil {
 		return fmt.Errorf("failed to update bucket: %v", err)
@@ -299,6 +339,42 @@ func (b *cmdBucketBuilder) registerPrintFlags(cmd *cobra.Command) {
 	registerPrintOptions(cmd, &b.hideHeaders, &b.json)
 }
 
+func (b *cmdBucketBuilder) registerRetentionTierFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&b.tiers, "retention-tier", nil, "Downsampled retention tier as window:aggregate:retention, e.g. 5m:mean:90d. Repeat for each tier, from the finest to the coarsest.")
+}
+
+func (b *cmdBucketBuilder) registerShardGroupDurationFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&b.sgDuration, "shard-group-duration", "", "Duration of the time partitions the bucket data is stored in, so that expired partitions are removed whole. 0 disables partitioning. Default is 0.")
+}
+
+// parseRetentionTiers parses retention tiers of the form
+// window:aggregate:retention, e.g. 5m:mean:90d.
+func parseRetentionTiers(raw []string) ([]influxdb.RetentionTier, error) {
+	var tiers []influxdb.RetentionTier
+	for _, r := range raw {
+		parts := strings.Split(r, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid retention tier %q: expected window:aggregate:retention", r)
+		}
+
+		window, err := rawDurationToTimeDuration(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid retention tier %q window: %v", r, err)
+		}
+		retention, err := rawDurationToTimeDuration(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid retention tier %q retention: %v", r, err)
+		}
+
+		tiers = append(tiers, influxdb.RetentionTier{
+			Window:          window,
+			Aggregate:       parts[1],
+			RetentionPeriod: retention,
+		})
+	}
+	return tiers, nil
+}
+
 type bucketPrintOpt struct {
 	deleted bool
 	bucket  *influxdb.Bucket
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http"
+	"github.com/influxdata/influxdb/v2/tenant"
+	"github.com/spf13/cobra"
+)
+
+type bucketSchemaSVCsFn func() (influxdb.BucketSchemaService, influxdb.BucketService, error)
+
+type cmdBucketSchemaBuilder struct {
+	genericCLIOpts
+	*globalFlags
+
+	svcFn bucketSchemaSVCsFn
+
+	id          string
+	name        string
+	org         organization
+	file        string
+	hideHeaders bool
+	json        bool
+}
+
+func newCmdBucketSchemaBuilder(svcFn bucketSchemaSVCsFn, f *globalFlags, opts genericCLIOpts) *cmdBucketSchemaBuilder {
+	return &cmdBucketSchemaBuilder{
+		globalFlags:    f,
+		genericCLIOpts: opts,
+		svcFn:          svcFn,
+	}
+}
+
+func (b *cmdBucketSchemaBuilder) cmd() *cobra.Command {
+	cmd := b.newCmd("schema", nil)
+	cmd.Short = "Bucket schema management commands"
+	cmd.Long = `Manage the explicit schema of a bucket.
+
+Once a bucket has a schema, writes are only accepted for the measurements
+it declares, with the tag keys and the fields, of the declared types, of
+their measurement. Points which do not match the schema are rejected and
+reported by line.`
+	cmd.Run = seeHelp
+	cmd.AddCommand(
+		b.cmdGet(),
+		b.cmdSet(),
+		b.cmdDelete(),
+	)
+
+	return cmd
+}
+
+func (b *cmdBucketSchemaBuilder) cmdGet() *cobra.Command {
+	cmd := b.newCmd("get", b.cmdGetRunEFn)
+	cmd.Short = "Get the schema of a bucket"
+	cmd.Aliases = []string{"find"}
+
+	b.registerBucketFlags(cmd)
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdBucketSchemaBuilder) cmdGetRunEFn(*cobra.Command, []string) error {
+	schemaSVC, bktSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	bucketID, err := b.bucketID(ctx, bktSVC)
+	if err != nil {
+		return err
+	}
+
+	schema, err := schemaSVC.FindBucketSchema(ctx, bucketID)
+	if err != nil {
+		return fmt.Errorf("failed to find schema of bucket %q: %v", bucketID, err)
+	}
+
+	return b.printSchema(schema)
+}
+
+func (b *cmdBucketSchemaBuilder) cmdSet() *cobra.Command {
+	cmd := b.newCmd("set", b.cmdSetRunEFn)
+	cmd.Short = "Set the schema of a bucket"
+	cmd.Long = `Set the schema of a bucket, replacing its current schema.
+
+The schema is read from a JSON file listing the measurements of the bucket:
+
+	{
+	  "measurements": [
+	    {
+	      "name": "cpu",
+	      "tags": ["host", "region"],
+	      "fields": [
+	        {"name": "usage_user", "type": "float"},
+	        {"name": "cores", "type": "unsigned"}
+	      ]
+	    }
+	  ]
+	}
+
+Field types are float, integer, unsigned, string and boolean.`
+
+	b.registerBucketFlags(cmd)
+	b.registerPrintFlags(cmd)
+	cmd.Flags().StringVarP(&b.file, "file", "f", "", "Path to the JSON file holding the schema")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func (b *cmdBucketSchemaBuilder) cmdSetRunEFn(*cobra.Command, []string) error {
+	schemaSVC, bktSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	raw, err := ioutil.ReadFile(b.file)
+	if err != nil {
+		return fmt.Errorf("failed to read schema file %q: %v", b.file, err)
+	}
+
+	var schema influxdb.BucketSchema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return fmt.Errorf("failed to decode schema file %q: %v", b.file, err)
+	}
+
+	ctx := context.Background()
+	schema.BucketID, err = b.bucketID(ctx, bktSVC)
+	if err != nil {
+		return err
+	}
+
+	if err := schemaSVC.PutBucketSchema(ctx, &schema); err != nil {
+		return fmt.Errorf("failed to set schema of bucket %q: %v", schema.BucketID, err)
+	}
+
+	return b.printSchema(&schema)
+}
+
+func (b *cmdBucketSchemaBuilder) cmdDelete() *cobra.Command {
+	cmd := b.newCmd("delete", b.cmdDeleteRunEFn)
+	cmd.Short = "Delete the schema of a bucket"
+
+	b.registerBucketFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdBucketSchemaBuilder) cmdDeleteRunEFn(*cobra.Command, []string) error {
+	schemaSVC, bktSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	bucketID, err := b.bucketID(ctx, bktSVC)
+	if err != nil {
+		return err
+	}
+
+	if err := schemaSVC.DeleteBucketSchema(ctx, bucketID); err != nil {
+		return fmt.Errorf("failed to delete schema of bucket %q: %v", bucketID, err)
+	}
+	return nil
+}
+
+func (b *cmdBucketSchemaBuilder) newCmd(use string, runE func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd(use, runE, true)
+	b.globalFlags.registerFlags(cmd)
+	return cmd
+}
+
+func (b *cmdBucketSchemaBuilder) registerBucketFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The bucket ID, required if name isn't provided")
+	cmd.Flags().StringVarP(&b.name, "name", "n", "", "The bucket name, org or org-id will be required by choosing this")
+	b.org.register(cmd, false)
+}
+
+func (b *cmdBucketSchemaBuilder) registerPrintFlags(cmd *cobra.Command) {
+	registerPrintOptions(cmd, &b.hideHeaders, &b.json)
+}
+
+// bucketID returns the ID of the bucket selected by the id or name flags.
+func (b *cmdBucketSchemaBuilder) bucketID(ctx context.Context, bktSVC influxdb.BucketService) (influxdb.ID, error) {
+	if b.id != "" {
+		var id influxdb.ID
+		if err := id.DecodeFromString(b.id); err != nil {
+			return 0, fmt.Errorf("failed to decode bucket id %q: %v", b.id, err)
+		}
+		return id, nil
+	}
+
+	if b.name == "" {
+		return 0, errors.New("must specify the bucket id or name")
+	}
+	if err := b.org.validOrgFlags(b.globalFlags); err != nil {
+		return 0, err
+	}
+
+	filter := influxdb.BucketFilter{Name: &b.name}
+	if b.org.id != "" {
+		orgID, err := influxdb.IDFromString(b.org.id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode org id %q: %v", b.org.id, err)
+		}
+		filter.OrganizationID = orgID
+	} else if b.org.name != "" {
+		filter.Org = &b.org.name
+	}
+
+	bkt, err := bktSVC.FindBucket(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find bucket %q: %v", b.name, err)
+	}
+	return bkt.ID, nil
+}
+
+func (b *cmdBucketSchemaBuilder) printSchema(schema *influxdb.BucketSchema) error {
+	if b.json {
+		return b.writeJSON(schema)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+	w.WriteHeaders("Measurement", "Tags", "Field", "Type")
+
+	for _, m := range schema.Measurements {
+		for _, f := range m.Fields {
+			w.Write(map[string]interface{}{
+				"Measurement": m.Name,
+				"Tags":        strings.Join(m.Tags, ","),
+				"Field":       f.Name,
+				"Type":        string(f.Type),
+			})
+		}
+	}
+
+	return nil
+}
+
+func newBucketSchemaSVCs() (influxdb.BucketSchemaService, influxdb.BucketService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &tenant.BucketSchemaClientService{Client: httpClient}, &http.BucketService{Client: httpClient}, nil
+}
@@ -60,6 +60,25 @@ func TestCmdBucket(t *testing.T) {
 					OrgID:           orgID,
 				},
 			},
+			{
+				name: "with retention tiers",
+				flags: []string{
+					"--name=new name",
+					"--retention=7d",
+					"--retention-tier=5m:mean:90d",
+					"--retention-tier=1h:max:0",
+					"--org=org name",
+				},
+				expectedBucket: influxdb.Bucket{
+					Name:            "new name",
+					RetentionPeriod: 7 * 24 * time.Hour,
+					RetentionTiers: []influxdb.RetentionTier{
+						{Window: 5 * time.Minute, Aggregate: "mean", RetentionPeriod: 90 * 24 * time.Hour},
+						{Window: time.Hour, Aggregate: "max"},
+					},
+					OrgID: orgID,
+				},
+			},
 			{
 				name: "shorts",
 				flags: []string{
@@ -95,7 +114,7 @@ func TestCmdBucket(t *testing.T) {
 		cmdFn := func(expectedBkt influxdb.Bucket) func(*globalFlags, genericCLIOpts) *cobra.Command {
 			svc := mock.NewBucketService()
 			svc.CreateBucketFn = func(ctx context.Context, bucket *influxdb.Bucket) error {
-				if expectedBkt != *bucket {
+				if !reflect.DeepEqual(expectedBkt, *bucket) {
 					return fmt.Errorf("unexpected bucket;\n\twant= %+v\n\tgot=  %+v", expectedBkt, *bucket)
 				}
 				return nil
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/spf13/cobra"
+)
+
+type checkSVCsFn func() (influxdb.CheckDryRunService, influxdb.OrganizationService, error)
+
+func cmdCheck(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	builder := newCmdCheckBuilder(newCheckSVCs, f, opt)
+	return builder.cmd()
+}
+
+type cmdCheckBuilder struct {
+	genericCLIOpts
+	*globalFlags
+
+	svcFn checkSVCsFn
+
+	file        string
+	org         organization
+	start       string
+	stop        string
+	hideHeaders bool
+	json        bool
+}
+
+func newCmdCheckBuilder(svcsFn checkSVCsFn, f *globalFlags, opts genericCLIOpts) *cmdCheckBuilder {
+	return &cmdCheckBuilder{
+		globalFlags:    f,
+		genericCLIOpts: opts,
+		svcFn:          svcsFn,
+	}
+}
+
+func (b *cmdCheckBuilder) cmd() *cobra.Command {
+	cmd := b.newCmd("check", nil)
+	cmd.Short = "Check management commands"
+	cmd.Run = seeHelp
+	cmd.AddCommand(
+		b.cmdTest(),
+	)
+
+	return cmd
+}
+
+func (b *cmdCheckBuilder) cmdTest() *cobra.Command {
+	cmd := b.newCmd("test", b.cmdTestRunEFn)
+	cmd.Short = "Test check against past data"
+	cmd.Long = `Run a check over a past time range without saving it.
+
+The check is read from a JSON file in the format of the checks API, and is
+run at each of its scheduled times between start and stop. The statuses it
+would have written are printed in time order. The time range defaults to the
+last day.
+
+Examples:
+	# test a threshold check over the last week
+	influx check test -f cpu.json --start 2020-06-01T00:00:00Z --stop 2020-06-08T00:00:00Z`
+
+	cmd.Flags().StringVarP(&b.file, "file", "f", "", "Path to the JSON file holding the check")
+	cmd.MarkFlagRequired("file")
+	cmd.Flags().StringVar(&b.start, "start", "", "Start of the time range, as an RFC3339 time")
+	cmd.Flags().StringVar(&b.stop, "stop", "", "Stop of the time range, as an RFC3339 time")
+	b.org.register(cmd, false)
+	registerPrintOptions(cmd, &b.hideHeaders, &b.json)
+
+	return cmd
+}
+
+func (b *cmdCheckBuilder) cmdTestRunEFn(*cobra.Command, []string) error {
+	raw, err := ioutil.ReadFile(b.file)
+	if err != nil {
+		return fmt.Errorf("failed to read check file %q: %v", b.file, err)
+	}
+	chk, err := check.UnmarshalJSON(raw)
+	if err != nil {
+		return fmt.Errorf("failed to decode check file %q: %v", b.file, err)
+	}
+
+	var start, stop time.Time
+	if b.start != "" {
+		if start, err = parseCheckTime("start", b.start); err != nil {
+			return err
+		}
+	}
+	if b.stop != "" {
+		if stop, err = parseCheckTime("stop", b.stop); err != nil {
+			return err
+		}
+	}
+
+	dryRunSVC, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	// The organization of the flags, or of the config, is only used when the
+	// file does not hold one.
+	if !chk.GetOrgID().Valid() {
+		if err := b.org.validOrgFlags(b.globalFlags); err != nil {
+			return err
+		}
+		orgID, err := b.org.getID(orgSVC)
+		if err != nil {
+			return err
+		}
+		chk.SetOrgID(orgID)
+	}
+
+	statuses, err := dryRunSVC.DryRunCheck(context.Background(), chk, start, stop)
+	if err != nil {
+		return fmt.Errorf("failed to test check %q: %v", chk.GetName(), err)
+	}
+
+	return b.printStatuses(statuses)
+}
+
+func (b *cmdCheckBuilder) newCmd(use string, runE func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd(use, runE, true)
+	b.globalFlags.registerFlags(cmd)
+	return cmd
+}
+
+func (b *cmdCheckBuilder) printStatuses(statuses []*influxdb.CheckStatus) error {
+	if b.json {
+		return b.writeJSON(statuses)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+	w.WriteHeaders("Time", "Level", "Message", "Tags")
+
+	for _, s := range statuses {
+		var tags []string
+		for _, t := range s.Tags {
+			tags = append(tags, t.Key+"="+t.Value)
+		}
+
+		w.Write(map[string]interface{}{
+			"Time":    s.Time.Format(time.RFC3339),
+			"Level":   s.Level,
+			"Message": s.Message,
+			"Tags":    strings.Join(tags, ","),
+		})
+	}
+
+	return nil
+}
+
+func parseCheckTime(flag, s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s %q, must be an RFC3339 time: %v", flag, s, err)
+	}
+	return t, nil
+}
+
+func newCheckSVCs() (influxdb.CheckDryRunService, influxdb.OrganizationService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &http.CheckService{Client: httpClient}, &http.OrganizationService{Client: httpClient}, nil
+}
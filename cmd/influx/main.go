@@ -323,6 +323,7 @@ func influxCmd(opts ...genericCLIOptFn) *cobra.Command {
 		cmdAuth,
 		cmdBackup,
 		cmdBucket,
+		cmdCheck,
 		cmdConfig,
 		cmdDashboard,
 		cmdDelete,
@@ -330,8 +331,10 @@ func influxCmd(opts ...genericCLIOptFn) *cobra.Command {
 		cmdOrganization,
 		cmdPing,
 		cmdQuery,
+		cmdRestore,
 		cmdSecret,
 		cmdSetup,
+		cmdSilence,
 		cmdStack,
 		cmdTask,
 		cmdTelegraf,
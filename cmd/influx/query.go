@@ -35,6 +35,12 @@ func cmdQuery(f *globalFlags, opts genericCLIOpts) *cobra.Command {
 	queryFlags.org.register(cmd, true)
 	cmd.Flags().StringVarP(&queryFlags.file, "file", "f", "", "Path to Flux query file")
 
+	builder := newCmdRunningQueryBuilder(newRunningQuerySVCs, f, opts)
+	cmd.AddCommand(
+		builder.cmdKill(),
+		builder.cmdList(),
+	)
+
 	return cmd
 }
 
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/bolt"
+	"github.com/influxdata/influxdb/v2/http"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/tenant"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func cmdRestore(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("restore", restoreF, false)
+	cmd.Short = "Restore a bucket from a backup into the running InfluxDB"
+	cmd.Long = fmt.Sprintf(
+		`Restores the data of a single bucket from a backup created by "influx backup"
+into the running InfluxDB instance, which keeps serving other buckets.
+The bucket is read from the %s meta data file of --path, and its data from the
+.tsm data files listed by the chain of backup manifests ending with the most
+recent manifest of --path, from the base backup to the last incremental backup.
+
+The bucket is restored into a new bucket with the same name in the organization
+with the same name, unless --new-bucket or --new-org is given. The target bucket
+must not already exist.`,
+		bolt.DefaultFilename)
+
+	f.registerFlags(cmd)
+
+	opts := flagOpts{
+		{
+			DestP:    &restoreFlags.Path,
+			Flag:     "path",
+			Short:    'p',
+			EnvVar:   "PATH",
+			Desc:     "directory path to read backup files from",
+			Required: true,
+		},
+		{
+			DestP: &restoreFlags.OrgID,
+			Flag:  "org-id",
+			Desc:  "The ID of the organization that owns the bucket in the backup",
+		},
+		{
+			DestP: &restoreFlags.Org,
+			Flag:  "org",
+			Short: 'o',
+			Desc:  "The name of the organization that owns the bucket in the backup",
+		},
+		{
+			DestP: &restoreFlags.BucketID,
+			Flag:  "bucket-id",
+			Desc:  "The ID of the bucket to restore",
+		},
+		{
+			DestP: &restoreFlags.Bucket,
+			Flag:  "bucket",
+			Short: 'b',
+			Desc:  "The name of the bucket to restore",
+		},
+		{
+			DestP: &restoreFlags.NewOrg,
+			Flag:  "new-org",
+			Desc:  "The name of the organization to restore the bucket into",
+		},
+		{
+			DestP: &restoreFlags.NewBucket,
+			Flag:  "new-bucket",
+			Desc:  "The name of the bucket to restore the data into",
+		},
+	}
+	opts.mustRegister(cmd)
+
+	return cmd
+}
+
+var restoreFlags struct {
+	Path      string
+	OrgID     string
+	Org       string
+	BucketID  string
+	Bucket    string
+	NewOrg    string
+	NewBucket string
+}
+
+func newRestoreService() (influxdb.RestoreService, error) {
+	ac := flags.config()
+	return &http.RestoreService{
+		Addr:               ac.Host,
+		Token:              ac.Token,
+		InsecureSkipVerify: flags.skipVerify,
+	}, nil
+}
+
+func restoreF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if restoreFlags.Path == "" {
+		return fmt.Errorf("must specify path")
+	}
+	if restoreFlags.Bucket != "" && restoreFlags.BucketID != "" {
+		return fmt.Errorf("must specify only one of bucket or bucket-id")
+	}
+
+	chain, err := backupManifestChain(restoreFlags.Path)
+	if err != nil {
+		return err
+	}
+
+	srcOrg, srcBucket, err := restoreSourceBucket(ctx, chain)
+	if err != nil {
+		return err
+	}
+
+	files, err := restoreDataFiles(restoreFlags.Path, chain)
+	if err != nil {
+		return err
+	}
+
+	orgSvc, err := newOrganizationService()
+	if err != nil {
+		return err
+	}
+	orgName := srcOrg.Name
+	if restoreFlags.NewOrg != "" {
+		orgName = restoreFlags.NewOrg
+	}
+	org, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &orgName})
+	if err != nil {
+		return fmt.Errorf("failed to find organization %q: %v", orgName, err)
+	}
+
+	bucketSvc, err := newBucketService()
+	if err != nil {
+		return err
+	}
+	bucket := &influxdb.Bucket{
+		OrgID:               org.ID,
+		Name:                srcBucket.Name,
+		Description:         srcBucket.Description,
+		RetentionPolicyName: srcBucket.RetentionPolicyName,
+		RetentionPeriod:     srcBucket.RetentionPeriod,
+	}
+	if restoreFlags.NewBucket != "" {
+		bucket.Name = restoreFlags.NewBucket
+	}
+	if err := bucketSvc.CreateBucket(ctx, bucket); err != nil {
+		return fmt.Errorf("failed to create bucket %q: %v", bucket.Name, err)
+	}
+
+	restoreService, err := newRestoreService()
+	if err != nil {
+		return err
+	}
+
+	req := influxdb.RestoreBucketRequest{
+		SourceOrgID:    srcBucket.OrgID,
+		SourceBucketID: srcBucket.ID,
+		OrgID:          bucket.OrgID,
+		BucketID:       bucket.ID,
+	}
+	for _, file := range files {
+		if err := restoreFile(ctx, restoreService, req, file); err != nil {
+			return fmt.Errorf("error restoring file %s: %v", filepath.Base(file), err)
+		}
+	}
+
+	fmt.Printf("Restored bucket %q into bucket %q (%s) from %d files\n", srcBucket.Name, bucket.Name, bucket.ID, len(files))
+
+	return nil
+}
+
+// restoreSourceBucket finds the bucket to restore, and the organization that
+// owns it, in the meta data of the backup.
+func restoreSourceBucket(ctx context.Context, chain []*influxdb.BackupManifest) (*influxdb.Organization, *influxdb.Bucket, error) {
+	boltPath := filepath.Join(restoreFlags.Path, bolt.DefaultFilename)
+	if _, err := os.Stat(boltPath); err != nil {
+		return nil, nil, fmt.Errorf("no %s file in backup: %v", bolt.DefaultFilename, err)
+	}
+
+	bucketID := restoreFlags.BucketID
+	if bucketID == "" && restoreFlags.Bucket == "" {
+		// A single bucket backup identifies the bucket in its manifest.
+		if len(chain) == 0 || chain[len(chain)-1].BucketID == nil {
+			return nil, nil, fmt.Errorf("must specify bucket or bucket-id")
+		}
+		bucketID = chain[len(chain)-1].BucketID.String()
+	}
+
+	kvStore := bolt.NewKVStore(zap.NewNop(), boltPath)
+	if err := kvStore.Open(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer kvStore.Close()
+
+	var (
+		store  = tenant.NewStore(kvStore)
+		org    *influxdb.Organization
+		bucket *influxdb.Bucket
+	)
+	err := store.View(ctx, func(tx kv.Tx) error {
+		if bucketID != "" {
+			id, err := influxdb.IDFromString(bucketID)
+			if err != nil {
+				return fmt.Errorf("invalid bucket ID provided: %s", err.Error())
+			}
+			if bucket, err = store.GetBucket(ctx, tx, *id); err != nil {
+				return err
+			}
+			org, err = store.GetOrg(ctx, tx, bucket.OrgID)
+			return err
+		}
+
+		var err error
+		switch {
+		case restoreFlags.OrgID != "":
+			id, err := influxdb.IDFromString(restoreFlags.OrgID)
+			if err != nil {
+				return fmt.Errorf("invalid org ID provided: %s", err.Error())
+			}
+			org, err = store.GetOrg(ctx, tx, *id)
+			if err != nil {
+				return err
+			}
+		case restoreFlags.Org != "":
+			if org, err = store.GetOrgByName(ctx, tx, restoreFlags.Org); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("must specify org-id, or org name when restoring a bucket by name")
+		}
+		bucket, err = store.GetBucketByName(ctx, tx, org.ID, restoreFlags.Bucket)
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find bucket in backup: %v", err)
+	}
+	return org, bucket, nil
+}
+
+// restoreDataFiles returns the paths of the data files listed by the
+// manifests of chain, each once, in the order they were first backed up.
+// Backups created without a manifest are restored from all of their data
+// files.
+func restoreDataFiles(path string, chain []*influxdb.BackupManifest) ([]string, error) {
+	if len(chain) == 0 {
+		files, err := filepath.Glob(filepath.Join(path, "*.tsm"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		return files, nil
+	}
+
+	var (
+		files []string
+		seen  = make(map[string]bool)
+	)
+	for _, manifest := range chain {
+		for _, name := range manifest.Files {
+			name = filepath.Base(name)
+			if filepath.Ext(name) != ".tsm" || seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			file := filepath.Join(path, name)
+			if _, err := os.Stat(file); err != nil {
+				return nil, fmt.Errorf("data file %s of the backup is missing: %v", name, err)
+			}
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// restoreFile uploads a backed up TSM file, along with its tombstone file if
+// the backup holds one.
+func restoreFile(ctx context.Context, svc influxdb.RestoreService, req influxdb.RestoreBucketRequest, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var tombstone io.Reader
+	tf, err := os.Open(strings.TrimSuffix(path, filepath.Ext(path)) + ".tombstone")
+	if err == nil {
+		defer tf.Close()
+		tombstone = tf
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	return svc.RestoreBucket(ctx, req, f, tombstone)
+}
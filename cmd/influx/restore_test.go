@@ -0,0 +1,75 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestRestoreDataFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influx-restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A base backup, an incremental backup of it, and the files of an
+	// unrelated backup sharing the directory.
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := &influxdb.BackupManifest{
+		ID:        1,
+		CreatedAt: t0,
+		Files:     []string{"000000001-000000001.tsm", "000000002-000000001.tsm", "influxd.bolt"},
+	}
+	incremental := &influxdb.BackupManifest{
+		ID:        2,
+		CreatedAt: t0.Add(time.Hour),
+		Since:     &t0,
+		Parent:    "20200101T000000.000000000Z" + backupManifestExt,
+		Files:     []string{"000000002-000000001.tsm", "000000003-000000001.tsm", "influxd.bolt"},
+	}
+	for _, m := range []*influxdb.BackupManifest{base, incremental} {
+		if err := writeBackupManifest(dir, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"000000001-000000001.tsm", "000000002-000000001.tsm", "000000003-000000001.tsm", "000000009-000000001.tsm"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chain, err := backupManifestChain(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 || chain[0].ID != base.ID || chain[1].ID != incremental.ID {
+		t.Fatalf("unexpected manifest chain %+v", chain)
+	}
+
+	files, err := restoreDataFiles(dir, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{
+		filepath.Join(dir, "000000001-000000001.tsm"),
+		filepath.Join(dir, "000000002-000000001.tsm"),
+		filepath.Join(dir, "000000003-000000001.tsm"),
+	}
+	if !reflect.DeepEqual(files, exp) {
+		t.Fatalf("got files %v, expected %v", files, exp)
+	}
+
+	// A data file listed by the chain must be in the backup.
+	if err := os.Remove(filepath.Join(dir, "000000001-000000001.tsm")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restoreDataFiles(dir, chain); err == nil {
+		t.Fatal("expected error restoring a backup missing a data file")
+	}
+}
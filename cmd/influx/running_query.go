@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http"
+	"github.com/spf13/cobra"
+)
+
+type runningQuerySVCsFn func() (influxdb.RunningQueryService, influxdb.OrganizationService, error)
+
+type cmdRunningQueryBuilder struct {
+	genericCLIOpts
+	*globalFlags
+
+	svcFn runningQuerySVCsFn
+
+	id          string
+	org         organization
+	hideHeaders bool
+	json        bool
+}
+
+func newCmdRunningQueryBuilder(svcsFn runningQuerySVCsFn, f *globalFlags, opts genericCLIOpts) *cmdRunningQueryBuilder {
+	return &cmdRunningQueryBuilder{
+		globalFlags:    f,
+		genericCLIOpts: opts,
+		svcFn:          svcsFn,
+	}
+}
+
+func (b *cmdRunningQueryBuilder) cmdList() *cobra.Command {
+	cmd := b.newCmd("list", b.cmdListRunEFn)
+	cmd.Short = "List running queries"
+	cmd.Long = `List the queries queued or executing on the server.
+
+Only the queries of the organizations the token may read are listed. The
+queries of an organization alone are listed with --org or --org-id.`
+	cmd.Aliases = []string{"find", "ls"}
+
+	b.org.register(cmd, false)
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdRunningQueryBuilder) cmdListRunEFn(*cobra.Command, []string) error {
+	if b.org.id != "" && b.org.name != "" {
+		return fmt.Errorf("must specify org-id, or org name not both")
+	}
+
+	runningQuerySVC, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var filter influxdb.RunningQueryFilter
+	if b.org.id != "" || b.org.name != "" {
+		orgID, err := b.org.getID(orgSVC)
+		if err != nil {
+			return err
+		}
+		filter.OrgID = &orgID
+	}
+
+	queries, err := runningQuerySVC.FindRunningQueries(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve running queries: %v", err)
+	}
+
+	return b.printRunningQueries(queries...)
+}
+
+func (b *cmdRunningQueryBuilder) cmdKill() *cobra.Command {
+	cmd := b.newCmd("kill", b.cmdKillRunEFn)
+	cmd.Short = "Cancel a running query"
+
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The running query ID")
+	cmd.MarkFlagRequired("id")
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdRunningQueryBuilder) cmdKillRunEFn(*cobra.Command, []string) error {
+	runningQuerySVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(b.id); err != nil {
+		return fmt.Errorf("failed to decode running query id %q: %v", b.id, err)
+	}
+
+	ctx := context.Background()
+	q, err := runningQuerySVC.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find running query with id %q: %v", id, err)
+	}
+	if err := runningQuerySVC.CancelRunningQuery(ctx, id); err != nil {
+		return fmt.Errorf("failed to cancel running query with id %q: %v", id, err)
+	}
+
+	return b.printRunningQueries(q)
+}
+
+func (b *cmdRunningQueryBuilder) newCmd(use string, runE func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd(use, runE, true)
+	b.globalFlags.registerFlags(cmd)
+	return cmd
+}
+
+func (b *cmdRunningQueryBuilder) registerPrintFlags(cmd *cobra.Command) {
+	registerPrintOptions(cmd, &b.hideHeaders, &b.json)
+}
+
+func (b *cmdRunningQueryBuilder) printRunningQueries(queries ...*influxdb.RunningQuery) error {
+	if b.json {
+		var v interface{} = queries
+		if len(queries) == 1 {
+			v = queries[0]
+		}
+		return b.writeJSON(v)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+	w.WriteHeaders("ID", "Organization ID", "User ID", "State", "Elapsed", "Memory Bytes", "Query")
+
+	for _, q := range queries {
+		var userID string
+		if q.UserID.Valid() {
+			userID = q.UserID.String()
+		}
+
+		w.Write(map[string]interface{}{
+			"ID":              q.ID.String(),
+			"Organization ID": q.OrgID.String(),
+			"User ID":         userID,
+			"State":           q.State,
+			"Elapsed":         q.Elapsed.Round(time.Millisecond).String(),
+			"Memory Bytes":    q.MemoryBytes,
+			"Query":           strings.Join(strings.Fields(q.Query), " "),
+		})
+	}
+
+	return nil
+}
+
+func newRunningQuerySVCs() (influxdb.RunningQueryService, influxdb.OrganizationService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &http.RunningQueryService{Client: httpClient}, &http.OrganizationService{Client: httpClient}, nil
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http"
+	"github.com/spf13/cobra"
+)
+
+type silenceSVCsFn func() (influxdb.SilenceService, influxdb.OrganizationService, error)
+
+func cmdSilence(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	builder := newCmdSilenceBuilder(newSilenceSVCs, f, opt)
+	return builder.cmd()
+}
+
+type cmdSilenceBuilder struct {
+	genericCLIOpts
+	*globalFlags
+
+	svcFn silenceSVCsFn
+
+	id          string
+	name        string
+	description string
+	org         organization
+	start       string
+	end         string
+	recur       string
+	until       string
+	tags        []string
+	active      bool
+	hideHeaders bool
+	json        bool
+}
+
+func newCmdSilenceBuilder(svcsFn silenceSVCsFn, f *globalFlags, opts genericCLIOpts) *cmdSilenceBuilder {
+	return &cmdSilenceBuilder{
+		globalFlags:    f,
+		genericCLIOpts: opts,
+		svcFn:          svcsFn,
+	}
+}
+
+func (b *cmdSilenceBuilder) cmd() *cobra.Command {
+	cmd := b.newCmd("silence", nil)
+	cmd.Short = "Silence management commands"
+	cmd.Long = `Manage the silences of the notifications.
+
+A silence mutes the notifications of the statuses it matches while it is
+active, during a maintenance window for instance. The statuses are still
+recorded by their checks, but the notification rules of the organization do
+not send them to their endpoints.
+
+A silence is active from its start to its end, and every day or every week
+after that when it recurs. Tag rules restrict it to the statuses with
+matching tags, given as key=value, key!=value, key=~regex or key!~regex.`
+	cmd.Run = seeHelp
+	cmd.AddCommand(
+		b.cmdCreate(),
+		b.cmdDelete(),
+		b.cmdList(),
+		b.cmdUpdate(),
+	)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdCreate() *cobra.Command {
+	cmd := b.newCmd("create", b.cmdCreateRunEFn)
+	cmd.Short = "Create silence"
+
+	cmd.Flags().StringVarP(&b.name, "name", "n", "", "Name of the silence")
+	cmd.MarkFlagRequired("name")
+	cmd.Flags().StringVarP(&b.description, "description", "d", "", "Description of the silence")
+	b.registerWindowFlags(cmd)
+	cmd.MarkFlagRequired("start")
+	cmd.MarkFlagRequired("end")
+	b.org.register(cmd, false)
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdCreateRunEFn(*cobra.Command, []string) error {
+	if err := b.org.validOrgFlags(b.globalFlags); err != nil {
+		return err
+	}
+
+	silenceSVC, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	s := &influxdb.Silence{
+		Name:        b.name,
+		Description: b.description,
+	}
+	if s.Start, err = parseSilenceTime("start", b.start); err != nil {
+		return err
+	}
+	if s.End, err = parseSilenceTime("end", b.end); err != nil {
+		return err
+	}
+	if s.Recurrence, err = b.recurrence(); err != nil {
+		return err
+	}
+	if s.TagRules, err = parseSilenceTagRules(b.tags); err != nil {
+		return err
+	}
+	s.OrgID, err = b.org.getID(orgSVC)
+	if err != nil {
+		return err
+	}
+
+	if err := silenceSVC.CreateSilence(context.Background(), s); err != nil {
+		return fmt.Errorf("failed to create silence: %v", err)
+	}
+
+	return b.printSilences(s)
+}
+
+func (b *cmdSilenceBuilder) cmdDelete() *cobra.Command {
+	cmd := b.newCmd("delete", b.cmdDeleteRunEFn)
+	cmd.Short = "Delete silence"
+
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The silence ID")
+	cmd.MarkFlagRequired("id")
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdDeleteRunEFn(*cobra.Command, []string) error {
+	silenceSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(b.id); err != nil {
+		return fmt.Errorf("failed to decode silence id %q: %v", b.id, err)
+	}
+
+	ctx := context.Background()
+	s, err := silenceSVC.FindSilenceByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find silence with id %q: %v", id, err)
+	}
+	if err := silenceSVC.DeleteSilence(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete silence with id %q: %v", id, err)
+	}
+
+	return b.printSilences(s)
+}
+
+func (b *cmdSilenceBuilder) cmdList() *cobra.Command {
+	cmd := b.newCmd("list", b.cmdListRunEFn)
+	cmd.Short = "List silences"
+	cmd.Aliases = []string{"find", "ls"}
+
+	cmd.Flags().BoolVar(&b.active, "active", false, "Only list the silences active now")
+	b.org.register(cmd, false)
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdListRunEFn(*cobra.Command, []string) error {
+	if err := b.org.validOrgFlags(b.globalFlags); err != nil {
+		return err
+	}
+
+	silenceSVC, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	orgID, err := b.org.getID(orgSVC)
+	if err != nil {
+		return err
+	}
+
+	filter := influxdb.SilenceFilter{OrgID: &orgID}
+	if b.active {
+		now := time.Now()
+		filter.Active = &now
+	}
+
+	silences, err := silenceSVC.FindSilences(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve silences: %v", err)
+	}
+
+	return b.printSilences(silences...)
+}
+
+func (b *cmdSilenceBuilder) cmdUpdate() *cobra.Command {
+	cmd := b.newCmd("update", b.cmdUpdateRunEFn)
+	cmd.Short = "Update silence"
+	cmd.Long = `Update the fields of a silence given as flags.
+
+The recurrence of a silence is removed with --recur none.`
+
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The silence ID")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVarP(&b.name, "name", "n", "", "New name of the silence")
+	cmd.Flags().StringVarP(&b.description, "description", "d", "", "New description of the silence")
+	b.registerWindowFlags(cmd)
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdUpdateRunEFn(cmd *cobra.Command, args []string) error {
+	silenceSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(b.id); err != nil {
+		return fmt.Errorf("failed to decode silence id %q: %v", b.id, err)
+	}
+
+	var update influxdb.SilenceUpdate
+	if b.name != "" {
+		update.Name = &b.name
+	}
+	if b.description != "" {
+		update.Description = &b.description
+	}
+	if b.start != "" {
+		start, err := parseSilenceTime("start", b.start)
+		if err != nil {
+			return err
+		}
+		update.Start = &start
+	}
+	if b.end != "" {
+		end, err := parseSilenceTime("end", b.end)
+		if err != nil {
+			return err
+		}
+		update.End = &end
+	}
+	if b.recur == "none" {
+		update.Recurrence = &influxdb.SilenceRecurrence{}
+	} else if b.recur != "" {
+		if update.Recurrence, err = b.recurrence(); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("tag") {
+		tagRules, err := parseSilenceTagRules(b.tags)
+		if err != nil {
+			return err
+		}
+		update.TagRules = &tagRules
+	}
+
+	s, err := silenceSVC.UpdateSilence(context.Background(), id, update)
+	if err != nil {
+		return fmt.Errorf("failed to update silence: %v", err)
+	}
+
+	return b.printSilences(s)
+}
+
+func (b *cmdSilenceBuilder) newCmd(use string, runE func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd(use, runE, true)
+	b.globalFlags.registerFlags(cmd)
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) registerWindowFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&b.start, "start", "", "Start of the silence, as an RFC3339 time")
+	cmd.Flags().StringVar(&b.end, "end", "", "End of the silence, as an RFC3339 time")
+	cmd.Flags().StringVar(&b.recur, "recur", "", "Repeat the silence every day or every week; one of daily or weekly")
+	cmd.Flags().StringVar(&b.until, "until", "", "End of the recurrence, as an RFC3339 time")
+	cmd.Flags().StringArrayVarP(&b.tags, "tag", "t", nil, "Tag rule restricting the silence, as key=value, key!=value, key=~regex or key!~regex")
+}
+
+func (b *cmdSilenceBuilder) registerPrintFlags(cmd *cobra.Command) {
+	registerPrintOptions(cmd, &b.hideHeaders, &b.json)
+}
+
+// recurrence returns the recurrence given by the recur and until flags, or
+// nil when the silence does not recur.
+func (b *cmdSilenceBuilder) recurrence() (*influxdb.SilenceRecurrence, error) {
+	if b.recur == "" {
+		if b.until != "" {
+			return nil, fmt.Errorf("--until requires --recur to be set")
+		}
+		return nil, nil
+	}
+
+	r := &influxdb.SilenceRecurrence{Every: b.recur}
+	if b.until != "" {
+		until, err := parseSilenceTime("until", b.until)
+		if err != nil {
+			return nil, err
+		}
+		r.Until = &until
+	}
+	return r, nil
+}
+
+func (b *cmdSilenceBuilder) printSilences(silences ...*influxdb.Silence) error {
+	if b.json {
+		var v interface{} = silences
+		if len(silences) == 1 {
+			v = silences[0]
+		}
+		return b.writeJSON(v)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+	w.WriteHeaders("ID", "Name", "Start", "End", "Recurrence", "Tag Rules", "Organization ID")
+
+	for _, s := range silences {
+		var tagRules []string
+		for _, tr := range s.TagRules {
+			tagRules = append(tagRules, tr.Key+silenceTagOperators[tr.Operator]+tr.Value)
+		}
+
+		w.Write(map[string]interface{}{
+			"ID":              s.ID.String(),
+			"Name":            s.Name,
+			"Start":           s.Start.Format(time.RFC3339),
+			"End":             s.End.Format(time.RFC3339),
+			"Recurrence":      printSilenceRecurrence(s.Recurrence),
+			"Tag Rules":       strings.Join(tagRules, ","),
+			"Organization ID": s.OrgID.String(),
+		})
+	}
+
+	return nil
+}
+
+func printSilenceRecurrence(r *influxdb.SilenceRecurrence) string {
+	if r == nil {
+		return ""
+	}
+	if r.Until == nil {
+		return r.Every
+	}
+	return r.Every + " until " + r.Until.Format(time.RFC3339)
+}
+
+var silenceTagOperators = map[influxdb.Operator]string{
+	influxdb.Equal:         "=",
+	influxdb.NotEqual:      "!=",
+	influxdb.RegexEqual:    "=~",
+	influxdb.NotRegexEqual: "!~",
+}
+
+func parseSilenceTime(flag, s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s %q, must be an RFC3339 time: %v", flag, s, err)
+	}
+	return t, nil
+}
+
+// parseSilenceTagRules parses the tag rules given as key=value, key!=value,
+// key=~regex or key!~regex.
+func parseSilenceTagRules(rawTags []string) ([]influxdb.TagRule, error) {
+	var tagRules []influxdb.TagRule
+	for _, raw := range rawTags {
+		i := strings.IndexAny(raw, "=!")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid tag rule %q, must be key=value, key!=value, key=~regex or key!~regex", raw)
+		}
+
+		op, value := influxdb.Equal, raw[i+1:]
+		switch {
+		case strings.HasPrefix(raw[i:], "!="):
+			op, value = influxdb.NotEqual, raw[i+2:]
+		case strings.HasPrefix(raw[i:], "=~"):
+			op, value = influxdb.RegexEqual, raw[i+2:]
+		case strings.HasPrefix(raw[i:], "!~"):
+			op, value = influxdb.NotRegexEqual, raw[i+2:]
+		case raw[i] == '!':
+			return nil, fmt.Errorf("invalid tag rule %q, must be key=value, key!=value, key=~regex or key!~regex", raw)
+		}
+
+		tagRules = append(tagRules, influxdb.TagRule{
+			Tag:      influxdb.Tag{Key: raw[:i], Value: value},
+			Operator: op,
+		})
+	}
+	return tagRules, nil
+}
+
+func newSilenceSVCs() (influxdb.SilenceService, influxdb.OrganizationService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &http.SilenceService{Client: httpClient}, &http.OrganizationService{Client: httpClient}, nil
+}
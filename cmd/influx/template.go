@@ -84,6 +84,7 @@ type cmdTemplateBuilder struct {
 		endpoints    string
 		labels       string
 		rules        string
+		silences     string
 		tasks        string
 		telegrafs    string
 		variables    string
@@ -348,6 +349,7 @@ func (b *cmdTemplateBuilder) cmdExport() *cobra.Command {
 	cmd.Flags().StringVar(&b.exportOpts.endpoints, "endpoints", "", "List of notification endpoint ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.labels, "labels", "", "List of label ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.rules, "rules", "", "List of notification rule ids comma separated")
+	cmd.Flags().StringVar(&b.exportOpts.silences, "silences", "", "List of silence ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.tasks, "tasks", "", "List of task ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.telegrafs, "telegraf-configs", "", "List of telegraf config ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.variables, "variables", "", "List of variable ids comma separated")
@@ -371,6 +373,7 @@ func (b *cmdTemplateBuilder) exportRunEFn(cmd *cobra.Command, args []string) err
 		{kind: pkger.KindLabel, idStrs: strings.Split(b.exportOpts.labels, ",")},
 		{kind: pkger.KindNotificationEndpoint, idStrs: strings.Split(b.exportOpts.endpoints, ",")},
 		{kind: pkger.KindNotificationRule, idStrs: strings.Split(b.exportOpts.rules, ",")},
+		{kind: pkger.KindSilence, idStrs: strings.Split(b.exportOpts.silences, ",")},
 		{kind: pkger.KindTask, idStrs: strings.Split(b.exportOpts.tasks, ",")},
 		{kind: pkger.KindTelegraf, idStrs: strings.Split(b.exportOpts.telegrafs, ",")},
 		{kind: pkger.KindVariable, idStrs: strings.Split(b.exportOpts.variables, ",")},
@@ -1402,6 +1405,38 @@ func (b *cmdTemplateBuilder) printTemplateDiff(diff pkger.Diff) error {
 		printer.Render()
 	}
 
+	if silences := diff.Silences; len(silences) > 0 {
+		printer := diffPrinterGen("Silences", []string{"Description", "Window", "Recurrence"})
+		appendValues := func(id pkger.SafeID, metaName string, v pkger.DiffSilenceValues) []string {
+			return []string{
+				metaName,
+				id.String(),
+				v.Name,
+				v.Description,
+				printSilenceWindow(v.Start, v.End),
+				printSilenceRecurrence(v.Recurrence),
+			}
+		}
+
+		for _, e := range silences {
+			var oldRow []string
+			if e.Old != nil {
+				oldRow = appendValues(e.ID, e.MetaName, *e.Old)
+			}
+
+			newRow := appendValues(e.ID, e.MetaName, e.New)
+			switch {
+			case pkger.IsNew(e.StateStatus):
+				printer.AppendDiff(nil, newRow)
+			case pkger.IsRemoval(e.StateStatus):
+				printer.AppendDiff(oldRow, nil)
+			default:
+				printer.AppendDiff(oldRow, newRow)
+			}
+		}
+		printer.Render()
+	}
+
 	if teles := diff.Telegrafs; len(teles) > 0 {
 		printer := diffPrinterGen("Telegraf Configurations", []string{"Description"})
 		appendValues := func(id pkger.SafeID, metaName string, v influxdb.TelegrafConfig) []string {
@@ -1619,6 +1654,21 @@ func (b *cmdTemplateBuilder) printTemplateSummary(stackID influxdb.ID, sum pkger
 		})
 	}
 
+	if silences := sum.Silences; len(silences) > 0 {
+		headers := append(commonHeaders, "Description", "Window", "Recurrence")
+		tablePrintFn("SILENCES", headers, len(silences), func(i int) []string {
+			v := silences[i]
+			return []string{
+				v.MetaName,
+				v.ID.String(),
+				v.Name,
+				v.Description,
+				printSilenceWindow(v.Start, v.End),
+				printSilenceRecurrence(v.Recurrence),
+			}
+		})
+	}
+
 	if tasks := sum.Tasks; len(tasks) > 0 {
 		headers := append(commonHeaders, "Description", "Cycle")
 		tablePrintFn("TASKS", headers, len(tasks), func(i int) []string {
@@ -1942,6 +1992,10 @@ func tablePrinter(wr io.Writer, table string, headers []string, count int, hasCo
 	fmt.Fprintln(wr)
 }
 
+func printSilenceWindow(start, end time.Time) string {
+	return start.Format(time.RFC3339) + " - " + end.Format(time.RFC3339)
+}
+
 func printVarArgs(a *influxdb.VariableArguments) string {
 	if a == nil {
 		return "<nil>"
@@ -30,6 +30,8 @@ type Engine interface {
 	storage.BucketDeleter
 	prom.PrometheusCollector
 	influxdb.BackupService
+	influxdb.RestoreService
+	influxdb.CardinalityService
 
 	SeriesCardinality() int64
 
@@ -114,6 +116,11 @@ func (t *TemporaryEngine) SeriesCardinality() int64 {
 	return t.engine.SeriesCardinality()
 }
 
+// CardinalityUsage returns the estimated cardinality of a bucket or an org.
+func (t *TemporaryEngine) CardinalityUsage(ctx context.Context, orgID, bucketID influxdb.ID) (*influxdb.CardinalityUsage, error) {
+	return t.engine.CardinalityUsage(ctx, orgID, bucketID)
+}
+
 // DeleteBucketRangePredicate will delete a bucket from the range and predicate.
 func (t *TemporaryEngine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
 	return t.engine.DeleteBucketRangePredicate(ctx, orgID, bucketID, min, max, pred)
@@ -167,8 +174,8 @@ func (t *TemporaryEngine) Flush(ctx context.Context) {
 	}
 }
 
-func (t *TemporaryEngine) CreateBackup(ctx context.Context) (int, []string, error) {
-	return t.engine.CreateBackup(ctx)
+func (t *TemporaryEngine) CreateBackup(ctx context.Context, filter influxdb.BackupFilter) (int, []string, error) {
+	return t.engine.CreateBackup(ctx, filter)
 }
 
 func (t *TemporaryEngine) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
@@ -178,3 +185,7 @@ func (t *TemporaryEngine) FetchBackupFile(ctx context.Context, backupID int, bac
 func (t *TemporaryEngine) InternalBackupPath(backupID int) string {
 	return t.engine.InternalBackupPath(backupID)
 }
+
+func (t *TemporaryEngine) RestoreBucket(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) error {
+	return t.engine.RestoreBucket(ctx, req, tsm, tombstone)
+}
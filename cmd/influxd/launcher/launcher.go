@@ -27,6 +27,7 @@ import (
 	"github.com/influxdata/influxdb/v2/endpoints"
 	"github.com/influxdata/influxdb/v2/gather"
 	"github.com/influxdata/influxdb/v2/http"
+	"github.com/influxdata/influxdb/v2/incident"
 	"github.com/influxdata/influxdb/v2/inmem"
 	"github.com/influxdata/influxdb/v2/internal/fs"
 	"github.com/influxdata/influxdb/v2/kit/cli"
@@ -42,13 +43,17 @@ import (
 	"github.com/influxdata/influxdb/v2/kv/migration/all"
 	"github.com/influxdata/influxdb/v2/label"
 	influxlogger "github.com/influxdata/influxdb/v2/logger"
+	"github.com/influxdata/influxdb/v2/monitoring"
 	"github.com/influxdata/influxdb/v2/nats"
+	"github.com/influxdata/influxdb/v2/notification/email"
 	"github.com/influxdata/influxdb/v2/pkger"
 	infprom "github.com/influxdata/influxdb/v2/prometheus"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/query/control"
 	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	"github.com/influxdata/influxdb/v2/query/promql"
 	"github.com/influxdata/influxdb/v2/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/v2/replication"
 	"github.com/influxdata/influxdb/v2/secret"
 	"github.com/influxdata/influxdb/v2/session"
 	"github.com/influxdata/influxdb/v2/snowflake"
@@ -220,6 +225,12 @@ func launcherOpts(l *Launcher) []cli.Opt {
 			Default: filepath.Join(dir, "engine"),
 			Desc:    "path to persistent engine files",
 		},
+		{
+			DestP:   &l.replicationsPath,
+			Flag:    "replications-path",
+			Default: filepath.Join(dir, "replicationq"),
+			Desc:    "path to the queues of writes replicated to remote instances",
+		},
 		{
 			DestP:   &l.secretStore,
 			Flag:    "secret-store",
@@ -360,6 +371,30 @@ func launcherOpts(l *Launcher) []cli.Opt {
 			Default: 0,
 			Desc:    "the number of page faults allowed per second in the storage engine",
 		},
+		{
+			DestP:   &l.StorageConfig.MaxSeriesPerBucket,
+			Flag:    "storage-max-series-per-bucket",
+			Default: 0,
+			Desc:    "the maximum number of series a bucket can hold; writes creating series beyond it are dropped. 0 is unlimited",
+		},
+		{
+			DestP:   &l.StorageConfig.MaxSeriesPerOrg,
+			Flag:    "storage-max-series-per-org",
+			Default: 0,
+			Desc:    "the maximum number of series the buckets of an organization can hold; writes creating series beyond it are dropped. 0 is unlimited",
+		},
+		{
+			DestP:   &l.StorageConfig.MaxValuesPerTag,
+			Flag:    "storage-max-values-per-tag",
+			Default: 0,
+			Desc:    "the maximum number of values a tag key of a bucket can have; writes creating values beyond it are dropped. 0 is unlimited",
+		},
+		{
+			DestP:   &l.StorageConfig.Engine.Compaction.StringCodec,
+			Flag:    "storage-compact-string-codec",
+			Default: "snappy",
+			Desc:    "the compression of the string blocks written by compactions; one of snappy, zstd or zstd-dictionary. Full compactions compress the blocks written with another codec again",
+		},
 		{
 			DestP: &l.featureFlags,
 			Flag:  "feature-flags",
@@ -384,10 +419,11 @@ type Launcher struct {
 	tracingType       string
 	reportingDisabled bool
 
-	httpBindAddress string
-	boltPath        string
-	enginePath      string
-	secretStore     string
+	httpBindAddress  string
+	boltPath         string
+	enginePath       string
+	replicationsPath string
+	secretStore      string
 
 	featureFlags map[string]string
 	flagger      feature.Flagger
@@ -405,6 +441,8 @@ type Launcher struct {
 	engine        Engine
 	StorageConfig storage.Config
 
+	replicationManager *replication.Manager
+
 	queryController *control.Controller
 
 	httpPort             int
@@ -506,6 +544,11 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 		m.log.Info("Failed closing query service", zap.Error(err))
 	}
 
+	m.log.Info("Stopping", zap.String("service", "replication"))
+	if err := m.replicationManager.Close(); err != nil {
+		m.log.Error("Failed to close replications", zap.Error(err))
+	}
+
 	m.log.Info("Stopping", zap.String("service", "storage-engine"))
 	if err := m.engine.Close(); err != nil {
 		m.log.Error("Failed to close engine", zap.Error(err))
@@ -728,10 +771,28 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	// The Engine's metrics must be registered after it opens.
 	m.reg.MustRegister(m.engine.PrometheusCollectors()...)
 
+	m.replicationManager = replication.NewManager(
+		replication.NewService(m.kvStore, ts.BucketService),
+		m.replicationsPath,
+		m.log.With(zap.String("service", "replication")),
+	)
+	if err := m.replicationManager.Open(ctx); err != nil {
+		m.log.Error("Failed to open replications", zap.Error(err))
+		return err
+	}
+	m.reg.MustRegister(m.replicationManager.PrometheusCollectors()...)
+
 	var (
 		deleteService platform.DeleteService = m.engine
-		pointsWriter  storage.PointsWriter   = m.engine
-		backupService platform.BackupService = m.engine
+		pointsWriter  storage.PointsWriter   = &storage.SchemaPointsWriter{
+			Underlying: &replication.PointsWriter{
+				Underlying: m.engine,
+				Manager:    m.replicationManager,
+			},
+			SchemaService: ts.BucketSchemaService,
+		}
+		backupService  platform.BackupService  = m.engine
+		restoreService platform.RestoreService = m.engine
 	)
 
 	deps, err := influxdb.NewDependencies(
@@ -755,6 +816,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		QueueSize:                       m.queueSize,
 		Logger:                          m.log.With(zap.String("service", "storage-reads")),
 		ExecutorDependencies:            []flux.Dependency{deps},
+		QuotaFinder:                     m.kvService,
 	})
 	if err != nil {
 		m.log.Error("Failed to create query controller", zap.Error(err))
@@ -918,6 +980,39 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		log.Info("Stopping")
 	}(m.log)
 
+	m.wg.Add(1)
+	go func(log *zap.Logger) {
+		defer m.wg.Done()
+		log = log.With(zap.String("service", "authorization_sweeper"))
+		sweeper := authorization.NewExpirationSweeper(log, m.kvService, authorization.DefaultSweepInterval)
+		if err := sweeper.Run(ctx); err != nil {
+			log.Error("Failed authorization sweeper service", zap.Error(err))
+		}
+		log.Info("Stopping")
+	}(m.log)
+
+	m.wg.Add(1)
+	go func(log *zap.Logger) {
+		defer m.wg.Done()
+		log = log.With(zap.String("service", "incident_tracker"))
+		tracker := incident.NewTracker(log, ts.OrganizationService, ts.BucketService, query.QueryServiceBridge{AsyncQueryService: m.queryController}, m.kvService, incident.DefaultTrackInterval)
+		if err := tracker.Run(ctx); err != nil {
+			log.Error("Failed incident tracker service", zap.Error(err))
+		}
+		log.Info("Stopping")
+	}(m.log)
+
+	m.wg.Add(1)
+	go func(log *zap.Logger) {
+		defer m.wg.Done()
+		log = log.With(zap.String("service", "email_sender"))
+		sender := email.NewSender(log, ts.OrganizationService, ts.BucketService, query.QueryServiceBridge{AsyncQueryService: m.queryController}, m.kvService, notificationEndpointStore, secretSvc, m.kvService, email.DefaultSendInterval)
+		if err := sender.Run(ctx); err != nil {
+			log.Error("Failed email sender service", zap.Error(err))
+		}
+		log.Info("Stopping")
+	}(m.log)
+
 	m.httpServer = &nethttp.Server{
 		Addr: m.httpBindAddress,
 	}
@@ -962,6 +1057,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	ts.BucketService = storage.NewBucketService(ts.BucketService, m.engine)
 	ts.BucketService = dbrp.NewBucketService(m.log, ts.BucketService, dbrpSvc)
+	ts.BucketService = replication.NewBucketService(m.log, ts.BucketService, m.replicationManager)
 
 	m.apibackend = &http.APIBackend{
 		AssetsPath:           m.assetsPath,
@@ -978,6 +1074,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		DeleteService:        deleteService,
 		BackupService:        backupService,
 		KVBackupService:      m.kvService,
+		RestoreService:       restoreService,
+		CardinalityService:   m.engine,
+		QueryQuotaService:    m.kvService,
+		RunningQueryService:  m.queryController,
 		AuthorizationService: authSvc,
 		AlgoWProxy:           &http.NoopProxyHandler{},
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
@@ -985,6 +1085,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		SessionService:                  sessionSvc,
 		UserService:                     ts.UserService,
 		DBRPService:                     dbrpSvc,
+		ReplicationService:              m.replicationManager,
 		OrganizationService:             ts.OrganizationService,
 		UserResourceMappingService:      ts.UserResourceMappingService,
 		LabelService:                    labelSvc,
@@ -1003,6 +1104,11 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		TelegrafService:                 telegrafSvc,
 		NotificationRuleStore:           notificationRuleSvc,
 		NotificationEndpointService:     endpoints.NewService(notificationEndpointStore, secretSvc, ts.UserResourceMappingService, ts.OrganizationService),
+		SilenceService:                  m.kvService,
+		IncidentService:                 m.kvService,
+		MonitoringHistoryService:        monitoring.NewHistoryService(ts.BucketService, query.QueryServiceBridge{AsyncQueryService: m.queryController}),
+		CheckDryRunService:              monitoring.NewDryRunService(fluxlang.DefaultService, query.QueryServiceBridge{AsyncQueryService: m.queryController}),
+		PromQLService:                   promql.NewService(query.QueryServiceBridge{AsyncQueryService: m.queryController}),
 		CheckService:                    checkSvc,
 		ScraperTargetStoreService:       scraperTargetSvc,
 		ChronografService:               chronografSvc,
@@ -1037,6 +1143,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			pkger.WithNotificationRuleSVC(authorizer.NewNotificationRuleStore(b.NotificationRuleStore, authedUrmSVC, authedOrgSVC)),
 			pkger.WithOrganizationService(authorizer.NewOrgService(b.OrganizationService)),
 			pkger.WithSecretSVC(authorizer.NewSecretService(b.SecretService)),
+			pkger.WithSilenceSVC(authorizer.NewSilenceService(b.SilenceService)),
 			pkger.WithTaskSVC(authorizer.NewTaskService(pkgerLogger, b.TaskService)),
 			pkger.WithTelegrafSVC(authorizer.NewTelegrafConfigService(b.TelegrafService, b.UserResourceMappingService)),
 			pkger.WithVariableSVC(authorizer.NewVariableService(b.VariableService)),
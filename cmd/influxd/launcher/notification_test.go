@@ -0,0 +1,306 @@
+package launcher_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/cmd/influxd/launcher"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+)
+
+func TestLauncher_NotificationRule_Escalation(t *testing.T) {
+	ctx := context.Background()
+	l := launcher.RunTestLauncherOrFail(t, ctx, nil)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	var (
+		mu        sync.Mutex
+		escalated []string
+	)
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path == "/oncall" {
+			var status struct {
+				Host string `json:"host"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+				t.Errorf("failed to decode escalated status: %v", err)
+			}
+			mu.Lock()
+			escalated = append(escalated, status.Host)
+			mu.Unlock()
+		}
+		w.WriteHeader(nethttp.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	monitoring, err := l.BucketService(t).FindBucketByName(ctx, l.Org.ID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The check writes a status of both hosts every minute. db1 is crit for
+	// three hours, db2 is crit twice, for 40 minutes and for two hours.
+	t0 := time.Now().UTC().Truncate(time.Hour).Add(-4 * time.Hour)
+	levels := map[string]func(time.Duration) string{
+		"db1": func(d time.Duration) string {
+			if d < 0 {
+				return "ok"
+			}
+			return "crit"
+		},
+		"db2": func(d time.Duration) string {
+			if d < 0 || (d >= 40*time.Minute && d < time.Hour) {
+				return "ok"
+			}
+			return "crit"
+		},
+	}
+	var lines []string
+	for d := -30 * time.Minute; d < 3*time.Hour; d += time.Minute {
+		ts := t0.Add(d).UnixNano()
+		for host, level := range levels {
+			lines = append(lines, fmt.Sprintf(
+				`statuses,_check_id=000000000000000a,_check_name=cpu,_level=%s,_source_measurement=cpu,_type=threshold,host=%s _message="cpu is %s",_source_timestamp=%di %d`,
+				level(d), host, level(d), ts, ts))
+		}
+	}
+	l.WriteOrFail(t, &influxdb.OnboardingResults{Org: l.Org, Bucket: monitoring, Auth: l.Auth}, strings.Join(lines, "\n"))
+
+	every, delay := mustDuration(t, "10m"), mustDuration(t, "30m")
+	r := &rule.HTTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "cpu",
+			Every:      every,
+			EndpointID: 2,
+			StatusRules: []notification.StatusRule{
+				{CurrentLevel: notification.Critical},
+			},
+			Escalation: []rule.EscalationStep{
+				{EndpointID: 3, Delay: delay},
+			},
+		},
+	}
+	oncallID, teamID := influxdb.ID(3), influxdb.ID(2)
+	r.SetEscalationEndpoints([]influxdb.NotificationEndpoint{
+		&endpoint.HTTP{
+			Base:       endpoint.Base{ID: &oncallID, Name: "oncall"},
+			URL:        srv.URL + "/oncall",
+			Method:     "POST",
+			AuthMethod: "none",
+		},
+	})
+	script, err := r.GenerateFlux(&endpoint.HTTP{
+		Base:       endpoint.Base{ID: &teamID, Name: "team"},
+		URL:        srv.URL + "/team",
+		Method:     "POST",
+		AuthMethod: "none",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run the task every 10 minutes while the statuses are written.
+	for now := t0.Add(10 * time.Minute); !now.After(t0.Add(3 * time.Hour)); now = now.Add(10 * time.Minute) {
+		req := &query.Request{
+			Authorization:  l.Auth,
+			OrganizationID: l.Org.ID,
+			Compiler: lang.FluxCompiler{
+				Query: strings.Replace(script, "option task = ", fmt.Sprintf("option now = () => %s\n\noption task = ", now.Format(time.RFC3339)), 1),
+			},
+		}
+		if err := l.QueryAndNopConsume(ctx, req); err != nil {
+			t.Fatalf("failed to run the task at %s: %v", now, err)
+		}
+	}
+
+	// Every run of crit statuses is escalated once, 30 minutes after it
+	// started.
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Strings(escalated)
+	if got, want := strings.Join(escalated, ","), "db1,db2,db2"; got != want {
+		t.Errorf("unexpected escalated hosts %q, want %q", got, want)
+	}
+}
+
+func TestLauncher_NotificationRule_BodyTemplate(t *testing.T) {
+	ctx := context.Background()
+	l := launcher.RunTestLauncherOrFail(t, ctx, nil)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	var (
+		mu     sync.Mutex
+		bodies []map[string]string
+	)
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode templated body: %v", err)
+		}
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		w.WriteHeader(nethttp.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	monitoring, err := l.BucketService(t).FindBucketByName(ctx, l.Org.ID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	ts := now.Add(-time.Minute).UnixNano()
+	l.WriteOrFail(t, &influxdb.OnboardingResults{Org: l.Org, Bucket: monitoring, Auth: l.Auth}, fmt.Sprintf(
+		`statuses,_check_id=000000000000000a,_check_name=cpu,_level=crit,_source_measurement=cpu,_type=threshold,host=db1 _message="cpu of \"db1\" is high\\",_source_timestamp=%di %d`,
+		ts, ts))
+
+	teamID := influxdb.ID(2)
+	r := &rule.HTTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "cpu",
+			Every:      mustDuration(t, "10m"),
+			EndpointID: teamID,
+			StatusRules: []notification.StatusRule{
+				{CurrentLevel: notification.Critical},
+			},
+		},
+		BodyTemplate: `{"host": "${r.host}", "message": ${json(r._message)}}`,
+	}
+	script, err := r.GenerateFlux(&endpoint.HTTP{
+		Base:       endpoint.Base{ID: &teamID, Name: "team"},
+		URL:        srv.URL,
+		Method:     "POST",
+		AuthMethod: "none",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &query.Request{
+		Authorization:  l.Auth,
+		OrganizationID: l.Org.ID,
+		Compiler: lang.FluxCompiler{
+			Query: strings.Replace(script, "option task = ", fmt.Sprintf("option now = () => %s\n\noption task = ", now.Format(time.RFC3339)), 1),
+		},
+	}
+	if err := l.QueryAndNopConsume(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	// The quotes and backslash of the message are escaped in the body.
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 1 {
+		t.Fatalf("got %d notifications, want 1", len(bodies))
+	}
+	if got, want := bodies[0]["message"], `cpu of "db1" is high\`; got != want {
+		t.Errorf("unexpected message %q, want %q", got, want)
+	}
+	if got, want := bodies[0]["host"], "db1"; got != want {
+		t.Errorf("unexpected host %q, want %q", got, want)
+	}
+}
+
+func TestLauncher_AnomalyCheck_Outlier(t *testing.T) {
+	ctx := context.Background()
+	l := launcher.RunTestLauncherOrFail(t, ctx, nil)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	// The usage alternates between 10 and 12 for an hour, its baseline is 11
+	// with a standard deviation of 1, before jumping to 30.
+	now := time.Now().UTC().Truncate(time.Minute)
+	var lines []string
+	for i := 60; i > 0; i-- {
+		v := 10 + 2*(i%2)
+		if i == 1 {
+			v = 30
+		}
+		lines = append(lines, fmt.Sprintf("cpu,host=db1 usage_user=%d %d", v, now.Add(-time.Duration(i)*time.Minute).UnixNano()))
+	}
+	l.WritePointsOrFail(t, strings.Join(lines, "\n"))
+
+	c := &check.Anomaly{
+		Base: check.Base{
+			ID:                    10,
+			Name:                  "cpu",
+			OrgID:                 l.Org.ID,
+			Every:                 mustDuration(t, "1m"),
+			StatusMessageTemplate: "cpu deviates by ${r._deviation}",
+			Query: influxdb.DashboardQuery{
+				Text: fmt.Sprintf(`from(bucket: "%s") |> range(start: -1h) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean)`, l.Bucket.Name),
+			},
+		},
+		Window: mustDuration(t, "1h"),
+		Thresholds: []check.AnomalyThreshold{
+			{Level: notification.Warn, Deviations: 2},
+			{Level: notification.Critical, Deviations: 3.5},
+		},
+	}
+	script, err := check.GenerateDryRunFlux(fluxlang.DefaultService, c, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var levels []string
+	req := &query.Request{
+		Authorization:  l.Auth,
+		OrganizationID: l.Org.ID,
+		Compiler:       lang.FluxCompiler{Query: script},
+	}
+	if err := l.QueryAndConsume(ctx, req, func(r flux.Result) error {
+		return r.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				for j, col := range cr.Cols() {
+					if col.Label != "_level" {
+						continue
+					}
+					for i := 0; i < cr.Len(); i++ {
+						levels = append(levels, cr.Strings(j).ValueString(i))
+					}
+				}
+				return nil
+			})
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Join(levels, ","), "crit"; got != want {
+		t.Errorf("unexpected levels %q, want %q", got, want)
+	}
+}
+
+func mustDuration(t *testing.T, d string) *notification.Duration {
+	t.Helper()
+	dur, err := time.ParseDuration(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nd, err := notification.FromTimeDuration(dur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &nd
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/jsonweb"
 )
 
 type contextKey string
@@ -37,6 +38,25 @@ func GetAuthorizer(ctx context.Context) (influxdb.Authorizer, error) {
 	return a, nil
 }
 
+// GetAuthorization retrieves the authorization of the authorizer on the
+// context for the organization; sessions and tokens get an ephemeral one.
+func GetAuthorization(ctx context.Context, orgID influxdb.ID) (*influxdb.Authorization, error) {
+	a, err := GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch a := a.(type) {
+	case *influxdb.Authorization:
+		return a, nil
+	case *influxdb.Session:
+		return a.EphemeralAuth(orgID), nil
+	case *jsonweb.Token:
+		return a.EphemeralAuth(orgID), nil
+	default:
+		return nil, influxdb.ErrAuthorizerNotSupported
+	}
+}
+
 // GetToken retrieves a token from the context; errors if no token.
 func GetToken(ctx context.Context) (string, error) {
 	a, ok := ctx.Value(authorizerCtxKey).(influxdb.Authorizer)
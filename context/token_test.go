@@ -23,6 +23,24 @@ func TestGetAuthorizer(t *testing.T) {
 	}
 }
 
+func TestGetAuthorization(t *testing.T) {
+	ctx := context.Background()
+	ctx = icontext.SetAuthorizer(ctx, &influxdb.Session{
+		UserID: 5678,
+		Permissions: []influxdb.Permission{
+			{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType}},
+		},
+	})
+	got, err := icontext.GetAuthorization(ctx, 1234)
+	if err != nil {
+		t.Fatalf("unexpected error while retrieving authorization: %v", err)
+	}
+
+	if got.UserID != 5678 || got.OrgID != 1234 || len(got.Permissions) != 1 {
+		t.Errorf("GetAuthorization() want an ephemeral authorization of the session, got %+v", got)
+	}
+}
+
 func TestGetToken(t *testing.T) {
 	ctx := context.Background()
 	ctx = icontext.SetAuthorizer(ctx, &influxdb.Authorization{
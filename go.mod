@@ -57,6 +57,7 @@ require (
 	github.com/jwilder/encoding v0.0.0-20170811194829-b4e1701a28ef
 	github.com/k0kubun/colorstring v0.0.0-20150214042306-9440f1994b88 // indirect
 	github.com/kevinburke/go-bindata v3.11.0+incompatible
+	github.com/klauspost/compress v1.10.10
 	github.com/lib/pq v1.2.0 // indirect
 	github.com/mattn/go-isatty v0.0.11
 	github.com/matttproud/golang_protobuf_extensions v1.0.1
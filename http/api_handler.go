@@ -14,6 +14,7 @@ import (
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
 	"github.com/influxdata/influxdb/v2/models"
 	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/replication"
 	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -59,8 +60,13 @@ type APIBackend struct {
 	DeleteService                   influxdb.DeleteService
 	BackupService                   influxdb.BackupService
 	KVBackupService                 influxdb.KVBackupService
+	RestoreService                  influxdb.RestoreService
+	CardinalityService              influxdb.CardinalityService
+	QueryQuotaService               influxdb.QueryQuotaService
+	RunningQueryService             influxdb.RunningQueryService
 	AuthorizationService            influxdb.AuthorizationService
 	DBRPService                     influxdb.DBRPMappingServiceV2
+	ReplicationService              influxdb.ReplicationService
 	BucketService                   influxdb.BucketService
 	SessionService                  influxdb.SessionService
 	UserService                     influxdb.UserService
@@ -89,6 +95,11 @@ type APIBackend struct {
 	DocumentService                 influxdb.DocumentService
 	NotificationRuleStore           influxdb.NotificationRuleStore
 	NotificationEndpointService     influxdb.NotificationEndpointService
+	SilenceService                  influxdb.SilenceService
+	IncidentService                 influxdb.IncidentService
+	MonitoringHistoryService        influxdb.MonitoringHistoryService
+	CheckDryRunService              influxdb.CheckDryRunService
+	PromQLService                   influxdb.PromQLService
 	Flagger                         feature.Flagger
 	FlagsHandler                    http.Handler
 }
@@ -133,6 +144,8 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	checkBackend.CheckService = authorizer.NewCheckService(b.CheckService,
 		b.UserResourceMappingService, b.OrganizationService)
 	h.Mount(prefixChecks, NewCheckHandler(b.Logger, checkBackend))
+	checkBackend.CheckDryRunService = authorizer.NewCheckDryRunService(b.CheckDryRunService)
+	h.Mount(prefixChecksTest, NewCheckDryRunHandler(b.Logger, checkBackend))
 
 	h.Mount(prefixChronograf, NewChronografHandler(b.ChronografService, b.HTTPErrorHandler))
 
@@ -160,6 +173,14 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 		b.UserResourceMappingService, b.OrganizationService)
 	h.Mount(prefixNotificationRules, NewNotificationRuleHandler(b.Logger, notificationRuleBackend))
 
+	silenceBackend := NewSilenceBackend(b.Logger.With(zap.String("handler", "silence")), b)
+	silenceBackend.SilenceService = authorizer.NewSilenceService(b.SilenceService)
+	h.Mount(prefixSilences, NewSilenceHandler(silenceBackend.log, silenceBackend))
+
+	incidentBackend := NewIncidentBackend(b.Logger.With(zap.String("handler", "incident")), b)
+	incidentBackend.IncidentService = authorizer.NewIncidentService(b.IncidentService)
+	h.Mount(prefixIncidents, NewIncidentHandler(incidentBackend.log, incidentBackend))
+
 	scraperBackend := NewScraperBackend(b.Logger.With(zap.String("handler", "scraper")), b)
 	scraperBackend.ScraperStorageService = authorizer.NewScraperTargetStoreService(b.ScraperTargetStoreService,
 		b.UserResourceMappingService,
@@ -194,8 +215,26 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	backupBackend.BackupService = authorizer.NewBackupService(backupBackend.BackupService)
 	h.Mount(prefixBackup, NewBackupHandler(backupBackend))
 
+	restoreBackend := NewRestoreBackend(b)
+	restoreBackend.RestoreService = authorizer.NewRestoreService(restoreBackend.RestoreService)
+	h.Mount(prefixRestore, NewRestoreHandler(restoreBackend))
+
+	cardinalityBackend := NewCardinalityBackend(b)
+	cardinalityBackend.CardinalityService = authorizer.NewCardinalityService(cardinalityBackend.CardinalityService)
+	h.Mount(prefixCardinality, NewCardinalityHandler(cardinalityBackend))
+
+	queryQuotaBackend := NewQueryQuotaBackend(b)
+	queryQuotaBackend.QueryQuotaService = authorizer.NewQueryQuotaService(queryQuotaBackend.QueryQuotaService)
+	h.Mount(prefixQueryQuotas, NewQueryQuotaHandler(queryQuotaBackend))
+
+	runningQueryBackend := NewRunningQueryBackend(b.Logger.With(zap.String("handler", "running_query")), b)
+	runningQueryBackend.RunningQueryService = authorizer.NewRunningQueryService(b.RunningQueryService)
+	h.Mount(prefixRunningQueries, NewRunningQueryHandler(runningQueryBackend.log, runningQueryBackend))
+
 	h.Mount(dbrp.PrefixDBRP, dbrp.NewHTTPHandler(b.Logger, b.DBRPService, b.OrganizationService))
 
+	h.Mount(replication.PrefixReplications, replication.NewHTTPHandler(b.Logger, replication.NewAuthorizedService(b.ReplicationService)))
+
 	writeBackend := NewWriteBackend(b.Logger.With(zap.String("handler", "write")), b)
 	h.Mount(prefixWrite, NewWriteHandler(b.Logger, writeBackend,
 		WithMaxBatchSizeBytes(b.MaxBatchSizeBytes),
@@ -206,6 +245,23 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 		),
 	))
 
+	legacyWriteBackend := NewWriteBackend(b.Logger.With(zap.String("handler", "legacy_write")), b)
+	h.Mount(prefixLegacyWrite, NewLegacyWriteHandler(b.Logger, legacyWriteBackend,
+		WithMaxBatchSizeBytes(b.MaxBatchSizeBytes),
+		WithParserOptions(
+			models.WithParserMaxBytes(b.WriteParserMaxBytes),
+			models.WithParserMaxLines(b.WriteParserMaxLines),
+			models.WithParserMaxValues(b.WriteParserMaxValues),
+		),
+	))
+
+	legacyQueryBackend := NewLegacyQueryBackend(b.Logger.With(zap.String("handler", "legacy_query")), b)
+	h.Mount(prefixLegacyQuery, NewLegacyQueryHandler(b.Logger, legacyQueryBackend))
+
+	prometheusBackend := NewPrometheusBackend(b.Logger.With(zap.String("handler", "prometheus")), b)
+	prometheusBackend.PromQLService = authorizer.NewPromQLService(b.PromQLService)
+	h.Mount(prefixPrometheus, NewPrometheusHandler(b.Logger, prometheusBackend))
+
 	for _, o := range opts {
 		o(h)
 	}
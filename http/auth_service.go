@@ -88,6 +88,7 @@ type authResponse struct {
 	Links       map[string]string    `json:"links"`
 	CreatedAt   time.Time            `json:"createdAt"`
 	UpdatedAt   time.Time            `json:"updatedAt"`
+	ExpiresAt   *time.Time           `json:"expiresAt,omitempty"`
 }
 
 func newAuthResponse(a *influxdb.Authorization, org *influxdb.Organization, user *influxdb.User, ps []permissionResponse) *authResponse {
@@ -107,6 +108,7 @@ func newAuthResponse(a *influxdb.Authorization, org *influxdb.Organization, user
 		},
 		CreatedAt: a.CreatedAt,
 		UpdatedAt: a.UpdatedAt,
+		ExpiresAt: a.ExpiresAt,
 	}
 	return res
 }
@@ -119,6 +121,7 @@ func (a *authResponse) toPlatform() *influxdb.Authorization {
 		Description: a.Description,
 		OrgID:       a.OrgID,
 		UserID:      a.UserID,
+		ExpiresAt:   a.ExpiresAt,
 		CRUDLog: influxdb.CRUDLog{
 			CreatedAt: a.CreatedAt,
 			UpdatedAt: a.UpdatedAt,
@@ -245,6 +248,7 @@ type postAuthorizationRequest struct {
 	UserID      *influxdb.ID          `json:"userID,omitempty"`
 	Description string                `json:"description"`
 	Permissions []influxdb.Permission `json:"permissions"`
+	ExpiresAt   *time.Time            `json:"expiresAt,omitempty"`
 }
 
 func (p *postAuthorizationRequest) toPlatform(userID influxdb.ID) *influxdb.Authorization {
@@ -254,6 +258,7 @@ func (p *postAuthorizationRequest) toPlatform(userID influxdb.ID) *influxdb.Auth
 		Description: p.Description,
 		Permissions: p.Permissions,
 		UserID:      userID,
+		ExpiresAt:   p.ExpiresAt,
 	}
 }
 
@@ -263,6 +268,7 @@ func newPostAuthorizationRequest(a *influxdb.Authorization) (*postAuthorizationR
 		Description: a.Description,
 		Permissions: a.Permissions,
 		Status:      a.Status,
+		ExpiresAt:   a.ExpiresAt,
 	}
 
 	if a.UserID.Valid() {
@@ -313,6 +319,13 @@ func (p *postAuthorizationRequest) Validate() error {
 		return err
 	}
 
+	if p.ExpiresAt != nil && !p.ExpiresAt.After(time.Now()) {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "expiresAt must be in the future",
+		}
+	}
+
 	return nil
 }
 
@@ -30,6 +30,10 @@ type AuthenticationHandler struct {
 	// handler used to register routes does not matter.
 	noAuthRouter *httprouter.Router
 
+	// legacyAuthRouter holds the routes that also accept influxdb 1.x
+	// style credentials. It is only used for its lookup method.
+	legacyAuthRouter *httprouter.Router
+
 	Handler http.Handler
 }
 
@@ -41,6 +45,7 @@ func NewAuthenticationHandler(log *zap.Logger, h platform.HTTPErrorHandler) *Aut
 		Handler:          http.DefaultServeMux,
 		TokenParser:      jsonweb.NewTokenParser(jsonweb.EmptyKeyStore),
 		noAuthRouter:     httprouter.New(),
+		legacyAuthRouter: httprouter.New(),
 	}
 }
 
@@ -50,6 +55,19 @@ func (h *AuthenticationHandler) RegisterNoAuthRoute(method, path string) {
 	h.noAuthRouter.HandlerFunc(method, path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 }
 
+// RegisterLegacyAuthRoute allows routes to be authenticated with influxdb 1.x
+// style credentials (basic auth or the u and p query parameters) in addition
+// to the token and session schemes.
+func (h *AuthenticationHandler) RegisterLegacyAuthRoute(method, path string) {
+	// the handler specified here does not matter.
+	h.legacyAuthRouter.HandlerFunc(method, path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+}
+
+func (h *AuthenticationHandler) isLegacyAuthRoute(r *http.Request) bool {
+	handler, _, _ := h.legacyAuthRouter.Lookup(r.Method, r.URL.Path)
+	return handler != nil
+}
+
 const (
 	tokenAuthScheme   = "token"
 	sessionAuthScheme = "session"
@@ -85,6 +103,11 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 
 	ctx := r.Context()
 	scheme, err := ProbeAuthScheme(r)
+	if h.isLegacyAuthRoute(r) {
+		if _, lerr := GetLegacyToken(r); lerr == nil {
+			scheme, err = tokenAuthScheme, nil
+		}
+	}
 	if err != nil {
 		h.unauthorized(ctx, w, err)
 		return
@@ -139,7 +162,12 @@ func (h *AuthenticationHandler) isUserActive(ctx context.Context, auth platform.
 }
 
 func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *http.Request) (platform.Authorizer, error) {
-	t, err := GetToken(r)
+	getToken := GetToken
+	if h.isLegacyAuthRoute(r) {
+		getToken = GetLegacyToken
+	}
+
+	t, err := getToken(r)
 	if err != nil {
 		return nil, err
 	}
@@ -157,7 +185,17 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return nil, err
 	}
 
-	return h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	a, err := h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	// the authorization service may not enforce expiry itself
+	if err := a.Expired(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
 }
 
 func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Request) (*platform.Session, error) {
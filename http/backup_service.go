@@ -92,7 +92,14 @@ func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	id, files, err := h.BackupService.CreateBackup(ctx)
+	filter, err := decodeBackupFilter(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	createdAt := time.Now().UTC()
+	id, files, err := h.BackupService.CreateBackup(ctx, filter)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
@@ -127,6 +134,23 @@ func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 		files = append(files, fs.DefaultConfigsFile)
 	}
 
+	manifest := influxdb.BackupManifest{
+		ID:        id,
+		CreatedAt: createdAt,
+		OrgID:     filter.OrgID,
+		BucketID:  filter.BucketID,
+		Files:     files,
+	}
+	if !filter.Since.IsZero() {
+		manifest.Since = &filter.Since
+	}
+	if err := writeBackupManifest(internalBackupPath, &manifest); err != nil {
+		err = multierr.Append(err, os.RemoveAll(internalBackupPath))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	files = append(files, influxdb.BackupManifestFilename)
+
 	b := backup{
 		ID:    id,
 		Files: files,
@@ -138,6 +162,55 @@ func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// decodeBackupFilter extracts the orgID, bucketID and since parameters of a
+// backup request.
+func decodeBackupFilter(r *http.Request) (influxdb.BackupFilter, error) {
+	var filter influxdb.BackupFilter
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		filter.OrgID = id
+	}
+	if bucketID := qp.Get("bucketID"); bucketID != "" {
+		id, err := influxdb.IDFromString(bucketID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid bucketID",
+				Err:  err,
+			}
+		}
+		filter.BucketID = id
+	}
+	if since := qp.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339Nano, since)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid since time; must be in RFC3339 format",
+				Err:  err,
+			}
+		}
+		filter.Since = t
+	}
+	return filter, filter.Validate()
+}
+
+func writeBackupManifest(internalBackupPath string, manifest *influxdb.BackupManifest) error {
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(internalBackupPath, influxdb.BackupManifestFilename), b, 0600)
+}
+
 func (h *BackupHandler) backupCredentials(internalBackupPath string) (bool, error) {
 	credBackupPath := filepath.Join(internalBackupPath, fs.DefaultConfigsFile)
 
@@ -185,7 +258,7 @@ type BackupService struct {
 	InsecureSkipVerify bool
 }
 
-func (s *BackupService) CreateBackup(ctx context.Context) (int, []string, error) {
+func (s *BackupService) CreateBackup(ctx context.Context, filter influxdb.BackupFilter) (int, []string, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -198,6 +271,18 @@ func (s *BackupService) CreateBackup(ctx context.Context) (int, []string, error)
 	if err != nil {
 		return 0, nil, err
 	}
+
+	params := req.URL.Query()
+	if filter.OrgID != nil {
+		params.Set("orgID", filter.OrgID.String())
+	}
+	if filter.BucketID != nil {
+		params.Set("bucketID", filter.BucketID.String())
+	}
+	if !filter.Since.IsZero() {
+		params.Set("since", filter.Since.UTC().Format(time.RFC3339Nano))
+	}
+	req.URL.RawQuery = params.Encode()
 	SetToken(s.Token, req)
 	req = req.WithContext(ctx)
 
@@ -137,13 +137,15 @@ type bucket struct {
 	Name                string          `json:"name"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+	RetentionTiers      []retentionTier `json:"retentionTiers,omitempty"`
 	influxdb.CRUDLog
 }
 
 // retentionRule is the retention rule action for a bucket.
 type retentionRule struct {
-	Type         string `json:"type"`
-	EverySeconds int64  `json:"everySeconds"`
+	Type                      string `json:"type"`
+	EverySeconds              int64  `json:"everySeconds"`
+	ShardGroupDurationSeconds *int64 `json:"shardGroupDurationSeconds,omitempty"`
 }
 
 func (rr *retentionRule) RetentionPeriod() (time.Duration, error) {
@@ -158,12 +160,66 @@ func (rr *retentionRule) RetentionPeriod() (time.Duration, error) {
 	return t, nil
 }
 
+// ShardGroupDuration returns the duration of the storage time partitions of
+// the rule, 0 when it is not set.
+func (rr *retentionRule) ShardGroupDuration() time.Duration {
+	if rr.ShardGroupDurationSeconds == nil {
+		return 0
+	}
+	return time.Duration(*rr.ShardGroupDurationSeconds) * time.Second
+}
+
+func shardGroupDurationSeconds(d time.Duration) *int64 {
+	s := int64(d.Round(time.Second) / time.Second)
+	return &s
+}
+
+// retentionTier is a downsampled retention tier of a bucket.
+type retentionTier struct {
+	WindowSeconds int64  `json:"windowSeconds"`
+	Aggregate     string `json:"aggregate"`
+	EverySeconds  int64  `json:"everySeconds"`
+}
+
+func newRetentionTiers(tiers []influxdb.RetentionTier) []retentionTier {
+	if len(tiers) == 0 {
+		return nil
+	}
+
+	rts := make([]retentionTier, 0, len(tiers))
+	for _, t := range tiers {
+		rts = append(rts, retentionTier{
+			WindowSeconds: int64(t.Window.Round(time.Second) / time.Second),
+			Aggregate:     t.Aggregate,
+			EverySeconds:  int64(t.RetentionPeriod.Round(time.Second) / time.Second),
+		})
+	}
+	return rts
+}
+
+func retentionTiersToInfluxDB(rts []retentionTier) []influxdb.RetentionTier {
+	if len(rts) == 0 {
+		return nil
+	}
+
+	tiers := make([]influxdb.RetentionTier, 0, len(rts))
+	for _, rt := range rts {
+		tiers = append(tiers, influxdb.RetentionTier{
+			Window:          time.Duration(rt.WindowSeconds) * time.Second,
+			Aggregate:       rt.Aggregate,
+			RetentionPeriod: time.Duration(rt.EverySeconds) * time.Second,
+		})
+	}
+	return tiers
+}
+
 func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 	if b == nil {
 		return nil, nil
 	}
 
 	var d time.Duration // zero value implies infinite retention policy
+	var sgd time.Duration
 
 	// Only support a single retention period for the moment
 	if len(b.RetentionRules) > 0 {
@@ -174,6 +230,7 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 				Msg:  "expiration seconds must be greater than or equal to one second",
 			}
 		}
+		sgd = b.RetentionRules[0].ShardGroupDuration()
 	}
 
 	return &influxdb.Bucket{
@@ -184,6 +241,8 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		ShardGroupDuration:  sgd,
+		RetentionTiers:      retentionTiersToInfluxDB(b.RetentionTiers),
 		CRUDLog:             b.CRUDLog,
 	}, nil
 }
@@ -196,10 +255,14 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 	rules := []retentionRule{}
 	rp := int64(pb.RetentionPeriod.Round(time.Second) / time.Second)
 	if rp > 0 {
-		rules = append(rules, retentionRule{
+		rule := retentionRule{
 			Type:         "expire",
 			EverySeconds: rp,
-		})
+		}
+		if pb.ShardGroupDuration > 0 {
+			rule.ShardGroupDurationSeconds = shardGroupDurationSeconds(pb.ShardGroupDuration)
+		}
+		rules = append(rules, rule)
 	}
 
 	return &bucket{
@@ -210,15 +273,17 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		Description:         pb.Description,
 		RetentionPolicyName: pb.RetentionPolicyName,
 		RetentionRules:      rules,
+		RetentionTiers:      newRetentionTiers(pb.RetentionTiers),
 		CRUDLog:             pb.CRUDLog,
 	}
 }
 
 // bucketUpdate is used for serialization/deserialization with retention rules.
 type bucketUpdate struct {
-	Name           *string         `json:"name,omitempty"`
-	Description    *string         `json:"description,omitempty"`
-	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+	Name           *string          `json:"name,omitempty"`
+	Description    *string          `json:"description,omitempty"`
+	RetentionRules []retentionRule  `json:"retentionRules,omitempty"`
+	RetentionTiers *[]retentionTier `json:"retentionTiers,omitempty"`
 }
 
 func (b *bucketUpdate) OK() error {
@@ -242,11 +307,21 @@ func (b *bucketUpdate) toInfluxDB() *influxdb.BucketUpdate {
 		d, _ = b.RetentionRules[0].RetentionPeriod()
 	}
 
-	return &influxdb.BucketUpdate{
+	upd := &influxdb.BucketUpdate{
 		Name:            b.Name,
 		Description:     b.Description,
 		RetentionPeriod: &d,
 	}
+	// An explicit shard group duration of 0 disables partitioning.
+	if len(b.RetentionRules) > 0 && b.RetentionRules[0].ShardGroupDurationSeconds != nil {
+		sgd := b.RetentionRules[0].ShardGroupDuration()
+		upd.ShardGroupDuration = &sgd
+	}
+	if b.RetentionTiers != nil {
+		tiers := retentionTiersToInfluxDB(*b.RetentionTiers)
+		upd.RetentionTiers = &tiers
+	}
+	return upd
 }
 
 func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
@@ -262,10 +337,22 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 
 	if pb.RetentionPeriod != nil {
 		d := int64((*pb.RetentionPeriod).Round(time.Second) / time.Second)
-		up.RetentionRules = append(up.RetentionRules, retentionRule{
+		rule := retentionRule{
 			Type:         "expire",
 			EverySeconds: d,
-		})
+		}
+		if pb.ShardGroupDuration != nil {
+			rule.ShardGroupDurationSeconds = shardGroupDurationSeconds(*pb.ShardGroupDuration)
+		}
+		up.RetentionRules = append(up.RetentionRules, rule)
+	}
+
+	if pb.RetentionTiers != nil {
+		tiers := newRetentionTiers(*pb.RetentionTiers)
+		if tiers == nil {
+			tiers = []retentionTier{}
+		}
+		up.RetentionTiers = &tiers
 	}
 	return up
 }
@@ -339,6 +426,7 @@ type postBucketRequest struct {
 	Description         string          `json:"description"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+	RetentionTiers      []retentionTier `json:"retentionTiers,omitempty"`
 }
 
 func (b *postBucketRequest) OK() error {
@@ -369,9 +457,10 @@ func (b *postBucketRequest) OK() error {
 
 func (b postBucketRequest) toInfluxDB() *influxdb.Bucket {
 	// Only support a single retention period for the moment
-	var dur time.Duration
+	var dur, sgd time.Duration
 	if len(b.RetentionRules) > 0 {
 		dur, _ = b.RetentionRules[0].RetentionPeriod()
+		sgd = b.RetentionRules[0].ShardGroupDuration()
 	}
 
 	return &influxdb.Bucket{
@@ -381,6 +470,8 @@ func (b postBucketRequest) toInfluxDB() *influxdb.Bucket {
 		Type:                influxdb.BucketTypeUser,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     dur,
+		ShardGroupDuration:  sgd,
+		RetentionTiers:      retentionTiersToInfluxDB(b.RetentionTiers),
 	}
 }
 
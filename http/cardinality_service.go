@@ -0,0 +1,104 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"go.uber.org/zap"
+)
+
+// CardinalityBackend is all services and associated parameters required to construct the CardinalityHandler.
+type CardinalityBackend struct {
+	Logger *zap.Logger
+	influxdb.HTTPErrorHandler
+
+	CardinalityService influxdb.CardinalityService
+}
+
+// NewCardinalityBackend returns a new instance of CardinalityBackend.
+func NewCardinalityBackend(b *APIBackend) *CardinalityBackend {
+	return &CardinalityBackend{
+		Logger: b.Logger.With(zap.String("handler", "cardinality")),
+
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		CardinalityService: b.CardinalityService,
+	}
+}
+
+// CardinalityHandler is http handler for cardinality service.
+type CardinalityHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	CardinalityService influxdb.CardinalityService
+}
+
+const prefixCardinality = "/api/v2/cardinality"
+
+// NewCardinalityHandler creates a new handler at /api/v2/cardinality to report
+// the series cardinality of buckets and organizations.
+func NewCardinalityHandler(b *CardinalityBackend) *CardinalityHandler {
+	h := &CardinalityHandler{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		Router:             NewRouter(b.HTTPErrorHandler),
+		Logger:             b.Logger,
+		CardinalityService: b.CardinalityService,
+	}
+
+	h.HandlerFunc(http.MethodGet, prefixCardinality, h.handleGetCardinality)
+
+	return h
+}
+
+// handleGetCardinality returns the cardinality of the bucket identified by the
+// bucketID parameter, or of the organization identified by the orgID
+// parameter when no bucket is given.
+func (h *CardinalityHandler) handleGetCardinality(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "CardinalityHandler.handleGetCardinality")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	orgID, bucketID, err := decodeCardinalityRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	usage, err := h.CardinalityService.CardinalityUsage(ctx, orgID, bucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, usage); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// decodeCardinalityRequest extracts the required orgID and the optional
+// bucketID parameters of a cardinality request.
+func decodeCardinalityRequest(r *http.Request) (orgID, bucketID influxdb.ID, err error) {
+	qp := r.URL.Query()
+	if err := orgID.DecodeFromString(qp.Get("orgID")); err != nil {
+		return 0, 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid orgID",
+			Err:  err,
+		}
+	}
+	if v := qp.Get("bucketID"); v != "" {
+		if err := bucketID.DecodeFromString(v); err != nil {
+			return 0, 0, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid bucketID",
+				Err:  err,
+			}
+		}
+	}
+	return orgID, bucketID, nil
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxtesting "github.com/influxdata/influxdb/v2/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestCardinalityHandler_handleGetCardinality(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		code  int
+		want  string
+	}{
+		{
+			name:  "bucket cardinality",
+			query: "orgID=020f755c3c082000&bucketID=020f755c3c082001",
+			code:  http.StatusOK,
+			want:  `{"orgID":"020f755c3c082000","bucketID":"020f755c3c082001","series":2,"maxSeries":100,"tagValues":{"host":2},"maxValuesPerTag":10}`,
+		},
+		{
+			name:  "org cardinality",
+			query: "orgID=020f755c3c082000",
+			code:  http.StatusOK,
+			want:  `{"orgID":"020f755c3c082000","series":2,"maxSeries":100,"maxValuesPerTag":10}`,
+		},
+		{
+			name: "missing org",
+			code: http.StatusBadRequest,
+			want: `{"code":"invalid","message":"invalid orgID: id must have a length of 16 bytes"}`,
+		},
+		{
+			name:  "invalid bucket id",
+			query: "orgID=020f755c3c082000&bucketID=bad",
+			code:  http.StatusBadRequest,
+			want:  `{"code":"invalid","message":"invalid bucketID: id must have a length of 16 bytes"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewCardinalityService()
+			svc.CardinalityUsageFn = func(ctx context.Context, orgID, bucketID influxdb.ID) (*influxdb.CardinalityUsage, error) {
+				if orgID != influxtesting.MustIDBase16("020f755c3c082000") {
+					t.Errorf("unexpected org ID %s", orgID)
+				}
+				u := &influxdb.CardinalityUsage{
+					OrgID:           orgID,
+					BucketID:        bucketID,
+					Series:          2,
+					MaxSeries:       100,
+					MaxValuesPerTag: 10,
+				}
+				if bucketID.Valid() {
+					u.TagValues = map[string]uint64{"host": 2}
+				}
+				return u, nil
+			}
+
+			handler := NewCardinalityHandler(&CardinalityBackend{
+				Logger:             zaptest.NewLogger(t),
+				HTTPErrorHandler:   DefaultErrorHandler,
+				CardinalityService: svc,
+			})
+
+			r := httptest.NewRequest(http.MethodGet, prefixCardinality+"?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.code {
+				t.Errorf("got status %d, want %d", res.StatusCode, tt.code)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.want); err != nil || !eq {
+				t.Errorf("unexpected body: %v %v\n%s", err, diff, body)
+			}
+		})
+	}
+}
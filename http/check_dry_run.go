@@ -0,0 +1,140 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"go.uber.org/zap"
+)
+
+// prefixChecksTest is mounted apart from the check handler, whose router can
+// not route a static path next to the check IDs.
+const prefixChecksTest = "/api/v2/checks/test"
+
+var _ influxdb.CheckDryRunService = (*CheckService)(nil)
+
+// CheckDryRunHandler is the handler running unsaved checks against past data.
+type CheckDryRunHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	CheckDryRunService influxdb.CheckDryRunService
+}
+
+// NewCheckDryRunHandler returns a new instance of CheckDryRunHandler.
+func NewCheckDryRunHandler(log *zap.Logger, b *CheckBackend) *CheckDryRunHandler {
+	h := &CheckDryRunHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		CheckDryRunService: b.CheckDryRunService,
+	}
+
+	h.HandlerFunc("POST", prefixChecksTest, h.handlePostCheckTest)
+
+	return h
+}
+
+type checkTestRequest struct {
+	Check json.RawMessage `json:"check"`
+	Start time.Time       `json:"start"`
+	Stop  time.Time       `json:"stop"`
+}
+
+type checkTestResponse struct {
+	Statuses []*influxdb.CheckStatus `json:"statuses"`
+}
+
+func decodePostCheckTestRequest(r *http.Request) (influxdb.Check, time.Time, time.Time, error) {
+	var req checkTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, time.Time{}, time.Time{}, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode the check test request",
+			Err:  err,
+		}
+	}
+	if len(req.Check) == 0 {
+		return nil, time.Time{}, time.Time{}, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "check is required",
+		}
+	}
+
+	chk, err := check.UnmarshalJSON(req.Check)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	stop := req.Stop
+	if stop.IsZero() {
+		stop = time.Now().UTC()
+	}
+	start := req.Start
+	if start.IsZero() {
+		start = stop.Add(-influxdb.DefaultMonitoringLookback)
+	}
+	return chk, start, stop, nil
+}
+
+// handlePostCheckTest runs the check of the request between its start and
+// stop, by default over the last day, and returns the statuses it would have
+// written.
+func (h *CheckDryRunHandler) handlePostCheckTest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	chk, start, stop, err := decodePostCheckTestRequest(r)
+	if err != nil {
+		h.log.Debug("Failed to decode request", zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	statuses, err := h.CheckDryRunService.DryRunCheck(ctx, chk, start, stop)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if statuses == nil {
+		statuses = []*influxdb.CheckStatus{}
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, checkTestResponse{Statuses: statuses}); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// DryRunCheck runs the unsaved check between start and stop and returns the
+// statuses it would have written.
+func (s *CheckService) DryRunCheck(ctx context.Context, c influxdb.Check, start, stop time.Time) ([]*influxdb.CheckStatus, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	b, err := c.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp checkTestResponse
+	err = s.Client.
+		PostJSON(checkTestRequest{Check: b, Start: start, Stop: stop}, prefixChecksTest).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Statuses, nil
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestCheckDryRunHandler(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		body string
+		code int
+		want string
+	}{
+		{
+			name: "threshold check",
+			body: `{
+				"check": {
+					"type": "threshold",
+					"name": "cpu",
+					"orgID": "020f755c3c082000",
+					"every": "1h",
+					"query": {"text": "from(bucket: \"telegraf\") |> range(start: -1h)"}
+				},
+				"start": "2020-01-01T21:00:00Z",
+				"stop": "2020-01-01T22:00:00Z"
+			}`,
+			code: http.StatusOK,
+			want: `{
+				"statuses": [{
+					"checkName": "cpu",
+					"level": "crit",
+					"message": "high cpu",
+					"time": "2020-01-01T22:00:00Z",
+					"tags": [{"key": "host", "value": "db1"}]
+				}]
+			}`,
+		},
+		{
+			name: "missing check",
+			body: `{"start": "2020-01-01T21:00:00Z"}`,
+			code: http.StatusBadRequest,
+			want: `{"code": "invalid", "message": "check is required"}`,
+		},
+		{
+			name: "invalid check type",
+			body: `{"check": {"type": "unknown"}}`,
+			code: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dryRun := mock.NewCheckDryRunService()
+			dryRun.DryRunCheckFn = func(ctx context.Context, c influxdb.Check, start, stop time.Time) ([]*influxdb.CheckStatus, error) {
+				if _, ok := c.(*check.Threshold); !ok || c.GetName() != "cpu" {
+					t.Errorf("unexpected check %+v", c)
+				}
+				if !start.Equal(t0.Add(-time.Hour)) || !stop.Equal(t0) {
+					t.Errorf("unexpected time range %s - %s", start, stop)
+				}
+				return []*influxdb.CheckStatus{{
+					CheckName: c.GetName(),
+					Level:     "crit",
+					Message:   "high cpu",
+					Time:      t0,
+					Tags:      []influxdb.Tag{{Key: "host", Value: "db1"}},
+				}}, nil
+			}
+
+			checkBackend := NewMockCheckBackend(t)
+			checkBackend.HTTPErrorHandler = DefaultErrorHandler
+			checkBackend.CheckDryRunService = dryRun
+			h := NewCheckDryRunHandler(zaptest.NewLogger(t), checkBackend)
+
+			r := httptest.NewRequest(http.MethodPost, prefixChecksTest, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.code {
+				t.Errorf("got status %d, want %d: %s", res.StatusCode, tt.code, body)
+			}
+			if tt.want == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.want); err != nil || !eq {
+				t.Errorf("unexpected body: %v %v\n%s", err, diff, body)
+			}
+		})
+	}
+}
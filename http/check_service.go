@@ -32,6 +32,8 @@ type CheckBackend struct {
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
 	FluxLanguageService        influxdb.FluxLanguageService
+	MonitoringHistoryService   influxdb.MonitoringHistoryService
+	CheckDryRunService         influxdb.CheckDryRunService
 }
 
 // NewCheckBackend returns a new instance of CheckBackend.
@@ -47,6 +49,8 @@ func NewCheckBackend(log *zap.Logger, b *APIBackend) *CheckBackend {
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
 		FluxLanguageService:        b.FluxLanguageService,
+		MonitoringHistoryService:   b.MonitoringHistoryService,
+		CheckDryRunService:         b.CheckDryRunService,
 	}
 }
 
@@ -63,12 +67,14 @@ type CheckHandler struct {
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
 	FluxLanguageService        influxdb.FluxLanguageService
+	MonitoringHistoryService   influxdb.MonitoringHistoryService
 }
 
 const (
 	prefixChecks          = "/api/v2/checks"
 	checksIDPath          = "/api/v2/checks/:id"
 	checksIDQueryPath     = "/api/v2/checks/:id/query"
+	checksIDStatusesPath  = "/api/v2/checks/:id/statuses"
 	checksIDMembersPath   = "/api/v2/checks/:id/members"
 	checksIDMembersIDPath = "/api/v2/checks/:id/members/:userID"
 	checksIDOwnersPath    = "/api/v2/checks/:id/owners"
@@ -91,12 +97,14 @@ func NewCheckHandler(log *zap.Logger, b *CheckBackend) *CheckHandler {
 		TaskService:                b.TaskService,
 		OrganizationService:        b.OrganizationService,
 		FluxLanguageService:        b.FluxLanguageService,
+		MonitoringHistoryService:   b.MonitoringHistoryService,
 	}
 
 	h.Handler("POST", prefixChecks, withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.handlePostCheck)))
 	h.HandlerFunc("GET", prefixChecks, h.handleGetChecks)
 	h.HandlerFunc("GET", checksIDPath, h.handleGetCheck)
 	h.HandlerFunc("GET", checksIDQueryPath, h.handleGetCheckQuery)
+	h.HandlerFunc("GET", checksIDStatusesPath, h.handleGetCheckStatuses)
 	h.HandlerFunc("DELETE", checksIDPath, h.handleDeleteCheck)
 	h.Handler("PUT", checksIDPath, withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.handlePutCheck)))
 	h.Handler("PATCH", checksIDPath, withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.handlePatchCheck)))
@@ -315,6 +323,37 @@ func (h *CheckHandler) handleGetCheckQuery(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// handleGetCheckStatuses lists the statuses written by the check into the
+// _monitoring bucket of its organization.
+func (h *CheckHandler) handleGetCheckStatuses(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeGetCheckRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	filter, opts, err := decodeMonitoringHistoryRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	chk, err := h.CheckService.FindCheckByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	statuses, _, err := h.MonitoringHistoryService.FindCheckStatuses(ctx, chk.GetOrgID(), id, filter, *opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	basePath := path.Join(prefixChecks, id.String(), "statuses")
+	if err := encodeResponse(ctx, w, http.StatusOK, newCheckStatusesResponse(basePath, statuses, filter, *opts)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
 type fluxResp struct {
 	Flux string `json:"flux"`
 }
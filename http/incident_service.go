@@ -0,0 +1,337 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	pctx "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const prefixIncidents = "/api/v2/incidents"
+
+// IncidentBackend is all services and associated parameters required to
+// construct the IncidentHandler.
+type IncidentBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	IncidentService     influxdb.IncidentService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewIncidentBackend creates a backend used by the incident handler.
+func NewIncidentBackend(log *zap.Logger, b *APIBackend) *IncidentBackend {
+	return &IncidentBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		log:                 log,
+		IncidentService:     b.IncidentService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// IncidentHandler is the handler for the incident service
+type IncidentHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	IncidentService     influxdb.IncidentService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewIncidentHandler creates a new handler at /api/v2/incidents to list the
+// incidents of the checks, and to acknowledge or resolve them.
+func NewIncidentHandler(log *zap.Logger, b *IncidentBackend) *IncidentHandler {
+	h := &IncidentHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		IncidentService:     b.IncidentService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixIncidents)
+
+	h.HandlerFunc("GET", prefixIncidents, h.handleGetIncidents)
+	h.HandlerFunc("GET", entityPath, h.handleGetIncident)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchIncident)
+
+	return h
+}
+
+type incidentLinks struct {
+	Self  string `json:"self"`
+	Org   string `json:"org"`
+	Check string `json:"check"`
+}
+
+type incidentResponse struct {
+	*influxdb.Incident
+	Links incidentLinks `json:"links"`
+}
+
+func newIncidentResponse(i *influxdb.Incident) incidentResponse {
+	return incidentResponse{
+		Incident: i,
+		Links: incidentLinks{
+			Self:  fmt.Sprintf("%s/%s", prefixIncidents, i.ID),
+			Org:   fmt.Sprintf("/api/v2/orgs/%s", i.OrgID),
+			Check: fmt.Sprintf("/api/v2/checks/%s", i.CheckID),
+		},
+	}
+}
+
+type getIncidentsResponse struct {
+	Incidents []incidentResponse `json:"incidents"`
+	Links     struct {
+		Self string `json:"self"`
+	} `json:"links"`
+}
+
+func newGetIncidentsResponse(is []*influxdb.Incident) getIncidentsResponse {
+	res := getIncidentsResponse{
+		Incidents: make([]incidentResponse, 0, len(is)),
+	}
+	res.Links.Self = prefixIncidents
+	for _, i := range is {
+		res.Incidents = append(res.Incidents, newIncidentResponse(i))
+	}
+	return res
+}
+
+func (h *IncidentHandler) handleGetIncidents(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "IncidentHandler.handleGetIncidents")
+	defer span.Finish()
+
+	ctx := r.Context()
+	filter, err := h.decodeGetIncidentsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	is, err := h.IncidentService.FindIncidents(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetIncidentsResponse(is)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// decodeGetIncidentsRequest extracts the organization, given by its orgID or
+// org name, and the optional check and status of the incidents to list.
+func (h *IncidentHandler) decodeGetIncidentsRequest(ctx context.Context, r *http.Request) (influxdb.IncidentFilter, error) {
+	var filter influxdb.IncidentFilter
+
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = &o.ID
+	} else {
+		return filter, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID or org is required",
+		}
+	}
+
+	if checkID := qp.Get("checkID"); checkID != "" {
+		id, err := influxdb.IDFromString(checkID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid checkID",
+				Err:  err,
+			}
+		}
+		filter.CheckID = id
+	}
+
+	if status := qp.Get("status"); status != "" {
+		switch status {
+		case influxdb.IncidentOpen, influxdb.IncidentAcknowledged, influxdb.IncidentResolved:
+		default:
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "status must be one of open, acknowledged or resolved",
+			}
+		}
+		filter.Status = &status
+	}
+	return filter, nil
+}
+
+func requestIncidentID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *IncidentHandler) handleGetIncident(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "IncidentHandler.handleGetIncident")
+	defer span.Finish()
+
+	ctx := r.Context()
+	id, err := requestIncidentID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	i, err := h.IncidentService.FindIncidentByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newIncidentResponse(i)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *IncidentHandler) handlePatchIncident(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "IncidentHandler.handlePatchIncident")
+	defer span.Finish()
+
+	ctx := r.Context()
+	id, err := requestIncidentID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.IncidentUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+	if err := upd.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	i, err := h.IncidentService.UpdateIncident(ctx, id, upd, auth.GetUserID())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Incident updated", zap.String("incident", fmt.Sprint(i)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newIncidentResponse(i)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// IncidentService is an incident service over HTTP to the influxdb server.
+type IncidentService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.IncidentService = (*IncidentService)(nil)
+
+// FindIncidentByID returns a single incident by ID.
+func (s *IncidentService) FindIncidentByID(ctx context.Context, id influxdb.ID) (*influxdb.Incident, error) {
+	var res incidentResponse
+	err := s.Client.
+		Get(prefixIncidents, id.String()).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return res.Incident, nil
+}
+
+// FindIncidents returns the incidents matching the filter, the most recent
+// first.
+func (s *IncidentService) FindIncidents(ctx context.Context, filter influxdb.IncidentFilter) ([]*influxdb.Incident, error) {
+	var params [][2]string
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+	if filter.CheckID != nil {
+		params = append(params, [2]string{"checkID", filter.CheckID.String()})
+	}
+	if filter.Status != nil {
+		params = append(params, [2]string{"status", *filter.Status})
+	}
+
+	var res getIncidentsResponse
+	err := s.Client.
+		Get(prefixIncidents).
+		QueryParams(params...).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	is := make([]*influxdb.Incident, 0, len(res.Incidents))
+	for _, r := range res.Incidents {
+		is = append(is, r.Incident)
+	}
+	return is, nil
+}
+
+// UpdateIncident acknowledges or resolves a single incident. The user is the
+// one of the authorization of the client.
+func (s *IncidentService) UpdateIncident(ctx context.Context, id influxdb.ID, upd influxdb.IncidentUpdate, userID influxdb.ID) (*influxdb.Incident, error) {
+	var res incidentResponse
+	err := s.Client.
+		PatchJSON(upd, prefixIncidents, id.String()).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return res.Incident, nil
+}
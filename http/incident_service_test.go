@@ -0,0 +1,158 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxtesting "github.com/influxdata/influxdb/v2/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestIncidentHandler(t *testing.T) {
+	start := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+	incident := influxdb.Incident{
+		ID:          influxtesting.MustIDBase16("020f755c3c082001"),
+		OrgID:       influxtesting.MustIDBase16("020f755c3c082000"),
+		CheckID:     influxtesting.MustIDBase16("020f755c3c082002"),
+		CheckName:   "cpu",
+		Tags:        []influxdb.Tag{{Key: "host", Value: "db1"}},
+		Status:      influxdb.IncidentOpen,
+		Level:       "crit",
+		Message:     "high cpu",
+		StatusCount: 3,
+		Start:       start,
+		LastSeen:    start.Add(2 * time.Minute),
+		CRUDLog:     influxdb.CRUDLog{CreatedAt: start, UpdatedAt: start},
+	}
+	incidentJSON := `{
+		"id": "020f755c3c082001",
+		"orgID": "020f755c3c082000",
+		"checkID": "020f755c3c082002",
+		"checkName": "cpu",
+		"tags": [{"key": "host", "value": "db1"}],
+		"status": "open",
+		"level": "crit",
+		"message": "high cpu",
+		"statusCount": 3,
+		"start": "2020-01-01T22:00:00Z",
+		"lastSeen": "2020-01-01T22:02:00Z",
+		"createdAt": "2020-01-01T22:00:00Z",
+		"updatedAt": "2020-01-01T22:00:00Z",
+		"links": {
+			"self": "/api/v2/incidents/020f755c3c082001",
+			"org": "/api/v2/orgs/020f755c3c082000",
+			"check": "/api/v2/checks/020f755c3c082002"
+		}
+	}`
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+		want   string
+	}{
+		{
+			name:   "list incidents",
+			method: http.MethodGet,
+			path:   prefixIncidents + "?orgID=020f755c3c082000&status=open",
+			code:   http.StatusOK,
+			want:   `{"incidents": [` + incidentJSON + `], "links": {"self": "/api/v2/incidents"}}`,
+		},
+		{
+			name:   "list incidents without org",
+			method: http.MethodGet,
+			path:   prefixIncidents,
+			code:   http.StatusBadRequest,
+			want:   `{"code": "invalid", "message": "orgID or org is required"}`,
+		},
+		{
+			name:   "list incidents with invalid status",
+			method: http.MethodGet,
+			path:   prefixIncidents + "?orgID=020f755c3c082000&status=closed",
+			code:   http.StatusBadRequest,
+			want:   `{"code": "invalid", "message": "status must be one of open, acknowledged or resolved"}`,
+		},
+		{
+			name:   "get incident",
+			method: http.MethodGet,
+			path:   prefixIncidents + "/020f755c3c082001",
+			code:   http.StatusOK,
+			want:   incidentJSON,
+		},
+		{
+			name:   "acknowledge incident",
+			method: http.MethodPatch,
+			path:   prefixIncidents + "/020f755c3c082001",
+			body:   `{"status": "acknowledged"}`,
+			code:   http.StatusOK,
+		},
+		{
+			name:   "reopen incident",
+			method: http.MethodPatch,
+			path:   prefixIncidents + "/020f755c3c082001",
+			body:   `{"status": "open"}`,
+			code:   http.StatusBadRequest,
+			want:   `{"code": "invalid", "message": "incident status must be one of acknowledged or resolved"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewIncidentService()
+			svc.FindIncidentsFn = func(ctx context.Context, filter influxdb.IncidentFilter) ([]*influxdb.Incident, error) {
+				if filter.OrgID == nil || *filter.OrgID != incident.OrgID {
+					t.Errorf("unexpected filter %+v", filter)
+				}
+				i := incident
+				return []*influxdb.Incident{&i}, nil
+			}
+			svc.FindIncidentByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Incident, error) {
+				i := incident
+				return &i, nil
+			}
+			svc.UpdateIncidentFn = func(ctx context.Context, id influxdb.ID, upd influxdb.IncidentUpdate, userID influxdb.ID) (*influxdb.Incident, error) {
+				if userID != 1 {
+					t.Errorf("unexpected user %s", userID)
+				}
+				i := incident
+				if err := upd.Apply(&i, userID, start); err != nil {
+					return nil, err
+				}
+				return &i, nil
+			}
+
+			handler := NewIncidentHandler(zaptest.NewLogger(t), &IncidentBackend{
+				HTTPErrorHandler: DefaultErrorHandler,
+				log:              zaptest.NewLogger(t),
+				IncidentService:  svc,
+			})
+
+			r := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Authorization{UserID: 1}))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.code {
+				t.Errorf("got status %d, want %d: %s", res.StatusCode, tt.code, body)
+			}
+			if tt.want == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.want); err != nil || !eq {
+				t.Errorf("unexpected body: %v %v\n%s", err, diff, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/http/metric"
+	"github.com/influxdata/influxdb/v2/jsonweb"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/logger"
+	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/query/influxql"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixLegacyQuery = "/query"
+
+	opLegacyQueryHandler = "http/legacyQueryHandler"
+)
+
+// LegacyQueryBackend is all services and associated parameters required to
+// construct the LegacyQueryHandler.
+type LegacyQueryBackend struct {
+	influxdb.HTTPErrorHandler
+	log                *zap.Logger
+	QueryEventRecorder metric.EventRecorder
+
+	OrganizationService influxdb.OrganizationService
+	DBRPMappingService  influxdb.DBRPMappingServiceV2
+	ProxyQueryService   query.ProxyQueryService
+}
+
+// NewLegacyQueryBackend returns a new instance of LegacyQueryBackend.
+func NewLegacyQueryBackend(log *zap.Logger, b *APIBackend) *LegacyQueryBackend {
+	return &LegacyQueryBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		log:                log,
+		QueryEventRecorder: b.QueryEventRecorder,
+
+		OrganizationService: b.OrganizationService,
+		DBRPMappingService:  b.DBRPService,
+		ProxyQueryService:   b.InfluxQLService,
+	}
+}
+
+// LegacyQueryHandler executes InfluxQL queries received on the influxdb 1.x
+// compatible /query endpoint and responds in the 1.x JSON or CSV format.
+type LegacyQueryHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	Now                 func() time.Time
+	OrganizationService influxdb.OrganizationService
+	DBRPMappingService  influxdb.DBRPMappingServiceV2
+	ProxyQueryService   query.ProxyQueryService
+
+	EventRecorder metric.EventRecorder
+}
+
+// Prefix provides the route prefix.
+func (*LegacyQueryHandler) Prefix() string {
+	return prefixLegacyQuery
+}
+
+// NewLegacyQueryHandler returns a new handler at /query for InfluxQL queries.
+func NewLegacyQueryHandler(log *zap.Logger, b *LegacyQueryBackend) *LegacyQueryHandler {
+	h := &LegacyQueryHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		Now:              time.Now,
+
+		OrganizationService: b.OrganizationService,
+		DBRPMappingService:  b.DBRPMappingService,
+		ProxyQueryService:   b.ProxyQueryService,
+		EventRecorder:       b.QueryEventRecorder,
+	}
+
+	h.HandlerFunc(http.MethodGet, prefixLegacyQuery, h.handleQuery)
+	h.HandlerFunc(http.MethodPost, prefixLegacyQuery, h.handleQuery)
+	return h
+}
+
+func (h *LegacyQueryHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "LegacyQueryHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	log := h.log.With(logger.TraceFields(ctx)...)
+	if id, _, found := tracing.InfoFromContext(ctx); found {
+		w.Header().Set(traceIDHeader, id)
+	}
+
+	var orgID influxdb.ID
+	sw := kithttp.NewStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.EventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			RequestBytes:  len(r.FormValue("q")),
+			ResponseBytes: sw.ResponseBytes(),
+			Status:        sw.Code(),
+		})
+	}()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.handleLegacyError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Op:   opLegacyQueryHandler,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	req, err := h.decodeLegacyQueryRequest(ctx, r, a)
+	if err != nil {
+		h.handleLegacyError(ctx, err, w)
+		return
+	}
+	req.Request.Source = r.Header.Get("User-Agent")
+	orgID = req.Request.OrganizationID
+
+	ctx = pcontext.SetAuthorizer(ctx, req.Request.Authorization)
+	req.Dialect.(*influxql.Dialect).SetHeaders(w)
+
+	cw := iocounter.Writer{Writer: w}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil {
+		if cw.Count() == 0 {
+			// Only record the error headers IFF nothing has been written to w.
+			h.handleLegacyError(ctx, err, w)
+			return
+		}
+		_ = tracing.LogError(span, err)
+		log.Info("Error writing response to client",
+			zap.String("handler", "influxql"),
+			zap.Error(err),
+		)
+	}
+}
+
+// decodeLegacyQueryRequest builds a proxy request for the InfluxQL query
+// service from the form values of an influxdb 1.x query request.
+func (h *LegacyQueryHandler) decodeLegacyQueryRequest(ctx context.Context, r *http.Request, auth influxdb.Authorizer) (*query.ProxyRequest, error) {
+	q := strings.TrimSpace(r.FormValue("q"))
+	if q == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opLegacyQueryHandler,
+			Msg:  `missing required parameter "q"`,
+		}
+	}
+
+	timeFormat, err := legacyTimeFormat(r.FormValue("epoch"))
+	if err != nil {
+		return nil, err
+	}
+
+	var token *influxdb.Authorization
+	switch a := auth.(type) {
+	case *influxdb.Authorization:
+		token = a
+	case *influxdb.Session:
+		// Sessions are not bound to an organization, so the organization
+		// must be provided as a parameter.
+		org, err := queryOrganization(ctx, r, h.OrganizationService)
+		if err != nil {
+			return nil, err
+		}
+		token = a.EphemeralAuth(org.ID)
+	case *jsonweb.Token:
+		org, err := queryOrganization(ctx, r, h.OrganizationService)
+		if err != nil {
+			return nil, err
+		}
+		token = a.EphemeralAuth(org.ID)
+	default:
+		return nil, influxdb.ErrAuthorizerNotSupported
+	}
+
+	// The compiler maps the database and retention policy of the query to
+	// a bucket with the DBRP mapping service when it transpiles it.
+	now := h.Now()
+	compiler := influxql.NewCompiler(h.DBRPMappingService)
+	compiler.DB = r.FormValue("db")
+	compiler.RP = r.FormValue("rp")
+	compiler.Query = q
+	compiler.Now = &now
+
+	dialect := &influxql.Dialect{
+		TimeFormat: timeFormat,
+		Encoding:   legacyEncoding(r),
+	}
+	return &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  token,
+			OrganizationID: token.OrgID,
+			Compiler:       compiler,
+		},
+		Dialect: dialect,
+	}, nil
+}
+
+// handleLegacyError writes err using the influxdb 1.x error response shape.
+func (h *LegacyQueryHandler) handleLegacyError(ctx context.Context, err error, w http.ResponseWriter) {
+	code := influxdb.ErrorCode(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(kithttp.PlatformErrorCodeHeader, code)
+	w.WriteHeader(kithttp.ErrorCodeToStatusCode(ctx, code))
+
+	resp := influxql.Response{Err: "An internal error has occurred"}
+	if e, ok := err.(*influxdb.Error); ok {
+		resp.Err = e.Error()
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Info("Error writing error response to client", zap.Error(err))
+	}
+}
+
+// legacyTimeFormat maps the epoch parameter of influxdb 1.x to a TimeFormat.
+func legacyTimeFormat(epoch string) (influxql.TimeFormat, error) {
+	switch epoch {
+	case "":
+		return influxql.RFC3339Nano, nil
+	case "h":
+		return influxql.Hour, nil
+	case "m":
+		return influxql.Minute, nil
+	case "s":
+		return influxql.Second, nil
+	case "ms":
+		return influxql.Millisecond, nil
+	case "u", "us", "µ":
+		return influxql.Microsecond, nil
+	case "n", "ns":
+		return influxql.Nanosecond, nil
+	default:
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opLegacyQueryHandler,
+			Msg:  "invalid epoch; valid epoch units are h, m, s, ms, u, and ns",
+		}
+	}
+}
+
+// legacyEncoding selects the response encoding from the Accept header and
+// the pretty parameter of an influxdb 1.x query request.
+func legacyEncoding(r *http.Request) influxql.EncodingFormat {
+	switch r.Header.Get("Accept") {
+	case "application/csv", "text/csv":
+		return influxql.CSV
+	}
+	if r.FormValue("pretty") == "true" {
+		return influxql.JSONPretty
+	}
+	return influxql.JSON
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http/metric"
+	httpmock "github.com/influxdata/influxdb/v2/http/mock"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/query"
+	querymock "github.com/influxdata/influxdb/v2/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+var errTranspiled = errors.New("transpiled")
+
+// transpileRuntime is a flux.Runtime which records the JSON of the AST
+// transpiled by an InfluxQL compiler instead of compiling it.
+type transpileRuntime struct {
+	flux.Runtime
+	ast string
+}
+
+func (r *transpileRuntime) JSONToHandle(json []byte) (flux.ASTHandle, error) {
+	r.ast = string(json)
+	return nil, errTranspiled
+}
+
+func TestLegacyQueryHandler_handleQuery(t *testing.T) {
+	const (
+		orgID    = "043e0780ee2b1000"
+		bucketID = "04504b356e23b000"
+	)
+
+	type wants struct {
+		code     int
+		body     string
+		database string
+		rp       string
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		mappings []*influxdb.DBRPMappingV2
+		wants    wants
+	}{
+		{
+			name:  "select from the default retention policy",
+			query: "db=mydb&q=SELECT+mean(f1)+FROM+m1",
+			mappings: []*influxdb.DBRPMappingV2{
+				legacyMapping(orgID, bucketID, "mydb", "autogen"),
+			},
+			wants: wants{
+				code:     200,
+				body:     `{"results":[{"statement_id":0}]}`,
+				database: "mydb",
+			},
+		},
+		{
+			name:  "select from an explicit retention policy",
+			query: "db=mydb&rp=weekly&q=SELECT+f1+FROM+m1",
+			mappings: []*influxdb.DBRPMappingV2{
+				legacyMapping(orgID, bucketID, "mydb", "weekly"),
+			},
+			wants: wants{
+				code:     200,
+				body:     `{"results":[{"statement_id":0}]}`,
+				database: "mydb",
+				rp:       "weekly",
+			},
+		},
+		{
+			name:  "missing database is rejected",
+			query: "q=SELECT+f1+FROM+m1",
+			wants: wants{
+				code: 400,
+				body: `{"error":"unable to transpile: database is required"}`,
+			},
+		},
+		{
+			name:  "missing query is rejected",
+			query: "db=mydb",
+			wants: wants{
+				code: 400,
+				body: `{"error":"missing required parameter \"q\""}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter influxdb.DBRPMappingFilterV2
+			dbrps := &mock.DBRPMappingServiceV2{
+				FindManyFn: func(ctx context.Context, f influxdb.DBRPMappingFilterV2, opts ...influxdb.FindOptions) ([]*influxdb.DBRPMappingV2, int, error) {
+					filter = f
+					return tt.mappings, len(tt.mappings), nil
+				},
+			}
+
+			var rt transpileRuntime
+			proxy := &querymock.ProxyQueryService{
+				QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+					if _, err := req.Request.Compiler.Compile(ctx, &rt); err != errTranspiled {
+						return flux.Statistics{}, err
+					}
+					_, err := io.WriteString(w, `{"results":[{"statement_id":0}]}`)
+					return flux.Statistics{}, err
+				},
+			}
+
+			b := &APIBackend{
+				HTTPErrorHandler:   DefaultErrorHandler,
+				Logger:             zaptest.NewLogger(t),
+				DBRPService:        dbrps,
+				InfluxQLService:    proxy,
+				QueryEventRecorder: &metric.NopEventRecorder{},
+			}
+			queryHandler := NewLegacyQueryHandler(zaptest.NewLogger(t), NewLegacyQueryBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(queryHandler, bucketWritePermission(orgID, bucketID))
+
+			r := httptest.NewRequest(http.MethodGet, "http://localhost:9999/query?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.wants.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+			if got, want := strings.TrimSpace(w.Body.String()), tt.wants.body; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+
+			if tt.wants.database == "" {
+				return
+			}
+			if filter.Database == nil || *filter.Database != tt.wants.database {
+				t.Errorf("unexpected database of the dbrp lookup: %v", filter.Database)
+			}
+			if tt.wants.rp != "" && (filter.RetentionPolicy == nil || *filter.RetentionPolicy != tt.wants.rp) {
+				t.Errorf("unexpected retention policy of the dbrp lookup: %v", filter.RetentionPolicy)
+			}
+			if !strings.Contains(rt.ast, `"bucketID"`) || !strings.Contains(rt.ast, bucketID) {
+				t.Errorf("expected the query to read from the mapped bucket, got AST %s", rt.ast)
+			}
+		})
+	}
+}
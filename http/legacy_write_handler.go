@@ -0,0 +1,197 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/models"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixLegacyWrite = "/write"
+
+	opLegacyWriteHandler = "http/legacyWriteHandler"
+)
+
+// LegacyWriteHandler receives line protocol on the influxdb 1.x compatible
+// /write endpoint. The db and rp parameters are resolved to a bucket through
+// the DBRP mapping service and the points are written through the same path
+// as the WriteHandler.
+type LegacyWriteHandler struct {
+	*WriteHandler
+	DBRPMappingService influxdb.DBRPMappingServiceV2
+
+	router *httprouter.Router
+}
+
+// Prefix provides the route prefix.
+func (*LegacyWriteHandler) Prefix() string {
+	return prefixLegacyWrite
+}
+
+// NewLegacyWriteHandler creates a new handler at /write to receive line protocol.
+func NewLegacyWriteHandler(log *zap.Logger, b *WriteBackend, opts ...WriteHandlerOption) *LegacyWriteHandler {
+	h := &LegacyWriteHandler{
+		WriteHandler:       NewWriteHandler(log, b, opts...),
+		DBRPMappingService: b.DBRPMappingService,
+		router:             NewRouter(b.HTTPErrorHandler),
+	}
+
+	h.router.HandlerFunc(http.MethodPost, prefixLegacyWrite, h.handleWrite)
+	return h
+}
+
+func (h *LegacyWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.ServeHTTP(w, r)
+}
+
+func (h *LegacyWriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "LegacyWriteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeLegacyWriteRequest(ctx, r, h.maxBatchSizeBytes)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	mapping, err := findLegacyMapping(ctx, h.DBRPMappingService, auth, req.Database, req.RetentionPolicy)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	span.LogKV("org_id", mapping.OrganizationID, "bucket_id", mapping.BucketID)
+
+	sw := kithttp.NewStatusResponseWriter(w)
+	recorder := NewWriteUsageRecorder(sw, h.EventRecorder)
+	var requestBytes int
+	defer func() {
+		// Close around the requestBytes variable to placate the linter.
+		recorder.Record(ctx, requestBytes, mapping.OrganizationID, r.URL.Path)
+	}()
+
+	requestBytes, err = h.writePoints(ctx, auth, mapping.OrganizationID, mapping.BucketID, req.Precision, nil, req.Body)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, sw)
+		return
+	}
+
+	sw.WriteHeader(http.StatusNoContent)
+}
+
+// findLegacyMapping resolves a database and retention policy to a DBRP mapping.
+// When rp is empty the default mapping for the database is used. Mappings are
+// scoped to the organization of the authorization when one is available.
+func findLegacyMapping(ctx context.Context, svc influxdb.DBRPMappingServiceV2, auth influxdb.Authorizer, db, rp string) (*influxdb.DBRPMappingV2, error) {
+	filter := influxdb.DBRPMappingFilterV2{
+		Database: &db,
+	}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	} else {
+		defaultRP := true
+		filter.Default = &defaultRP
+	}
+	if a, ok := auth.(*influxdb.Authorization); ok {
+		filter.OrgID = &a.OrgID
+	}
+
+	mappings, _, err := svc.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		msg := fmt.Sprintf("no dbrp mapping found for database %q", db)
+		if rp != "" {
+			msg = fmt.Sprintf("no dbrp mapping found for database %q and retention policy %q", db, rp)
+		}
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Op:   opLegacyWriteHandler,
+			Msg:  msg,
+		}
+	}
+	if len(mappings) > 1 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EConflict,
+			Op:   opLegacyWriteHandler,
+			Msg:  fmt.Sprintf("database %q is mapped in more than one organization; use a token scoped to a single organization", db),
+		}
+	}
+	return mappings[0], nil
+}
+
+// legacyWriteRequest is a request object holding information about a batch
+// of points written to the influxdb 1.x compatible endpoint.
+type legacyWriteRequest struct {
+	Database        string
+	RetentionPolicy string
+	Precision       string
+	Body            io.ReadCloser
+}
+
+// decodeLegacyWriteRequest extracts information from an http.Request object to
+// produce a legacyWriteRequest.
+func decodeLegacyWriteRequest(ctx context.Context, r *http.Request, maxBatchSizeBytes int64) (*legacyWriteRequest, error) {
+	const op = "http/newLegacyWriteRequest"
+
+	qp := r.URL.Query()
+	db := qp.Get("db")
+	if db == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   op,
+			Msg:  "database is required",
+		}
+	}
+
+	precision := legacyPrecision(qp.Get("precision"))
+	if !models.ValidPrecision(precision) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   op,
+			Msg:  msgInvalidPrecision,
+		}
+	}
+
+	encoding := r.Header.Get("Content-Encoding")
+	body, err := PointBatchReadCloser(r.Body, encoding, maxBatchSizeBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &legacyWriteRequest{
+		Database:        db,
+		RetentionPolicy: qp.Get("rp"),
+		Precision:       precision,
+		Body:            body,
+	}, nil
+}
+
+// legacyPrecision converts the precision units accepted by influxdb 1.x
+// to the units understood by the line protocol parser.
+func legacyPrecision(precision string) string {
+	switch precision {
+	case "", "n":
+		return "ns"
+	case "u":
+		return "us"
+	default:
+		return precision
+	}
+}
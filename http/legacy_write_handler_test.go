@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http/metric"
+	httpmock "github.com/influxdata/influxdb/v2/http/mock"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxtesting "github.com/influxdata/influxdb/v2/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestLegacyWriteHandler_handleWrite(t *testing.T) {
+	const (
+		orgID    = "043e0780ee2b1000"
+		bucketID = "04504b356e23b000"
+	)
+
+	type wants struct {
+		body string
+		code int
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		body     string
+		auth     influxdb.Authorizer
+		mappings []*influxdb.DBRPMappingV2
+		wants    wants
+	}{
+		{
+			name:  "write to the default retention policy",
+			query: "db=mydb",
+			body:  "m1,t1=v1 f1=1",
+			auth:  bucketWritePermission(orgID, bucketID),
+			mappings: []*influxdb.DBRPMappingV2{
+				legacyMapping(orgID, bucketID, "mydb", "autogen"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name:  "write to an explicit retention policy with seconds precision",
+			query: "db=mydb&rp=weekly&precision=s",
+			body:  "m1,t1=v1 f1=1 1600000000",
+			auth:  bucketWritePermission(orgID, bucketID),
+			mappings: []*influxdb.DBRPMappingV2{
+				legacyMapping(orgID, bucketID, "mydb", "weekly"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name:  "missing database is rejected",
+			query: "rp=weekly",
+			body:  "m1,t1=v1 f1=1",
+			auth:  bucketWritePermission(orgID, bucketID),
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"database is required"}`,
+			},
+		},
+		{
+			name:  "invalid precision is rejected",
+			query: "db=mydb&precision=h",
+			body:  "m1,t1=v1 f1=1",
+			auth:  bucketWritePermission(orgID, bucketID),
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"invalid precision; valid precision units are ns, us, ms, and s"}`,
+			},
+		},
+		{
+			name:  "unmapped database returns 404",
+			query: "db=unknown",
+			body:  "m1,t1=v1 f1=1",
+			auth:  bucketWritePermission(orgID, bucketID),
+			wants: wants{
+				code: 404,
+				body: `{"code":"not found","message":"no dbrp mapping found for database \"unknown\""}`,
+			},
+		},
+		{
+			name:  "forbidden to write with insufficient permission",
+			query: "db=mydb",
+			body:  "m1,t1=v1 f1=1",
+			auth:  bucketWritePermission(orgID, "000000000000000a"),
+			mappings: []*influxdb.DBRPMappingV2{
+				legacyMapping(orgID, bucketID, "mydb", "autogen"),
+			},
+			wants: wants{
+				code: 403,
+				body: `{"code":"forbidden","message":"insufficient permissions for write"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter influxdb.DBRPMappingFilterV2
+			dbrps := &mock.DBRPMappingServiceV2{
+				FindManyFn: func(ctx context.Context, f influxdb.DBRPMappingFilterV2, opts ...influxdb.FindOptions) ([]*influxdb.DBRPMappingV2, int, error) {
+					filter = f
+					return tt.mappings, len(tt.mappings), nil
+				},
+			}
+
+			b := &APIBackend{
+				HTTPErrorHandler:   DefaultErrorHandler,
+				Logger:             zaptest.NewLogger(t),
+				DBRPService:        dbrps,
+				PointsWriter:       &mock.PointsWriter{},
+				WriteEventRecorder: &metric.NopEventRecorder{},
+			}
+			writeHandler := NewLegacyWriteHandler(zaptest.NewLogger(t), NewWriteBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(writeHandler, tt.auth)
+
+			r := httptest.NewRequest(
+				"POST",
+				"http://localhost:9999/write?"+tt.query,
+				strings.NewReader(tt.body),
+			)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.wants.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+
+			if got, want := w.Body.String(), tt.wants.body; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+
+			if filter.Database != nil && filter.OrgID == nil {
+				t.Errorf("expected dbrp lookup to be scoped to the authorization's organization")
+			}
+		})
+	}
+}
+
+func legacyMapping(org, bucket, db, rp string) *influxdb.DBRPMappingV2 {
+	return &influxdb.DBRPMappingV2{
+		ID:              influxtesting.MustIDBase16("0000000000000001"),
+		Database:        db,
+		RetentionPolicy: rp,
+		Default:         true,
+		OrganizationID:  influxtesting.MustIDBase16(org),
+		BucketID:        influxtesting.MustIDBase16(bucket),
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// decodeMonitoringHistoryRequest decodes the paging options and the optional
+// start, stop and level filters of the history of a check or of a rule.
+func decodeMonitoringHistoryRequest(r *http.Request) (influxdb.MonitoringFilter, *influxdb.FindOptions, error) {
+	var filter influxdb.MonitoringFilter
+
+	opts, err := influxdb.DecodeFindOptions(r)
+	if err != nil {
+		return filter, nil, err
+	}
+
+	qp := r.URL.Query()
+	for _, param := range []struct {
+		name string
+		t    *time.Time
+	}{
+		{name: "start", t: &filter.Start},
+		{name: "stop", t: &filter.Stop},
+	} {
+		v := qp.Get(param.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return filter, nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  param.name + " must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		*param.t = t
+	}
+
+	if level := qp.Get("level"); level != "" {
+		switch level {
+		case "ok", "info", "warn", "crit", "unknown":
+		default:
+			return filter, nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "level must be one of ok, info, warn, crit or unknown",
+			}
+		}
+		filter.Level = &level
+	}
+	return filter, opts, nil
+}
+
+type checkStatusesResponse struct {
+	Statuses []*influxdb.CheckStatus `json:"statuses"`
+	Links    *influxdb.PagingLinks   `json:"links"`
+}
+
+func newCheckStatusesResponse(basePath string, statuses []*influxdb.CheckStatus, filter influxdb.MonitoringFilter, opts influxdb.FindOptions) checkStatusesResponse {
+	if statuses == nil {
+		statuses = []*influxdb.CheckStatus{}
+	}
+	return checkStatusesResponse{
+		Statuses: statuses,
+		Links:    influxdb.NewPagingLinks(basePath, opts, filter, len(statuses)),
+	}
+}
+
+type notificationRecordsResponse struct {
+	Notifications []*influxdb.NotificationRecord `json:"notifications"`
+	Links         *influxdb.PagingLinks          `json:"links"`
+}
+
+func newNotificationRecordsResponse(basePath string, notifications []*influxdb.NotificationRecord, filter influxdb.MonitoringFilter, opts influxdb.FindOptions) notificationRecordsResponse {
+	if notifications == nil {
+		notifications = []*influxdb.NotificationRecord{}
+	}
+	return notificationRecordsResponse{
+		Notifications: notifications,
+		Links:         influxdb.NewPagingLinks(basePath, opts, filter, len(notifications)),
+	}
+}
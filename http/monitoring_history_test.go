@@ -0,0 +1,158 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	influxtesting "github.com/influxdata/influxdb/v2/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMonitoringHistoryHandlers(t *testing.T) {
+	orgID := influxtesting.MustIDBase16("020f755c3c082000")
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		path string
+		code int
+		want string
+	}{
+		{
+			name: "check statuses",
+			path: "/api/v2/checks/020f755c3c082001/statuses?level=crit&start=2020-01-01T21:00:00Z&limit=1",
+			code: http.StatusOK,
+			want: `{
+				"statuses": [{
+					"checkID": "020f755c3c082001",
+					"checkName": "cpu",
+					"level": "crit",
+					"message": "high cpu",
+					"time": "2020-01-01T22:00:00Z",
+					"tags": [{"key": "host", "value": "db1"}]
+				}],
+				"links": {
+					"self": "/api/v2/checks/020f755c3c082001/statuses?descending=false&level=crit&limit=1&offset=0&start=2020-01-01T21%3A00%3A00Z",
+					"next": "/api/v2/checks/020f755c3c082001/statuses?descending=false&level=crit&limit=1&offset=1&start=2020-01-01T21%3A00%3A00Z"
+				}
+			}`,
+		},
+		{
+			name: "check statuses with invalid level",
+			path: "/api/v2/checks/020f755c3c082001/statuses?level=error",
+			code: http.StatusBadRequest,
+			want: `{"code": "invalid", "message": "level must be one of ok, info, warn, crit or unknown"}`,
+		},
+		{
+			name: "check statuses with invalid start",
+			path: "/api/v2/checks/020f755c3c082001/statuses?start=-1d",
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "notification rule history",
+			path: "/api/v2/notificationRules/020f755c3c082002/history",
+			code: http.StatusOK,
+			want: `{
+				"notifications": [{
+					"ruleID": "020f755c3c082002",
+					"ruleName": "crit",
+					"endpointID": "020f755c3c082003",
+					"endpointName": "slack",
+					"checkID": "020f755c3c082001",
+					"checkName": "cpu",
+					"level": "crit",
+					"message": "high cpu",
+					"sent": true,
+					"time": "2020-01-01T22:00:00Z"
+				}],
+				"links": {
+					"self": "/api/v2/notificationRules/020f755c3c082002/history?descending=false&limit=20&offset=0"
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := mock.NewMonitoringHistoryService()
+			history.FindCheckStatusesFn = func(ctx context.Context, oid, checkID influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]*influxdb.CheckStatus, int, error) {
+				if oid != orgID || filter.Level == nil || *filter.Level != "crit" || !filter.Start.Equal(t0.Add(-time.Hour)) {
+					t.Errorf("unexpected filter %s %+v", oid, filter)
+				}
+				return []*influxdb.CheckStatus{{
+					CheckID:   checkID,
+					CheckName: "cpu",
+					Level:     "crit",
+					Message:   "high cpu",
+					Time:      t0,
+					Tags:      []influxdb.Tag{{Key: "host", Value: "db1"}},
+				}}, 2, nil
+			}
+			history.FindNotificationRecordsFn = func(ctx context.Context, oid, ruleID influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationRecord, int, error) {
+				if oid != orgID {
+					t.Errorf("unexpected organization %s", oid)
+				}
+				return []*influxdb.NotificationRecord{{
+					RuleID:       ruleID,
+					RuleName:     "crit",
+					EndpointID:   influxtesting.MustIDBase16("020f755c3c082003"),
+					EndpointName: "slack",
+					CheckID:      influxtesting.MustIDBase16("020f755c3c082001"),
+					CheckName:    "cpu",
+					Level:        "crit",
+					Message:      "high cpu",
+					Sent:         true,
+					Time:         t0,
+				}}, 1, nil
+			}
+
+			checkBackend := NewMockCheckBackend(t)
+			checkBackend.HTTPErrorHandler = DefaultErrorHandler
+			checkBackend.MonitoringHistoryService = history
+			checkBackend.CheckService = &mock.CheckService{
+				FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+					return &check.Deadman{Base: check.Base{ID: id, OrgID: orgID}}, nil
+				},
+			}
+
+			ruleBackend := NewMockNotificationRuleBackend(t)
+			ruleBackend.HTTPErrorHandler = DefaultErrorHandler
+			ruleBackend.MonitoringHistoryService = history
+			ruleBackend.NotificationRuleStore = &mock.NotificationRuleStore{
+				FindNotificationRuleByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+					return &rule.Slack{Base: rule.Base{ID: id, OrgID: orgID}}, nil
+				},
+			}
+
+			var h http.Handler = NewCheckHandler(zaptest.NewLogger(t), checkBackend)
+			if strings.HasPrefix(tt.path, prefixNotificationRules) {
+				h = NewNotificationRuleHandler(zaptest.NewLogger(t), ruleBackend)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.code {
+				t.Errorf("got status %d, want %d: %s", res.StatusCode, tt.code, body)
+			}
+			if tt.want == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.want); err != nil || !eq {
+				t.Errorf("unexpected body: %v %v\n%s", err, diff, body)
+			}
+		})
+	}
+}
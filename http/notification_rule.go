@@ -37,6 +37,7 @@ type NotificationRuleBackend struct {
 	UserService                 influxdb.UserService
 	OrganizationService         influxdb.OrganizationService
 	TaskService                 influxdb.TaskService
+	MonitoringHistoryService    influxdb.MonitoringHistoryService
 }
 
 // NewNotificationRuleBackend returns a new instance of NotificationRuleBackend.
@@ -53,6 +54,7 @@ func NewNotificationRuleBackend(log *zap.Logger, b *APIBackend) *NotificationRul
 		UserService:                 b.UserService,
 		OrganizationService:         b.OrganizationService,
 		TaskService:                 b.TaskService,
+		MonitoringHistoryService:    b.MonitoringHistoryService,
 	}
 }
 
@@ -69,12 +71,14 @@ type NotificationRuleHandler struct {
 	UserService                 influxdb.UserService
 	OrganizationService         influxdb.OrganizationService
 	TaskService                 influxdb.TaskService
+	MonitoringHistoryService    influxdb.MonitoringHistoryService
 }
 
 const (
 	prefixNotificationRules          = "/api/v2/notificationRules"
 	notificationRulesIDPath          = "/api/v2/notificationRules/:id"
 	notificationRulesIDQueryPath     = "/api/v2/notificationRules/:id/query"
+	notificationRulesIDHistoryPath   = "/api/v2/notificationRules/:id/history"
 	notificationRulesIDMembersPath   = "/api/v2/notificationRules/:id/members"
 	notificationRulesIDMembersIDPath = "/api/v2/notificationRules/:id/members/:userID"
 	notificationRulesIDOwnersPath    = "/api/v2/notificationRules/:id/owners"
@@ -97,12 +101,14 @@ func NewNotificationRuleHandler(log *zap.Logger, b *NotificationRuleBackend) *No
 		UserService:                 b.UserService,
 		OrganizationService:         b.OrganizationService,
 		TaskService:                 b.TaskService,
+		MonitoringHistoryService:    b.MonitoringHistoryService,
 	}
 
 	h.Handler("POST", prefixNotificationRules, withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.handlePostNotificationRule)))
 	h.HandlerFunc("GET", prefixNotificationRules, h.handleGetNotificationRules)
 	h.HandlerFunc("GET", notificationRulesIDPath, h.handleGetNotificationRule)
 	h.HandlerFunc("GET", notificationRulesIDQueryPath, h.handleGetNotificationRuleQuery)
+	h.HandlerFunc("GET", notificationRulesIDHistoryPath, h.handleGetNotificationRuleHistory)
 	h.HandlerFunc("DELETE", notificationRulesIDPath, h.handleDeleteNotificationRule)
 	h.Handler("PUT", notificationRulesIDPath, withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.handlePutNotificationRule)))
 	h.Handler("PATCH", notificationRulesIDPath, withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.handlePatchNotificationRule)))
@@ -310,6 +316,20 @@ func (h *NotificationRuleHandler) handleGetNotificationRuleQuery(w http.Response
 		}, w)
 		return
 	}
+	escalationEdps := make([]influxdb.NotificationEndpoint, 0, len(nr.GetEscalationEndpointIDs()))
+	for _, edpID := range nr.GetEscalationEndpointIDs() {
+		escalationEdp, err := h.NotificationEndpointService.FindNotificationEndpointByID(ctx, edpID)
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   "http/handleGetNotificationRuleQuery",
+				Err:  err,
+			}, w)
+			return
+		}
+		escalationEdps = append(escalationEdps, escalationEdp)
+	}
+	nr.SetEscalationEndpoints(escalationEdps)
 	flux, err := nr.GenerateFlux(edp)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
@@ -322,6 +342,37 @@ func (h *NotificationRuleHandler) handleGetNotificationRuleQuery(w http.Response
 	}
 }
 
+// handleGetNotificationRuleHistory lists the notifications logged by the rule
+// into the _monitoring bucket of its organization.
+func (h *NotificationRuleHandler) handleGetNotificationRuleHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeGetNotificationRuleRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	filter, opts, err := decodeMonitoringHistoryRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	nr, err := h.NotificationRuleStore.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	notifications, _, err := h.MonitoringHistoryService.FindNotificationRecords(ctx, nr.GetOrgID(), id, filter, *opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	basePath := path.Join(prefixNotificationRules, id.String(), "history")
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationRecordsResponse(basePath, notifications, filter, *opts)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
 func (h *NotificationRuleHandler) handleGetNotificationRule(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id, err := decodeGetNotificationRuleRequest(ctx, r)
@@ -31,6 +31,10 @@ func NewPlatformHandler(b *APIBackend, opts ...APIHandlerOptFn) *PlatformHandler
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/swagger.json")
 
+	h.RegisterLegacyAuthRoute("POST", prefixLegacyWrite)
+	h.RegisterLegacyAuthRoute("GET", prefixLegacyQuery)
+	h.RegisterLegacyAuthRoute("POST", prefixLegacyQuery)
+
 	assetHandler := NewAssetHandler()
 	assetHandler.Path = b.AssetsPath
 
@@ -52,9 +56,13 @@ func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Serve the chronograf assets for any basepath that does not start with addressable parts
-	// of the platform API.
-	if !strings.HasPrefix(r.URL.Path, "/v1") &&
+	// of the platform API, the influxdb 1.x compatibility endpoints or the
+	// Prometheus compatibility endpoints.
+	if r.URL.Path != prefixLegacyWrite &&
+		r.URL.Path != prefixLegacyQuery &&
+		!strings.HasPrefix(r.URL.Path, "/v1") &&
 		!strings.HasPrefix(r.URL.Path, "/api/v2") &&
+		!strings.HasPrefix(r.URL.Path, prefixPrometheus+"/") &&
 		!strings.HasPrefix(r.URL.Path, "/chronograf/") {
 		h.AssetHandler.ServeHTTP(w, r)
 		return
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/models"
+)
+
+const (
+	contentTypeJSON     = "application/json"
+	contentTypeProtobuf = "application/x-protobuf"
+)
+
+// PointsDecoder decodes the points of the body of a write request which is not
+// line protocol. The write handler picks the decoder of a request by the media
+// type of its Content-Type header.
+type PointsDecoder interface {
+	// DecodePoints decodes the points of data. The integer timestamps of the
+	// data, if any, are in precision.
+	DecodePoints(data []byte, precision string) (models.Points, error)
+}
+
+// JSONPointsDecoder decodes a JSON array of points, such as:
+//
+//	[{
+//		"measurement": "cpu",
+//		"tags": {"host": "server01"},
+//		"fields": {"usage_user": 12.5, "cores": 8, "healthy": true, "model": "x86"},
+//		"time": 1590000000000000000
+//	}]
+//
+// The time is either an integer timestamp in the precision of the request or
+// an RFC3339 string, and is the time of the write when missing. As in line
+// protocol without the integer suffix, numbers are float fields.
+type JSONPointsDecoder struct{}
+
+type jsonPoint struct {
+	Measurement string                 `json:"measurement"`
+	Tags        map[string]string      `json:"tags"`
+	Fields      map[string]interface{} `json:"fields"`
+	Time        json.RawMessage        `json:"time"`
+}
+
+// DecodePoints decodes the JSON array of points of data.
+func (JSONPointsDecoder) DecodePoints(data []byte, precision string) (models.Points, error) {
+	var jps []jsonPoint
+	if err := json.Unmarshal(data, &jps); err != nil {
+		return nil, fmt.Errorf("unable to decode JSON points: %v", err)
+	}
+
+	points := make(models.Points, 0, len(jps))
+	for i, jp := range jps {
+		p, err := jp.point(precision)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode JSON point %d: %v", i+1, err)
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func (jp *jsonPoint) point(precision string) (models.Point, error) {
+	if jp.Measurement == "" {
+		return nil, fmt.Errorf("missing measurement")
+	}
+
+	for k, v := range jp.Fields {
+		switch v.(type) {
+		case float64, string, bool:
+		default:
+			return nil, fmt.Errorf("field %q is neither a number, a string nor a boolean", k)
+		}
+	}
+
+	var t time.Time
+	if raw := bytes.TrimSpace(jp.Time); len(raw) > 0 && !bytes.Equal(raw, []byte("null")) {
+		var err error
+		if raw[0] == '"' {
+			var s string
+			if err = json.Unmarshal(raw, &s); err == nil {
+				t, err = time.Parse(time.RFC3339Nano, s)
+			}
+		} else {
+			var ts int64
+			if ts, err = strconv.ParseInt(string(raw), 10, 64); err == nil {
+				t, err = models.SafeCalcTime(ts, precision)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %s: %v", raw, err)
+		}
+	}
+
+	return models.NewPoint(jp.Measurement, models.NewTags(jp.Tags), jp.Fields, t)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/models"
+)
+
+const (
+	// prometheusNameLabel is the label of the name of the metric of a series.
+	prometheusNameLabel = "__name__"
+	// prometheusValueField is the field of the value of the samples.
+	prometheusValueField = "value"
+)
+
+// PrometheusPointsDecoder decodes a Prometheus remote write request, the
+// protobuf WriteRequest message of the remote storage API, which remote
+// writers send snappy encoded.
+//
+// Every sample becomes a point of the measurement named after the metric with
+// the other labels of the series as tags and the sample as its float "value"
+// field. Samples are timestamped in milliseconds regardless of the precision
+// of the request. The NaN and infinite samples, such as the stale markers of
+// Prometheus, are dropped as fields can not hold them.
+type PrometheusPointsDecoder struct{}
+
+// DecodePoints decodes the points of the remote write request of data.
+func (PrometheusPointsDecoder) DecodePoints(data []byte, _ string) (models.Points, error) {
+	var points models.Points
+	err := forEachProtobufField(data, func(num int, _ uint64, b []byte) error {
+		// WriteRequest: repeated TimeSeries timeseries = 1.
+		if num != 1 {
+			return nil
+		}
+		var err error
+		points, err = appendPrometheusSeries(points, b)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode Prometheus remote write request: %v", err)
+	}
+	return points, nil
+}
+
+// appendPrometheusSeries appends the points of the samples of the encoded
+// TimeSeries to points.
+func appendPrometheusSeries(points models.Points, series []byte) (models.Points, error) {
+	var (
+		name    string
+		tags    models.Tags
+		samples [][]byte
+	)
+	err := forEachProtobufField(series, func(num int, _ uint64, b []byte) error {
+		switch num {
+		case 1: // repeated Label labels = 1.
+			var key, value []byte
+			err := forEachProtobufField(b, func(num int, _ uint64, b []byte) error {
+				switch num {
+				case 1:
+					key = b
+				case 2:
+					value = b
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if string(key) == prometheusNameLabel {
+				name = string(value)
+			} else {
+				tags = append(tags, models.NewTag(key, value))
+			}
+		case 2: // repeated Sample samples = 2.
+			samples = append(samples, b)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("series without the %s label", prometheusNameLabel)
+	}
+	sort.Sort(tags)
+
+	for _, sample := range samples {
+		var (
+			value float64
+			ts    int64
+		)
+		err := forEachProtobufField(sample, func(num int, v uint64, _ []byte) error {
+			switch num {
+			case 1: // double value = 1.
+				value = math.Float64frombits(v)
+			case 2: // int64 timestamp = 2.
+				ts = int64(v)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+
+		p, err := models.NewPoint(name, tags, models.Fields{prometheusValueField: value}, time.Unix(0, ts*int64(time.Millisecond)).UTC())
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
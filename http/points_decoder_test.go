@@ -0,0 +1,153 @@
+package http
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestJSONPointsDecoder(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		precision string
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:      "points with integer timestamps",
+			data:      `[{"measurement": "cpu", "tags": {"host": "a b"}, "fields": {"usage": 1, "model": "x86", "ok": true}, "time": 1590000000}]`,
+			precision: "s",
+			want:      []string{`cpu,host=a\ b model="x86",ok=true,usage=1 1590000000000000000`},
+		},
+		{
+			name:      "points with RFC3339 or missing times",
+			data:      `[{"measurement": "cpu", "fields": {"usage": 1.5}, "time": "2020-05-20T18:40:00Z"}, {"measurement": "mem", "fields": {"used": 2}}]`,
+			precision: "ns",
+			want:      []string{"cpu usage=1.5 1590000000000000000", "mem used=2"},
+		},
+		{
+			name:    "not an array",
+			data:    `{"measurement": "cpu"}`,
+			wantErr: "unable to decode JSON points: json: cannot unmarshal object into Go value of type []http.jsonPoint",
+		},
+		{
+			name:    "missing measurement",
+			data:    `[{"fields": {"usage": 1}}]`,
+			wantErr: "unable to decode JSON point 1: missing measurement",
+		},
+		{
+			name:    "missing fields",
+			data:    `[{"measurement": "cpu", "fields": {"usage": 1}}, {"measurement": "cpu"}]`,
+			wantErr: "unable to decode JSON point 2: point without fields is unsupported",
+		},
+		{
+			name:    "unsupported field",
+			data:    `[{"measurement": "cpu", "fields": {"usage": [1]}}]`,
+			wantErr: `unable to decode JSON point 1: field "usage" is neither a number, a string nor a boolean`,
+		},
+		{
+			name:    "invalid time",
+			data:    `[{"measurement": "cpu", "fields": {"usage": 1}, "time": "yesterday"}]`,
+			wantErr: `unable to decode JSON point 1: invalid time "yesterday": parsing time "yesterday" as "2006-01-02T15:04:05.999999999Z07:00": cannot parse "yesterday" as "2006"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := JSONPointsDecoder{}.DecodePoints([]byte(tt.data), tt.precision)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("unexpected error: got %v want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, p := range points {
+				got = append(got, p.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("unexpected points:\ngot  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrometheusPointsDecoder(t *testing.T) {
+	data := prometheusWriteRequest(
+		prometheusSeries{
+			labels:  []string{"__name__", "http_requests_total", "job", "api", "code", "200"},
+			samples: []prometheusSample{{value: 3, ts: 1590000000000}, {value: math.NaN(), ts: 1590000015000}, {value: 5, ts: 1590000030000}},
+		},
+		prometheusSeries{
+			labels:  []string{"__name__", "up"},
+			samples: []prometheusSample{{value: 1, ts: 1590000000123}},
+		},
+	)
+
+	points, err := PrometheusPointsDecoder{}.DecodePoints(data, "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"http_requests_total,code=200,job=api value=3 1590000000000000000",
+		"http_requests_total,code=200,job=api value=5 1590000030000000000",
+		"up value=1 1590000000123000000",
+	}
+	var got []string
+	for _, p := range points {
+		got = append(got, p.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected points:\ngot  %q\nwant %q", got, want)
+	}
+
+	t.Run("series without name", func(t *testing.T) {
+		data := prometheusWriteRequest(prometheusSeries{labels: []string{"job", "api"}, samples: []prometheusSample{{value: 1}}})
+		_, err := PrometheusPointsDecoder{}.DecodePoints(data, "ns")
+		if want := "unable to decode Prometheus remote write request: series without the __name__ label"; err == nil || err.Error() != want {
+			t.Errorf("unexpected error: got %v want %s", err, want)
+		}
+	})
+
+	t.Run("truncated request", func(t *testing.T) {
+		_, err := PrometheusPointsDecoder{}.DecodePoints(data[:len(data)-3], "ns")
+		if want := "unable to decode Prometheus remote write request: truncated protobuf message"; err == nil || err.Error() != want {
+			t.Errorf("unexpected error: got %v want %s", err, want)
+		}
+	})
+}
+
+type prometheusSeries struct {
+	labels  []string
+	samples []prometheusSample
+}
+
+type prometheusSample struct {
+	value float64
+	ts    int64
+}
+
+// prometheusWriteRequest encodes the protobuf WriteRequest of the series.
+func prometheusWriteRequest(series ...prometheusSeries) []byte {
+	var req []byte
+	for _, s := range series {
+		var ts []byte
+		for i := 0; i < len(s.labels); i += 2 {
+			var label []byte
+			label = appendProtobufBytes(label, 1, []byte(s.labels[i]))
+			label = appendProtobufBytes(label, 2, []byte(s.labels[i+1]))
+			ts = appendProtobufBytes(ts, 1, label)
+		}
+		for _, sample := range s.samples {
+			var b []byte
+			b = appendProtobufDouble(b, 1, sample.value)
+			b = appendProtobufVarint(b, 2, uint64(sample.ts))
+			ts = appendProtobufBytes(ts, 2, b)
+		}
+		req = appendProtobufBytes(req, 1, ts)
+	}
+	return req
+}
@@ -0,0 +1,555 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixPrometheus         = "/api/v1"
+	prometheusQueryPath      = prefixPrometheus + "/query"
+	prometheusQueryRangePath = prefixPrometheus + "/query_range"
+	prometheusRemoteReadPath = prefixPrometheus + "/prom/read"
+
+	opPrometheusHandler = "http/prometheusHandler"
+
+	// prometheusBucketHeader names or identifies the bucket of a request.
+	prometheusBucketHeader = "X-Influxdb-Bucket"
+	// prometheusMaxPoints is the maximum number of steps of a range query,
+	// as limited by Prometheus.
+	prometheusMaxPoints = 11000
+	// prometheusMaxReadRequest is the maximum size of an encoded remote
+	// read request.
+	prometheusMaxReadRequest = 1 << 20
+)
+
+// Error types of the Prometheus HTTP API.
+const (
+	prometheusErrorBadData     = "bad_data"
+	prometheusErrorExecution   = "execution"
+	prometheusErrorInternal    = "internal"
+	prometheusErrorNotFound    = "not_found"
+	prometheusErrorUnavailable = "unavailable"
+)
+
+// PrometheusBackend is all services and associated parameters required to
+// construct the PrometheusHandler.
+type PrometheusBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	PromQLService       influxdb.PromQLService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+	DBRPMappingService  influxdb.DBRPMappingServiceV2
+}
+
+// NewPrometheusBackend returns a new instance of PrometheusBackend.
+func NewPrometheusBackend(log *zap.Logger, b *APIBackend) *PrometheusBackend {
+	return &PrometheusBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		PromQLService:       b.PromQLService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+		DBRPMappingService:  b.DBRPService,
+	}
+}
+
+// PrometheusHandler serves the Prometheus compatible endpoints: the PromQL
+// queries of the Prometheus HTTP API, as used by the Prometheus datasource of
+// Grafana, and the remote reads of the Prometheus remote storage API.
+//
+// The bucket is either named, or identified, by the X-Influxdb-Bucket header
+// or the bucket parameter, or mapped from the db and rp parameters as by the
+// influxdb 1.x compatible endpoints.
+type PrometheusHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	Now                 func() time.Time
+	PromQLService       influxdb.PromQLService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+	DBRPMappingService  influxdb.DBRPMappingServiceV2
+}
+
+// Prefix provides the route prefix.
+func (*PrometheusHandler) Prefix() string {
+	return prefixPrometheus
+}
+
+// NewPrometheusHandler returns a new handler of the Prometheus compatible
+// endpoints.
+func NewPrometheusHandler(log *zap.Logger, b *PrometheusBackend) *PrometheusHandler {
+	h := &PrometheusHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		Now:              time.Now,
+
+		PromQLService:       b.PromQLService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+		DBRPMappingService:  b.DBRPMappingService,
+	}
+
+	h.HandlerFunc(http.MethodGet, prometheusQueryPath, h.handleQuery)
+	h.HandlerFunc(http.MethodPost, prometheusQueryPath, h.handleQuery)
+	h.HandlerFunc(http.MethodGet, prometheusQueryRangePath, h.handleQueryRange)
+	h.HandlerFunc(http.MethodPost, prometheusQueryRangePath, h.handleQueryRange)
+	h.HandlerFunc(http.MethodPost, prometheusRemoteReadPath, h.handleRemoteRead)
+	return h
+}
+
+// handleQuery evaluates the PromQL query of the request at its time, now by
+// default.
+func (h *PrometheusHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PrometheusHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	q, err := h.decodeQuery(r, false)
+	if err != nil {
+		h.handlePrometheusError(ctx, err, w)
+		return
+	}
+	h.query(ctx, w, r, q)
+}
+
+// handleQueryRange evaluates the PromQL query of the request at every step
+// from its start to its end.
+func (h *PrometheusHandler) handleQueryRange(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PrometheusHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	q, err := h.decodeQuery(r, true)
+	if err != nil {
+		h.handlePrometheusError(ctx, err, w)
+		return
+	}
+	h.query(ctx, w, r, q)
+}
+
+func (h *PrometheusHandler) query(ctx context.Context, w http.ResponseWriter, r *http.Request, q influxdb.PromQLQuery) {
+	bucket, err := h.findBucket(ctx, r)
+	if err != nil {
+		h.handlePrometheusError(ctx, err, w)
+		return
+	}
+
+	res, err := h.PromQLService.QueryPromQL(ctx, bucket.OrgID, bucket.ID, q)
+	if err != nil {
+		h.handlePrometheusError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newPromQLResponse(res)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// decodeQuery decodes the PromQL query of the URL or form parameters of r.
+func (h *PrometheusHandler) decodeQuery(r *http.Request, ranged bool) (influxdb.PromQLQuery, error) {
+	q := influxdb.PromQLQuery{Query: r.FormValue("query")}
+	if q.Query == "" {
+		return q, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opPrometheusHandler,
+			Msg:  "query is required",
+		}
+	}
+
+	if !ranged {
+		t, err := parsePrometheusTime(r.FormValue("time"), h.Now().UTC())
+		if err != nil {
+			return q, err
+		}
+		q.Start, q.Stop = t, t
+		return q, nil
+	}
+
+	var err error
+	if q.Start, err = parsePrometheusTime(r.FormValue("start"), time.Time{}); err != nil {
+		return q, err
+	}
+	if q.Stop, err = parsePrometheusTime(r.FormValue("end"), time.Time{}); err != nil {
+		return q, err
+	}
+	if q.Start.IsZero() || q.Stop.IsZero() {
+		return q, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opPrometheusHandler,
+			Msg:  "start and end are required",
+		}
+	}
+	if q.Stop.Before(q.Start) {
+		return q, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opPrometheusHandler,
+			Msg:  "end timestamp must not be before start time",
+		}
+	}
+
+	if q.Step, err = parsePrometheusDuration(r.FormValue("step")); err != nil {
+		return q, err
+	}
+	if q.Step <= 0 {
+		return q, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opPrometheusHandler,
+			Msg:  "zero or negative query resolution step widths are not accepted; try a positive integer",
+		}
+	}
+	if q.Stop.Sub(q.Start)/q.Step > prometheusMaxPoints {
+		return q, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opPrometheusHandler,
+			Msg:  fmt.Sprintf("exceeded maximum resolution of %d points per timeseries; try decreasing the query resolution (?step=XX)", prometheusMaxPoints),
+		}
+	}
+	return q, nil
+}
+
+// parsePrometheusTime parses a time of the Prometheus HTTP API, in seconds
+// since the epoch or in RFC3339, returning def when s is empty.
+func parsePrometheusTime(s string, def time.Time) (time.Time, error) {
+	if s == "" {
+		return def, nil
+	}
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		whole, frac := math.Modf(secs)
+		return time.Unix(int64(whole), int64(math.Round(frac*1000))*int64(time.Millisecond)).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opPrometheusHandler,
+			Msg:  fmt.Sprintf("cannot parse %q to a valid timestamp", s),
+		}
+	}
+	return t.UTC(), nil
+}
+
+// parsePrometheusDuration parses a duration of the Prometheus HTTP API, in
+// seconds or as a duration literal.
+func parsePrometheusDuration(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opPrometheusHandler,
+			Msg:  fmt.Sprintf("cannot parse %q to a valid duration", s),
+		}
+	}
+	return d, nil
+}
+
+// findBucket returns the bucket of the request, given by the bucket header or
+// parameter, or else mapped from its db and rp parameters.
+func (h *PrometheusHandler) findBucket(ctx context.Context, r *http.Request) (*influxdb.Bucket, error) {
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := r.Header.Get(prometheusBucketHeader)
+	if bucket == "" {
+		bucket = r.URL.Query().Get(Bucket)
+	}
+	if bucket == "" {
+		db := r.URL.Query().Get("db")
+		if db == "" {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   opPrometheusHandler,
+				Msg:  fmt.Sprintf("bucket is required, set it with the %s header or map it with the db and rp parameters", prometheusBucketHeader),
+			}
+		}
+		mapping, err := findLegacyMapping(ctx, h.DBRPMappingService, auth, db, r.URL.Query().Get("rp"))
+		if err != nil {
+			return nil, err
+		}
+		return &influxdb.Bucket{ID: mapping.BucketID, OrgID: mapping.OrganizationID}, nil
+	}
+
+	var orgID influxdb.ID
+	if a, ok := auth.(*influxdb.Authorization); ok && r.URL.Query().Get(Org) == "" && r.URL.Query().Get(OrgID) == "" {
+		orgID = a.OrgID
+	} else {
+		org, err := queryOrganization(ctx, r, h.OrganizationService)
+		if err != nil {
+			return nil, err
+		}
+		orgID = org.ID
+	}
+
+	if id, err := influxdb.IDFromString(bucket); err == nil {
+		b, err := h.BucketService.FindBucket(ctx, influxdb.BucketFilter{
+			OrganizationID: &orgID,
+			ID:             id,
+		})
+		if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return nil, err
+		} else if err == nil {
+			return b, nil
+		}
+	}
+	return h.BucketService.FindBucket(ctx, influxdb.BucketFilter{
+		OrganizationID: &orgID,
+		Name:           &bucket,
+	})
+}
+
+// handlePrometheusError writes err in the error format of the Prometheus HTTP
+// API.
+func (h *PrometheusHandler) handlePrometheusError(ctx context.Context, err error, w http.ResponseWriter) {
+	code := influxdb.ErrorCode(err)
+	typ := prometheusErrorExecution
+	switch code {
+	case influxdb.EInvalid, influxdb.EUnprocessableEntity:
+		typ = prometheusErrorBadData
+	case influxdb.ENotFound:
+		typ = prometheusErrorNotFound
+	case influxdb.EUnavailable, influxdb.ETooManyRequests:
+		typ = prometheusErrorUnavailable
+	case influxdb.EInternal:
+		typ = prometheusErrorInternal
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set(kithttp.PlatformErrorCodeHeader, code)
+	w.WriteHeader(kithttp.ErrorCodeToStatusCode(ctx, code))
+	_ = json.NewEncoder(w).Encode(promQLResponse{
+		Status:    "error",
+		ErrorType: typ,
+		Error:     err.Error(),
+	})
+}
+
+type promQLResponse struct {
+	Status    string            `json:"status"`
+	Data      *promQLResultData `json:"data,omitempty"`
+	ErrorType string            `json:"errorType,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+type promQLResultData struct {
+	ResultType string         `json:"resultType"`
+	Result     []promQLSeries `json:"result"`
+}
+
+type promQLSeries struct {
+	Metric map[string]string `json:"metric"`
+	Value  *promQLSample     `json:"value,omitempty"`
+	Values []promQLSample    `json:"values,omitempty"`
+}
+
+// promQLSample is encoded as a pair of its time in seconds and its value
+// formatted as a string.
+type promQLSample influxdb.PromSample
+
+// MarshalJSON implements json.Marshaler.
+func (s promQLSample) MarshalJSON() ([]byte, error) {
+	ms := s.Time.UnixNano() / int64(time.Millisecond)
+	secs := strconv.FormatFloat(float64(ms)/1000, 'f', -1, 64)
+	return []byte(fmt.Sprintf("[%s,%q]", secs, strconv.FormatFloat(s.Value, 'f', -1, 64))), nil
+}
+
+func newPromQLResponse(res *influxdb.PromQLResult) promQLResponse {
+	data := &promQLResultData{
+		ResultType: res.Type,
+		Result:     make([]promQLSeries, 0, len(res.Series)),
+	}
+	for _, s := range res.Series {
+		ps := promQLSeries{Metric: s.Labels}
+		if ps.Metric == nil {
+			ps.Metric = map[string]string{}
+		}
+		if res.Type == influxdb.PromQLVector {
+			if len(s.Samples) == 0 {
+				continue
+			}
+			sample := promQLSample(s.Samples[len(s.Samples)-1])
+			ps.Value = &sample
+		} else {
+			ps.Values = make([]promQLSample, 0, len(s.Samples))
+			for _, sample := range s.Samples {
+				ps.Values = append(ps.Values, promQLSample(sample))
+			}
+		}
+		data.Result = append(data.Result, ps)
+	}
+	return promQLResponse{Status: "success", Data: data}
+}
+
+// handleRemoteRead serves the snappy encoded protobuf ReadRequest of a
+// Prometheus remote read with the series of the bucket matching its queries.
+func (h *PrometheusHandler) handleRemoteRead(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PrometheusHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	queries, err := decodePrometheusReadRequest(r.Body)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	bucket, err := h.findBucket(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// ReadResponse: repeated QueryResult results = 1.
+	var resp []byte
+	for _, q := range queries {
+		series, err := h.PromQLService.ReadPromSeries(ctx, bucket.OrgID, bucket.ID, q.matchers, q.start, q.stop)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		resp = appendProtobufBytes(resp, 1, encodePrometheusQueryResult(series))
+	}
+
+	w.Header().Set("Content-Type", contentTypeProtobuf)
+	w.Header().Set("Content-Encoding", "snappy")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(snappy.Encode(nil, resp)); err != nil {
+		logEncodingError(h.log, r, err)
+	}
+}
+
+// prometheusReadQuery is a query of a Prometheus remote read.
+type prometheusReadQuery struct {
+	start, stop time.Time
+	matchers    []influxdb.PromLabelMatcher
+}
+
+// decodePrometheusReadRequest decodes the queries of the snappy encoded
+// protobuf ReadRequest of body.
+func decodePrometheusReadRequest(body io.Reader) ([]prometheusReadQuery, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(body, prometheusMaxReadRequest+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > prometheusMaxReadRequest {
+		return nil, &influxdb.Error{
+			Code: influxdb.ETooLarge,
+			Op:   opPrometheusHandler,
+			Msg:  "remote read request is too large",
+		}
+	}
+	if data, err = snappy.Decode(nil, data); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opPrometheusHandler,
+			Msg:  "unable to decode snappy encoded remote read request",
+			Err:  err,
+		}
+	}
+
+	var queries []prometheusReadQuery
+	err = forEachProtobufField(data, func(num int, _ uint64, b []byte) error {
+		// ReadRequest: repeated Query queries = 1.
+		if num != 1 {
+			return nil
+		}
+		var q prometheusReadQuery
+		err := forEachProtobufField(b, func(num int, v uint64, b []byte) error {
+			switch num {
+			case 1: // int64 start_timestamp_ms = 1.
+				q.start = time.Unix(0, int64(v)*int64(time.Millisecond)).UTC()
+			case 2: // int64 end_timestamp_ms = 2.
+				q.stop = time.Unix(0, int64(v)*int64(time.Millisecond)).UTC()
+			case 3: // repeated LabelMatcher matchers = 3.
+				var m influxdb.PromLabelMatcher
+				err := forEachProtobufField(b, func(num int, v uint64, b []byte) error {
+					switch num {
+					case 1:
+						m.Type = influxdb.PromMatchType(v)
+					case 2:
+						m.Name = string(b)
+					case 3:
+						m.Value = string(b)
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				q.matchers = append(q.matchers, m)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		queries = append(queries, q)
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opPrometheusHandler,
+			Msg:  "unable to decode remote read request",
+			Err:  err,
+		}
+	}
+	return queries, nil
+}
+
+// encodePrometheusQueryResult encodes the protobuf QueryResult of the series.
+func encodePrometheusQueryResult(series []*influxdb.PromSeries) []byte {
+	var result []byte
+	for _, s := range series {
+		names := make([]string, 0, len(s.Labels))
+		for name := range s.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		// TimeSeries: repeated Label labels = 1, repeated Sample samples = 2.
+		var ts []byte
+		for _, name := range names {
+			var label []byte
+			label = appendProtobufBytes(label, 1, []byte(name))
+			label = appendProtobufBytes(label, 2, []byte(s.Labels[name]))
+			ts = appendProtobufBytes(ts, 1, label)
+		}
+		for _, sample := range s.Samples {
+			var b []byte
+			b = appendProtobufDouble(b, 1, sample.Value)
+			b = appendProtobufVarint(b, 2, uint64(sample.Time.UnixNano()/int64(time.Millisecond)))
+			ts = appendProtobufBytes(ts, 2, b)
+		}
+		// QueryResult: repeated TimeSeries timeseries = 1.
+		result = appendProtobufBytes(result, 1, ts)
+	}
+	return result
+}
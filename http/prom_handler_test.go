@@ -0,0 +1,240 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb/v2"
+	httpmock "github.com/influxdata/influxdb/v2/http/mock"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestPrometheusHandler(t *testing.T, promql influxdb.PromQLService, orgID, bucketID influxdb.ID) http.Handler {
+	buckets := mock.NewBucketService()
+	buckets.FindBucketFn = func(ctx context.Context, f influxdb.BucketFilter) (*influxdb.Bucket, error) {
+		if f.OrganizationID == nil || *f.OrganizationID != orgID || f.Name == nil || *f.Name != "prometheus" {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+		}
+		return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: *f.Name}, nil
+	}
+	dbrps := &mock.DBRPMappingServiceV2{
+		FindManyFn: func(ctx context.Context, f influxdb.DBRPMappingFilterV2, opts ...influxdb.FindOptions) ([]*influxdb.DBRPMappingV2, int, error) {
+			if f.Database == nil || *f.Database != "prometheus" {
+				return nil, 0, nil
+			}
+			return []*influxdb.DBRPMappingV2{legacyMapping(orgID.String(), bucketID.String(), "prometheus", "autogen")}, 1, nil
+		},
+	}
+
+	b := &APIBackend{
+		HTTPErrorHandler: DefaultErrorHandler,
+		Logger:           zaptest.NewLogger(t),
+		PromQLService:    promql,
+		BucketService:    buckets,
+		DBRPService:      dbrps,
+	}
+	h := NewPrometheusHandler(zaptest.NewLogger(t), NewPrometheusBackend(zaptest.NewLogger(t), b))
+	h.Now = func() time.Time { return time.Unix(1590000100, 0) }
+	return httpmock.NewAuthMiddlewareHandler(h, &influxdb.Authorization{OrgID: orgID})
+}
+
+func TestPrometheusHandler_handleQuery(t *testing.T) {
+	orgID, bucketID := influxdb.ID(0x043e0780ee2b1000), influxdb.ID(0x04504b356e23b000)
+	t0 := time.Unix(1590000000, 0).UTC()
+
+	tests := []struct {
+		name      string
+		url       string
+		bucket    string
+		wantQuery influxdb.PromQLQuery
+		code      int
+		want      string
+	}{
+		{
+			name:      "instant query of the bucket of the header",
+			url:       "/api/v1/query?query=up",
+			bucket:    "prometheus",
+			wantQuery: influxdb.PromQLQuery{Query: "up", Start: time.Unix(1590000100, 0).UTC(), Stop: time.Unix(1590000100, 0).UTC()},
+			code:      http.StatusOK,
+			want:      `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"up","job":"api"},"value":[1590000000,"1"]}]}}`,
+		},
+		{
+			name:      "range query of the bucket mapped from the database",
+			url:       "/api/v1/query_range?query=up&start=1590000000&end=2020-05-20T18:41:00.5Z&step=30&db=prometheus",
+			wantQuery: influxdb.PromQLQuery{Query: "up", Start: t0, Stop: t0.Add(time.Minute + 500*time.Millisecond), Step: 30 * time.Second},
+			code:      http.StatusOK,
+			want:      `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up","job":"api"},"values":[[1590000000,"1"],[1590000030.5,"0.25"]]}]}}`,
+		},
+		{
+			name: "missing bucket",
+			url:  "/api/v1/query?query=up",
+			code: http.StatusBadRequest,
+			want: `{"status":"error","errorType":"bad_data","error":"bucket is required, set it with the X-Influxdb-Bucket header or map it with the db and rp parameters"}`,
+		},
+		{
+			name: "unknown database",
+			url:  "/api/v1/query?query=up&db=telegraf",
+			code: http.StatusNotFound,
+			want: `{"status":"error","errorType":"not_found","error":"no dbrp mapping found for database \"telegraf\""}`,
+		},
+		{
+			name:   "invalid step",
+			url:    "/api/v1/query_range?query=up&start=1590000000&end=1590000060&step=0",
+			bucket: "prometheus",
+			code:   http.StatusBadRequest,
+			want:   `{"status":"error","errorType":"bad_data","error":"zero or negative query resolution step widths are not accepted; try a positive integer"}`,
+		},
+		{
+			name:   "too many points",
+			url:    "/api/v1/query_range?query=up&start=1590000000&end=1600000000&step=1s",
+			bucket: "prometheus",
+			code:   http.StatusBadRequest,
+			want:   `{"status":"error","errorType":"bad_data","error":"exceeded maximum resolution of 11000 points per timeseries; try decreasing the query resolution (?step=XX)"}`,
+		},
+		{
+			name:   "invalid query",
+			url:    "/api/v1/query?query=sum(&time=1590000000",
+			bucket: "prometheus",
+			code:   http.StatusBadRequest,
+			want:   `{"status":"error","errorType":"bad_data","error":"unable to evaluate PromQL query: parse error"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promql := mock.NewPromQLService()
+			promql.QueryPromQLFn = func(ctx context.Context, gotOrgID, gotBucketID influxdb.ID, q influxdb.PromQLQuery) (*influxdb.PromQLResult, error) {
+				if gotOrgID != orgID || gotBucketID != bucketID {
+					t.Errorf("unexpected bucket %s of org %s", gotBucketID, gotOrgID)
+				}
+				if q.Query == "sum(" {
+					return nil, &influxdb.Error{Code: influxdb.EInvalid, Msg: "unable to evaluate PromQL query", Err: errors.New("parse error")}
+				}
+				if q != tt.wantQuery {
+					t.Errorf("unexpected query: got %+v want %+v", q, tt.wantQuery)
+				}
+				labels := map[string]string{"__name__": "up", "job": "api"}
+				if q.Step == 0 {
+					return &influxdb.PromQLResult{
+						Type:   influxdb.PromQLVector,
+						Series: []*influxdb.PromSeries{{Labels: labels, Samples: []influxdb.PromSample{{Time: t0, Value: 1}}}},
+					}, nil
+				}
+				return &influxdb.PromQLResult{
+					Type: influxdb.PromQLMatrix,
+					Series: []*influxdb.PromSeries{{Labels: labels, Samples: []influxdb.PromSample{
+						{Time: t0, Value: 1},
+						{Time: t0.Add(30*time.Second + 500*time.Millisecond), Value: 0.25},
+					}}},
+				}, nil
+			}
+
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.bucket != "" {
+				r.Header.Set(prometheusBucketHeader, tt.bucket)
+			}
+			w := httptest.NewRecorder()
+			newTestPrometheusHandler(t, promql, orgID, bucketID).ServeHTTP(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("unexpected status code: got %d want %d", w.Code, tt.code)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tt.want {
+				t.Errorf("unexpected body:\ngot  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrometheusHandler_handleRemoteRead(t *testing.T) {
+	orgID, bucketID := influxdb.ID(0x043e0780ee2b1000), influxdb.ID(0x04504b356e23b000)
+
+	// ReadRequest of a query of the http_requests_total series with a job
+	// label matching api.*
+	var query []byte
+	query = appendProtobufVarint(query, 1, 1590000000000)
+	query = appendProtobufVarint(query, 2, 1590000060000)
+	for _, m := range []influxdb.PromLabelMatcher{
+		{Type: influxdb.PromMatchEqual, Name: "__name__", Value: "http_requests_total"},
+		{Type: influxdb.PromMatchRegexp, Name: "job", Value: "api.*"},
+	} {
+		var b []byte
+		b = appendProtobufVarint(b, 1, uint64(m.Type))
+		b = appendProtobufBytes(b, 2, []byte(m.Name))
+		b = appendProtobufBytes(b, 3, []byte(m.Value))
+		query = appendProtobufBytes(query, 3, b)
+	}
+	req := appendProtobufBytes(nil, 1, query)
+
+	promql := mock.NewPromQLService()
+	promql.ReadPromSeriesFn = func(ctx context.Context, gotOrgID, gotBucketID influxdb.ID, matchers []influxdb.PromLabelMatcher, start, stop time.Time) ([]*influxdb.PromSeries, error) {
+		if gotOrgID != orgID || gotBucketID != bucketID {
+			t.Errorf("unexpected bucket %s of org %s", gotBucketID, gotOrgID)
+		}
+		if len(matchers) != 2 || matchers[1] != (influxdb.PromLabelMatcher{Type: influxdb.PromMatchRegexp, Name: "job", Value: "api.*"}) {
+			t.Errorf("unexpected matchers %+v", matchers)
+		}
+		if !start.Equal(time.Unix(1590000000, 0)) || !stop.Equal(time.Unix(1590000060, 0)) {
+			t.Errorf("unexpected time range %s - %s", start, stop)
+		}
+		return []*influxdb.PromSeries{{
+			Labels:  map[string]string{"__name__": "http_requests_total", "job": "api", "code": "200"},
+			Samples: []influxdb.PromSample{{Time: time.Unix(1590000000, 0), Value: 3}, {Time: time.Unix(1590000030, 0), Value: 5}},
+		}}, nil
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/prom/read", bytes.NewReader(snappy.Encode(nil, req)))
+	r.Header.Set(prometheusBucketHeader, "prometheus")
+	w := httptest.NewRecorder()
+	newTestPrometheusHandler(t, promql, orgID, bucketID).ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "snappy" {
+		t.Errorf("unexpected content encoding %q", got)
+	}
+	body, _ := ioutil.ReadAll(w.Body)
+	resp, err := snappy.Decode(nil, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The QueryResult messages are encoded as the WriteRequest messages.
+	var got []string
+	err = forEachProtobufField(resp, func(num int, _ uint64, b []byte) error {
+		points, err := PrometheusPointsDecoder{}.DecodePoints(b, "ms")
+		for _, p := range points {
+			got = append(got, p.String())
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"http_requests_total,code=200,job=api value=3 1590000000000000000",
+		"http_requests_total,code=200,job=api value=5 1590000030000000000",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected series:\ngot  %q\nwant %q", got, want)
+	}
+
+	t.Run("invalid request", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/prom/read", strings.NewReader("not snappy"))
+		r.Header.Set(prometheusBucketHeader, "prometheus")
+		w := httptest.NewRecorder()
+		newTestPrometheusHandler(t, promql, orgID, bucketID).ServeHTTP(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("unexpected status code: got %d want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+}
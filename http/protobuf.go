@@ -0,0 +1,87 @@
+package http
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The Prometheus remote storage messages are handled with these helpers
+// rather than generated code, as only a handful of their fields are used.
+
+var errProtobufTruncated = errors.New("truncated protobuf message")
+
+// forEachProtobufField calls fn with the number and the value of every field
+// of the encoded protobuf message msg: v holds the varint and fixed size
+// values, b the length delimited ones.
+func forEachProtobufField(msg []byte, fn func(num int, v uint64, b []byte) error) error {
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return errProtobufTruncated
+		}
+		msg = msg[n:]
+
+		var (
+			v uint64
+			b []byte
+		)
+		switch wire := key & 7; wire {
+		case 0: // varint
+			if v, n = binary.Uvarint(msg); n <= 0 {
+				return errProtobufTruncated
+			}
+			msg = msg[n:]
+		case 1: // 64-bit
+			if len(msg) < 8 {
+				return errProtobufTruncated
+			}
+			v, msg = binary.LittleEndian.Uint64(msg), msg[8:]
+		case 2: // length delimited
+			l, n := binary.Uvarint(msg)
+			if n <= 0 || l > uint64(len(msg)-n) {
+				return errProtobufTruncated
+			}
+			b, msg = msg[n:n+int(l)], msg[n+int(l):]
+		case 5: // 32-bit
+			if len(msg) < 4 {
+				return errProtobufTruncated
+			}
+			v, msg = uint64(binary.LittleEndian.Uint32(msg)), msg[4:]
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", wire)
+		}
+
+		if err := fn(int(key>>3), v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendProtobufVarint appends the varint field num of value v to b.
+func appendProtobufVarint(b []byte, num int, v uint64) []byte {
+	b = appendUvarint(b, uint64(num)<<3|0)
+	return appendUvarint(b, v)
+}
+
+// appendProtobufDouble appends the double field num of value v to b.
+func appendProtobufDouble(b []byte, num int, v float64) []byte {
+	b = appendUvarint(b, uint64(num)<<3|1)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+	return append(b, buf[:]...)
+}
+
+// appendProtobufBytes appends the length delimited field num of value v to b.
+func appendProtobufBytes(b []byte, num int, v []byte) []byte {
+	b = appendUvarint(b, uint64(num)<<3|2)
+	b = appendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
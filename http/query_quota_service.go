@@ -0,0 +1,157 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"go.uber.org/zap"
+)
+
+// QueryQuotaBackend is all services and associated parameters required to construct the QueryQuotaHandler.
+type QueryQuotaBackend struct {
+	Logger *zap.Logger
+	influxdb.HTTPErrorHandler
+
+	QueryQuotaService influxdb.QueryQuotaService
+}
+
+// NewQueryQuotaBackend returns a new instance of QueryQuotaBackend.
+func NewQueryQuotaBackend(b *APIBackend) *QueryQuotaBackend {
+	return &QueryQuotaBackend{
+		Logger: b.Logger.With(zap.String("handler", "query_quota")),
+
+		HTTPErrorHandler:  b.HTTPErrorHandler,
+		QueryQuotaService: b.QueryQuotaService,
+	}
+}
+
+// QueryQuotaHandler is http handler for query quota service.
+type QueryQuotaHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	QueryQuotaService influxdb.QueryQuotaService
+}
+
+const prefixQueryQuotas = "/api/v2/queryQuotas"
+
+// NewQueryQuotaHandler creates a new handler at /api/v2/queryQuotas to manage
+// the query quotas of the organizations.
+func NewQueryQuotaHandler(b *QueryQuotaBackend) *QueryQuotaHandler {
+	h := &QueryQuotaHandler{
+		HTTPErrorHandler:  b.HTTPErrorHandler,
+		Router:            NewRouter(b.HTTPErrorHandler),
+		Logger:            b.Logger,
+		QueryQuotaService: b.QueryQuotaService,
+	}
+
+	h.HandlerFunc(http.MethodGet, prefixQueryQuotas, h.handleGetQueryQuota)
+	h.HandlerFunc(http.MethodPut, prefixQueryQuotas, h.handlePutQueryQuota)
+	h.HandlerFunc(http.MethodDelete, prefixQueryQuotas, h.handleDeleteQueryQuota)
+
+	return h
+}
+
+// handleGetQueryQuota returns the query quota of the organization identified
+// by the orgID parameter.
+func (h *QueryQuotaHandler) handleGetQueryQuota(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "QueryQuotaHandler.handleGetQueryQuota")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	orgID, err := decodeQueryQuotaOrgID(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	q, err := h.QueryQuotaService.FindQueryQuota(ctx, orgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, q); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePutQueryQuota sets the query quota of the organization identified
+// by the orgID parameter.
+func (h *QueryQuotaHandler) handlePutQueryQuota(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "QueryQuotaHandler.handlePutQueryQuota")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	orgID, err := decodeQueryQuotaOrgID(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var q influxdb.QueryQuota
+	if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+	q.OrgID = orgID
+
+	if err := h.QueryQuotaService.SetQueryQuota(ctx, &q); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("Query quota set", zap.String("orgID", orgID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, &q); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteQueryQuota removes the query quota of the organization
+// identified by the orgID parameter.
+func (h *QueryQuotaHandler) handleDeleteQueryQuota(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "QueryQuotaHandler.handleDeleteQueryQuota")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	orgID, err := decodeQueryQuotaOrgID(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.QueryQuotaService.DeleteQueryQuota(ctx, orgID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("Query quota deleted", zap.String("orgID", orgID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeQueryQuotaOrgID extracts the required orgID parameter of a query
+// quota request.
+func decodeQueryQuotaOrgID(r *http.Request) (influxdb.ID, error) {
+	var orgID influxdb.ID
+	if err := orgID.DecodeFromString(r.URL.Query().Get("orgID")); err != nil {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid orgID",
+			Err:  err,
+		}
+	}
+	return orgID, nil
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxtesting "github.com/influxdata/influxdb/v2/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestQueryQuotaHandler(t *testing.T) {
+	orgID := influxtesting.MustIDBase16("020f755c3c082000")
+
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		body   string
+		code   int
+		want   string
+	}{
+		{
+			name:   "get query quota",
+			method: http.MethodGet,
+			query:  "orgID=020f755c3c082000",
+			code:   http.StatusOK,
+			want:   `{"orgID":"020f755c3c082000","maxConcurrency":2,"maxQueueSize":0,"maxMemoryBytes":0,"maxExecutionTime":"1m0s"}`,
+		},
+		{
+			name:   "set query quota",
+			method: http.MethodPut,
+			query:  "orgID=020f755c3c082000",
+			body:   `{"orgID":"020f755c3c082001","maxConcurrency":4,"maxMemoryBytes":1048576,"maxExecutionTime":"30s"}`,
+			code:   http.StatusOK,
+			want:   `{"orgID":"020f755c3c082000","maxConcurrency":4,"maxQueueSize":0,"maxMemoryBytes":1048576,"maxExecutionTime":"30s"}`,
+		},
+		{
+			name:   "set negative query quota",
+			method: http.MethodPut,
+			query:  "orgID=020f755c3c082000",
+			body:   `{"maxConcurrency":-1}`,
+			code:   http.StatusBadRequest,
+			want:   `{"code":"invalid","message":"query quota limits must not be negative"}`,
+		},
+		{
+			name:   "set invalid query quota",
+			method: http.MethodPut,
+			query:  "orgID=020f755c3c082000",
+			body:   `{"maxExecutionTime":"forever"}`,
+			code:   http.StatusBadRequest,
+			want:   `{"code":"invalid","message":"invalid json structure: time: invalid duration \"forever\""}`,
+		},
+		{
+			name:   "delete query quota",
+			method: http.MethodDelete,
+			query:  "orgID=020f755c3c082000",
+			code:   http.StatusNoContent,
+		},
+		{
+			name:   "missing org",
+			method: http.MethodGet,
+			code:   http.StatusBadRequest,
+			want:   `{"code":"invalid","message":"invalid orgID: id must have a length of 16 bytes"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewQueryQuotaService()
+			svc.FindQueryQuotaFn = func(ctx context.Context, id influxdb.ID) (*influxdb.QueryQuota, error) {
+				if id != orgID {
+					t.Errorf("unexpected org ID %s", id)
+				}
+				return &influxdb.QueryQuota{
+					OrgID:            id,
+					MaxConcurrency:   2,
+					MaxExecutionTime: influxdb.Duration{Duration: time.Minute},
+				}, nil
+			}
+			svc.SetQueryQuotaFn = func(ctx context.Context, q *influxdb.QueryQuota) error {
+				if q.OrgID != orgID {
+					t.Errorf("unexpected org ID %s", q.OrgID)
+				}
+				return q.Valid()
+			}
+			svc.DeleteQueryQuotaFn = func(ctx context.Context, id influxdb.ID) error {
+				if id != orgID {
+					t.Errorf("unexpected org ID %s", id)
+				}
+				return nil
+			}
+
+			handler := NewQueryQuotaHandler(&QueryQuotaBackend{
+				Logger:            zaptest.NewLogger(t),
+				HTTPErrorHandler:  DefaultErrorHandler,
+				QueryQuotaService: svc,
+			})
+
+			r := httptest.NewRequest(tt.method, prefixQueryQuotas+"?"+tt.query, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.code {
+				t.Errorf("got status %d, want %d", res.StatusCode, tt.code)
+			}
+			if tt.want == "" {
+				if len(body) != 0 {
+					t.Errorf("unexpected body %s", body)
+				}
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.want); err != nil || !eq {
+				t.Errorf("unexpected body: %v %v\n%s", err, diff, body)
+			}
+		})
+	}
+}
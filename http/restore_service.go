@@ -0,0 +1,234 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"go.uber.org/zap"
+)
+
+// RestoreBackend is all services and associated parameters required to construct the RestoreHandler.
+type RestoreBackend struct {
+	Logger *zap.Logger
+	influxdb.HTTPErrorHandler
+
+	RestoreService influxdb.RestoreService
+}
+
+// NewRestoreBackend returns a new instance of RestoreBackend.
+func NewRestoreBackend(b *APIBackend) *RestoreBackend {
+	return &RestoreBackend{
+		Logger: b.Logger.With(zap.String("handler", "restore")),
+
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		RestoreService:   b.RestoreService,
+	}
+}
+
+// RestoreHandler is http handler for restore service.
+type RestoreHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RestoreService influxdb.RestoreService
+}
+
+const (
+	prefixRestore = "/api/v2/restore"
+
+	restoreTSMFormName       = "tsm"
+	restoreTombstoneFormName = "tombstone"
+)
+
+// NewRestoreHandler creates a new handler at /api/v2/restore to receive restore requests.
+func NewRestoreHandler(b *RestoreBackend) *RestoreHandler {
+	h := &RestoreHandler{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Router:           NewRouter(b.HTTPErrorHandler),
+		Logger:           b.Logger,
+		RestoreService:   b.RestoreService,
+	}
+
+	h.HandlerFunc(http.MethodPost, prefixRestore, h.handleRestore)
+
+	return h
+}
+
+// handleRestore restores the data of a backed up bucket into a bucket of the
+// running instance. The body is a multipart form holding a TSM file of the
+// backup in the tsm part, optionally preceded by its tombstone file in the
+// tombstone part.
+func (h *RestoreHandler) handleRestore(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "RestoreHandler.handleRestore")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	req, err := decodeRestoreBucketRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "restore request body must be a multipart form",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	var tombstone io.Reader
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid restore request body",
+				Err:  err,
+			}, w)
+			return
+		}
+
+		switch part.FormName() {
+		case restoreTombstoneFormName:
+			// The tombstone file is held in memory as it must be in place
+			// before the TSM file is read.
+			var buf bytes.Buffer
+			if _, err := io.Copy(&buf, part); err != nil {
+				h.HandleHTTPError(ctx, err, w)
+				return
+			}
+			tombstone = &buf
+		case restoreTSMFormName:
+			if err := h.RestoreService.RestoreBucket(ctx, req, part, tombstone); err != nil {
+				h.HandleHTTPError(ctx, err, w)
+				return
+			}
+			h.Logger.Debug("Bucket restored", zap.String("bucket_id", req.BucketID.String()))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	h.HandleHTTPError(ctx, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "restore request is missing a TSM file",
+	}, w)
+}
+
+// decodeRestoreBucketRequest extracts the orgID, bucketID, sourceOrgID and
+// sourceBucketID parameters of a restore request.
+func decodeRestoreBucketRequest(r *http.Request) (influxdb.RestoreBucketRequest, error) {
+	var req influxdb.RestoreBucketRequest
+	qp := r.URL.Query()
+	for _, p := range []struct {
+		name string
+		id   *influxdb.ID
+	}{
+		{name: "orgID", id: &req.OrgID},
+		{name: "bucketID", id: &req.BucketID},
+		{name: "sourceOrgID", id: &req.SourceOrgID},
+		{name: "sourceBucketID", id: &req.SourceBucketID},
+	} {
+		v := qp.Get(p.name)
+		if v == "" {
+			continue
+		}
+		if err := p.id.DecodeFromString(v); err != nil {
+			return req, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid " + p.name,
+				Err:  err,
+			}
+		}
+	}
+	return req, req.Validate()
+}
+
+// RestoreService is the client implementation of influxdb.RestoreService.
+type RestoreService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// RestoreBucket uploads a backed up TSM file and its tombstone file, if any,
+// to be restored into the bucket identified by req.
+func (s *RestoreService) RestoreBucket(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, prefixRestore)
+	if err != nil {
+		return err
+	}
+
+	// Stream the multipart body so that large TSM files are not held in memory.
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeRestoreParts(mw, tsm, tombstone))
+	}()
+
+	hreq, err := http.NewRequest(http.MethodPost, u.String(), pr)
+	if err != nil {
+		pr.Close()
+		return err
+	}
+
+	params := hreq.URL.Query()
+	params.Set("orgID", req.OrgID.String())
+	params.Set("bucketID", req.BucketID.String())
+	params.Set("sourceOrgID", req.SourceOrgID.String())
+	params.Set("sourceBucketID", req.SourceBucketID.String())
+	hreq.URL.RawQuery = params.Encode()
+	hreq.Header.Set("Content-Type", mw.FormDataContentType())
+	SetToken(s.Token, hreq)
+	hreq = hreq.WithContext(ctx)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	hc.Timeout = httpClientTimeout
+	resp, err := hc.Do(hreq)
+	if err != nil {
+		pr.Close()
+		return err
+	}
+	defer resp.Body.Close()
+	pr.Close()
+
+	return CheckError(resp)
+}
+
+func writeRestoreParts(mw *multipart.Writer, tsm, tombstone io.Reader) error {
+	if tombstone != nil {
+		fw, err := mw.CreateFormFile(restoreTombstoneFormName, restoreTombstoneFormName)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(fw, tombstone); err != nil {
+			return err
+		}
+	}
+
+	fw, err := mw.CreateFormFile(restoreTSMFormName, restoreTSMFormName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fw, tsm); err != nil {
+		return err
+	}
+	return mw.Close()
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxtesting "github.com/influxdata/influxdb/v2/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRestoreService_RestoreBucket(t *testing.T) {
+	req := influxdb.RestoreBucketRequest{
+		SourceOrgID:    influxtesting.MustIDBase16("020f755c3c082000"),
+		SourceBucketID: influxtesting.MustIDBase16("020f755c3c082001"),
+		OrgID:          influxtesting.MustIDBase16("020f755c3c082002"),
+		BucketID:       influxtesting.MustIDBase16("020f755c3c082003"),
+	}
+
+	tests := []struct {
+		name      string
+		tombstone io.Reader
+	}{
+		{
+			name: "restore a TSM file",
+		},
+		{
+			name:      "restore a TSM file with its tombstone file",
+			tombstone: strings.NewReader("tombstone data"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotReq                 influxdb.RestoreBucketRequest
+				gotTSM, gotTombstone   string
+				hasTombstone, restored bool
+			)
+			svc := mock.NewRestoreService()
+			svc.RestoreBucketFn = func(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) error {
+				gotReq, restored = req, true
+				b, err := ioutil.ReadAll(tsm)
+				if err != nil {
+					return err
+				}
+				gotTSM = string(b)
+				if tombstone != nil {
+					hasTombstone = true
+					b, err := ioutil.ReadAll(tombstone)
+					if err != nil {
+						return err
+					}
+					gotTombstone = string(b)
+				}
+				return nil
+			}
+
+			handler := NewRestoreHandler(&RestoreBackend{
+				Logger:           zaptest.NewLogger(t),
+				HTTPErrorHandler: DefaultErrorHandler,
+				RestoreService:   svc,
+			})
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			client := RestoreService{Addr: server.URL}
+			if err := client.RestoreBucket(context.Background(), req, strings.NewReader("tsm data"), tt.tombstone); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !restored {
+				t.Fatal("expected bucket to be restored")
+			}
+			if gotReq != req {
+				t.Errorf("unexpected request: got %+v want %+v", gotReq, req)
+			}
+			if gotTSM != "tsm data" {
+				t.Errorf("unexpected TSM data: got %q", gotTSM)
+			}
+			if hasTombstone != (tt.tombstone != nil) {
+				t.Errorf("unexpected tombstone: got %v want %v", hasTombstone, tt.tombstone != nil)
+			}
+			if tt.tombstone != nil && gotTombstone != "tombstone data" {
+				t.Errorf("unexpected tombstone data: got %q", gotTombstone)
+			}
+		})
+	}
+}
+
+func TestRestoreHandler_handleRestore_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		body  string
+		code  int
+		want  string
+	}{
+		{
+			name: "missing bucket",
+			code: http.StatusBadRequest,
+			want: `{"code":"invalid","message":"source orgID and bucketID are required"}`,
+		},
+		{
+			name:  "invalid bucket id",
+			query: "sourceOrgID=020f755c3c082000&sourceBucketID=020f755c3c082001&orgID=020f755c3c082002&bucketID=bad",
+			code:  http.StatusBadRequest,
+			want:  `{"code":"invalid","message":"invalid bucketID: id must have a length of 16 bytes"}`,
+		},
+		{
+			name:  "body is not a multipart form",
+			query: "sourceOrgID=020f755c3c082000&sourceBucketID=020f755c3c082001&orgID=020f755c3c082002&bucketID=020f755c3c082003",
+			body:  "tsm data",
+			code:  http.StatusBadRequest,
+			want:  `{"code":"invalid","message":"restore request body must be a multipart form: request Content-Type isn't multipart/form-data"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewRestoreHandler(&RestoreBackend{
+				Logger:           zaptest.NewLogger(t),
+				HTTPErrorHandler: DefaultErrorHandler,
+				RestoreService:   mock.NewRestoreService(),
+			})
+
+			r := httptest.NewRequest(http.MethodPost, "http://localhost:9999/api/v2/restore?"+tt.query, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+			if got, want := strings.TrimSpace(w.Body.String()), tt.want; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const prefixRunningQueries = "/api/v2/queries"
+
+// RunningQueryBackend is all services and associated parameters required to
+// construct the RunningQueryHandler.
+type RunningQueryBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	RunningQueryService influxdb.RunningQueryService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewRunningQueryBackend creates a backend used by the running query handler.
+func NewRunningQueryBackend(log *zap.Logger, b *APIBackend) *RunningQueryBackend {
+	return &RunningQueryBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		log:                 log,
+		RunningQueryService: b.RunningQueryService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// RunningQueryHandler is the handler for the running query service.
+type RunningQueryHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	RunningQueryService influxdb.RunningQueryService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewRunningQueryHandler creates a new handler at /api/v2/queries to inspect
+// and cancel the queries queued or executing in the query controller.
+func NewRunningQueryHandler(log *zap.Logger, b *RunningQueryBackend) *RunningQueryHandler {
+	h := &RunningQueryHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		RunningQueryService: b.RunningQueryService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixRunningQueries)
+
+	h.HandlerFunc("GET", prefixRunningQueries, h.handleGetRunningQueries)
+	h.HandlerFunc("GET", entityPath, h.handleGetRunningQuery)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteRunningQuery)
+
+	return h
+}
+
+type runningQueryLinks struct {
+	Self string `json:"self"`
+	Org  string `json:"org"`
+}
+
+type runningQueryResponse struct {
+	*influxdb.RunningQuery
+	Links runningQueryLinks `json:"links"`
+}
+
+func newRunningQueryResponse(q *influxdb.RunningQuery) runningQueryResponse {
+	return runningQueryResponse{
+		RunningQuery: q,
+		Links: runningQueryLinks{
+			Self: fmt.Sprintf("%s/%s", prefixRunningQueries, q.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", q.OrgID),
+		},
+	}
+}
+
+type getRunningQueriesResponse struct {
+	Queries []runningQueryResponse `json:"queries"`
+	Links   struct {
+		Self string `json:"self"`
+	} `json:"links"`
+}
+
+func newGetRunningQueriesResponse(qs []*influxdb.RunningQuery) getRunningQueriesResponse {
+	res := getRunningQueriesResponse{
+		Queries: make([]runningQueryResponse, 0, len(qs)),
+	}
+	res.Links.Self = prefixRunningQueries
+	for _, q := range qs {
+		res.Queries = append(res.Queries, newRunningQueryResponse(q))
+	}
+	return res
+}
+
+func (h *RunningQueryHandler) handleGetRunningQueries(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "RunningQueryHandler.handleGetRunningQueries")
+	defer span.Finish()
+
+	ctx := r.Context()
+	filter, err := h.decodeGetRunningQueriesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	qs, err := h.RunningQueryService.FindRunningQueries(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetRunningQueriesResponse(qs)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// decodeGetRunningQueriesRequest extracts the optional organization, given by
+// its orgID or org name, of the queries to list.
+func (h *RunningQueryHandler) decodeGetRunningQueriesRequest(ctx context.Context, r *http.Request) (influxdb.RunningQueryFilter, error) {
+	var filter influxdb.RunningQueryFilter
+
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = &o.ID
+	}
+	return filter, nil
+}
+
+func requestRunningQueryID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *RunningQueryHandler) handleGetRunningQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "RunningQueryHandler.handleGetRunningQuery")
+	defer span.Finish()
+
+	ctx := r.Context()
+	id, err := requestRunningQueryID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	q, err := h.RunningQueryService.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRunningQueryResponse(q)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *RunningQueryHandler) handleDeleteRunningQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "RunningQueryHandler.handleDeleteRunningQuery")
+	defer span.Finish()
+
+	ctx := r.Context()
+	id, err := requestRunningQueryID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RunningQueryService.CancelRunningQuery(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Query canceled", zap.String("queryID", fmt.Sprint(id)))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunningQueryService is a running query service over HTTP to the influxdb
+// server.
+type RunningQueryService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.RunningQueryService = (*RunningQueryService)(nil)
+
+// FindRunningQueries returns the queued and executing queries matching the
+// filter.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+	var params [][2]string
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+
+	var res getRunningQueriesResponse
+	err := s.Client.
+		Get(prefixRunningQueries).
+		QueryParams(params...).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	qs := make([]*influxdb.RunningQuery, 0, len(res.Queries))
+	for _, r := range res.Queries {
+		qs = append(qs, r.RunningQuery)
+	}
+	return qs, nil
+}
+
+// FindRunningQueryByID returns a single queued or executing query by ID.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+	var res runningQueryResponse
+	err := s.Client.
+		Get(prefixRunningQueries, id.String()).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return res.RunningQuery, nil
+}
+
+// CancelRunningQuery cancels a queued or executing query by ID.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id influxdb.ID) error {
+	return s.Client.
+		Delete(prefixRunningQueries, id.String()).
+		Do(ctx)
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxtesting "github.com/influxdata/influxdb/v2/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRunningQueryHandler(t *testing.T) {
+	query := influxdb.RunningQuery{
+		ID:              influxtesting.MustIDBase16("020f755c3c082001"),
+		OrgID:           influxtesting.MustIDBase16("020f755c3c082000"),
+		UserID:          influxtesting.MustIDBase16("020f755c3c082002"),
+		AuthorizationID: influxtesting.MustIDBase16("020f755c3c082003"),
+		CompilerType:    "flux",
+		Query:           `from(bucket: "telegraf") |> range(start: -1h)`,
+		State:           "executing",
+		CreatedAt:       time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC),
+		Elapsed:         influxdb.Duration{Duration: 1500 * time.Millisecond},
+		MemoryBytes:     1024,
+	}
+	queryJSON := `{
+		"id": "020f755c3c082001",
+		"orgID": "020f755c3c082000",
+		"userID": "020f755c3c082002",
+		"authorizationID": "020f755c3c082003",
+		"compilerType": "flux",
+		"query": "from(bucket: \"telegraf\") |> range(start: -1h)",
+		"state": "executing",
+		"createdAt": "2020-01-01T22:00:00Z",
+		"elapsed": "1.5s",
+		"memoryBytes": 1024,
+		"links": {
+			"self": "/api/v2/queries/020f755c3c082001",
+			"org": "/api/v2/orgs/020f755c3c082000"
+		}
+	}`
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		code   int
+		want   string
+	}{
+		{
+			name:   "list queries",
+			method: http.MethodGet,
+			path:   prefixRunningQueries,
+			code:   http.StatusOK,
+			want:   `{"queries": [` + queryJSON + `], "links": {"self": "/api/v2/queries"}}`,
+		},
+		{
+			name:   "list queries of org",
+			method: http.MethodGet,
+			path:   prefixRunningQueries + "?orgID=020f755c3c082000",
+			code:   http.StatusOK,
+			want:   `{"queries": [` + queryJSON + `], "links": {"self": "/api/v2/queries"}}`,
+		},
+		{
+			name:   "list queries of other org",
+			method: http.MethodGet,
+			path:   prefixRunningQueries + "?orgID=020f755c3c082004",
+			code:   http.StatusOK,
+			want:   `{"queries": [], "links": {"self": "/api/v2/queries"}}`,
+		},
+		{
+			name:   "list queries with invalid orgID",
+			method: http.MethodGet,
+			path:   prefixRunningQueries + "?orgID=invalid",
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "get query",
+			method: http.MethodGet,
+			path:   prefixRunningQueries + "/020f755c3c082001",
+			code:   http.StatusOK,
+			want:   queryJSON,
+		},
+		{
+			name:   "get finished query",
+			method: http.MethodGet,
+			path:   prefixRunningQueries + "/020f755c3c082004",
+			code:   http.StatusNotFound,
+			want:   `{"code": "not found", "message": "running query not found"}`,
+		},
+		{
+			name:   "cancel query",
+			method: http.MethodDelete,
+			path:   prefixRunningQueries + "/020f755c3c082001",
+			code:   http.StatusNoContent,
+		},
+		{
+			name:   "cancel finished query",
+			method: http.MethodDelete,
+			path:   prefixRunningQueries + "/020f755c3c082004",
+			code:   http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewRunningQueryService()
+			svc.FindRunningQueriesFn = func(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+				if filter.OrgID != nil && *filter.OrgID != query.OrgID {
+					return nil, nil
+				}
+				q := query
+				return []*influxdb.RunningQuery{&q}, nil
+			}
+			svc.FindRunningQueryByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+				if id != query.ID {
+					return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "running query not found"}
+				}
+				q := query
+				return &q, nil
+			}
+			svc.CancelRunningQueryFn = func(ctx context.Context, id influxdb.ID) error {
+				if id != query.ID {
+					return &influxdb.Error{Code: influxdb.ENotFound, Msg: "running query not found"}
+				}
+				return nil
+			}
+
+			handler := NewRunningQueryHandler(zaptest.NewLogger(t), &RunningQueryBackend{
+				HTTPErrorHandler:    DefaultErrorHandler,
+				log:                 zaptest.NewLogger(t),
+				RunningQueryService: svc,
+			})
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.code {
+				t.Errorf("got status %d, want %d", res.StatusCode, tt.code)
+			}
+			if tt.want == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.want); err != nil || !eq {
+				t.Errorf("unexpected body: %v %v\n%s", err, diff, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const prefixSilences = "/api/v2/silences"
+
+// SilenceBackend is all services and associated parameters required to construct
+// the SilenceHandler.
+type SilenceBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	SilenceService      influxdb.SilenceService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewSilenceBackend creates a backend used by the silence handler.
+func NewSilenceBackend(log *zap.Logger, b *APIBackend) *SilenceBackend {
+	return &SilenceBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		log:                 log,
+		SilenceService:      b.SilenceService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// SilenceHandler is the handler for the silence service
+type SilenceHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	SilenceService      influxdb.SilenceService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewSilenceHandler creates a new handler at /api/v2/silences to manage the
+// silences muting the notifications of the notification rules.
+func NewSilenceHandler(log *zap.Logger, b *SilenceBackend) *SilenceHandler {
+	h := &SilenceHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		SilenceService:      b.SilenceService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixSilences)
+
+	h.HandlerFunc("GET", prefixSilences, h.handleGetSilences)
+	h.HandlerFunc("POST", prefixSilences, h.handlePostSilence)
+	h.HandlerFunc("GET", entityPath, h.handleGetSilence)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchSilence)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteSilence)
+
+	return h
+}
+
+type silenceLinks struct {
+	Self string `json:"self"`
+	Org  string `json:"org"`
+}
+
+type silenceResponse struct {
+	*influxdb.Silence
+	Links silenceLinks `json:"links"`
+}
+
+func newSilenceResponse(s *influxdb.Silence) silenceResponse {
+	return silenceResponse{
+		Silence: s,
+		Links: silenceLinks{
+			Self: fmt.Sprintf("%s/%s", prefixSilences, s.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", s.OrgID),
+		},
+	}
+}
+
+type getSilencesResponse struct {
+	Silences []silenceResponse `json:"silences"`
+	Links    struct {
+		Self string `json:"self"`
+	} `json:"links"`
+}
+
+func newGetSilencesResponse(sls []*influxdb.Silence) getSilencesResponse {
+	res := getSilencesResponse{
+		Silences: make([]silenceResponse, 0, len(sls)),
+	}
+	res.Links.Self = prefixSilences
+	for _, s := range sls {
+		res.Silences = append(res.Silences, newSilenceResponse(s))
+	}
+	return res
+}
+
+func (h *SilenceHandler) handleGetSilences(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "SilenceHandler.handleGetSilences")
+	defer span.Finish()
+
+	ctx := r.Context()
+	filter, err := h.decodeGetSilencesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	sls, err := h.SilenceService.FindSilences(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetSilencesResponse(sls)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// decodeGetSilencesRequest extracts the organization, given by its orgID or
+// org name, and the optional active time of the silences to list.
+func (h *SilenceHandler) decodeGetSilencesRequest(ctx context.Context, r *http.Request) (influxdb.SilenceFilter, error) {
+	var filter influxdb.SilenceFilter
+
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = &o.ID
+	} else {
+		return filter, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID or org is required",
+		}
+	}
+
+	if active := qp.Get("active"); active != "" {
+		t, err := time.Parse(time.RFC3339, active)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "active must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		filter.Active = &t
+	}
+	return filter, nil
+}
+
+func requestSilenceID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *SilenceHandler) handleGetSilence(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "SilenceHandler.handleGetSilence")
+	defer span.Finish()
+
+	ctx := r.Context()
+	id, err := requestSilenceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	s, err := h.SilenceService.FindSilenceByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSilenceResponse(s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *SilenceHandler) handlePostSilence(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "SilenceHandler.handlePostSilence")
+	defer span.Finish()
+
+	ctx := r.Context()
+	var s influxdb.Silence
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.SilenceService.CreateSilence(ctx, &s); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence created", zap.String("silence", fmt.Sprint(s)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newSilenceResponse(&s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *SilenceHandler) handlePatchSilence(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "SilenceHandler.handlePatchSilence")
+	defer span.Finish()
+
+	ctx := r.Context()
+	id, err := requestSilenceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.SilenceUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	s, err := h.SilenceService.UpdateSilence(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence updated", zap.String("silence", fmt.Sprint(s)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSilenceResponse(s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *SilenceHandler) handleDeleteSilence(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "SilenceHandler.handleDeleteSilence")
+	defer span.Finish()
+
+	ctx := r.Context()
+	id, err := requestSilenceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.SilenceService.DeleteSilence(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence deleted", zap.String("silenceID", fmt.Sprint(id)))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SilenceService is a silence service over HTTP to the influxdb server.
+type SilenceService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// FindSilenceByID returns a single silence by ID.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	var res silenceResponse
+	err := s.Client.
+		Get(prefixSilences, id.String()).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return res.Silence, nil
+}
+
+// FindSilences returns the silences matching the filter.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	var params [][2]string
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+	if filter.Active != nil {
+		params = append(params, [2]string{"active", filter.Active.Format(time.RFC3339)})
+	}
+
+	var res getSilencesResponse
+	err := s.Client.
+		Get(prefixSilences).
+		QueryParams(params...).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sls := make([]*influxdb.Silence, 0, len(res.Silences))
+	for _, r := range res.Silences {
+		sls = append(sls, r.Silence)
+	}
+	return sls, nil
+}
+
+// CreateSilence creates a new silence and sets sl.ID with the new identifier.
+func (s *SilenceService) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	return s.Client.
+		PostJSON(sl, prefixSilences).
+		DecodeJSON(sl).
+		Do(ctx)
+}
+
+// UpdateSilence updates a single silence with the changeset.
+func (s *SilenceService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	var sl influxdb.Silence
+	err := s.Client.
+		PatchJSON(upd, prefixSilences, id.String()).
+		DecodeJSON(&sl).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sl, nil
+}
+
+// DeleteSilence removes a silence by ID.
+func (s *SilenceService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	return s.Client.
+		Delete(prefixSilences, id.String()).
+		Do(ctx)
+}
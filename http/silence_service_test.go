@@ -0,0 +1,145 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxtesting "github.com/influxdata/influxdb/v2/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSilenceHandler(t *testing.T) {
+	start := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+	silence := influxdb.Silence{
+		ID:    influxtesting.MustIDBase16("020f755c3c082001"),
+		OrgID: influxtesting.MustIDBase16("020f755c3c082000"),
+		Name:  "maintenance",
+		Start: start,
+		End:   start.Add(2 * time.Hour),
+		Recurrence: &influxdb.SilenceRecurrence{
+			Every: influxdb.SilenceRecurWeekly,
+		},
+		TagRules: []influxdb.TagRule{
+			{Tag: influxdb.Tag{Key: "host", Value: "db1"}, Operator: influxdb.Equal},
+		},
+		CRUDLog: influxdb.CRUDLog{CreatedAt: start, UpdatedAt: start},
+	}
+	silenceJSON := `{
+		"id": "020f755c3c082001",
+		"orgID": "020f755c3c082000",
+		"name": "maintenance",
+		"start": "2020-01-01T22:00:00Z",
+		"end": "2020-01-02T00:00:00Z",
+		"recurrence": {"every": "weekly"},
+		"tagRules": [{"key": "host", "value": "db1", "operator": "equal"}],
+		"createdAt": "2020-01-01T22:00:00Z",
+		"updatedAt": "2020-01-01T22:00:00Z",
+		"links": {
+			"self": "/api/v2/silences/020f755c3c082001",
+			"org": "/api/v2/orgs/020f755c3c082000"
+		}
+	}`
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+		want   string
+	}{
+		{
+			name:   "list silences",
+			method: http.MethodGet,
+			path:   prefixSilences + "?orgID=020f755c3c082000",
+			code:   http.StatusOK,
+			want:   `{"silences": [` + silenceJSON + `], "links": {"self": "/api/v2/silences"}}`,
+		},
+		{
+			name:   "list silences without org",
+			method: http.MethodGet,
+			path:   prefixSilences,
+			code:   http.StatusBadRequest,
+			want:   `{"code": "invalid", "message": "orgID or org is required"}`,
+		},
+		{
+			name:   "list silences with invalid active time",
+			method: http.MethodGet,
+			path:   prefixSilences + "?orgID=020f755c3c082000&active=now",
+			code:   http.StatusBadRequest,
+			want:   `{"code": "invalid", "message": "active must be an RFC3339 time: parsing time \"now\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"now\" as \"2006\""}`,
+		},
+		{
+			name:   "get silence",
+			method: http.MethodGet,
+			path:   prefixSilences + "/020f755c3c082001",
+			code:   http.StatusOK,
+			want:   silenceJSON,
+		},
+		{
+			name:   "create silence",
+			method: http.MethodPost,
+			path:   prefixSilences,
+			body:   `{"orgID": "020f755c3c082000", "name": "maintenance", "start": "2020-01-01T22:00:00Z", "end": "2020-01-02T00:00:00Z", "recurrence": {"every": "weekly"}, "tagRules": [{"key": "host", "value": "db1", "operator": "equal"}]}`,
+			code:   http.StatusCreated,
+			want:   silenceJSON,
+		},
+		{
+			name:   "delete silence",
+			method: http.MethodDelete,
+			path:   prefixSilences + "/020f755c3c082001",
+			code:   http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewSilenceService()
+			svc.FindSilencesFn = func(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+				if filter.OrgID == nil || *filter.OrgID != silence.OrgID {
+					t.Errorf("unexpected filter %+v", filter)
+				}
+				s := silence
+				return []*influxdb.Silence{&s}, nil
+			}
+			svc.FindSilenceByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+				s := silence
+				return &s, nil
+			}
+			svc.CreateSilenceFn = func(ctx context.Context, s *influxdb.Silence) error {
+				s.ID = silence.ID
+				s.CRUDLog = silence.CRUDLog
+				return nil
+			}
+
+			handler := NewSilenceHandler(zaptest.NewLogger(t), &SilenceBackend{
+				HTTPErrorHandler: DefaultErrorHandler,
+				log:              zaptest.NewLogger(t),
+				SilenceService:   svc,
+			})
+
+			r := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.code {
+				t.Errorf("got status %d, want %d", res.StatusCode, tt.code)
+			}
+			if tt.want == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.want); err != nil || !eq {
+				t.Errorf("unexpected body: %v %v\n%s", err, diff, body)
+			}
+		})
+	}
+}
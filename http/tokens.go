@@ -27,6 +27,23 @@ func GetToken(r *http.Request) (string, error) {
 	return header[len(tokenScheme):], nil
 }
 
+// GetLegacyToken will parse the token from an influxdb 1.x style request.
+// The token may be provided in the Authorization header using the Token
+// scheme, as the password of HTTP basic authentication or as the p query
+// parameter. The username is ignored.
+func GetLegacyToken(r *http.Request) (string, error) {
+	if token, err := GetToken(r); err == nil {
+		return token, nil
+	}
+	if _, password, ok := r.BasicAuth(); ok && password != "" {
+		return password, nil
+	}
+	if password := r.URL.Query().Get("p"); password != "" {
+		return password, nil
+	}
+	return "", ErrAuthHeaderMissing
+}
+
 // SetToken adds the token to the request.
 func SetToken(token string, req *http.Request) {
 	req.Header.Set("Authorization", fmt.Sprintf("%s%s", tokenScheme, token))
@@ -99,3 +99,56 @@ func TestSetToken(t *testing.T) {
 		})
 	}
 }
+
+func TestGetLegacyToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     func() *http.Request
+		want    string
+		wantErr error
+	}{
+		{
+			name: "token header",
+			req: func() *http.Request {
+				r := httptest.NewRequest("POST", "/write?db=db0", nil)
+				r.Header.Set("Authorization", "Token tok1")
+				return r
+			},
+			want: "tok1",
+		},
+		{
+			name: "basic auth password",
+			req: func() *http.Request {
+				r := httptest.NewRequest("POST", "/write?db=db0", nil)
+				r.SetBasicAuth("me", "tok2")
+				return r
+			},
+			want: "tok2",
+		},
+		{
+			name: "p query parameter",
+			req: func() *http.Request {
+				return httptest.NewRequest("GET", "/query?db=db0&u=me&p=tok3", nil)
+			},
+			want: "tok3",
+		},
+		{
+			name: "no credentials",
+			req: func() *http.Request {
+				return httptest.NewRequest("GET", "/query?db=db0&u=me", nil)
+			},
+			wantErr: ErrAuthHeaderMissing,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetLegacyToken(tt.req())
+			if err != tt.wantErr {
+				t.Fatalf("err incorrect want %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("GetLegacyToken() want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
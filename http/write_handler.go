@@ -1,14 +1,18 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"strings"
 
+	"github.com/golang/snappy"
 	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb/v2"
 	pcontext "github.com/influxdata/influxdb/v2/context"
@@ -41,6 +45,7 @@ type WriteBackend struct {
 	PointsWriter        storage.PointsWriter
 	BucketService       influxdb.BucketService
 	OrganizationService influxdb.OrganizationService
+	DBRPMappingService  influxdb.DBRPMappingServiceV2
 }
 
 // NewWriteBackend returns a new instance of WriteBackend.
@@ -53,10 +58,12 @@ func NewWriteBackend(log *zap.Logger, b *APIBackend) *WriteBackend {
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
+		DBRPMappingService:  b.DBRPService,
 	}
 }
 
-// WriteHandler receives line protocol and sends to a publish function.
+// WriteHandler receives line protocol, JSON points or Prometheus remote write
+// requests and sends their points to a publish function.
 type WriteHandler struct {
 	influxdb.HTTPErrorHandler
 	BucketService       influxdb.BucketService
@@ -68,6 +75,7 @@ type WriteHandler struct {
 	log               *zap.Logger
 	maxBatchSizeBytes int64
 	parserOptions     []models.ParserOption
+	decoders          map[string]PointsDecoder
 }
 
 // WriteHandlerOption is a functional option for a *WriteHandler
@@ -87,6 +95,14 @@ func WithParserOptions(opts ...models.ParserOption) WriteHandlerOption {
 	}
 }
 
+// WithPointsDecoder configures the decoder of the write requests whose body
+// has the media type contentType, replacing the default one if any.
+func WithPointsDecoder(contentType string, d PointsDecoder) WriteHandlerOption {
+	return func(w *WriteHandler) {
+		w.decoders[contentType] = d
+	}
+}
+
 // Prefix provides the route prefix.
 func (*WriteHandler) Prefix() string {
 	return prefixWrite
@@ -115,6 +131,10 @@ func NewWriteHandler(log *zap.Logger, b *WriteBackend, opts ...WriteHandlerOptio
 
 		router: NewRouter(b.HTTPErrorHandler),
 		log:    log,
+		decoders: map[string]PointsDecoder{
+			contentTypeJSON:     JSONPointsDecoder{},
+			contentTypeProtobuf: PrometheusPointsDecoder{},
+		},
 	}
 
 	for _, opt := range opts {
@@ -187,31 +207,77 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	}
 	span.LogKV("bucket_id", bucket.ID)
 
-	if err := checkBucketWritePermissions(auth, org.ID, bucket.ID); err != nil {
+	// Bodies whose media type has no decoder are parsed as line protocol.
+	decoder := h.decoders[req.ContentType]
+	requestBytes, err = h.writePoints(ctx, auth, org.ID, bucket.ID, req.Precision, decoder, req.Body)
+	if err != nil {
 		h.HandleHTTPError(ctx, err, sw)
 		return
 	}
 
+	sw.WriteHeader(http.StatusNoContent)
+}
+
+// writePoints checks that auth may write to the bucket, parses the points in
+// body, as line protocol unless decoder is set, and hands them to the
+// PointsWriter. It returns the number of bytes read from body after
+// decompression.
+func (h *WriteHandler) writePoints(ctx context.Context, auth influxdb.Authorizer, orgID, bucketID influxdb.ID, precision string, decoder PointsDecoder, body io.ReadCloser) (int, error) {
+	if err := checkBucketWritePermissions(auth, orgID, bucketID); err != nil {
+		return 0, err
+	}
+
 	opts := append([]models.ParserOption{}, h.parserOptions...)
-	opts = append(opts, models.WithParserPrecision(req.Precision))
-	parsed, err := NewPointsParser(opts...).ParsePoints(ctx, org.ID, bucket.ID, req.Body)
+	opts = append(opts, models.WithParserPrecision(precision))
+	parser := NewPointsParser(opts...)
+	parser.Decoder = decoder
+	parser.Precision = precision
+	parsed, err := parser.ParsePoints(ctx, orgID, bucketID, body)
 	if err != nil {
-		h.HandleHTTPError(ctx, err, sw)
-		return
+		return 0, err
 	}
-	requestBytes = parsed.RawSize
 
 	if err := h.PointsWriter.WritePoints(ctx, parsed.Points); err != nil {
-		h.HandleHTTPError(ctx, &influxdb.Error{
+		var verr *storage.SchemaViolationError
+		if errors.As(err, &verr) {
+			return parsed.RawSize, schemaViolationError(verr, parsed.Lines)
+		}
+		// Points dropped by a cardinality limit of the storage engine, or
+		// not queued because the queue of a replication of the bucket is full.
+		if code := influxdb.ErrorCode(err); code == influxdb.EUnprocessableEntity || code == influxdb.ETooManyRequests {
+			return parsed.RawSize, &influxdb.Error{
+				Code: code,
+				Op:   opWriteHandler,
+				Err:  err,
+			}
+		}
+		return parsed.RawSize, &influxdb.Error{
 			Code: influxdb.EInternal,
 			Op:   opWriteHandler,
-			Msg:  "unexpected error writing points to database",
+			Msg:  msgUnexpectedWriteError,
 			Err:  err,
-		}, sw)
-		return
+		}
 	}
+	return parsed.RawSize, nil
+}
 
-	sw.WriteHeader(http.StatusNoContent)
+// schemaViolationError returns the error of a write partially rejected by the
+// schema of the bucket, with the reason of every rejected line of the request.
+func schemaViolationError(verr *storage.SchemaViolationError, lines []int) error {
+	failed := make([]string, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		if v.Index < len(lines) {
+			failed = append(failed, fmt.Sprintf("line %d: %s", lines[v.Index], v.Reason))
+		} else {
+			failed = append(failed, v.Reason)
+		}
+	}
+	return &influxdb.Error{
+		Code: influxdb.EUnprocessableEntity,
+		Op:   opWriteHandler,
+		Msg:  fmt.Sprintf("partial write: %d points rejected by the bucket schema", len(verr.Violations)),
+		Err:  errors.New(strings.Join(failed, "\n")),
+	}
 }
 
 // checkBucketWritePermissions checks an Authorizer for write permissions to a
@@ -237,7 +303,7 @@ func checkBucketWritePermissions(auth influxdb.Authorizer, orgID, bucketID influ
 	return nil
 }
 
-// PointBatchReadCloser (potentially) wraps an io.ReadCloser in Gzip
+// PointBatchReadCloser (potentially) wraps an io.ReadCloser in Gzip or Snappy
 // decompression and limits the reading to a specific number of bytes.
 func PointBatchReadCloser(rc io.ReadCloser, encoding string, maxBatchSizeBytes int64) (io.ReadCloser, error) {
 	switch encoding {
@@ -247,6 +313,8 @@ func PointBatchReadCloser(rc io.ReadCloser, encoding string, maxBatchSizeBytes i
 		if err != nil {
 			return nil, err
 		}
+	case "snappy":
+		rc = &snappyReadCloser{rc: rc, max: maxBatchSizeBytes}
 	}
 	if maxBatchSizeBytes > 0 {
 		rc = kitio.NewLimitedReadCloser(rc, maxBatchSizeBytes)
@@ -266,11 +334,20 @@ func NewPointsParser(parserOptions ...models.ParserOption) *PointsParser {
 type ParsedPoints struct {
 	Points  models.Points
 	RawSize int
+
+	// Lines holds the line number of the request each point was parsed from.
+	Lines []int
 }
 
 // PointsParser parses batches of Points.
 type PointsParser struct {
 	ParserOptions []models.ParserOption
+
+	// Decoder decodes the batches which are not line protocol, with their
+	// timestamps in Precision. The decoded points are parsed again as line
+	// protocol, so that they are subject to the limits of the ParserOptions.
+	Decoder   PointsDecoder
+	Precision string
 }
 
 // ParsePoints parses the points from an io.ReadCloser for a specific Bucket.
@@ -286,7 +363,7 @@ func (pw *PointsParser) parsePoints(ctx context.Context, orgID, bucketID influxd
 		code := influxdb.EInternal
 		if errors.Is(err, ErrMaxBatchSizeExceeded) {
 			code = influxdb.ETooLarge
-		} else if errors.Is(err, gzip.ErrHeader) || errors.Is(err, gzip.ErrChecksum) {
+		} else if errors.Is(err, gzip.ErrHeader) || errors.Is(err, gzip.ErrChecksum) || errors.Is(err, snappy.ErrCorrupt) {
 			code = influxdb.EInvalid
 		}
 		return nil, &influxdb.Error{
@@ -310,7 +387,20 @@ func (pw *PointsParser) parsePoints(ctx context.Context, orgID, bucketID influxd
 	encoded := tsdb.EncodeName(orgID, bucketID)
 	mm := models.EscapeMeasurement(encoded[:])
 
-	points, err := models.ParsePointsWithOptions(data, mm, pw.ParserOptions...)
+	var lines []int
+	opts := append([]models.ParserOption{models.WithParserLines(&lines)}, pw.ParserOptions...)
+	if pw.Decoder != nil {
+		if data, err = pw.decode(data); err != nil {
+			span.Finish()
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   opPointsWriter,
+				Err:  err,
+			}
+		}
+		opts = append(opts, models.WithParserPrecision("ns"))
+	}
+	points, err := models.ParsePointsWithOptions(data, mm, opts...)
 	span.LogKV("values_total", len(points))
 	span.Finish()
 	if err != nil {
@@ -334,9 +424,25 @@ func (pw *PointsParser) parsePoints(ctx context.Context, orgID, bucketID influxd
 	return &ParsedPoints{
 		Points:  points,
 		RawSize: requestBytes,
+		Lines:   lines,
 	}, nil
 }
 
+// decode decodes the points of data with the Decoder and returns them as line
+// protocol, one line per decoded point with its timestamp in nanoseconds.
+func (pw *PointsParser) decode(data []byte) ([]byte, error) {
+	points, err := pw.Decoder.DecodePoints(data, pw.Precision)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, len(data))
+	for _, p := range points {
+		buf = p.AppendString(buf)
+		buf = append(buf, '\n')
+	}
+	return buf, nil
+}
+
 func readAll(ctx context.Context, rc io.ReadCloser) (data []byte, err error) {
 	defer func() {
 		if cerr := rc.Close(); cerr != nil && err == nil {
@@ -365,10 +471,11 @@ func readAll(ctx context.Context, rc io.ReadCloser) (data []byte, err error) {
 // writeRequest is a request object holding information about a batch of points
 // to be written to a Bucket.
 type writeRequest struct {
-	Org       string
-	Bucket    string
-	Precision string
-	Body      io.ReadCloser
+	Org         string
+	Bucket      string
+	Precision   string
+	ContentType string
+	Body        io.ReadCloser
 }
 
 // decodeWriteRequest extracts information from an http.Request object to
@@ -403,14 +510,56 @@ func decodeWriteRequest(ctx context.Context, r *http.Request, maxBatchSizeBytes
 		return nil, err
 	}
 
+	// An invalid or missing Content-Type is line protocol, as it has
+	// historically been for every request.
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
 	return &writeRequest{
-		Bucket:    qp.Get("bucket"),
-		Org:       qp.Get("org"),
-		Precision: precision,
-		Body:      body,
+		Bucket:      qp.Get("bucket"),
+		Org:         qp.Get("org"),
+		Precision:   precision,
+		ContentType: contentType,
+		Body:        body,
 	}, nil
 }
 
+// snappyReadCloser decompresses the snappy block encoded body of rc. The
+// block format can not be streamed, the body is read whole on the first read.
+type snappyReadCloser struct {
+	rc  io.ReadCloser
+	max int64
+	r   io.Reader
+}
+
+func (s *snappyReadCloser) Read(p []byte) (int, error) {
+	if s.r == nil {
+		var r io.Reader = s.rc
+		if n := snappy.MaxEncodedLen(int(s.max)); s.max > 0 && n >= 0 {
+			r = io.LimitReader(r, int64(n)+1)
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return 0, err
+		}
+		n, err := snappy.DecodedLen(data)
+		if err != nil {
+			return 0, err
+		}
+		if s.max > 0 && int64(n) > s.max {
+			return 0, ErrMaxBatchSizeExceeded
+		}
+		if data, err = snappy.Decode(nil, data); err != nil {
+			return 0, err
+		}
+		s.r = bytes.NewReader(data)
+	}
+	return s.r.Read(p)
+}
+
+func (s *snappyReadCloser) Close() error {
+	return s.rc.Close()
+}
+
 // WriteService sends data over HTTP to influxdb via line protocol.
 type WriteService struct {
 	Addr               string
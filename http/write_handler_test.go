@@ -11,12 +11,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/golang/snappy"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/http/metric"
 	httpmock "github.com/influxdata/influxdb/v2/http/mock"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
 	"github.com/influxdata/influxdb/v2/mock"
 	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
 	influxtesting "github.com/influxdata/influxdb/v2/testing"
 	"go.uber.org/zap/zaptest"
 )
@@ -97,10 +99,12 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 
 	// request is sent to the HTTP endpoint
 	type request struct {
-		auth   influxdb.Authorizer
-		org    string
-		bucket string
-		body   string
+		auth        influxdb.Authorizer
+		org         string
+		bucket      string
+		body        string
+		contentType string
+		encoding    string
 	}
 
 	tests := []struct {
@@ -143,6 +147,28 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				body: `{"code":"internal error","message":"unexpected error writing points to database: error"}`,
 			},
 		},
+		{
+			name: "points rejected by the bucket schema are reported by line",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,t1=v1 f1=1\n\nm2 f1=1,f2=2",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				writeErr: &storage.SchemaViolationError{
+					Violations: []storage.SchemaViolation{
+						{Index: 2, Reason: "field f2 is not declared"},
+					},
+				},
+			},
+			wants: wants{
+				code: 422,
+				body: `{"code":"unprocessable entity","message":"partial write: 1 points rejected by the bucket schema: line 3: field f2 is not declared"}`,
+			},
+		},
 		{
 			name: "empty request body returns 400 error",
 			request: request{
@@ -347,6 +373,101 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				body: `{"code":"request too large","message":"points: number of values exceeded"}`,
 			},
 		},
+		{
+			name: "JSON body is accepted",
+			request: request{
+				org:         "043e0780ee2b1000",
+				bucket:      "04504b356e23b000",
+				body:        `[{"measurement": "m1", "tags": {"t1": "v1"}, "fields": {"f1": 1}}]`,
+				contentType: "application/json; charset=utf-8",
+				auth:        bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name: "invalid JSON returns 400",
+			request: request{
+				org:         "043e0780ee2b1000",
+				bucket:      "04504b356e23b000",
+				body:        `[{"tags": {"t1": "v1"}, "fields": {"f1": 1}}]`,
+				contentType: "application/json",
+				auth:        bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"unable to decode JSON point 1: missing measurement"}`,
+			},
+		},
+		{
+			name: "JSON lines limit rejected",
+			request: request{
+				org:         "043e0780ee2b1000",
+				bucket:      "04504b356e23b000",
+				body:        `[{"measurement": "m1", "fields": {"f1": 1}}, {"measurement": "m1", "fields": {"f1": 1}}, {"measurement": "m1", "fields": {"f1": 1}}]`,
+				contentType: "application/json",
+				auth:        bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				opts:   []WriteHandlerOption{WithParserOptions(models.WithParserMaxLines(2))},
+			},
+			wants: wants{
+				code: 413,
+				body: `{"code":"request too large","message":"points: number of lines exceeded"}`,
+			},
+		},
+		{
+			name: "Prometheus remote write body is accepted",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body: string(snappy.Encode(nil, prometheusWriteRequest(prometheusSeries{
+					labels:  []string{"__name__", "up", "job", "api"},
+					samples: []prometheusSample{{value: 1, ts: 1590000000000}},
+				}))),
+				contentType: "application/x-protobuf",
+				encoding:    "snappy",
+				auth:        bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name: "large snappy requests rejected",
+			request: request{
+				org:         "043e0780ee2b1000",
+				bucket:      "04504b356e23b000",
+				body:        string(snappy.Encode(nil, []byte(strings.Repeat("m1,t1=v1 f1=1\n", 10)))),
+				contentType: "text/plain",
+				encoding:    "snappy",
+				auth:        bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				opts:   []WriteHandlerOption{WithMaxBatchSizeBytes(100)},
+			},
+			wants: wants{
+				code: 413,
+				body: `{"code":"request too large","message":"unable to read data: points batch is too large"}`,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -376,6 +497,13 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				strings.NewReader(tt.request.body),
 			)
 
+			if tt.request.contentType != "" {
+				r.Header.Set("Content-Type", tt.request.contentType)
+			}
+			if tt.request.encoding != "" {
+				r.Header.Set("Content-Encoding", tt.request.encoding)
+			}
+
 			params := r.URL.Query()
 			params.Set("org", tt.request.org)
 			params.Set("bucket", tt.request.bucket)
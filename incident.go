@@ -0,0 +1,147 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// Statuses of an incident.
+const (
+	IncidentOpen         = "open"
+	IncidentAcknowledged = "acknowledged"
+	IncidentResolved     = "resolved"
+)
+
+// CheckLevelOK is the level of the statuses of a check whose data is back to
+// normal.
+const CheckLevelOK = "ok"
+
+// CheckStatus is a status written by a check into the _monitoring bucket.
+type CheckStatus struct {
+	// CheckID is missing from the statuses of the dry runs of unsaved checks.
+	CheckID   ID        `json:"checkID,omitempty"`
+	CheckName string    `json:"checkName"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
+	// Tags are the tags of the series the check computed the status for.
+	Tags []Tag `json:"tags,omitempty"`
+}
+
+// Incident groups the consecutive statuses of a check for a series whose
+// level is not ok. It is opened by the first of them and is resolved once
+// the series is back to ok, or once a user resolves it.
+type Incident struct {
+	ID        ID     `json:"id,omitempty"`
+	OrgID     ID     `json:"orgID,omitempty"`
+	CheckID   ID     `json:"checkID"`
+	CheckName string `json:"checkName"`
+	Tags      []Tag  `json:"tags"`
+	Status    string `json:"status"`
+
+	// Level and Message are the ones of the latest status of the incident.
+	Level       string `json:"level"`
+	Message     string `json:"message,omitempty"`
+	StatusCount int    `json:"statusCount"`
+
+	// Start is the time of the first status of the incident, LastSeen the
+	// time of its latest one.
+	Start    time.Time `json:"start"`
+	LastSeen time.Time `json:"lastSeen"`
+
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+	AcknowledgedBy ID         `json:"acknowledgedBy,omitempty"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty"`
+	// ResolvedBy is the user who resolved the incident, it is not set when
+	// the series got back to ok on its own.
+	ResolvedBy ID `json:"resolvedBy,omitempty"`
+
+	CRUDLog
+}
+
+// Acknowledge marks the incident as acknowledged by the user. The statuses
+// of an acknowledged incident are not notified anymore until it is resolved.
+func (i *Incident) Acknowledge(userID ID, now time.Time) error {
+	switch i.Status {
+	case IncidentAcknowledged:
+		return &Error{
+			Code: EConflict,
+			Msg:  "incident is already acknowledged",
+		}
+	case IncidentResolved:
+		return &Error{
+			Code: EConflict,
+			Msg:  "incident is resolved",
+		}
+	}
+	i.Status = IncidentAcknowledged
+	i.AcknowledgedAt = &now
+	i.AcknowledgedBy = userID
+	return nil
+}
+
+// Resolve marks the incident as resolved at the given time. The user is
+// invalid when the series of the incident got back to ok on its own.
+func (i *Incident) Resolve(userID ID, now time.Time) error {
+	if i.Status == IncidentResolved {
+		return &Error{
+			Code: EConflict,
+			Msg:  "incident is already resolved",
+		}
+	}
+	i.Status = IncidentResolved
+	i.ResolvedAt = &now
+	i.ResolvedBy = userID
+	return nil
+}
+
+// IncidentUpdate is the change of status of an incident requested by a user.
+type IncidentUpdate struct {
+	Status string `json:"status"`
+}
+
+// Valid returns an error if the update does not acknowledge or resolve the
+// incident.
+func (u IncidentUpdate) Valid() error {
+	if u.Status != IncidentAcknowledged && u.Status != IncidentResolved {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "incident status must be one of acknowledged or resolved",
+		}
+	}
+	return nil
+}
+
+// Apply applies the update to the incident on behalf of the user.
+func (u IncidentUpdate) Apply(i *Incident, userID ID, now time.Time) error {
+	if err := u.Valid(); err != nil {
+		return err
+	}
+	if u.Status == IncidentAcknowledged {
+		return i.Acknowledge(userID, now)
+	}
+	return i.Resolve(userID, now)
+}
+
+// IncidentFilter represents a set of filters that restrict the returned
+// incidents.
+type IncidentFilter struct {
+	OrgID   *ID
+	CheckID *ID
+	Status  *string
+}
+
+// IncidentService represents a service for managing the incidents of the
+// checks.
+type IncidentService interface {
+	// FindIncidentByID returns a single incident by ID.
+	FindIncidentByID(ctx context.Context, id ID) (*Incident, error)
+
+	// FindIncidents returns the incidents matching the filter, the most
+	// recent first.
+	FindIncidents(ctx context.Context, filter IncidentFilter) ([]*Incident, error)
+
+	// UpdateIncident acknowledges or resolves a single incident on behalf of
+	// the user.
+	UpdateIncident(ctx context.Context, id ID, upd IncidentUpdate, userID ID) (*Incident, error)
+}
@@ -0,0 +1,164 @@
+package incident
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/monitoring"
+	"github.com/influxdata/influxdb/v2/query"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultTrackInterval is the default period between two reads of the
+	// statuses of the checks.
+	DefaultTrackInterval = 30 * time.Second
+
+	// DefaultLookback is how far back the statuses of an organization are
+	// read the first time it is tracked.
+	DefaultLookback = time.Hour
+)
+
+// StatusRecorder groups the statuses of the checks of an organization into
+// incidents. Recording the same statuses more than once must not change the
+// incidents.
+type StatusRecorder interface {
+	RecordCheckStatuses(ctx context.Context, orgID influxdb.ID, statuses []influxdb.CheckStatus) error
+}
+
+// Tracker periodically reads the statuses written by the checks into the
+// _monitoring bucket of every organization and records them into incidents.
+type Tracker struct {
+	log    *zap.Logger
+	qs     query.QueryService
+	rec    StatusRecorder
+	poller *monitoring.Poller
+}
+
+// NewTracker returns a tracker that records the statuses of the checks into
+// rec every interval.
+func NewTracker(log *zap.Logger, orgs influxdb.OrganizationService, buckets influxdb.BucketService, qs query.QueryService, rec StatusRecorder, interval time.Duration) *Tracker {
+	if interval <= 0 {
+		interval = DefaultTrackInterval
+	}
+	t := &Tracker{
+		log: log,
+		qs:  qs,
+		rec: rec,
+	}
+	t.poller = monitoring.NewPoller(log, orgs, buckets, t, interval, DefaultLookback)
+	return t
+}
+
+// Run tracks the statuses of the checks every interval until ctx is done.
+func (t *Tracker) Run(ctx context.Context) error {
+	return t.poller.Run(ctx)
+}
+
+// Track records the statuses written since the last call into the incidents
+// of every organization. The recorder ignores the statuses it already
+// recorded.
+func (t *Tracker) Track(ctx context.Context) error {
+	return t.poller.Poll(ctx)
+}
+
+// Read records the statuses of the range into the incidents of the
+// organization.
+func (t *Tracker) Read(ctx context.Context, orgID, bucketID influxdb.ID, start, stop time.Time) error {
+	script := fmt.Sprintf(`from(bucketID: %q)
+	  |> range(start: %s, stop: %s)
+	  |> filter(fn: (r) => r._measurement == "statuses" and r._field == "_message")
+	  |> group()
+	  |> sort(columns: ["_time"])
+	  `, bucketID.String(), start.Format(time.RFC3339Nano), stop.Format(time.RFC3339Nano))
+
+	sr := &statusReader{log: t.log.With(zap.Stringer("orgID", orgID))}
+	if err := monitoring.QueryBucket(ctx, t.qs, orgID, bucketID, script, sr.readTable); err != nil {
+		return err
+	}
+	return t.rec.RecordCheckStatuses(ctx, orgID, sr.statuses)
+}
+
+// Done implements monitoring.Reader.
+func (t *Tracker) Done(ctx context.Context) error {
+	return nil
+}
+
+// statusColumns are the columns of the statuses which are not tags of the
+// series the check computed the status for.
+var statusColumns = map[string]bool{
+	"result":              true,
+	"table":               true,
+	"_start":              true,
+	"_stop":               true,
+	"_time":               true,
+	"_value":              true,
+	"_field":              true,
+	"_measurement":        true,
+	"_check_id":           true,
+	"_check_name":         true,
+	"_level":              true,
+	"_source_measurement": true,
+	"_type":               true,
+}
+
+type statusReader struct {
+	statuses []influxdb.CheckStatus
+	log      *zap.Logger
+}
+
+func (sr *statusReader) readTable(tbl flux.Table) error {
+	return tbl.Do(sr.readStatuses)
+}
+
+func (sr *statusReader) readStatuses(cr flux.ColReader) error {
+	for i := 0; i < cr.Len(); i++ {
+		var st influxdb.CheckStatus
+		for j, col := range cr.Cols() {
+			switch col.Type {
+			case flux.TTime:
+				if col.Label == "_time" && cr.Times(j).IsValid(i) {
+					st.Time = values.Time(cr.Times(j).Value(i)).Time().UTC()
+				}
+				continue
+			case flux.TString:
+			default:
+				continue
+			}
+			if !cr.Strings(j).IsValid(i) {
+				continue
+			}
+
+			v := cr.Strings(j).ValueString(i)
+			switch col.Label {
+			case "_value":
+				st.Message = v
+			case "_check_id":
+				id, err := influxdb.IDFromString(v)
+				if err != nil {
+					sr.log.Info("Failed to parse check ID of status", zap.Error(err))
+					continue
+				}
+				st.CheckID = *id
+			case "_check_name":
+				st.CheckName = v
+			case "_level":
+				st.Level = v
+			default:
+				if !statusColumns[col.Label] {
+					st.Tags = append(st.Tags, influxdb.Tag{Key: col.Label, Value: v})
+				}
+			}
+		}
+
+		// statuses missing their check or level can not be grouped.
+		if st.CheckID.Valid() && st.Level != "" {
+			sr.statuses = append(sr.statuses, st)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package incident_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/incident"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/query"
+	qmock "github.com/influxdata/influxdb/v2/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+type recorder struct {
+	orgID    influxdb.ID
+	statuses []influxdb.CheckStatus
+}
+
+func (r *recorder) RecordCheckStatuses(ctx context.Context, orgID influxdb.ID, statuses []influxdb.CheckStatus) error {
+	r.orgID = orgID
+	r.statuses = append(r.statuses, statuses...)
+	return nil
+}
+
+func TestTracker_Track(t *testing.T) {
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationsF = func(ctx context.Context, filter influxdb.OrganizationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Organization, int, error) {
+		return []*influxdb.Organization{{ID: orgID, Name: "org"}}, 1, nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		if id != orgID || name != influxdb.MonitoringSystemBucketName {
+			t.Errorf("unexpected bucket lookup %s %q", id, name)
+		}
+		return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: name}, nil
+	}
+
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			q := req.Compiler.(lang.FluxCompiler).Query
+			if !strings.Contains(q, `from(bucketID: "0000000000000002")`) {
+				t.Errorf("unexpected query:\n%s", q)
+			}
+			if req.OrganizationID != orgID {
+				t.Errorf("unexpected organization %s", req.OrganizationID)
+			}
+
+			tbl := &executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_start", Type: flux.TTime},
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TString},
+					{Label: "_check_id", Type: flux.TString},
+					{Label: "_check_name", Type: flux.TString},
+					{Label: "_level", Type: flux.TString},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(0), execute.Time(t0.UnixNano()), "high cpu", "0000000000000003", "cpu", "crit", "statuses", "db1"},
+					{execute.Time(0), execute.Time(t0.Add(time.Minute).UnixNano()), "cpu ok", "0000000000000003", "cpu", "ok", "statuses", nil},
+					{execute.Time(0), execute.Time(t0.UnixNano()), "no check", nil, "cpu", "crit", "statuses", "db1"},
+				},
+			}
+			return flux.NewSliceResultIterator([]flux.Result{executetest.NewResult([]*executetest.Table{tbl})}), nil
+		},
+	}
+
+	rec := &recorder{}
+	tracker := incident.NewTracker(zaptest.NewLogger(t), orgs, buckets, qs, rec, time.Minute)
+	if err := tracker.Track(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []influxdb.CheckStatus{
+		{
+			CheckID:   3,
+			CheckName: "cpu",
+			Level:     "crit",
+			Message:   "high cpu",
+			Time:      t0,
+			Tags:      []influxdb.Tag{{Key: "host", Value: "db1"}},
+		},
+		{
+			CheckID:   3,
+			CheckName: "cpu",
+			Level:     "ok",
+			Message:   "cpu ok",
+			Time:      t0.Add(time.Minute),
+		},
+	}
+	if rec.orgID != orgID {
+		t.Errorf("unexpected organization %s", rec.orgID)
+	}
+	if !reflect.DeepEqual(rec.statuses, want) {
+		t.Errorf("unexpected statuses:\n%+v\nwant:\n%+v", rec.statuses, want)
+	}
+}
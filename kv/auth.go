@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/buger/jsonparser"
 	influxdb "github.com/influxdata/influxdb/v2"
 	jsonp "github.com/influxdata/influxdb/v2/pkg/jsonparser"
 )
@@ -75,6 +74,7 @@ func (s *Service) findAuthorizationByID(ctx context.Context, tx Tx, id influxdb.
 }
 
 // FindAuthorizationByToken returns a authorization by token for a particular authorization.
+// An error is returned if the authorization has expired.
 func (s *Service) FindAuthorizationByToken(ctx context.Context, n string) (*influxdb.Authorization, error) {
 	var a *influxdb.Authorization
 	err := s.kv.View(ctx, func(tx Tx) error {
@@ -92,6 +92,10 @@ func (s *Service) FindAuthorizationByToken(ctx context.Context, n string) (*infl
 		return nil, err
 	}
 
+	if err := a.Expired(); err != nil {
+		return nil, err
+	}
+
 	return a, nil
 }
 
@@ -109,6 +113,20 @@ func (s *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string)
 		}
 	}
 
+	token, err := findHashedToken(tx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	// the index is keyed by the token prefix, the salted hash tells whether
+	// the authorization found is the one of the token.
+	if !token.Matches(n) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "authorization not found",
+		}
+	}
+
 	var id influxdb.ID
 	if err := id.Decode(a); err != nil {
 		return nil, &influxdb.Error{
@@ -116,7 +134,45 @@ func (s *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string)
 			Err:  err,
 		}
 	}
-	return s.findAuthorizationByID(ctx, tx, id)
+
+	auth, err := s.findAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the token is not stored, but the caller already knows it
+	auth.Token = n
+	return auth, nil
+}
+
+// findHashedToken returns the hashed token of the authorization with the
+// encoded ID.
+func findHashedToken(tx Tx, encodedID []byte) (HashedToken, error) {
+	var token HashedToken
+
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return token, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return token, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "authorization not found",
+		}
+	}
+	if err != nil {
+		return token, err
+	}
+
+	if err := json.Unmarshal(v, &token); err != nil {
+		return token, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return token, nil
 }
 
 func authorizationsPredicateFn(f influxdb.AuthorizationFilter) CursorPredicateFunc {
@@ -134,18 +190,6 @@ func authorizationsPredicateFn(f influxdb.AuthorizationFilter) CursorPredicateFu
 		}
 	}
 
-	if f.Token != nil {
-		exp := *f.Token
-		return func(_, value []byte) bool {
-			// it is assumed that token never has escaped string data
-			got, _, _, err := jsonparser.Get(value, "token")
-			if err != nil {
-				return true
-			}
-			return string(got) == exp
-		}
-	}
-
 	var pred CursorPredicateFunc
 	if f.OrgID != nil {
 		exp := *f.OrgID
@@ -179,12 +223,6 @@ func filterAuthorizationsFn(filter influxdb.AuthorizationFilter) func(a *influxd
 		}
 	}
 
-	if filter.Token != nil {
-		return func(a *influxdb.Authorization) bool {
-			return a.Token == *filter.Token
-		}
-	}
-
 	// Filter by org and user
 	if filter.OrgID != nil && filter.UserID != nil {
 		return func(a *influxdb.Authorization) bool {
@@ -347,7 +385,9 @@ func (s *Service) PutAuthorization(ctx context.Context, a *influxdb.Authorizatio
 	})
 }
 
-func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
+// encodeAuthorization encodes a with the hash of its token in place of the
+// token itself.
+func encodeAuthorization(a *influxdb.Authorization, token HashedToken) ([]byte, error) {
 	switch a.Status {
 	case influxdb.Active, influxdb.Inactive:
 	case "":
@@ -359,22 +399,39 @@ func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(a)
+	stored := *a
+	stored.Token = ""
+	return json.Marshal(struct {
+		*influxdb.Authorization
+		HashedToken
+	}{&stored, token})
 }
 
+// putAuthorization stores a. The token of a is hashed if it is set, otherwise
+// the hash of the stored authorization is kept.
 func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
-	v, err := encodeAuthorization(a)
+	encodedID, err := a.ID.Encode()
 	if err != nil {
 		return &influxdb.Error{
-			Code: influxdb.EInvalid,
+			Code: influxdb.ENotFound,
 			Err:  err,
 		}
 	}
 
-	encodedID, err := a.ID.Encode()
+	var token HashedToken
+	if a.Token != "" {
+		token, err = HashToken(a.Token)
+	} else {
+		token, err = findHashedToken(tx, encodedID)
+	}
+	if err != nil {
+		return err
+	}
+
+	v, err := encodeAuthorization(a, token)
 	if err != nil {
 		return &influxdb.Error{
-			Code: influxdb.ENotFound,
+			Code: influxdb.EInvalid,
 			Err:  err,
 		}
 	}
@@ -384,7 +441,7 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 		return err
 	}
 
-	if err := idx.Put(authIndexKey(a.Token), encodedID); err != nil {
+	if err := idx.Put([]byte(token.Prefix), encodedID); err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInternal,
 			Err:  err,
@@ -405,8 +462,9 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 	return nil
 }
 
-func authIndexKey(n string) []byte {
-	return []byte(n)
+// authIndexKey returns the key of token in the token index.
+func authIndexKey(token string) []byte {
+	return []byte(TokenPrefix(token))
 }
 
 func decodeAuthorization(b []byte, a *influxdb.Authorization) error {
@@ -461,7 +519,14 @@ func (s *Service) DeleteAuthorization(ctx context.Context, id influxdb.ID) error
 }
 
 func (s *Service) deleteAuthorization(ctx context.Context, tx Tx, id influxdb.ID) error {
-	a, err := s.findAuthorizationByID(ctx, tx, id)
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	token, err := findHashedToken(tx, encodedID)
 	if err != nil {
 		return err
 	}
@@ -471,13 +536,7 @@ func (s *Service) deleteAuthorization(ctx context.Context, tx Tx, id influxdb.ID
 		return err
 	}
 
-	if err := idx.Delete(authIndexKey(a.Token)); err != nil {
-		return &influxdb.Error{
-			Err: err,
-		}
-	}
-	encodedID, err := id.Encode()
-	if err != nil {
+	if err := idx.Delete([]byte(token.Prefix)); err != nil {
 		return &influxdb.Error{
 			Err: err,
 		}
@@ -0,0 +1,66 @@
+package kv
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// tokenSaltSize is the number of random bytes used to salt a token hash.
+const tokenSaltSize = 16
+
+// tokenPrefixSize is the number of bytes of the unsalted token digest kept as
+// the lookup prefix of a token.
+const tokenPrefixSize = 8
+
+// HashedToken is how an authorization token is persisted. The plaintext token
+// is never stored. The prefix is the key of the token index, the salted hash
+// is used to check a token found through that index.
+type HashedToken struct {
+	Prefix string `json:"tokenPrefix"`
+	Salt   string `json:"tokenSalt"`
+	Hash   string `json:"tokenHash"`
+}
+
+// TokenPrefix returns the lookup prefix of token. The prefix is a truncated
+// digest of the token so that it reveals nothing usable about the token itself.
+func TokenPrefix(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:tokenPrefixSize])
+}
+
+// HashToken returns the lookup prefix and the hash of token, salted with
+// newly generated random bytes.
+func HashToken(token string) (HashedToken, error) {
+	salt := make([]byte, tokenSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return HashedToken{}, err
+	}
+
+	return HashedToken{
+		Prefix: TokenPrefix(token),
+		Salt:   hex.EncodeToString(salt),
+		Hash:   hex.EncodeToString(saltedTokenHash(salt, token)),
+	}, nil
+}
+
+// Matches reports whether token is the token that was hashed.
+func (h HashedToken) Matches(token string) bool {
+	salt, err := hex.DecodeString(h.Salt)
+	if err != nil {
+		return false
+	}
+	hash, err := hex.DecodeString(h.Hash)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(hash, saltedTokenHash(salt, token)) == 1
+}
+
+func saltedTokenHash(salt []byte, token string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(token))
+	return h.Sum(nil)
+}
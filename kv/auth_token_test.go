@@ -0,0 +1,35 @@
+package kv_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+func TestHashToken(t *testing.T) {
+	h1, err := kv.HashToken("my-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := kv.HashToken("my-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h1.Prefix != kv.TokenPrefix("my-token") || h1.Prefix != h2.Prefix {
+		t.Errorf("expected prefix of the same token to be stable, got %q and %q", h1.Prefix, h2.Prefix)
+	}
+	if h1.Salt == h2.Salt || h1.Hash == h2.Hash {
+		t.Error("expected hashes of the same token to be salted differently")
+	}
+
+	if !h1.Matches("my-token") || !h2.Matches("my-token") {
+		t.Error("expected hashes to match their token")
+	}
+	if h1.Matches("other-token") {
+		t.Error("expected hash not to match another token")
+	}
+	if (kv.HashedToken{}).Matches("my-token") {
+		t.Error("expected empty hash not to match any token")
+	}
+}
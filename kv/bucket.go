@@ -472,6 +472,14 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) (
 		return err
 	}
 
+	if err := b.ValidateRetentionTiers(); err != nil {
+		return err
+	}
+
+	if err := b.ValidateShardGroupDuration(); err != nil {
+		return err
+	}
+
 	if b.ID, err = s.generateBucketID(ctx, tx); err != nil {
 		return err
 	}
@@ -700,6 +708,22 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.RetentionTiers != nil {
+		b.RetentionTiers = *upd.RetentionTiers
+	}
+
+	if upd.ShardGroupDuration != nil {
+		b.ShardGroupDuration = *upd.ShardGroupDuration
+	}
+
+	if err := b.ValidateRetentionTiers(); err != nil {
+		return nil, err
+	}
+
+	if err := b.ValidateShardGroupDuration(); err != nil {
+		return nil, err
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
@@ -0,0 +1,117 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var emailDeliveryBucket = []byte("emaildeliveriesv1")
+
+// InternalEmailDeliveryStoreError is used when the error comes from an
+// internal system.
+func InternalEmailDeliveryStoreError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  fmt.Sprintf("Unknown internal email delivery data error; Err: %v", err),
+		Op:   "kv/emailDelivery",
+	}
+}
+
+func (s *Service) emailDeliveryBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(emailDeliveryBucket)
+	if err != nil {
+		return nil, InternalEmailDeliveryStoreError(err)
+	}
+	return b, nil
+}
+
+// EmailDelivered returns whether the email notification identified by key
+// has already been delivered.
+func (s *Service) EmailDelivered(ctx context.Context, key string) (bool, error) {
+	var delivered bool
+	err := s.kv.View(ctx, func(tx Tx) error {
+		bucket, err := s.emailDeliveryBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		_, err = bucket.Get([]byte(key))
+		if IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return InternalEmailDeliveryStoreError(err)
+		}
+		delivered = true
+		return nil
+	})
+	return delivered, err
+}
+
+// RecordEmailDelivery records that the email notification identified by key
+// has been delivered at t.
+func (s *Service) RecordEmailDelivery(ctx context.Context, key string, t time.Time) error {
+	v, err := t.UTC().MarshalText()
+	if err != nil {
+		return InternalEmailDeliveryStoreError(err)
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		bucket, err := s.emailDeliveryBucket(tx)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put([]byte(key), v); err != nil {
+			return InternalEmailDeliveryStoreError(err)
+		}
+		return nil
+	})
+}
+
+// DeleteEmailDeliveries forgets the email notifications delivered before the
+// given time.
+func (s *Service) DeleteEmailDeliveries(ctx context.Context, before time.Time) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		bucket, err := s.emailDeliveryBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		keys, err := emailDeliveriesBefore(bucket, before)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := bucket.Delete(k); err != nil {
+				return InternalEmailDeliveryStoreError(err)
+			}
+		}
+		return nil
+	})
+}
+
+func emailDeliveriesBefore(bucket Bucket, before time.Time) ([][]byte, error) {
+	cur, err := bucket.ForwardCursor(nil)
+	if err != nil {
+		return nil, InternalEmailDeliveryStoreError(err)
+	}
+	defer cur.Close()
+
+	var keys [][]byte
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		var t time.Time
+		if err := t.UnmarshalText(v); err != nil {
+			return nil, InternalEmailDeliveryStoreError(err)
+		}
+		if t.Before(before) {
+			keys = append(keys, k)
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return nil, InternalEmailDeliveryStoreError(err)
+	}
+	return keys, nil
+}
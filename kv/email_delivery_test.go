@@ -0,0 +1,50 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_EmailDeliveries(t *testing.T) {
+	ctx := context.Background()
+
+	store, closeBolt, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+	if err := svc.RecordEmailDelivery(ctx, "old", t0); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RecordEmailDelivery(ctx, "new", t0.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	assertDelivered := func(key string, want bool) {
+		t.Helper()
+		got, err := svc.EmailDelivered(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("expected delivery of %q to be %v, got %v", key, want, got)
+		}
+	}
+	assertDelivered("old", true)
+	assertDelivered("new", true)
+	assertDelivered("other", false)
+
+	if err := svc.DeleteEmailDeliveries(ctx, t0.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	assertDelivered("old", false)
+	assertDelivered("new", true)
+}
@@ -0,0 +1,375 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	incidentBucket      = []byte("incidentsv1")
+	incidentSeriesIndex = []byte("incidentseriesv1")
+
+	// ErrIncidentNotFound is used when the incident is not found.
+	ErrIncidentNotFound = &influxdb.Error{
+		Msg:  "incident not found",
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidIncidentID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidIncidentID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided incident ID has invalid format",
+	}
+)
+
+var _ influxdb.IncidentService = (*Service)(nil)
+
+// InternalIncidentStoreError is used when the error comes from an
+// internal system.
+func InternalIncidentStoreError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  fmt.Sprintf("Unknown internal incident data error; Err: %v", err),
+		Op:   "kv/incident",
+	}
+}
+
+func (s *Service) incidentBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(incidentBucket)
+	if err != nil {
+		return nil, InternalIncidentStoreError(err)
+	}
+	return b, nil
+}
+
+func (s *Service) incidentSeriesIndex(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(incidentSeriesIndex)
+	if err != nil {
+		return nil, InternalIncidentStoreError(err)
+	}
+	return b, nil
+}
+
+// FindIncidentByID returns a single incident by ID.
+func (s *Service) FindIncidentByID(ctx context.Context, id influxdb.ID) (*influxdb.Incident, error) {
+	var i *influxdb.Incident
+	err := s.kv.View(ctx, func(tx Tx) (err error) {
+		i, err = s.findIncidentByID(ctx, tx, id)
+		return err
+	})
+	return i, err
+}
+
+func (s *Service) findIncidentByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Incident, error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidIncidentID
+	}
+
+	bucket, err := s.incidentBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := bucket.Get(encID)
+	if IsNotFound(err) {
+		return nil, ErrIncidentNotFound
+	}
+	if err != nil {
+		return nil, InternalIncidentStoreError(err)
+	}
+
+	var i influxdb.Incident
+	if err := json.Unmarshal(v, &i); err != nil {
+		return nil, InternalIncidentStoreError(err)
+	}
+	return &i, nil
+}
+
+// FindIncidents returns the incidents matching the filter, the most recent
+// first.
+func (s *Service) FindIncidents(ctx context.Context, filter influxdb.IncidentFilter) ([]*influxdb.Incident, error) {
+	var is []*influxdb.Incident
+	err := s.kv.View(ctx, func(tx Tx) (err error) {
+		is, err = s.findIncidents(ctx, tx, filter)
+		return err
+	})
+	return is, err
+}
+
+func (s *Service) findIncidents(ctx context.Context, tx Tx, filter influxdb.IncidentFilter) ([]*influxdb.Incident, error) {
+	bucket, err := s.incidentBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := bucket.ForwardCursor(nil)
+	if err != nil {
+		return nil, InternalIncidentStoreError(err)
+	}
+	defer cur.Close()
+
+	is := make([]*influxdb.Incident, 0)
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		var i influxdb.Incident
+		if err := json.Unmarshal(v, &i); err != nil {
+			return nil, InternalIncidentStoreError(err)
+		}
+		if filter.OrgID != nil && i.OrgID != *filter.OrgID {
+			continue
+		}
+		if filter.CheckID != nil && i.CheckID != *filter.CheckID {
+			continue
+		}
+		if filter.Status != nil && i.Status != *filter.Status {
+			continue
+		}
+		is = append(is, &i)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, InternalIncidentStoreError(err)
+	}
+
+	sort.SliceStable(is, func(i, j int) bool {
+		return is[i].Start.After(is[j].Start)
+	})
+	return is, nil
+}
+
+// UpdateIncident acknowledges or resolves a single incident on behalf of the
+// user. The tasks of the notification rules of its organization are
+// regenerated when an acknowledged incident changes, so that they stop or
+// resume notifying the statuses of the incident.
+func (s *Service) UpdateIncident(ctx context.Context, id influxdb.ID, upd influxdb.IncidentUpdate, userID influxdb.ID) (*influxdb.Incident, error) {
+	if err := upd.Valid(); err != nil {
+		return nil, err
+	}
+
+	var i *influxdb.Incident
+	err := s.kv.Update(ctx, func(tx Tx) (err error) {
+		i, err = s.findIncidentByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		wasAcknowledged := i.Status == influxdb.IncidentAcknowledged
+		now := s.TimeGenerator.Now()
+		if err := upd.Apply(i, userID, now); err != nil {
+			return err
+		}
+		i.SetUpdatedAt(now)
+		if err := s.putIncident(ctx, tx, i); err != nil {
+			return err
+		}
+
+		if wasAcknowledged || i.Status == influxdb.IncidentAcknowledged {
+			return s.updateOrgNotificationTasks(ctx, tx, i.OrgID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// RecordCheckStatuses groups the statuses written by the checks of the
+// organization into incidents. A status which is not ok extends the
+// unresolved incident of its series, or opens a new one, and an ok status
+// resolves it. Statuses must be sorted by time, the ones older than the
+// latest incident of their series are ignored so that statuses may be
+// recorded more than once.
+func (s *Service) RecordCheckStatuses(ctx context.Context, orgID influxdb.ID, statuses []influxdb.CheckStatus) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		var regenerate bool
+		for _, st := range statuses {
+			i, err := s.findSeriesIncident(ctx, tx, orgID, st)
+			if err != nil {
+				return err
+			}
+
+			if i != nil && !st.Time.After(i.LastSeen) {
+				continue
+			}
+			if i != nil && i.Status == influxdb.IncidentResolved && !st.Time.After(*i.ResolvedAt) {
+				continue
+			}
+
+			now := s.TimeGenerator.Now()
+			if st.Level == influxdb.CheckLevelOK {
+				if i == nil || i.Status == influxdb.IncidentResolved {
+					continue
+				}
+				regenerate = regenerate || i.Status == influxdb.IncidentAcknowledged
+				if err := i.Resolve(0, st.Time); err != nil {
+					return err
+				}
+				i.SetUpdatedAt(now)
+				if err := s.putIncident(ctx, tx, i); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if i == nil || i.Status == influxdb.IncidentResolved {
+				i = &influxdb.Incident{
+					ID:        s.IDGenerator.ID(),
+					OrgID:     orgID,
+					CheckID:   st.CheckID,
+					CheckName: st.CheckName,
+					Tags:      st.Tags,
+					Status:    influxdb.IncidentOpen,
+					Start:     st.Time,
+				}
+				i.SetCreatedAt(now)
+				if err := s.putSeriesIncident(ctx, tx, i); err != nil {
+					return err
+				}
+			}
+			i.Level = st.Level
+			i.Message = st.Message
+			i.LastSeen = st.Time
+			i.StatusCount++
+			i.SetUpdatedAt(now)
+			if err := s.putIncident(ctx, tx, i); err != nil {
+				return err
+			}
+		}
+
+		if regenerate {
+			return s.updateOrgNotificationTasks(ctx, tx, orgID)
+		}
+		return nil
+	})
+}
+
+// findSeriesIncident returns the latest incident of the series of the
+// status, or nil if the series never had one.
+func (s *Service) findSeriesIncident(ctx context.Context, tx Tx, orgID influxdb.ID, st influxdb.CheckStatus) (*influxdb.Incident, error) {
+	key, err := incidentSeriesKey(orgID, st.CheckID, st.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := s.incidentSeriesIndex(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := idx.Get(key)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, InternalIncidentStoreError(err)
+	}
+
+	var id influxdb.ID
+	if err := id.Decode(v); err != nil {
+		return nil, InternalIncidentStoreError(err)
+	}
+	return s.findIncidentByID(ctx, tx, id)
+}
+
+// putSeriesIncident makes the incident the latest one of its series.
+func (s *Service) putSeriesIncident(ctx context.Context, tx Tx, i *influxdb.Incident) error {
+	key, err := incidentSeriesKey(i.OrgID, i.CheckID, i.Tags)
+	if err != nil {
+		return err
+	}
+
+	encID, err := i.ID.Encode()
+	if err != nil {
+		return ErrInvalidIncidentID
+	}
+
+	idx, err := s.incidentSeriesIndex(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Put(key, encID); err != nil {
+		return InternalIncidentStoreError(err)
+	}
+	return nil
+}
+
+func (s *Service) putIncident(ctx context.Context, tx Tx, i *influxdb.Incident) error {
+	encID, err := i.ID.Encode()
+	if err != nil {
+		return ErrInvalidIncidentID
+	}
+
+	v, err := json.Marshal(i)
+	if err != nil {
+		return InternalIncidentStoreError(err)
+	}
+
+	bucket, err := s.incidentBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(encID, v); err != nil {
+		return InternalIncidentStoreError(err)
+	}
+	return nil
+}
+
+// incidentSeriesKey returns the key of the series of a check in the
+// organization, which is made of the IDs and of the sorted tags of the
+// series.
+func incidentSeriesKey(orgID, checkID influxdb.ID, tags []influxdb.Tag) ([]byte, error) {
+	encOrgID, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "provided organization ID has invalid format",
+			Err:  err,
+		}
+	}
+	encCheckID, err := checkID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "provided check ID has invalid format",
+			Err:  err,
+		}
+	}
+
+	pairs := make([]string, 0, len(tags))
+	for _, t := range tags {
+		pairs = append(pairs, t.Key+"="+t.Value)
+	}
+	sort.Strings(pairs)
+
+	key := make([]byte, 0, len(encOrgID)+len(encCheckID)+1)
+	key = append(key, encOrgID...)
+	key = append(key, encCheckID...)
+	key = append(key, ',')
+	key = append(key, strings.Join(pairs, ",")...)
+	return key, nil
+}
+
+// acknowledgedIncidents returns the acknowledged incidents of the
+// organization, whose statuses are not notified by the tasks of its
+// notification rules.
+func (s *Service) acknowledgedIncidents(ctx context.Context, tx Tx, orgID influxdb.ID) ([]influxdb.Incident, error) {
+	status := influxdb.IncidentAcknowledged
+	is, err := s.findIncidents(ctx, tx, influxdb.IncidentFilter{OrgID: &orgID, Status: &status})
+	if err != nil {
+		return nil, err
+	}
+
+	incidents := make([]influxdb.Incident, 0, len(is))
+	for _, i := range is {
+		incidents = append(incidents, *i)
+	}
+	return incidents, nil
+}
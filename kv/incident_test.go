@@ -0,0 +1,173 @@
+package kv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_Incidents(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store, closeBolt, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	svc := kv.NewService(zaptest.NewLogger(t), store, kv.ServiceConfig{
+		FluxLanguageService: fluxlang.DefaultService,
+	})
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	ep := &endpoint.Slack{
+		Base: endpoint.Base{
+			Name:   "slack",
+			OrgID:  &org.ID,
+			Status: influxdb.Active,
+		},
+		URL: "http://localhost:7777",
+	}
+	if err := svc.CreateNotificationEndpoint(ctx, ep, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	every, err := notification.FromTimeDuration(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nr := &rule.Slack{
+		Base: rule.Base{
+			Name:        "crit",
+			OrgID:       org.ID,
+			EndpointID:  *ep.ID,
+			Every:       &every,
+			StatusRules: []notification.StatusRule{{CurrentLevel: notification.Critical}},
+		},
+		MessageTemplate: "msg",
+	}
+	nrc := influxdb.NotificationRuleCreate{NotificationRule: nr, Status: influxdb.Active}
+	if err := svc.CreateNotificationRule(ctx, nrc, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	muted := func() bool {
+		t.Helper()
+		task, err := svc.FindTaskByID(ctx, nr.GetTaskID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Contains(task.Flux, "not (")
+	}
+
+	db1 := []influxdb.Tag{{Key: "host", Value: "db1"}}
+	db2 := []influxdb.Tag{{Key: "host", Value: "db2"}}
+	status := func(level string, tags []influxdb.Tag, d time.Duration) influxdb.CheckStatus {
+		return influxdb.CheckStatus{CheckID: 1, CheckName: "cpu", Level: level, Tags: tags, Time: now.Add(d)}
+	}
+
+	statuses := []influxdb.CheckStatus{
+		status("warn", db1, 0),
+		status("crit", db1, time.Minute),
+		status("ok", db2, time.Minute),
+		status("crit", db2, 2*time.Minute),
+	}
+	if err := svc.RecordCheckStatuses(ctx, org.ID, statuses); err != nil {
+		t.Fatal(err)
+	}
+	// Statuses recorded twice do not change the incidents.
+	if err := svc.RecordCheckStatuses(ctx, org.ID, statuses); err != nil {
+		t.Fatal(err)
+	}
+
+	is, err := svc.FindIncidents(ctx, influxdb.IncidentFilter{OrgID: &org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(is) != 2 {
+		t.Fatalf("expected 2 incidents, got %d", len(is))
+	}
+	i2, i1 := is[0], is[1]
+	if i1.Status != influxdb.IncidentOpen || i1.StatusCount != 2 || i1.Level != "crit" || !i1.Start.Equal(now) {
+		t.Errorf("unexpected incident of db1: %+v", i1)
+	}
+	if i2.StatusCount != 1 || !i2.Start.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("unexpected incident of db2: %+v", i2)
+	}
+
+	if muted() {
+		t.Fatal("expected open incidents not to be muted")
+	}
+
+	upd := influxdb.IncidentUpdate{Status: influxdb.IncidentAcknowledged}
+	i1, err = svc.UpdateIncident(ctx, i1.ID, upd, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i1.AcknowledgedBy != 2 || i1.AcknowledgedAt == nil {
+		t.Errorf("unexpected acknowledged incident: %+v", i1)
+	}
+	if !muted() {
+		t.Fatal("expected the acknowledged incident to be muted")
+	}
+	if _, err := svc.UpdateIncident(ctx, i1.ID, upd, 2); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected acknowledging twice to conflict, got %v", err)
+	}
+
+	// The series of the acknowledged incident got back to ok, and fails
+	// again in a new incident.
+	statuses = []influxdb.CheckStatus{
+		status("ok", db1, 3*time.Minute),
+		status("crit", db1, 4*time.Minute),
+	}
+	if err := svc.RecordCheckStatuses(ctx, org.ID, statuses); err != nil {
+		t.Fatal(err)
+	}
+	if muted() {
+		t.Fatal("expected the recovered incident not to be muted anymore")
+	}
+
+	i1, err = svc.FindIncidentByID(ctx, i1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i1.Status != influxdb.IncidentResolved || i1.ResolvedBy.Valid() || !i1.ResolvedAt.Equal(now.Add(3*time.Minute)) {
+		t.Errorf("unexpected recovered incident: %+v", i1)
+	}
+
+	open := influxdb.IncidentOpen
+	is, err = svc.FindIncidents(ctx, influxdb.IncidentFilter{OrgID: &org.ID, Status: &open})
+	if err != nil || len(is) != 2 {
+		t.Fatalf("expected 2 open incidents, got %d, %v", len(is), err)
+	}
+
+	if _, err := svc.UpdateIncident(ctx, i1.ID, influxdb.IncidentUpdate{Status: influxdb.IncidentResolved}, 2); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected resolving a resolved incident to conflict, got %v", err)
+	}
+	if _, err := svc.UpdateIncident(ctx, i2.ID, influxdb.IncidentUpdate{Status: influxdb.IncidentOpen}, 2); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected reopening an incident to be invalid, got %v", err)
+	}
+	i2, err = svc.UpdateIncident(ctx, i2.ID, influxdb.IncidentUpdate{Status: influxdb.IncidentResolved}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i2.ResolvedBy != 2 {
+		t.Errorf("unexpected resolved incident: %+v", i2)
+	}
+}
@@ -0,0 +1,114 @@
+package all
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+var (
+	authorizationBucket      = []byte("authorizationsv1")
+	authorizationIndexBucket = []byte("authorizationindexv1")
+)
+
+// Migration0007_HashAuthorizationTokens replaces the plaintext token of every
+// authorization with its salted hash and rekeys the token index by the token
+// lookup prefix.
+var Migration0007_HashAuthorizationTokens = UpOnlyMigration(
+	"hash authorization tokens",
+	func(ctx context.Context, store kv.SchemaStore) error {
+		return store.Update(ctx, func(tx kv.Tx) error {
+			auths, err := tx.Bucket(authorizationBucket)
+			if err != nil {
+				return err
+			}
+
+			idx, err := tx.Bucket(authorizationIndexBucket)
+			if err != nil {
+				return err
+			}
+
+			type plaintextAuth struct {
+				key    []byte
+				fields map[string]json.RawMessage
+				token  string
+			}
+
+			// collect the authorizations which still hold their token before
+			// modifying the bucket the cursor is reading.
+			var plaintext []plaintextAuth
+			c, err := auths.ForwardCursor(nil)
+			if err != nil {
+				return err
+			}
+
+			for k, v := c.Next(); k != nil; k, v = c.Next() {
+				var fields map[string]json.RawMessage
+				if err := json.Unmarshal(v, &fields); err != nil {
+					return err
+				}
+
+				var token string
+				if raw, ok := fields["token"]; ok {
+					if err := json.Unmarshal(raw, &token); err != nil {
+						return err
+					}
+				}
+				if token == "" {
+					// already hashed
+					continue
+				}
+
+				plaintext = append(plaintext, plaintextAuth{
+					key:    append([]byte(nil), k...),
+					fields: fields,
+					token:  token,
+				})
+			}
+			if err := c.Err(); err != nil {
+				return err
+			}
+			if err := c.Close(); err != nil {
+				return err
+			}
+
+			for _, a := range plaintext {
+				hashed, err := kv.HashToken(a.token)
+				if err != nil {
+					return err
+				}
+
+				for name, value := range map[string]string{
+					"token":       "",
+					"tokenPrefix": hashed.Prefix,
+					"tokenSalt":   hashed.Salt,
+					"tokenHash":   hashed.Hash,
+				} {
+					if a.fields[name], err = json.Marshal(value); err != nil {
+						return err
+					}
+				}
+
+				v, err := json.Marshal(a.fields)
+				if err != nil {
+					return err
+				}
+
+				if err := auths.Put(a.key, v); err != nil {
+					return err
+				}
+
+				if err := idx.Delete([]byte(a.token)); err != nil {
+					return err
+				}
+
+				if err := idx.Put([]byte(hashed.Prefix), a.key); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	},
+)
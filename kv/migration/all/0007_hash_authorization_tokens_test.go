@@ -0,0 +1,107 @@
+package all
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/kv/migration"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMigration_HashAuthorizationTokens(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	var (
+		store  = inmem.NewKVStore()
+		logger = zaptest.NewLogger(t)
+		id     = influxdb.ID(1)
+		token  = "plaintext-token"
+	)
+
+	// apply migrations up to (but not including) this one
+	migrator, err := migration.NewMigrator(logger, store, Migrations[:6]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// store an authorization the way it was stored before tokens were hashed
+	err = store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(authorizationBucket)
+		if err != nil {
+			return err
+		}
+		v := []byte(`{"id":"0000000000000001","token":"plaintext-token","status":"active","orgID":"0000000000000002","userID":"0000000000000003","permissions":[]}`)
+		if err := b.Put(encodedID, v); err != nil {
+			return err
+		}
+
+		idx, err := tx.Bucket(authorizationIndexBucket)
+		if err != nil {
+			return err
+		}
+		return idx.Put([]byte(token), encodedID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// running the migration twice must leave hashed tokens alone
+	for i := 0; i < 2; i++ {
+		if err := Migration0007_HashAuthorizationTokens.Up(ctx, store); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(authorizationBucket)
+		if err != nil {
+			return err
+		}
+		v, err := b.Get(encodedID)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(v, []byte(token)) {
+			t.Errorf("expected plaintext token to be removed, got %s", v)
+		}
+
+		idx, err := tx.Bucket(authorizationIndexBucket)
+		if err != nil {
+			return err
+		}
+		if _, err := idx.Get([]byte(token)); !kv.IsNotFound(err) {
+			t.Errorf("expected plaintext token to be removed from the index, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := kv.NewService(logger, store)
+	a, err := svc.FindAuthorizationByToken(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != id {
+		t.Errorf("expected authorization %s, got %s", id, a.ID)
+	}
+
+	if _, err := svc.FindAuthorizationByToken(ctx, "another-token"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected another token not to be found, got %v", err)
+	}
+}
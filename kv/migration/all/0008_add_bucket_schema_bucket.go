@@ -0,0 +1,12 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var bucketSchemaBucket = []byte("bucketschemasv1")
+
+// Migration0008_AddBucketSchemaBucket creates the bucket holding the explicit
+// schemas of buckets.
+var Migration0008_AddBucketSchemaBucket = migration.CreateBuckets(
+	"create bucket schemas bucket",
+	bucketSchemaBucket,
+)
@@ -0,0 +1,12 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var replicationsBucket = []byte("replicationsv1")
+
+// Migration0009_AddReplicationsBucket creates the bucket holding the
+// replications of buckets to remote instances.
+var Migration0009_AddReplicationsBucket = migration.CreateBuckets(
+	"create replications bucket",
+	replicationsBucket,
+)
@@ -0,0 +1,12 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var silencesBucket = []byte("silencesv1")
+
+// Migration0010_AddSilencesBucket creates the bucket holding the silences of
+// the notifications.
+var Migration0010_AddSilencesBucket = migration.CreateBuckets(
+	"create silences bucket",
+	silencesBucket,
+)
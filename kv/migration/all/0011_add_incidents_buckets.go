@@ -0,0 +1,16 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var (
+	incidentsBucket      = []byte("incidentsv1")
+	incidentsSeriesIndex = []byte("incidentseriesv1")
+)
+
+// Migration0011_AddIncidentsBuckets creates the buckets holding the incidents
+// of the checks and the index of the latest incident of each series.
+var Migration0011_AddIncidentsBuckets = migration.CreateBuckets(
+	"create incidents buckets",
+	incidentsBucket,
+	incidentsSeriesIndex,
+)
@@ -0,0 +1,12 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var queryQuotasBucket = []byte("queryquotasv1")
+
+// Migration0012_AddQueryQuotasBucket creates the bucket holding the query
+// quotas of the organizations.
+var Migration0012_AddQueryQuotasBucket = migration.CreateBuckets(
+	"create query quotas bucket",
+	queryQuotasBucket,
+)
@@ -0,0 +1,12 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var emailDeliveriesBucket = []byte("emaildeliveriesv1")
+
+// Migration0013_AddEmailDeliveriesBucket creates the bucket recording the
+// email notifications delivered by influxd.
+var Migration0013_AddEmailDeliveriesBucket = migration.CreateBuckets(
+	"create email deliveries bucket",
+	emailDeliveriesBucket,
+)
@@ -19,5 +19,19 @@ var Migrations = [...]migration.Spec{
 	Migration0005_AddPkgerBuckets,
 	// delete bucket sessionsv1
 	Migration0006_DeleteBucketSessionsv1,
+	// hash authorization tokens
+	Migration0007_HashAuthorizationTokens,
+	// add bucket schemas bucket
+	Migration0008_AddBucketSchemaBucket,
+	// add replications bucket
+	Migration0009_AddReplicationsBucket,
+	// add silences bucket
+	Migration0010_AddSilencesBucket,
+	// add incidents buckets
+	Migration0011_AddIncidentsBuckets,
+	// add query quotas bucket
+	Migration0012_AddQueryQuotasBucket,
+	// add email deliveries bucket
+	Migration0013_AddEmailDeliveriesBucket,
 	// {{ do_not_edit . }}
 }
@@ -107,6 +107,14 @@ func (s *Service) createNotificationTask(ctx context.Context, tx Tx, r influxdb.
 		return nil, err
 	}
 
+	if err := s.setMutedStatuses(ctx, tx, r); err != nil {
+		return nil, err
+	}
+
+	if err := s.setEscalationEndpoints(ctx, tx, r, ep); err != nil {
+		return nil, err
+	}
+
 	script, err := r.GenerateFlux(ep)
 	if err != nil {
 		return nil, err
@@ -130,12 +138,66 @@ func (s *Service) createNotificationTask(ctx context.Context, tx Tx, r influxdb.
 	return t, nil
 }
 
+// setMutedStatuses sets the silences and the acknowledged incidents of the
+// organization of the rule, whose statuses its task does not notify.
+func (s *Service) setMutedStatuses(ctx context.Context, tx Tx, r influxdb.NotificationRule) error {
+	silences, err := s.notificationSilences(ctx, tx, r.GetOrgID())
+	if err != nil {
+		return err
+	}
+	r.SetSilences(silences)
+
+	incidents, err := s.acknowledgedIncidents(ctx, tx, r.GetOrgID())
+	if err != nil {
+		return err
+	}
+	r.SetAcknowledgedIncidents(incidents)
+	return nil
+}
+
+// setEscalationEndpoints sets the endpoints of the escalation steps of the
+// rule, which must be endpoints of the organization of the rule, of the type
+// of its endpoint.
+func (s *Service) setEscalationEndpoints(ctx context.Context, tx Tx, r influxdb.NotificationRule, ep influxdb.NotificationEndpoint) error {
+	ids := r.GetEscalationEndpointIDs()
+	endpoints := make([]influxdb.NotificationEndpoint, 0, len(ids))
+	for _, id := range ids {
+		e, err := s.findNotificationEndpointByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if e.GetOrgID() != r.GetOrgID() {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("escalation endpoint %s does not belong to the organization of the notification rule", id),
+			}
+		}
+		if e.Type() != ep.Type() {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("escalation endpoint %s is a %s endpoint, not a %s endpoint", id, e.Type(), ep.Type()),
+			}
+		}
+		endpoints = append(endpoints, e)
+	}
+	r.SetEscalationEndpoints(endpoints)
+	return nil
+}
+
 func (s *Service) updateNotificationTask(ctx context.Context, tx Tx, r influxdb.NotificationRule, status *string) (*influxdb.Task, error) {
 	ep, err := s.findNotificationEndpointByID(ctx, tx, r.GetEndpointID())
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.setMutedStatuses(ctx, tx, r); err != nil {
+		return nil, err
+	}
+
+	if err := s.setEscalationEndpoints(ctx, tx, r, ep); err != nil {
+		return nil, err
+	}
+
 	script, err := r.GenerateFlux(ep)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,125 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	queryQuotaBucket = []byte("queryquotasv1")
+
+	// ErrInvalidQueryQuotaOrgID is used when the service was provided
+	// an invalid organization ID format.
+	ErrInvalidQueryQuotaOrgID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided query quota orgID has invalid format",
+	}
+)
+
+var _ influxdb.QueryQuotaService = (*Service)(nil)
+
+// InternalQueryQuotaStoreError is used when the error comes from an
+// internal system.
+func InternalQueryQuotaStoreError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  fmt.Sprintf("Unknown internal query quota data error; Err: %v", err),
+		Op:   "kv/queryQuota",
+	}
+}
+
+func (s *Service) queryQuotaBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(queryQuotaBucket)
+	if err != nil {
+		return nil, InternalQueryQuotaStoreError(err)
+	}
+	return b, nil
+}
+
+// FindQueryQuota returns the query quota of the organization, without limits
+// if none was set.
+func (s *Service) FindQueryQuota(ctx context.Context, orgID influxdb.ID) (*influxdb.QueryQuota, error) {
+	encID, err := orgID.Encode()
+	if err != nil {
+		return nil, ErrInvalidQueryQuotaOrgID
+	}
+
+	q := &influxdb.QueryQuota{OrgID: orgID}
+	err = s.kv.View(ctx, func(tx Tx) error {
+		bucket, err := s.queryQuotaBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		v, err := bucket.Get(encID)
+		if IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return InternalQueryQuotaStoreError(err)
+		}
+		if err := json.Unmarshal(v, q); err != nil {
+			return InternalQueryQuotaStoreError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// SetQueryQuota sets the query quota of the organization of q. Setting a
+// quota without limits removes it.
+func (s *Service) SetQueryQuota(ctx context.Context, q *influxdb.QueryQuota) error {
+	if err := q.Valid(); err != nil {
+		return err
+	}
+	if q.Unlimited() {
+		return s.DeleteQueryQuota(ctx, q.OrgID)
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, q.OrgID); err != nil {
+			return err
+		}
+
+		encID, _ := q.OrgID.Encode()
+		v, err := json.Marshal(q)
+		if err != nil {
+			return InternalQueryQuotaStoreError(err)
+		}
+
+		bucket, err := s.queryQuotaBucket(tx)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put(encID, v); err != nil {
+			return InternalQueryQuotaStoreError(err)
+		}
+		return nil
+	})
+}
+
+// DeleteQueryQuota removes the query quota of the organization, lifting its
+// limits.
+func (s *Service) DeleteQueryQuota(ctx context.Context, orgID influxdb.ID) error {
+	encID, err := orgID.Encode()
+	if err != nil {
+		return ErrInvalidQueryQuotaOrgID
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		bucket, err := s.queryQuotaBucket(tx)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Delete(encID); err != nil && !IsNotFound(err) {
+			return InternalQueryQuotaStoreError(err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_QueryQuotas(t *testing.T) {
+	ctx := context.Background()
+
+	store, closeBolt, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := svc.FindQueryQuota(ctx, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Unlimited() || got.OrgID != org.ID {
+		t.Fatalf("expected no limits of org %s, got %+v", org.ID, got)
+	}
+
+	want := &influxdb.QueryQuota{
+		OrgID:            org.ID,
+		MaxConcurrency:   2,
+		MaxQueueSize:     10,
+		MaxMemoryBytes:   1 << 20,
+		MaxExecutionTime: influxdb.Duration{Duration: time.Minute},
+	}
+	if err := svc.SetQueryQuota(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err = svc.FindQueryQuota(ctx, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected query quota -want/+got:\n%s", diff)
+	}
+
+	t.Run("negative limit", func(t *testing.T) {
+		err := svc.SetQueryQuota(ctx, &influxdb.QueryQuota{OrgID: org.ID, MaxConcurrency: -1})
+		if influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error, got %v", err)
+		}
+	})
+
+	t.Run("unknown organization", func(t *testing.T) {
+		err := svc.SetQueryQuota(ctx, &influxdb.QueryQuota{OrgID: influxdb.ID(1), MaxConcurrency: 1})
+		if influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+
+	if err := svc.DeleteQueryQuota(ctx, org.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteQueryQuota(ctx, org.ID); err != nil {
+		t.Fatalf("deleting a missing query quota: %v", err)
+	}
+	got, err = svc.FindQueryQuota(ctx, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Unlimited() {
+		t.Errorf("expected no limits after delete, got %+v", got)
+	}
+}
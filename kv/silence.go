@@ -0,0 +1,259 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	silenceBucket = []byte("silencesv1")
+
+	// ErrSilenceNotFound is used when the silence is not found.
+	ErrSilenceNotFound = &influxdb.Error{
+		Msg:  "silence not found",
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidSilenceID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidSilenceID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided silence ID has invalid format",
+	}
+)
+
+var _ influxdb.SilenceService = (*Service)(nil)
+
+// InternalSilenceStoreError is used when the error comes from an
+// internal system.
+func InternalSilenceStoreError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  fmt.Sprintf("Unknown internal silence data error; Err: %v", err),
+		Op:   "kv/silence",
+	}
+}
+
+func (s *Service) silenceBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return nil, InternalSilenceStoreError(err)
+	}
+	return b, nil
+}
+
+// FindSilenceByID returns a single silence by ID.
+func (s *Service) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	var sl *influxdb.Silence
+	err := s.kv.View(ctx, func(tx Tx) (err error) {
+		sl, err = s.findSilenceByID(ctx, tx, id)
+		return err
+	})
+	return sl, err
+}
+
+func (s *Service) findSilenceByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Silence, error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidSilenceID
+	}
+
+	bucket, err := s.silenceBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := bucket.Get(encID)
+	if IsNotFound(err) {
+		return nil, ErrSilenceNotFound
+	}
+	if err != nil {
+		return nil, InternalSilenceStoreError(err)
+	}
+
+	var sl influxdb.Silence
+	if err := json.Unmarshal(v, &sl); err != nil {
+		return nil, InternalSilenceStoreError(err)
+	}
+	return &sl, nil
+}
+
+// FindSilences returns the silences matching the filter.
+func (s *Service) FindSilences(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	var sls []*influxdb.Silence
+	err := s.kv.View(ctx, func(tx Tx) (err error) {
+		sls, err = s.findSilences(ctx, tx, filter)
+		return err
+	})
+	return sls, err
+}
+
+func (s *Service) findSilences(ctx context.Context, tx Tx, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	bucket, err := s.silenceBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := bucket.ForwardCursor(nil)
+	if err != nil {
+		return nil, InternalSilenceStoreError(err)
+	}
+	defer cur.Close()
+
+	sls := make([]*influxdb.Silence, 0)
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		var sl influxdb.Silence
+		if err := json.Unmarshal(v, &sl); err != nil {
+			return nil, InternalSilenceStoreError(err)
+		}
+		if filter.OrgID != nil && sl.OrgID != *filter.OrgID {
+			continue
+		}
+		if filter.Active != nil && !sl.ActiveAt(*filter.Active) {
+			continue
+		}
+		sls = append(sls, &sl)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, InternalSilenceStoreError(err)
+	}
+	return sls, nil
+}
+
+// CreateSilence creates a new silence and sets sl.ID with the new identifier.
+// The tasks of the notification rules of the organization are regenerated to
+// honor the silence.
+func (s *Service) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	if err := sl.Valid(); err != nil {
+		return err
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, sl.OrgID); err != nil {
+			return err
+		}
+
+		sl.ID = s.IDGenerator.ID()
+		now := s.TimeGenerator.Now()
+		sl.SetCreatedAt(now)
+		sl.SetUpdatedAt(now)
+		if err := s.putSilence(ctx, tx, sl); err != nil {
+			return err
+		}
+		return s.updateOrgNotificationTasks(ctx, tx, sl.OrgID)
+	})
+}
+
+// UpdateSilence updates a single silence with the changeset, and regenerates
+// the tasks of the notification rules of its organization.
+func (s *Service) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	var sl *influxdb.Silence
+	err := s.kv.Update(ctx, func(tx Tx) (err error) {
+		sl, err = s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(sl)
+		if err := sl.Valid(); err != nil {
+			return err
+		}
+		sl.SetUpdatedAt(s.TimeGenerator.Now())
+		if err := s.putSilence(ctx, tx, sl); err != nil {
+			return err
+		}
+		return s.updateOrgNotificationTasks(ctx, tx, sl.OrgID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// DeleteSilence removes a silence by ID, and regenerates the tasks of the
+// notification rules of its organization.
+func (s *Service) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		sl, err := s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		bucket, err := s.silenceBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		encID, _ := id.Encode()
+		if err := bucket.Delete(encID); err != nil {
+			return InternalSilenceStoreError(err)
+		}
+		return s.updateOrgNotificationTasks(ctx, tx, sl.OrgID)
+	})
+}
+
+func (s *Service) putSilence(ctx context.Context, tx Tx, sl *influxdb.Silence) error {
+	encID, err := sl.ID.Encode()
+	if err != nil {
+		return ErrInvalidSilenceID
+	}
+
+	v, err := json.Marshal(sl)
+	if err != nil {
+		return InternalSilenceStoreError(err)
+	}
+
+	bucket, err := s.silenceBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(encID, v); err != nil {
+		return InternalSilenceStoreError(err)
+	}
+	return nil
+}
+
+// notificationSilences returns the silences of the organization which are
+// not expired yet, to be honored by the tasks of its notification rules.
+func (s *Service) notificationSilences(ctx context.Context, tx Tx, orgID influxdb.ID) ([]influxdb.Silence, error) {
+	sls, err := s.findSilences(ctx, tx, influxdb.SilenceFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.TimeGenerator.Now()
+	var silences []influxdb.Silence
+	for _, sl := range sls {
+		if !sl.Expired(now) {
+			silences = append(silences, *sl)
+		}
+	}
+	return silences, nil
+}
+
+// updateOrgNotificationTasks regenerates the tasks of the notification rules
+// of the organization, once its silences or its acknowledged incidents have
+// changed.
+func (s *Service) updateOrgNotificationTasks(ctx context.Context, tx Tx, orgID influxdb.ID) error {
+	var rules []influxdb.NotificationRule
+	err := s.forEachNotificationRule(ctx, tx, false, func(nr influxdb.NotificationRule) bool {
+		if nr.GetOrgID() == orgID {
+			rules = append(rules, nr)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, nr := range rules {
+		if _, err := s.updateNotificationTask(ctx, tx, nr, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package kv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_Silences(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store, closeBolt, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	svc := kv.NewService(zaptest.NewLogger(t), store, kv.ServiceConfig{
+		FluxLanguageService: fluxlang.DefaultService,
+	})
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	ep := &endpoint.Slack{
+		Base: endpoint.Base{
+			Name:   "slack",
+			OrgID:  &org.ID,
+			Status: influxdb.Active,
+		},
+		URL: "http://localhost:7777",
+	}
+	if err := svc.CreateNotificationEndpoint(ctx, ep, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	every, err := notification.FromTimeDuration(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nr := &rule.Slack{
+		Base: rule.Base{
+			Name:        "crit",
+			OrgID:       org.ID,
+			EndpointID:  *ep.ID,
+			Every:       &every,
+			StatusRules: []notification.StatusRule{{CurrentLevel: notification.Critical}},
+		},
+		MessageTemplate: "msg",
+	}
+	nrc := influxdb.NotificationRuleCreate{NotificationRule: nr, Status: influxdb.Active}
+	if err := svc.CreateNotificationRule(ctx, nrc, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	taskFlux := func() string {
+		t.Helper()
+		task, err := svc.FindTaskByID(ctx, nr.GetTaskID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task.Flux
+	}
+	silenced := func() bool {
+		return strings.Contains(taskFlux(), "not (")
+	}
+
+	if silenced() {
+		t.Fatal("expected the task of the rule not to be silenced")
+	}
+
+	s := &influxdb.Silence{
+		OrgID: org.ID,
+		Name:  "maintenance",
+		Start: now,
+		End:   now.Add(time.Hour),
+	}
+	if err := svc.CreateSilence(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if !silenced() {
+		t.Fatalf("expected the task of the rule to honor the silence, got:\n%s", taskFlux())
+	}
+
+	sls, err := svc.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &org.ID})
+	if err != nil || len(sls) != 1 {
+		t.Fatalf("expected 1 silence, got %d, %v", len(sls), err)
+	}
+	later := now.Add(2 * time.Hour)
+	sls, err = svc.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &org.ID, Active: &later})
+	if err != nil || len(sls) != 0 {
+		t.Fatalf("expected no active silence, got %d, %v", len(sls), err)
+	}
+
+	// Expired silences are dropped from the task.
+	past := now.Add(-time.Minute)
+	if _, err := svc.UpdateSilence(ctx, s.ID, influxdb.SilenceUpdate{Start: &past, End: &now}); err != nil {
+		t.Fatal(err)
+	}
+	if silenced() {
+		t.Fatalf("expected the task of the rule to drop the expired silence, got:\n%s", taskFlux())
+	}
+
+	invalid := now.Add(-time.Hour)
+	if _, err := svc.UpdateSilence(ctx, s.ID, influxdb.SilenceUpdate{End: &invalid}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid update to fail, got %v", err)
+	}
+
+	end := now.Add(time.Hour)
+	if _, err := svc.UpdateSilence(ctx, s.ID, influxdb.SilenceUpdate{End: &end}); err != nil {
+		t.Fatal(err)
+	}
+	if !silenced() {
+		t.Fatalf("expected the task of the rule to honor the silence, got:\n%s", taskFlux())
+	}
+
+	if err := svc.DeleteSilence(ctx, s.ID); err != nil {
+		t.Fatal(err)
+	}
+	if silenced() {
+		t.Fatal("expected the task of the rule not to be silenced once the silence is deleted")
+	}
+	if _, err := svc.FindSilenceByID(ctx, s.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected deleted silence to be not found, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaService is a mock implementation of influxdb.BucketSchemaService.
+type BucketSchemaService struct {
+	FindBucketSchemaFn   func(context.Context, influxdb.ID) (*influxdb.BucketSchema, error)
+	PutBucketSchemaFn    func(context.Context, *influxdb.BucketSchema) error
+	DeleteBucketSchemaFn func(context.Context, influxdb.ID) error
+}
+
+// NewBucketSchemaService returns a mock BucketSchemaService where no bucket
+// has a schema.
+func NewBucketSchemaService() *BucketSchemaService {
+	return &BucketSchemaService{
+		FindBucketSchemaFn: func(context.Context, influxdb.ID) (*influxdb.BucketSchema, error) {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket schema not found"}
+		},
+		PutBucketSchemaFn:    func(context.Context, *influxdb.BucketSchema) error { return nil },
+		DeleteBucketSchemaFn: func(context.Context, influxdb.ID) error { return nil },
+	}
+}
+
+// FindBucketSchema returns the schema of a bucket.
+func (s *BucketSchemaService) FindBucketSchema(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+	return s.FindBucketSchemaFn(ctx, bucketID)
+}
+
+// PutBucketSchema sets the schema of a bucket.
+func (s *BucketSchemaService) PutBucketSchema(ctx context.Context, schema *influxdb.BucketSchema) error {
+	return s.PutBucketSchemaFn(ctx, schema)
+}
+
+// DeleteBucketSchema removes the schema of a bucket.
+func (s *BucketSchemaService) DeleteBucketSchema(ctx context.Context, bucketID influxdb.ID) error {
+	return s.DeleteBucketSchemaFn(ctx, bucketID)
+}
@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.CardinalityService = (*CardinalityService)(nil)
+
+// CardinalityService is a mock implementation of influxdb.CardinalityService.
+type CardinalityService struct {
+	CardinalityUsageFn func(ctx context.Context, orgID, bucketID influxdb.ID) (*influxdb.CardinalityUsage, error)
+}
+
+// NewCardinalityService returns a mock CardinalityService where its methods
+// will return zero values.
+func NewCardinalityService() *CardinalityService {
+	return &CardinalityService{
+		CardinalityUsageFn: func(ctx context.Context, orgID, bucketID influxdb.ID) (*influxdb.CardinalityUsage, error) {
+			return &influxdb.CardinalityUsage{OrgID: orgID, BucketID: bucketID}, nil
+		},
+	}
+}
+
+// CardinalityUsage calls CardinalityUsageFn.
+func (s *CardinalityService) CardinalityUsage(ctx context.Context, orgID, bucketID influxdb.ID) (*influxdb.CardinalityUsage, error) {
+	return s.CardinalityUsageFn(ctx, orgID, bucketID)
+}
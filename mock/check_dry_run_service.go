@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.CheckDryRunService = (*CheckDryRunService)(nil)
+
+// CheckDryRunService is a mock implementation of influxdb.CheckDryRunService.
+type CheckDryRunService struct {
+	DryRunCheckFn func(ctx context.Context, c influxdb.Check, start, stop time.Time) ([]*influxdb.CheckStatus, error)
+}
+
+// NewCheckDryRunService returns a mock CheckDryRunService where its methods
+// will return zero values.
+func NewCheckDryRunService() *CheckDryRunService {
+	return &CheckDryRunService{
+		DryRunCheckFn: func(ctx context.Context, c influxdb.Check, start, stop time.Time) ([]*influxdb.CheckStatus, error) {
+			return nil, nil
+		},
+	}
+}
+
+// DryRunCheck calls DryRunCheckFn.
+func (s *CheckDryRunService) DryRunCheck(ctx context.Context, c influxdb.Check, start, stop time.Time) ([]*influxdb.CheckStatus, error) {
+	return s.DryRunCheckFn(ctx, c, start, stop)
+}
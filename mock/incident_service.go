@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.IncidentService = (*IncidentService)(nil)
+
+// IncidentService is a mock implementation of influxdb.IncidentService.
+type IncidentService struct {
+	FindIncidentByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.Incident, error)
+	FindIncidentsFn    func(ctx context.Context, filter influxdb.IncidentFilter) ([]*influxdb.Incident, error)
+	UpdateIncidentFn   func(ctx context.Context, id influxdb.ID, upd influxdb.IncidentUpdate, userID influxdb.ID) (*influxdb.Incident, error)
+}
+
+// NewIncidentService returns a mock IncidentService where its methods will
+// return zero values.
+func NewIncidentService() *IncidentService {
+	return &IncidentService{
+		FindIncidentByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Incident, error) {
+			return nil, nil
+		},
+		FindIncidentsFn: func(ctx context.Context, filter influxdb.IncidentFilter) ([]*influxdb.Incident, error) {
+			return nil, nil
+		},
+		UpdateIncidentFn: func(ctx context.Context, id influxdb.ID, upd influxdb.IncidentUpdate, userID influxdb.ID) (*influxdb.Incident, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindIncidentByID calls FindIncidentByIDFn.
+func (s *IncidentService) FindIncidentByID(ctx context.Context, id influxdb.ID) (*influxdb.Incident, error) {
+	return s.FindIncidentByIDFn(ctx, id)
+}
+
+// FindIncidents calls FindIncidentsFn.
+func (s *IncidentService) FindIncidents(ctx context.Context, filter influxdb.IncidentFilter) ([]*influxdb.Incident, error) {
+	return s.FindIncidentsFn(ctx, filter)
+}
+
+// UpdateIncident calls UpdateIncidentFn.
+func (s *IncidentService) UpdateIncident(ctx context.Context, id influxdb.ID, upd influxdb.IncidentUpdate, userID influxdb.ID) (*influxdb.Incident, error) {
+	return s.UpdateIncidentFn(ctx, id, upd, userID)
+}
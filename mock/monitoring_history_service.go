@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.MonitoringHistoryService = (*MonitoringHistoryService)(nil)
+
+// MonitoringHistoryService is a mock implementation of
+// influxdb.MonitoringHistoryService.
+type MonitoringHistoryService struct {
+	FindCheckStatusesFn       func(ctx context.Context, orgID, checkID influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]*influxdb.CheckStatus, int, error)
+	FindNotificationRecordsFn func(ctx context.Context, orgID, ruleID influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationRecord, int, error)
+}
+
+// NewMonitoringHistoryService returns a mock MonitoringHistoryService where
+// its methods will return zero values.
+func NewMonitoringHistoryService() *MonitoringHistoryService {
+	return &MonitoringHistoryService{
+		FindCheckStatusesFn: func(ctx context.Context, orgID, checkID influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]*influxdb.CheckStatus, int, error) {
+			return nil, 0, nil
+		},
+		FindNotificationRecordsFn: func(ctx context.Context, orgID, ruleID influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationRecord, int, error) {
+			return nil, 0, nil
+		},
+	}
+}
+
+// FindCheckStatuses calls FindCheckStatusesFn.
+func (s *MonitoringHistoryService) FindCheckStatuses(ctx context.Context, orgID, checkID influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]*influxdb.CheckStatus, int, error) {
+	return s.FindCheckStatusesFn(ctx, orgID, checkID, filter, opt...)
+}
+
+// FindNotificationRecords calls FindNotificationRecordsFn.
+func (s *MonitoringHistoryService) FindNotificationRecords(ctx context.Context, orgID, ruleID influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationRecord, int, error) {
+	return s.FindNotificationRecordsFn(ctx, orgID, ruleID, filter, opt...)
+}
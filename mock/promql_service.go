@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.PromQLService = (*PromQLService)(nil)
+
+// PromQLService is a mock implementation of influxdb.PromQLService.
+type PromQLService struct {
+	QueryPromQLFn    func(ctx context.Context, orgID, bucketID influxdb.ID, q influxdb.PromQLQuery) (*influxdb.PromQLResult, error)
+	ReadPromSeriesFn func(ctx context.Context, orgID, bucketID influxdb.ID, matchers []influxdb.PromLabelMatcher, start, stop time.Time) ([]*influxdb.PromSeries, error)
+}
+
+// NewPromQLService returns a mock PromQLService where its methods will return
+// zero values.
+func NewPromQLService() *PromQLService {
+	return &PromQLService{
+		QueryPromQLFn: func(ctx context.Context, orgID, bucketID influxdb.ID, q influxdb.PromQLQuery) (*influxdb.PromQLResult, error) {
+			return &influxdb.PromQLResult{Type: influxdb.PromQLVector}, nil
+		},
+		ReadPromSeriesFn: func(ctx context.Context, orgID, bucketID influxdb.ID, matchers []influxdb.PromLabelMatcher, start, stop time.Time) ([]*influxdb.PromSeries, error) {
+			return nil, nil
+		},
+	}
+}
+
+// QueryPromQL calls QueryPromQLFn.
+func (s *PromQLService) QueryPromQL(ctx context.Context, orgID, bucketID influxdb.ID, q influxdb.PromQLQuery) (*influxdb.PromQLResult, error) {
+	return s.QueryPromQLFn(ctx, orgID, bucketID, q)
+}
+
+// ReadPromSeries calls ReadPromSeriesFn.
+func (s *PromQLService) ReadPromSeries(ctx context.Context, orgID, bucketID influxdb.ID, matchers []influxdb.PromLabelMatcher, start, stop time.Time) ([]*influxdb.PromSeries, error) {
+	return s.ReadPromSeriesFn(ctx, orgID, bucketID, matchers, start, stop)
+}
@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.QueryQuotaService = (*QueryQuotaService)(nil)
+
+// QueryQuotaService is a mock implementation of influxdb.QueryQuotaService.
+type QueryQuotaService struct {
+	FindQueryQuotaFn   func(ctx context.Context, orgID influxdb.ID) (*influxdb.QueryQuota, error)
+	SetQueryQuotaFn    func(ctx context.Context, q *influxdb.QueryQuota) error
+	DeleteQueryQuotaFn func(ctx context.Context, orgID influxdb.ID) error
+}
+
+// NewQueryQuotaService returns a mock QueryQuotaService where its methods
+// will return zero values.
+func NewQueryQuotaService() *QueryQuotaService {
+	return &QueryQuotaService{
+		FindQueryQuotaFn: func(ctx context.Context, orgID influxdb.ID) (*influxdb.QueryQuota, error) {
+			return &influxdb.QueryQuota{OrgID: orgID}, nil
+		},
+		SetQueryQuotaFn:    func(ctx context.Context, q *influxdb.QueryQuota) error { return nil },
+		DeleteQueryQuotaFn: func(ctx context.Context, orgID influxdb.ID) error { return nil },
+	}
+}
+
+// FindQueryQuota calls FindQueryQuotaFn.
+func (s *QueryQuotaService) FindQueryQuota(ctx context.Context, orgID influxdb.ID) (*influxdb.QueryQuota, error) {
+	return s.FindQueryQuotaFn(ctx, orgID)
+}
+
+// SetQueryQuota calls SetQueryQuotaFn.
+func (s *QueryQuotaService) SetQueryQuota(ctx context.Context, q *influxdb.QueryQuota) error {
+	return s.SetQueryQuotaFn(ctx, q)
+}
+
+// DeleteQueryQuota calls DeleteQueryQuotaFn.
+func (s *QueryQuotaService) DeleteQueryQuota(ctx context.Context, orgID influxdb.ID) error {
+	return s.DeleteQueryQuotaFn(ctx, orgID)
+}
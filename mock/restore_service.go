@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.RestoreService = (*RestoreService)(nil)
+
+// RestoreService is a mock implementation of influxdb.RestoreService.
+type RestoreService struct {
+	RestoreBucketFn func(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) error
+}
+
+// NewRestoreService returns a mock RestoreService where its methods will return
+// zero values.
+func NewRestoreService() *RestoreService {
+	return &RestoreService{
+		RestoreBucketFn: func(context.Context, influxdb.RestoreBucketRequest, io.Reader, io.Reader) error {
+			return nil
+		},
+	}
+}
+
+// RestoreBucket calls RestoreBucketFn.
+func (s *RestoreService) RestoreBucket(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) error {
+	return s.RestoreBucketFn(ctx, req, tsm, tombstone)
+}
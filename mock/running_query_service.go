@@ -0,0 +1,45 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.RunningQueryService = (*RunningQueryService)(nil)
+
+// RunningQueryService is a mock implementation of influxdb.RunningQueryService.
+type RunningQueryService struct {
+	FindRunningQueriesFn   func(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error)
+	FindRunningQueryByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error)
+	CancelRunningQueryFn   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewRunningQueryService returns a mock RunningQueryService where its methods
+// will return zero values.
+func NewRunningQueryService() *RunningQueryService {
+	return &RunningQueryService{
+		FindRunningQueriesFn: func(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+			return nil, nil
+		},
+		FindRunningQueryByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+			return nil, nil
+		},
+		CancelRunningQueryFn: func(ctx context.Context, id influxdb.ID) error { return nil },
+	}
+}
+
+// FindRunningQueries calls FindRunningQueriesFn.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+	return s.FindRunningQueriesFn(ctx, filter)
+}
+
+// FindRunningQueryByID calls FindRunningQueryByIDFn.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+	return s.FindRunningQueryByIDFn(ctx, id)
+}
+
+// CancelRunningQuery calls CancelRunningQueryFn.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id influxdb.ID) error {
+	return s.CancelRunningQueryFn(ctx, id)
+}
@@ -0,0 +1,65 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// SilenceService is a mock implementation of influxdb.SilenceService.
+type SilenceService struct {
+	FindSilenceByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error)
+	FindSilencesFn    func(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error)
+	CreateSilenceFn   func(ctx context.Context, s *influxdb.Silence) error
+	UpdateSilenceFn   func(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error)
+	DeleteSilenceFn   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewSilenceService returns a mock SilenceService where its methods will
+// return zero values.
+func NewSilenceService() *SilenceService {
+	return &SilenceService{
+		FindSilenceByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+			return nil, nil
+		},
+		FindSilencesFn: func(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+			return nil, nil
+		},
+		CreateSilenceFn: func(ctx context.Context, s *influxdb.Silence) error {
+			return nil
+		},
+		UpdateSilenceFn: func(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+			return nil, nil
+		},
+		DeleteSilenceFn: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// FindSilenceByID calls FindSilenceByIDFn.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	return s.FindSilenceByIDFn(ctx, id)
+}
+
+// FindSilences calls FindSilencesFn.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	return s.FindSilencesFn(ctx, filter)
+}
+
+// CreateSilence calls CreateSilenceFn.
+func (s *SilenceService) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	return s.CreateSilenceFn(ctx, sl)
+}
+
+// UpdateSilence calls UpdateSilenceFn.
+func (s *SilenceService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	return s.UpdateSilenceFn(ctx, id, upd)
+}
+
+// DeleteSilence calls DeleteSilenceFn.
+func (s *SilenceService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteSilenceFn(ctx, id)
+}
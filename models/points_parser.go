@@ -75,6 +75,14 @@ func WithParserStats(s *ParserStats) ParserOption {
 	}
 }
 
+// WithParserLines specifies that lines will contain the 1-based line number of
+// the source buffer each parsed point was read from, in the order of the points.
+func WithParserLines(lines *[]int) ParserOption {
+	return func(pp *pointsParser) {
+		pp.lines = lines
+	}
+}
+
 type parserState int
 
 const (
@@ -94,6 +102,8 @@ type pointsParser struct {
 	points      []Point
 	state       parserState
 	stats       *ParserStats
+	lines       *[]int
+	line        int // line number of the line being parsed.
 }
 
 func newPointsParser(orgBucket []byte, opts ...ParserOption) *pointsParser {
@@ -127,11 +137,18 @@ func (pp *pointsParser) parsePoints(buf []byte) (err error) {
 	pp.points = make([]Point, 0, lineCount+1)
 
 	var (
-		pos    int
-		block  []byte
-		failed []string
+		pos     int
+		counted int
+		block   []byte
+		failed  []string
 	)
+	pp.line = 1
 	for pos < len(buf) && pp.state == parserStateOK {
+		if pp.lines != nil {
+			pp.line += bytes.Count(buf[counted:pos], []byte{'\n'})
+			counted = pos
+		}
+
 		pos, block = scanLine(buf, pos)
 		pos++
 
@@ -295,6 +312,9 @@ func (pp *pointsParser) append(p point) error {
 		return errLimit
 	}
 	pp.points = append(pp.points, &p)
+	if pp.lines != nil {
+		*pp.lines = append(*pp.lines, pp.line)
+	}
 	return nil
 }
 
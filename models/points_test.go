@@ -2592,6 +2592,29 @@ func TestParsePointsWithOptions(t *testing.T) {
 	}
 }
 
+func TestParsePointsWithOptions_Lines(t *testing.T) {
+	buf := []byte("# comment\n" +
+		"cpu,host=a usage=1,idle=2 0\n" +
+		"\n" +
+		"mem,host=a used=3i 0\n" +
+		"log msg=\"two\nlines\" 0\n" +
+		"disk free=4i 0")
+
+	var lines []int
+	points, err := models.ParsePointsWithOptions(buf, []byte("org_bucket"), models.WithParserLines(&lines))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := []int{2, 2, 4, 5, 7}
+	if len(points) != len(exp) {
+		t.Fatalf("unexpected number of points; got %d, exp %d", len(points), len(exp))
+	}
+	if !cmp.Equal(lines, exp) {
+		t.Errorf("unexpected lines; -got/+exp\n%s", cmp.Diff(lines, exp))
+	}
+}
+
 func TestNewPointsWithBytesWithCorruptData(t *testing.T) {
 	corrupted := []byte{0, 0, 0, 3, 102, 111, 111, 0, 0, 0, 4, 61, 34, 65, 34, 1, 0, 0, 0, 14, 206, 86, 119, 24, 32, 72, 233, 168, 2, 148}
 	p, err := models.NewPointFromBytes(corrupted)
@@ -0,0 +1,72 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultMonitoringLookback is how far back the history of a check or of a
+// notification rule is read when the filter has no start.
+const DefaultMonitoringLookback = 24 * time.Hour
+
+// NotificationRecord is a notification sent by a notification rule, logged
+// into the _monitoring bucket.
+type NotificationRecord struct {
+	RuleID       ID     `json:"ruleID"`
+	RuleName     string `json:"ruleName"`
+	EndpointID   ID     `json:"endpointID"`
+	EndpointName string `json:"endpointName"`
+	CheckID      ID     `json:"checkID"`
+	CheckName    string `json:"checkName"`
+	Level        string `json:"level"`
+	Message      string `json:"message"`
+	// Sent is false when the endpoint failed to send the notification.
+	Sent bool      `json:"sent"`
+	Time time.Time `json:"time"`
+	// EscalationStep is the escalation step of the rule which sent the
+	// notification, it is 0 for the notifications sent to its endpoint.
+	EscalationStep int `json:"escalationStep,omitempty"`
+	// Tags are the tags of the series of the notified status.
+	Tags []Tag `json:"tags,omitempty"`
+}
+
+// MonitoringFilter represents a set of filters of the statuses and of the
+// notifications read from the _monitoring bucket.
+type MonitoringFilter struct {
+	// Start and Stop bound the time of the records. The records of the last
+	// DefaultMonitoringLookback are read when Start is zero, and the records
+	// up to now when Stop is zero.
+	Start time.Time
+	Stop  time.Time
+	Level *string
+}
+
+// QueryParams converts MonitoringFilter fields to url query params.
+func (f MonitoringFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if !f.Start.IsZero() {
+		qp["start"] = []string{f.Start.Format(time.RFC3339Nano)}
+	}
+	if !f.Stop.IsZero() {
+		qp["stop"] = []string{f.Stop.Format(time.RFC3339Nano)}
+	}
+	if f.Level != nil {
+		qp["level"] = []string{*f.Level}
+	}
+	return qp
+}
+
+// MonitoringHistoryService reads the history of the checks and of the
+// notification rules from the _monitoring bucket of their organization.
+// The callers are responsible for authorizing the access to the check or
+// to the rule.
+type MonitoringHistoryService interface {
+	// FindCheckStatuses returns the statuses written by a check, sorted by
+	// time, and the total count of the statuses matching the filter.
+	FindCheckStatuses(ctx context.Context, orgID, checkID ID, filter MonitoringFilter, opt ...FindOptions) ([]*CheckStatus, int, error)
+
+	// FindNotificationRecords returns the notifications sent by a rule,
+	// sorted by time, and the total count of the notifications matching the
+	// filter.
+	FindNotificationRecords(ctx context.Context, orgID, ruleID ID, filter MonitoringFilter, opt ...FindOptions) ([]*NotificationRecord, int, error)
+}
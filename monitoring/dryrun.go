@@ -0,0 +1,96 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	pctx "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+var _ influxdb.CheckDryRunService = (*DryRunService)(nil)
+
+// DryRunService evaluates checks against past data by running their flux at
+// each of their scheduled times, without writing their statuses.
+type DryRunService struct {
+	lang influxdb.FluxLanguageService
+	qs   query.QueryService
+}
+
+// NewDryRunService returns a dry run service running the checks with qs.
+func NewDryRunService(lang influxdb.FluxLanguageService, qs query.QueryService) *DryRunService {
+	return &DryRunService{
+		lang: lang,
+		qs:   qs,
+	}
+}
+
+// DryRunCheck returns the statuses the check would have written between start
+// and stop, sorted by time. The data of the check is read with the
+// authorization of the context.
+func (s *DryRunService) DryRunCheck(ctx context.Context, c influxdb.Check, start, stop time.Time) ([]*influxdb.CheckStatus, error) {
+	orgID := c.GetOrgID()
+	if !orgID.Valid() {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Check OrgID is invalid",
+		}
+	}
+	auth, err := pctx.GetAuthorization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := check.DryRunSchedule(c, start, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []*influxdb.CheckStatus{}
+	for _, now := range runs {
+		script, err := check.GenerateDryRunFlux(s.lang, c, now)
+		if err != nil {
+			return nil, err
+		}
+		records, err := s.run(ctx, auth, orgID, script)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			statuses = append(statuses, &influxdb.CheckStatus{
+				CheckID:   c.GetID(),
+				CheckName: c.GetName(),
+				Level:     r.columns["_level"],
+				Message:   r.columns["_message"],
+				Time:      now,
+				Tags:      r.tags(),
+			})
+		}
+	}
+	return statuses, nil
+}
+
+func (s *DryRunService) run(ctx context.Context, auth *influxdb.Authorization, orgID influxdb.ID, script string) ([]record, error) {
+	request := &query.Request{Authorization: auth, OrganizationID: orgID, Compiler: lang.FluxCompiler{Query: script}}
+
+	ittr, err := s.qs.Query(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer ittr.Release()
+
+	var rr recordReader
+	for ittr.More() {
+		if err := ittr.Next().Tables().Do(rr.readTable); err != nil {
+			return nil, err
+		}
+	}
+	if err := ittr.Err(); err != nil {
+		return nil, fmt.Errorf("unexpected internal error while running check: %v", err)
+	}
+	return rr.records, nil
+}
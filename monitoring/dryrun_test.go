@@ -0,0 +1,149 @@
+package monitoring_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb/v2"
+	pctx "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/monitoring"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	qmock "github.com/influxdata/influxdb/v2/query/mock"
+)
+
+func mustDuration(d string) *notification.Duration {
+	dur, err := parser.ParseDuration(d)
+	if err != nil {
+		panic(err)
+	}
+
+	return (*notification.Duration)(dur)
+}
+
+func TestDryRunService_DryRunCheck(t *testing.T) {
+	orgID := influxdb.ID(1)
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+	auth := &influxdb.Authorization{ID: 3, OrgID: orgID, Status: influxdb.Active}
+
+	levels := []string{"crit", "ok"}
+	var runs []string
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			q := req.Compiler.(lang.FluxCompiler).Query
+			for _, want := range []string{
+				`option monitor.write = (tables=<-) =>`,
+				`|> monitor["check"](`,
+			} {
+				if !strings.Contains(q, want) {
+					t.Errorf("expected query to contain %s:\n%s", want, q)
+				}
+			}
+			if req.Authorization != auth || req.OrganizationID != orgID {
+				t.Errorf("unexpected authorization %v for organization %s", req.Authorization, req.OrganizationID)
+			}
+			runs = append(runs, q[strings.Index(q, "option now"):strings.Index(q, "option monitor.write")])
+
+			level := levels[len(runs)-1]
+			tbl := &executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+					{Label: "_check_name", Type: flux.TString},
+					{Label: "_level", Type: flux.TString},
+					{Label: "_message", Type: flux.TString},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(t0.UnixNano()), 95.0, "cpu", level, "cpu is " + level, "cpu", "db1"},
+				},
+			}
+			return flux.NewSliceResultIterator([]flux.Result{executetest.NewResult([]*executetest.Table{tbl})}), nil
+		},
+	}
+
+	c := &check.Threshold{
+		Base: check.Base{
+			Name:                  "cpu",
+			OrgID:                 orgID,
+			Every:                 mustDuration("1h"),
+			StatusMessageTemplate: "cpu is ${r._level}",
+			Query: influxdb.DashboardQuery{
+				Text: `from(bucket: "telegraf") |> range(start: -1h) |> filter(fn: (r) => r._field == "usage_user")`,
+			},
+		},
+		Thresholds: []check.ThresholdConfig{
+			check.Greater{
+				ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Critical},
+				Value:               90,
+			},
+		},
+	}
+
+	svc := monitoring.NewDryRunService(fluxlang.DefaultService, qs)
+	ctx := pctx.SetAuthorizer(context.Background(), auth)
+	statuses, err := svc.DryRunCheck(ctx, c, t0.Add(-90*time.Minute), t0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRuns := []string{
+		"option now = () =>\n\t(2020-01-01T21:00:00Z)\n",
+		"option now = () =>\n\t(2020-01-01T22:00:00Z)\n",
+	}
+	if !reflect.DeepEqual(runs, wantRuns) {
+		t.Errorf("unexpected runs %q, want %q", runs, wantRuns)
+	}
+
+	tags := []influxdb.Tag{{Key: "host", Value: "db1"}}
+	want := []*influxdb.CheckStatus{
+		{CheckName: "cpu", Level: "crit", Message: "cpu is crit", Time: t0.Add(-time.Hour), Tags: tags},
+		{CheckName: "cpu", Level: "ok", Message: "cpu is ok", Time: t0, Tags: tags},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("unexpected statuses:\n%+v\nwant:\n%+v", statuses, want)
+	}
+}
+
+func TestDryRunService_invalid(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+	ctx := pctx.SetAuthorizer(context.Background(), &influxdb.Authorization{ID: 3, OrgID: 1})
+	svc := monitoring.NewDryRunService(fluxlang.DefaultService, &qmock.QueryService{})
+
+	tests := []struct {
+		name  string
+		check influxdb.Check
+	}{
+		{
+			name:  "missing organization",
+			check: &check.Threshold{Base: check.Base{Every: mustDuration("1h")}},
+		},
+		{
+			name: "custom check",
+			check: &check.Custom{
+				ID:    2,
+				Name:  "custom",
+				OrgID: 1,
+				Query: influxdb.DashboardQuery{Text: `from(bucket: "telegraf") |> range(start: -1h)`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.DryRunCheck(ctx, tt.check, t0.Add(-time.Hour), t0); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected an invalid error, got %v", err)
+			}
+		})
+	}
+}
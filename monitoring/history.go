@@ -0,0 +1,263 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+var _ influxdb.MonitoringHistoryService = (*HistoryService)(nil)
+
+// HistoryService reads the statuses written by the checks and the
+// notifications logged by the notification rules from the _monitoring
+// bucket of their organization.
+type HistoryService struct {
+	buckets influxdb.BucketService
+	qs      query.QueryService
+	now     func() time.Time
+}
+
+// NewHistoryService returns a history service querying the _monitoring
+// buckets with qs.
+func NewHistoryService(buckets influxdb.BucketService, qs query.QueryService) *HistoryService {
+	return &HistoryService{
+		buckets: buckets,
+		qs:      qs,
+		now:     time.Now,
+	}
+}
+
+// FindCheckStatuses returns the statuses written by the check.
+func (s *HistoryService) FindCheckStatuses(ctx context.Context, orgID, checkID influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]*influxdb.CheckStatus, int, error) {
+	records, total, err := s.findRecords(ctx, orgID, "statuses", "_check_id", checkID, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	statuses := make([]*influxdb.CheckStatus, 0, len(records))
+	for _, r := range records {
+		statuses = append(statuses, &influxdb.CheckStatus{
+			CheckID:   r.id("_check_id"),
+			CheckName: r.columns["_check_name"],
+			Level:     r.columns["_level"],
+			Message:   r.message,
+			Time:      r.time,
+			Tags:      r.tags(),
+		})
+	}
+	return statuses, total, nil
+}
+
+// FindNotificationRecords returns the notifications sent by the rule.
+func (s *HistoryService) FindNotificationRecords(ctx context.Context, orgID, ruleID influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationRecord, int, error) {
+	records, total, err := s.findRecords(ctx, orgID, "notifications", "_notification_rule_id", ruleID, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	notifications := make([]*influxdb.NotificationRecord, 0, len(records))
+	for _, r := range records {
+		// The escalation step is missing from the notifications sent to the
+		// endpoint of the rule.
+		step, _ := strconv.Atoi(r.columns["_escalation_step"])
+		notifications = append(notifications, &influxdb.NotificationRecord{
+			RuleID:         r.id("_notification_rule_id"),
+			RuleName:       r.columns["_notification_rule_name"],
+			EndpointID:     r.id("_notification_endpoint_id"),
+			EndpointName:   r.columns["_notification_endpoint_name"],
+			CheckID:        r.id("_check_id"),
+			CheckName:      r.columns["_check_name"],
+			Level:          r.columns["_level"],
+			Message:        r.message,
+			Sent:           r.columns["_sent"] == "true",
+			Time:           r.time,
+			EscalationStep: step,
+			Tags:           r.tags(),
+		})
+	}
+	return notifications, total, nil
+}
+
+// findRecords reads the page of the find options of the messages of the
+// measurement of the _monitoring bucket of the organization whose column is
+// id, sorted by time, along with the total count of the messages. At most
+// influxdb.MaxPageSize messages are read without a limit.
+func (s *HistoryService) findRecords(ctx context.Context, orgID influxdb.ID, measurement, column string, id influxdb.ID, filter influxdb.MonitoringFilter, opt ...influxdb.FindOptions) ([]record, int, error) {
+	stop := filter.Stop
+	if stop.IsZero() {
+		stop = s.now().UTC()
+	}
+	start := filter.Start
+	if start.IsZero() {
+		start = stop.Add(-influxdb.DefaultMonitoringLookback)
+	}
+	if !start.Before(stop) {
+		return nil, 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "start must be before stop",
+		}
+	}
+
+	sb, err := s.buckets.FindBucketByName(ctx, orgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	predicate := fmt.Sprintf(`r._measurement == %q and r._field == "_message" and r.%s == %q`, measurement, column, id.String())
+	if filter.Level != nil {
+		predicate += fmt.Sprintf(` and r._level == %q`, *filter.Level)
+	}
+	from := fmt.Sprintf(`from(bucketID: %q)
+	  |> range(start: %s, stop: %s)
+	  |> filter(fn: (r) => %s)
+	  |> group()
+	  `, sb.ID.String(), start.Format(time.RFC3339Nano), stop.Format(time.RFC3339Nano), predicate)
+
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+	limit, offset := o.Limit, o.Offset
+	if limit <= 0 {
+		limit = influxdb.MaxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var rr recordReader
+	script := from + fmt.Sprintf(`|> sort(columns: ["_time"], desc: %t)
+	  |> limit(n: %d, offset: %d)
+	  `, o.Descending, limit, offset)
+
+	// The access to the check or to the rule is authorized by the caller, so
+	// that users who can not read the bucket get their history.
+	if err := QueryBucket(ctx, s.qs, orgID, sb.ID, script, rr.readTable); err != nil {
+		return nil, 0, err
+	}
+
+	// The total is counted separately, rather than the whole range read.
+	var cr countReader
+	if err := QueryBucket(ctx, s.qs, orgID, sb.ID, from+"|> count()\n", cr.readTable); err != nil {
+		return nil, 0, err
+	}
+	return rr.records, cr.count, nil
+}
+
+// recordColumns are the columns of the records which are not tags of the
+// series of the status.
+var recordColumns = map[string]bool{
+	"result":                      true,
+	"table":                       true,
+	"_start":                      true,
+	"_stop":                       true,
+	"_time":                       true,
+	"_value":                      true,
+	"_field":                      true,
+	"_measurement":                true,
+	"_check_id":                   true,
+	"_check_name":                 true,
+	"_level":                      true,
+	"_source_measurement":         true,
+	"_type":                       true,
+	"_notification_rule_id":       true,
+	"_notification_rule_name":     true,
+	"_notification_endpoint_id":   true,
+	"_notification_endpoint_name": true,
+	"_sent":                       true,
+	"_escalation_step":            true,
+	"_message":                    true,
+}
+
+// record is a message of a status or of a notification, along with the
+// string columns of its row.
+type record struct {
+	time    time.Time
+	message string
+	columns map[string]string
+}
+
+// id returns the ID of the column, which is invalid if the column is missing.
+func (r record) id(column string) influxdb.ID {
+	id, err := influxdb.IDFromString(r.columns[column])
+	if err != nil {
+		return influxdb.InvalidID()
+	}
+	return *id
+}
+
+// tags returns the tags of the series of the status, sorted by key.
+func (r record) tags() []influxdb.Tag {
+	var tags []influxdb.Tag
+	for k, v := range r.columns {
+		if !recordColumns[k] {
+			tags = append(tags, influxdb.Tag{Key: k, Value: v})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Key < tags[j].Key
+	})
+	return tags
+}
+
+// countReader reads the count of the messages.
+type countReader struct {
+	count int
+}
+
+func (cr *countReader) readTable(tbl flux.Table) error {
+	return tbl.Do(func(r flux.ColReader) error {
+		for j, col := range r.Cols() {
+			if col.Label != "_value" || col.Type != flux.TInt {
+				continue
+			}
+			for i := 0; i < r.Len(); i++ {
+				if r.Ints(j).IsValid(i) {
+					cr.count += int(r.Ints(j).Value(i))
+				}
+			}
+		}
+		return nil
+	})
+}
+
+type recordReader struct {
+	records []record
+}
+
+func (rr *recordReader) readTable(tbl flux.Table) error {
+	return tbl.Do(rr.readRecords)
+}
+
+func (rr *recordReader) readRecords(cr flux.ColReader) error {
+	for i := 0; i < cr.Len(); i++ {
+		r := record{columns: make(map[string]string)}
+		for j, col := range cr.Cols() {
+			switch col.Type {
+			case flux.TTime:
+				if col.Label == "_time" && cr.Times(j).IsValid(i) {
+					r.time = values.Time(cr.Times(j).Value(i)).Time().UTC()
+				}
+			case flux.TString:
+				if !cr.Strings(j).IsValid(i) {
+					continue
+				}
+				v := cr.Strings(j).ValueString(i)
+				if col.Label == "_value" {
+					r.message = v
+					continue
+				}
+				r.columns[col.Label] = v
+			}
+		}
+		rr.records = append(rr.records, r)
+	}
+	return nil
+}
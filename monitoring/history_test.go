@@ -0,0 +1,195 @@
+package monitoring_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/monitoring"
+	"github.com/influxdata/influxdb/v2/query"
+	qmock "github.com/influxdata/influxdb/v2/query/mock"
+)
+
+func newBucketService(t *testing.T, orgID, bucketID influxdb.ID) *mock.BucketService {
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		if id != orgID || name != influxdb.MonitoringSystemBucketName {
+			t.Errorf("unexpected bucket lookup %s %q", id, name)
+		}
+		return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: name}, nil
+	}
+	return buckets
+}
+
+// newQueryService returns a query service returning tbl for the queries of
+// the records, and total for the queries counting them.
+func newQueryService(t *testing.T, orgID influxdb.ID, wantQuery []string, tbl *executetest.Table, total int64) *qmock.QueryService {
+	return &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			q := req.Compiler.(lang.FluxCompiler).Query
+			if strings.Contains(q, "count()") {
+				if strings.Contains(q, "limit(") {
+					t.Errorf("expected the count not to be limited:\n%s", q)
+				}
+				count := &executetest.Table{
+					ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TInt}},
+					Data:    [][]interface{}{{total}},
+				}
+				return flux.NewSliceResultIterator([]flux.Result{executetest.NewResult([]*executetest.Table{count})}), nil
+			}
+			for _, want := range wantQuery {
+				if !strings.Contains(q, want) {
+					t.Errorf("expected query to contain %s:\n%s", want, q)
+				}
+			}
+			if req.OrganizationID != orgID {
+				t.Errorf("unexpected organization %s", req.OrganizationID)
+			}
+			return flux.NewSliceResultIterator([]flux.Result{executetest.NewResult([]*executetest.Table{tbl})}), nil
+		},
+	}
+}
+
+func TestHistoryService_FindCheckStatuses(t *testing.T) {
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+
+	tbl := &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TString},
+			{Label: "_check_id", Type: flux.TString},
+			{Label: "_check_name", Type: flux.TString},
+			{Label: "_level", Type: flux.TString},
+			{Label: "_measurement", Type: flux.TString},
+			{Label: "host", Type: flux.TString},
+		},
+		Data: [][]interface{}{
+			{execute.Time(t0.Add(time.Minute).UnixNano()), "high cpu", "0000000000000003", "cpu", "crit", "statuses", "db1"},
+		},
+	}
+	qs := newQueryService(t, orgID, []string{
+		`from(bucketID: "0000000000000002")`,
+		`range(start: 2020-01-01T21:00:00Z, stop: 2020-01-01T23:00:00Z)`,
+		`r._measurement == "statuses" and r._field == "_message" and r._check_id == "0000000000000003"`,
+		`sort(columns: ["_time"], desc: true)`,
+		`limit(n: 1, offset: 1)`,
+	}, tbl, 3)
+
+	svc := monitoring.NewHistoryService(newBucketService(t, orgID, bucketID), qs)
+	filter := influxdb.MonitoringFilter{Start: t0.Add(-time.Hour), Stop: t0.Add(time.Hour)}
+	statuses, n, err := svc.FindCheckStatuses(context.Background(), orgID, 3, filter, influxdb.FindOptions{Offset: 1, Limit: 1, Descending: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*influxdb.CheckStatus{
+		{
+			CheckID:   3,
+			CheckName: "cpu",
+			Level:     "crit",
+			Message:   "high cpu",
+			Time:      t0.Add(time.Minute),
+			Tags:      []influxdb.Tag{{Key: "host", Value: "db1"}},
+		},
+	}
+	if n != 3 {
+		t.Errorf("expected 3 statuses, got %d", n)
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("unexpected statuses:\n%+v\nwant:\n%+v", statuses, want)
+	}
+}
+
+func TestHistoryService_FindNotificationRecords(t *testing.T) {
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+
+	tbl := &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TString},
+			{Label: "_check_id", Type: flux.TString},
+			{Label: "_check_name", Type: flux.TString},
+			{Label: "_level", Type: flux.TString},
+			{Label: "_notification_rule_id", Type: flux.TString},
+			{Label: "_notification_rule_name", Type: flux.TString},
+			{Label: "_notification_endpoint_id", Type: flux.TString},
+			{Label: "_notification_endpoint_name", Type: flux.TString},
+			{Label: "_sent", Type: flux.TString},
+			{Label: "_escalation_step", Type: flux.TString},
+			{Label: "host", Type: flux.TString},
+		},
+		Data: [][]interface{}{
+			{execute.Time(t0.UnixNano()), "high cpu", "0000000000000003", "cpu", "crit", "0000000000000004", "crit", "0000000000000005", "slack", "true", nil, "db1"},
+			{execute.Time(t0.Add(15 * time.Minute).UnixNano()), "high cpu", "0000000000000003", "cpu", "crit", "0000000000000004", "crit", "0000000000000006", "oncall", "false", "1", "db1"},
+		},
+	}
+	qs := newQueryService(t, orgID, []string{
+		`r._measurement == "notifications" and r._field == "_message" and r._notification_rule_id == "0000000000000004" and r._level == "crit"`,
+		`sort(columns: ["_time"], desc: false)`,
+		`limit(n: 20, offset: 0)`,
+	}, tbl, 2)
+
+	svc := monitoring.NewHistoryService(newBucketService(t, orgID, bucketID), qs)
+	crit := "crit"
+	filter := influxdb.MonitoringFilter{Start: t0.Add(-time.Hour), Stop: t0.Add(time.Hour), Level: &crit}
+	notifications, n, err := svc.FindNotificationRecords(context.Background(), orgID, 4, filter, influxdb.FindOptions{Limit: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags := []influxdb.Tag{{Key: "host", Value: "db1"}}
+	want := []*influxdb.NotificationRecord{
+		{
+			RuleID:       4,
+			RuleName:     "crit",
+			EndpointID:   5,
+			EndpointName: "slack",
+			CheckID:      3,
+			CheckName:    "cpu",
+			Level:        "crit",
+			Message:      "high cpu",
+			Sent:         true,
+			Time:         t0,
+			Tags:         tags,
+		},
+		{
+			RuleID:         4,
+			RuleName:       "crit",
+			EndpointID:     6,
+			EndpointName:   "oncall",
+			CheckID:        3,
+			CheckName:      "cpu",
+			Level:          "crit",
+			Message:        "high cpu",
+			Time:           t0.Add(15 * time.Minute),
+			EscalationStep: 1,
+			Tags:           tags,
+		},
+	}
+	if n != 2 {
+		t.Errorf("expected 2 notifications, got %d", n)
+	}
+	if !reflect.DeepEqual(notifications, want) {
+		t.Errorf("unexpected notifications:\n%+v\nwant:\n%+v", notifications, want)
+	}
+}
+
+func TestHistoryService_invalidRange(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+	svc := monitoring.NewHistoryService(mock.NewBucketService(), &qmock.QueryService{})
+
+	filter := influxdb.MonitoringFilter{Start: t0, Stop: t0}
+	if _, _, err := svc.FindCheckStatuses(context.Background(), 1, 3, filter); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected an empty range to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/query"
+	"go.uber.org/zap"
+)
+
+// A Reader reads the data written into the _monitoring bucket of the
+// organizations by the checks and the notification rules, for a Poller.
+type Reader interface {
+	// Read reads the range [start, stop) of the _monitoring bucket of the
+	// organization. The range is read again by the next poll if it fails.
+	Read(ctx context.Context, orgID, bucketID influxdb.ID, start, stop time.Time) error
+	// Done is called at the end of every poll, once every organization has
+	// been read.
+	Done(ctx context.Context) error
+}
+
+// Poller periodically reads the _monitoring bucket of every organization with
+// a Reader, on behalf of no user.
+type Poller struct {
+	log      *zap.Logger
+	orgs     influxdb.OrganizationService
+	buckets  influxdb.BucketService
+	reader   Reader
+	interval time.Duration
+	lookback time.Duration
+
+	// read is the time up to which the bucket of each organization has been
+	// read.
+	read map[influxdb.ID]time.Time
+	now  func() time.Time
+}
+
+// NewPoller returns a poller reading the _monitoring buckets with reader
+// every interval. Each poll reads the data written since the previous one
+// along with the last interval of it, as data written late would be missed
+// otherwise, but never more than lookback.
+func NewPoller(log *zap.Logger, orgs influxdb.OrganizationService, buckets influxdb.BucketService, reader Reader, interval, lookback time.Duration) *Poller {
+	return &Poller{
+		log:      log,
+		orgs:     orgs,
+		buckets:  buckets,
+		reader:   reader,
+		interval: interval,
+		lookback: lookback,
+		read:     make(map[influxdb.ID]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Run polls every interval until ctx is done.
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := p.Poll(ctx); err != nil {
+				p.log.Error("Failed to poll the monitoring buckets", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Poll reads the data written since the last call into the _monitoring
+// bucket of every organization. An organization failing to be read does not
+// prevent the other ones from being read.
+func (p *Poller) Poll(ctx context.Context) error {
+	orgs, _, err := p.orgs.FindOrganizations(ctx, influxdb.OrganizationFilter{})
+	if err != nil {
+		return err
+	}
+
+	for _, o := range orgs {
+		if err := p.pollOrg(ctx, o.ID); err != nil {
+			p.log.Error("Failed to poll the monitoring bucket of organization", zap.Stringer("orgID", o.ID), zap.Error(err))
+		}
+	}
+	return p.reader.Done(ctx)
+}
+
+func (p *Poller) pollOrg(ctx context.Context, orgID influxdb.ID) error {
+	sb, err := p.buckets.FindBucketByName(ctx, orgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return err
+	}
+
+	stop := p.now().UTC()
+	start := stop.Add(-p.lookback)
+	if read, ok := p.read[orgID]; ok && read.Add(-p.interval).After(start) {
+		start = read.Add(-p.interval)
+	}
+
+	if err := p.reader.Read(ctx, orgID, sb.ID, start, stop); err != nil {
+		return err
+	}
+	p.read[orgID] = stop
+	return nil
+}
+
+// QueryBucket runs the script against the _monitoring bucket of the
+// organization, calling fn with each table of its results. The script is run
+// with a read only permission to the bucket, on behalf of no user, so callers
+// must authorize the access to what they read themselves.
+func QueryBucket(ctx context.Context, qs query.QueryService, orgID, bucketID influxdb.ID, script string, fn func(flux.Table) error) error {
+	auth := &influxdb.Authorization{
+		Status: influxdb.Active,
+		ID:     bucketID,
+		OrgID:  orgID,
+		Permissions: []influxdb.Permission{
+			{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: &orgID,
+					ID:    &bucketID,
+				},
+			},
+		},
+	}
+	request := &query.Request{Authorization: auth, OrganizationID: orgID, Compiler: lang.FluxCompiler{Query: script}}
+
+	ittr, err := qs.Query(ctx, request)
+	if err != nil {
+		return err
+	}
+	defer ittr.Release()
+
+	for ittr.More() {
+		if err := ittr.Next().Tables().Do(fn); err != nil {
+			return err
+		}
+	}
+	if err := ittr.Err(); err != nil {
+		return fmt.Errorf("unexpected internal error while decoding the %s bucket: %v", influxdb.MonitoringSystemBucketName, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package monitoring_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/monitoring"
+	"go.uber.org/zap/zaptest"
+)
+
+type readRange struct {
+	orgID, bucketID influxdb.ID
+	start, stop     time.Time
+}
+
+type reader struct {
+	fail  bool
+	reads []readRange
+	done  int
+}
+
+func (r *reader) Read(ctx context.Context, orgID, bucketID influxdb.ID, start, stop time.Time) error {
+	r.reads = append(r.reads, readRange{orgID: orgID, bucketID: bucketID, start: start, stop: stop})
+	if r.fail {
+		return errors.New("read failed")
+	}
+	return nil
+}
+
+func (r *reader) Done(ctx context.Context) error {
+	r.done++
+	return nil
+}
+
+func TestPoller_Poll(t *testing.T) {
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+	interval, lookback := time.Minute, time.Hour
+
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationsF = func(ctx context.Context, filter influxdb.OrganizationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Organization, int, error) {
+		return []*influxdb.Organization{{ID: orgID, Name: "org"}}, 1, nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		if id != orgID || name != influxdb.MonitoringSystemBucketName {
+			t.Errorf("unexpected bucket lookup %s %q", id, name)
+		}
+		return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: name}, nil
+	}
+
+	r := &reader{fail: true}
+	poller := monitoring.NewPoller(zaptest.NewLogger(t), orgs, buckets, r, interval, lookback)
+
+	// a range failing to be read is read again by the next poll.
+	for i := 0; i < 2; i++ {
+		if err := poller.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r.fail = false
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.reads) != 3 || r.done != 3 {
+		t.Fatalf("expected 3 reads and 3 polls done, got %d reads and %d", len(r.reads), r.done)
+	}
+	for _, rr := range r.reads {
+		if rr.orgID != orgID || rr.bucketID != bucketID {
+			t.Errorf("unexpected read of bucket %s of org %s", rr.bucketID, rr.orgID)
+		}
+		if !rr.start.Equal(rr.stop.Add(-lookback)) {
+			t.Errorf("expected read from %s, got %s", rr.stop.Add(-lookback), rr.start)
+		}
+	}
+
+	// the next poll reads again the last interval of the range read.
+	last := r.reads[2]
+	if err := poller.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if next := r.reads[3]; !next.start.Equal(last.stop.Add(-interval)) {
+		t.Errorf("expected read from %s, got %s", last.stop.Add(-interval), next.start)
+	}
+}
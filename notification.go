@@ -31,6 +31,10 @@ type NotificationRule interface {
 	GetLimit() *Limit
 	GenerateFlux(NotificationEndpoint) (string, error)
 	MatchesTags(tags []Tag) bool
+	SetSilences(silences []Silence)
+	SetAcknowledgedIncidents(incidents []Incident)
+	GetEscalationEndpointIDs() []ID
+	SetEscalationEndpoints(endpoints []NotificationEndpoint)
 }
 
 // NotificationRuleStore represents a service for managing notification rule.
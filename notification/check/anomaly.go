@@ -0,0 +1,254 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+var _ influxdb.Check = (*Anomaly)(nil)
+
+// Anomaly is the anomaly detection check. It compares the latest value of
+// its query to the baseline of the values of its window, and its thresholds
+// apply to the deviation of the value from the baseline, in standard
+// deviations.
+//
+// The statuses of the check carry the _value, _baseline, _stddev and
+// _deviation columns, to be used by its status message template.
+type Anomaly struct {
+	Base
+	// Window is how far back the values of the baseline are read, it must
+	// span several intervals of the check.
+	Window     *notification.Duration `json:"window"`
+	Thresholds []AnomalyThreshold     `json:"thresholds"`
+}
+
+// AnomalyThreshold is the level of the statuses of an anomaly check whose
+// value deviates more than Deviations standard deviations from the baseline.
+type AnomalyThreshold struct {
+	Level      notification.CheckLevel `json:"level"`
+	Deviations float64                 `json:"deviations"`
+}
+
+// Valid returns error if the threshold is invalid.
+func (t AnomalyThreshold) Valid() error {
+	if t.Deviations <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "anomaly threshold deviations must be greater than 0",
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the check.
+func (c Anomaly) Type() string {
+	return "anomaly"
+}
+
+// Valid returns error if something is invalid.
+func (c Anomaly) Valid(lang influxdb.FluxLanguageService) error {
+	if err := c.Base.Valid(lang); err != nil {
+		return err
+	}
+	if c.Window == nil || len(c.Window.Values) == 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Anomaly check window must exist",
+		}
+	}
+	if c.Window.TimeDuration() < 2*c.Every.TimeDuration() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Anomaly check window must span at least 2 intervals",
+		}
+	}
+	for _, th := range c.Thresholds {
+		if err := th.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateFlux returns a flux script for the anomaly check provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (c Anomaly) GenerateFlux(lang influxdb.FluxLanguageService) (string, error) {
+	p, err := c.GenerateFluxAST(lang)
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the anomaly check provided. The
+// query reads the window of the check, aggregated every interval.
+func (c Anomaly) GenerateFluxAST(lang influxdb.FluxLanguageService) (*ast.Package, error) {
+	p, err := query.Parse(lang, c.Query.Text)
+	if p == nil {
+		return nil, err
+	}
+	replaceDurationsWithEvery(p, c.Every)
+	replaceStartWith(p, c.Window)
+	removeStopFromRange(p)
+	addCreateEmptyFalseToAggregateWindow(p)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	if fields := getFields(p); len(fields) != 1 {
+		return nil, fmt.Errorf("expected a single field but got: %s", fields)
+	}
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	f.Imports = append(f.Imports, flux.Imports("influxdata/influxdb/monitor", "math")...)
+	f.Body = append(f.Body, c.generateFluxASTBody()...)
+
+	return p, nil
+}
+
+func (c Anomaly) generateFluxASTBody() []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, c.generateTaskOption())
+	statements = append(statements, c.generateFluxASTCheckDefinition("anomaly"))
+	for _, th := range c.Thresholds {
+		statements = append(statements, th.generateFluxASTThresholdFunction())
+	}
+	statements = append(statements, c.generateFluxASTMessageFunction())
+	return append(statements, c.generateFluxASTChecksFunction())
+}
+
+func (t AnomalyThreshold) generateFluxASTThresholdFunction() ast.Statement {
+	fnBody := flux.GreaterThan(flux.Member("r", "_deviation"), flux.Float(t.Deviations))
+	fn := flux.Function(flux.FunctionParams("r"), fnBody)
+
+	lvl := strings.ToLower(t.Level.String())
+
+	return flux.DefineVariable(lvl, fn)
+}
+
+// generateFluxASTChecksFunction reduces the values of each series to its
+// latest value, along with the mean and the standard deviation of the values
+// before it, before checking the deviation of the latest value. The latest
+// value is left out of its own baseline, it would otherwise bound its
+// deviation by the square root of the number of the other values. The values
+// are converted to floats, for the queries aggregating integers.
+func (c Anomaly) generateFluxASTChecksFunction() ast.Statement {
+	acc := func(p string) *ast.MemberExpression { return flux.Member("accumulator", p) }
+	val := flux.Member("r", "_value")
+	fval := flux.Call(flux.Identifier("float"), flux.Object(flux.Property("v", val)))
+
+	reduceFn := flux.Function(flux.FunctionParams("r", "accumulator"), flux.Object(
+		flux.Property("count", flux.Add(acc("count"), flux.Float(1))),
+		flux.Property("sum", flux.Add(acc("sum"), fval)),
+		flux.Property("sumsq", flux.Add(acc("sumsq"), flux.Multiply(fval, fval))),
+		flux.Property("_time", flux.Member("r", "_time")),
+		flux.Property("_value", fval),
+	))
+	identity := flux.Object(
+		flux.Property("count", flux.Float(0)),
+		flux.Property("sum", flux.Float(0)),
+		flux.Property("sumsq", flux.Float(0)),
+		flux.Property("_time", flux.DateTime(time.Unix(0, 0))),
+		flux.Property("_value", flux.Float(0)),
+	)
+
+	excludeFn := flux.Function(flux.FunctionParams("r"), flux.ObjectWith("r",
+		flux.Property("count", flux.Subtract(flux.Member("r", "count"), flux.Float(1))),
+		flux.Property("sum", flux.Subtract(flux.Member("r", "sum"), val)),
+		flux.Property("sumsq", flux.Subtract(flux.Member("r", "sumsq"), flux.Multiply(val, val))),
+	))
+
+	// A series with a single value has no baseline to deviate from.
+	hasBaseline := flux.GreaterThan(flux.Member("r", "count"), flux.Float(0))
+	mean := flux.Divide(flux.Member("r", "sum"), flux.Member("r", "count"))
+	variance := flux.Subtract(
+		flux.Divide(flux.Member("r", "sumsq"), flux.Member("r", "count")),
+		flux.Multiply(mean, mean),
+	)
+	// The variance of equal values may come out just below 0 when rounded.
+	stddev := flux.Call(flux.Member("math", "sqrt"), flux.Object(flux.Property("x",
+		flux.Call(flux.Member("math", "mMax"), flux.Object(
+			flux.Property("x", variance),
+			flux.Property("y", flux.Float(0)),
+		)),
+	)))
+	baselineFn := flux.Function(flux.FunctionParams("r"), flux.ObjectWith("r",
+		flux.Property("_baseline", flux.If(hasBaseline, mean, val)),
+		flux.Property("_stddev", flux.If(hasBaseline, stddev, flux.Float(0))),
+	))
+
+	deviation := flux.If(
+		flux.GreaterThan(flux.Member("r", "_stddev"), flux.Float(0)),
+		flux.Divide(
+			flux.Call(flux.Member("math", "abs"), flux.Object(
+				flux.Property("x", flux.Subtract(val, flux.Member("r", "_baseline"))),
+			)),
+			flux.Member("r", "_stddev"),
+		),
+		flux.Float(0),
+	)
+	deviationFn := flux.Function(flux.FunctionParams("r"), flux.ObjectWith("r",
+		flux.Property("_deviation", deviation),
+	))
+
+	return flux.ExpressionStatement(flux.Pipe(
+		flux.Identifier("data"),
+		flux.Call(flux.Identifier("reduce"), flux.Object(
+			flux.Property("fn", reduceFn),
+			flux.Property("identity", identity),
+		)),
+		flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", excludeFn))),
+		flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", baselineFn))),
+		flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", deviationFn))),
+		flux.Call(flux.Identifier("drop"), flux.Object(
+			flux.Property("columns", flux.Array(flux.String("count"), flux.String("sum"), flux.String("sumsq"))),
+		)),
+		c.generateFluxASTChecksCall(),
+	))
+}
+
+func (c Anomaly) generateFluxASTChecksCall() *ast.CallExpression {
+	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
+	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
+
+	// This assumes that the AnomalyThresholds we've been provided do not have duplicates.
+	for _, th := range c.Thresholds {
+		lvl := strings.ToLower(th.Level.String())
+		objectProps = append(objectProps, flux.Property(lvl, flux.Identifier(lvl)))
+	}
+
+	return flux.Call(flux.Member("monitor", "check"), flux.Object(objectProps...))
+}
+
+type anomalyAlias Anomaly
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Anomaly) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			anomalyAlias
+			Type string `json:"type"`
+		}{
+			anomalyAlias: anomalyAlias(c),
+			Type:         c.Type(),
+		})
+}
@@ -0,0 +1,93 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnomaly_GenerateFlux(t *testing.T) {
+	anomaly := check.Anomaly{
+		Base: check.Base{
+			ID:                    10,
+			Name:                  "moo",
+			Tags:                  []influxdb.Tag{{Key: "aaa", Value: "vaaa"}},
+			Every:                 mustDuration("1h"),
+			StatusMessageTemplate: "whoa! {r[\"_deviation\"]}",
+			Query: influxdb.DashboardQuery{
+				Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+			},
+		},
+		Window: mustDuration("24h"),
+		Thresholds: []check.AnomalyThreshold{
+			{Level: notification.Warn, Deviations: 2},
+			{Level: notification.Critical, Deviations: 3.5},
+		},
+	}
+
+	want := `package main
+import "influxdata/influxdb/monitor"
+import "math"
+
+data = from(bucket: "foo")
+	|> range(start: -24h)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1h, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {aaa: "vaaa"},
+}
+warn = (r) =>
+	(r["_deviation"] > 2.0)
+crit = (r) =>
+	(r["_deviation"] > 3.5)
+messageFn = (r) =>
+	("whoa! {r[\"_deviation\"]}")
+
+data
+	|> reduce(fn: (r, accumulator) =>
+		({
+			count: accumulator["count"] + 1.0,
+			sum: accumulator["sum"] + float(v: r["_value"]),
+			sumsq: accumulator["sumsq"] + float(v: r["_value"]) * float(v: r["_value"]),
+			_time: r["_time"],
+			_value: float(v: r["_value"]),
+		}), identity: {
+		count: 0.0,
+		sum: 0.0,
+		sumsq: 0.0,
+		_time: 1970-01-01T00:00:00Z,
+		_value: 0.0,
+	})
+	|> map(fn: (r) =>
+		({r with count: r["count"] - 1.0, sum: r["sum"] - r["_value"], sumsq: r["sumsq"] - r["_value"] * r["_value"]}))
+	|> map(fn: (r) =>
+		({r with _baseline: if r["count"] > 0.0 then r["sum"] / r["count"] else r["_value"], _stddev: if r["count"] > 0.0 then math["sqrt"](x: math["mMax"](x: r["sumsq"] / r["count"] - r["sum"] / r["count"] * (r["sum"] / r["count"]), y: 0.0)) else 0.0}))
+	|> map(fn: (r) =>
+		({r with _deviation: if r["_stddev"] > 0.0 then math["abs"](x: r["_value"] - r["_baseline"]) / r["_stddev"] else 0.0}))
+	|> drop(columns: ["count", "sum", "sumsq"])
+	|> monitor["check"](
+		data: check,
+		messageFn: messageFn,
+		warn: warn,
+		crit: crit,
+	)`
+
+	p, err := anomaly.GenerateFluxAST(fluxlang.DefaultService)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.Equal(t, want, ast.Format(p))
+}
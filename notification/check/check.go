@@ -205,6 +205,8 @@ var typeToCheck = map[string](func() influxdb.Check){
 	"deadman":   func() influxdb.Check { return &Deadman{} },
 	"threshold": func() influxdb.Check { return &Threshold{} },
 	"custom":    func() influxdb.Check { return &Custom{} },
+	"rate":      func() influxdb.Check { return &Rate{} },
+	"anomaly":   func() influxdb.Check { return &Anomaly{} },
 }
 
 // UnmarshalJSON will convert
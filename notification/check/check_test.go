@@ -153,6 +153,52 @@ func TestValidCheck(t *testing.T) {
 				Msg:  "range threshold min can't be larger than max",
 			},
 		},
+		{
+			name: "zero rate unit",
+			src: &check.Rate{
+				Base: goodBase,
+				Unit: mustDuration("0s"),
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Rate check unit must be a positive duration",
+			},
+		},
+		{
+			name: "nil anomaly window",
+			src: &check.Anomaly{
+				Base: goodBase,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Anomaly check window must exist",
+			},
+		},
+		{
+			name: "anomaly window shorter than 2 intervals",
+			src: &check.Anomaly{
+				Base:   goodBase,
+				Window: mustDuration("90s"),
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Anomaly check window must span at least 2 intervals",
+			},
+		},
+		{
+			name: "bad anomaly threshold",
+			src: &check.Anomaly{
+				Base:   goodBase,
+				Window: mustDuration("1h"),
+				Thresholds: []check.AnomalyThreshold{
+					{Level: notification.Critical},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly threshold deviations must be greater than 0",
+			},
+		},
 	}
 	for _, c := range cases {
 		got := c.src.Valid(fluxlang.DefaultService)
@@ -263,6 +309,28 @@ func TestJSON(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "simple rate",
+			src: &check.Rate{
+				Base:        goodBase,
+				Unit:        mustDuration("1m"),
+				NonNegative: true,
+				Thresholds: []check.ThresholdConfig{
+					&check.Greater{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Critical}, Value: 100},
+				},
+			},
+		},
+		{
+			name: "simple anomaly",
+			src: &check.Anomaly{
+				Base:   goodBase,
+				Window: mustDuration("1h"),
+				Thresholds: []check.AnomalyThreshold{
+					{Level: notification.Warn, Deviations: 2},
+					{Level: notification.Critical, Deviations: 3.5},
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		fn := func(t *testing.T) {
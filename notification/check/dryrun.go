@@ -0,0 +1,131 @@
+package check
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// MaxDryRunRuns is the maximum number of runs of a check evaluated by a dry
+// run.
+const MaxDryRunRuns = 1000
+
+// dryRunner is a check whose generated flux can be evaluated by a dry run.
+// Custom checks are not, as their script may write anywhere.
+type dryRunner interface {
+	influxdb.Check
+	GenerateFluxAST(lang influxdb.FluxLanguageService) (*ast.Package, error)
+	every() *notification.Duration
+}
+
+func (b Base) every() *notification.Duration {
+	return b.Every
+}
+
+func toDryRunner(c influxdb.Check) (dryRunner, error) {
+	d, ok := c.(dryRunner)
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("dry runs are not supported by %s checks", c.Type()),
+		}
+	}
+	if d.every() == nil || d.every().TimeDuration() <= 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Check Every must exist",
+		}
+	}
+	return d, nil
+}
+
+// DryRunSchedule returns the times between start and stop at which the task
+// of the check would have run.
+func DryRunSchedule(c influxdb.Check, start, stop time.Time) ([]time.Time, error) {
+	d, err := toDryRunner(c)
+	if err != nil {
+		return nil, err
+	}
+	if !start.Before(stop) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "start must be before stop",
+		}
+	}
+
+	every := d.every().TimeDuration()
+	if n := stop.Sub(start) / every; n > MaxDryRunRuns {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("the time range spans %d runs of the check, the maximum is %d", n, MaxDryRunRuns),
+		}
+	}
+
+	// The runs of the tasks are aligned on their interval, the offset only
+	// delays their execution.
+	var runs []time.Time
+	t := start.Truncate(every)
+	if t.Before(start) {
+		t = t.Add(every)
+	}
+	for ; !t.After(stop); t = t.Add(every) {
+		runs = append(runs, t.UTC())
+	}
+	return runs, nil
+}
+
+// GenerateDryRunFlux returns the flux script of the check run at now. The
+// script returns the statuses of the check rather than writing them to the
+// _monitoring bucket.
+func GenerateDryRunFlux(lang influxdb.FluxLanguageService, c influxdb.Check, now time.Time) (string, error) {
+	d, err := toDryRunner(c)
+	if err != nil {
+		return "", err
+	}
+	p, err := d.GenerateFluxAST(lang)
+	if err != nil {
+		return "", err
+	}
+	if len(p.Files) == 0 {
+		return "", &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "the check generated an empty script",
+		}
+	}
+
+	f := p.Files[0]
+	f.Body = append([]ast.Statement{
+		generateNowOption(now),
+		generateMonitorWriteOption(),
+	}, f.Body...)
+
+	return ast.Format(p), nil
+}
+
+// generateNowOption returns the option setting the time of the run.
+func generateNowOption(now time.Time) ast.Statement {
+	return &ast.OptionStatement{
+		Assignment: flux.DefineVariable("now", flux.Function(nil, flux.DateTime(now))),
+	}
+}
+
+// generateMonitorWriteOption returns the option replacing the write of the
+// statuses by monitor.check with the identity.
+func generateMonitorWriteOption() ast.Statement {
+	return &ast.OptionStatement{
+		Assignment: &ast.MemberAssignment{
+			Member: &ast.MemberExpression{
+				Object:   flux.Identifier("monitor"),
+				Property: flux.Identifier("write"),
+			},
+			Init: flux.Function(
+				[]*ast.Property{{Key: flux.Identifier("tables"), Value: &ast.PipeLiteral{}}},
+				flux.Identifier("tables"),
+			),
+		},
+	}
+}
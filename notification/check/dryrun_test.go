@@ -0,0 +1,138 @@
+package check_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+)
+
+func TestGenerateDryRunFlux(t *testing.T) {
+	want := `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+import "influxdata/influxdb/v1"
+
+option now = () =>
+	(2020-01-01T22:00:00Z)
+option monitor.write = (tables=<-) =>
+	(tables)
+
+data = from(bucket: "foo")
+	|> range(start: -10m)
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "deadman",
+	tags: {aaa: "vaaa"},
+}
+info = (r) =>
+	(r["dead"])
+messageFn = (r) =>
+	("whoa! {r[\"dead\"]}")
+
+data
+	|> v1["fieldsAsCols"]()
+	|> monitor["deadman"](t: experimental["subDuration"](from: now(), d: 60s))
+	|> monitor["check"](data: check, messageFn: messageFn, info: info)`
+
+	c := &check.Deadman{
+		Base: check.Base{
+			ID:                    10,
+			Name:                  "moo",
+			Tags:                  []influxdb.Tag{{Key: "aaa", Value: "vaaa"}},
+			Every:                 mustDuration("1h"),
+			StatusMessageTemplate: "whoa! {r[\"dead\"]}",
+			Query: influxdb.DashboardQuery{
+				Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> yield()`,
+			},
+		},
+		TimeSince: mustDuration("60s"),
+		StaleTime: mustDuration("10m"),
+		Level:     notification.Info,
+	}
+
+	now := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+	got, err := check.GenerateDryRunFlux(fluxlang.DefaultService, c, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, got)
+	}
+
+	custom := &check.Custom{
+		ID:    1,
+		Name:  "custom",
+		OrgID: 2,
+		Query: influxdb.DashboardQuery{Text: `from(bucket: "telegraf") |> range(start: -1h)`},
+	}
+	if _, err := check.GenerateDryRunFlux(fluxlang.DefaultService, custom, now); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected dry runs of custom checks to be invalid, got %v", err)
+	}
+}
+
+func TestDryRunSchedule(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		every       string
+		start, stop time.Time
+		want        []time.Time
+		wantErr     bool
+	}{
+		{
+			name:  "aligned on the interval",
+			every: "1h",
+			start: t0.Add(-90 * time.Minute),
+			stop:  t0,
+			want:  []time.Time{t0.Add(-time.Hour), t0},
+		},
+		{
+			name:  "range shorter than the interval",
+			every: "1h",
+			start: t0.Add(time.Minute),
+			stop:  t0.Add(2 * time.Minute),
+		},
+		{
+			name:    "empty range",
+			every:   "1h",
+			start:   t0,
+			stop:    t0,
+			wantErr: true,
+		},
+		{
+			name:    "too many runs",
+			every:   "1s",
+			start:   t0.Add(-time.Hour),
+			stop:    t0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &check.Threshold{Base: check.Base{Every: mustDuration(tt.every)}}
+			runs, err := check.DryRunSchedule(c, tt.start, tt.stop)
+			if tt.wantErr {
+				if influxdb.ErrorCode(err) != influxdb.EInvalid {
+					t.Fatalf("expected an invalid error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(runs, tt.want) {
+				t.Errorf("unexpected runs %v, want %v", runs, tt.want)
+			}
+		})
+	}
+}
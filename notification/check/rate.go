@@ -0,0 +1,166 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+var _ influxdb.Check = (*Rate)(nil)
+
+// Rate is the rate of change check. Its thresholds apply to the derivative
+// of the values of its query instead of the values themselves.
+type Rate struct {
+	Base
+	// Unit is the duration the rate of change is computed for, the change
+	// per second is computed when unset.
+	Unit *notification.Duration `json:"unit,omitempty"`
+	// NonNegative drops the negative rates of change, such as the ones of
+	// counters being reset.
+	NonNegative bool              `json:"nonNegative"`
+	Thresholds  []ThresholdConfig `json:"thresholds"`
+}
+
+// Type returns the type of the check.
+func (c Rate) Type() string {
+	return "rate"
+}
+
+// Valid returns error if something is invalid.
+func (c Rate) Valid(lang influxdb.FluxLanguageService) error {
+	if err := c.Base.Valid(lang); err != nil {
+		return err
+	}
+	if c.Unit != nil && c.Unit.TimeDuration() <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Rate check unit must be a positive duration",
+		}
+	}
+	for _, cc := range c.Thresholds {
+		if err := cc.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type rateDecode struct {
+	Base
+	Unit        *notification.Duration  `json:"unit,omitempty"`
+	NonNegative bool                    `json:"nonNegative"`
+	Thresholds  []thresholdConfigDecode `json:"thresholds"`
+}
+
+// UnmarshalJSON implement json.Unmarshaler interface.
+func (c *Rate) UnmarshalJSON(b []byte) error {
+	raw := new(rateDecode)
+	if err := json.Unmarshal(b, raw); err != nil {
+		return err
+	}
+	c.Base = raw.Base
+	c.Unit = raw.Unit
+	c.NonNegative = raw.NonNegative
+	thresholds, err := decodeThresholdConfigs(raw.Thresholds)
+	if err != nil {
+		return err
+	}
+	c.Thresholds = thresholds
+	return nil
+}
+
+// GenerateFlux returns a flux script for the rate check provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (c Rate) GenerateFlux(lang influxdb.FluxLanguageService) (string, error) {
+	p, err := c.GenerateFluxAST(lang)
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the rate check provided. The query
+// reads two intervals of the check, so that the derivative of the values has
+// a point for the latest interval.
+func (c Rate) GenerateFluxAST(lang influxdb.FluxLanguageService) (*ast.Package, error) {
+	p, err := query.Parse(lang, c.Query.Text)
+	if p == nil {
+		return nil, err
+	}
+	replaceDurationsWithEvery(p, c.Every)
+	replaceStartWith(p, multiplyDuration(c.Every, 2))
+	removeStopFromRange(p)
+	addCreateEmptyFalseToAggregateWindow(p)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	fields := getFields(p)
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("expected a single field but got: %s", fields)
+	}
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	f.Imports = append(f.Imports, flux.Imports("influxdata/influxdb/monitor", "influxdata/influxdb/v1")...)
+	f.Body = append(f.Body, c.generateFluxASTBody(fields[0])...)
+
+	return p, nil
+}
+
+func (c Rate) generateFluxASTBody(field string) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, c.generateTaskOption())
+	statements = append(statements, c.generateFluxASTCheckDefinition("rate"))
+	for _, th := range c.Thresholds {
+		statements = append(statements, th.generateFluxASTThresholdFunction(field))
+	}
+	statements = append(statements, c.generateFluxASTMessageFunction())
+	return append(statements, c.generateFluxASTChecksFunction())
+}
+
+func (c Rate) generateFluxASTChecksFunction() ast.Statement {
+	var unit ast.Expression = flux.Duration(1, "s")
+	if c.Unit != nil {
+		unit = (*ast.DurationLiteral)(c.Unit)
+	}
+
+	return flux.ExpressionStatement(flux.Pipe(
+		flux.Identifier("data"),
+		flux.Call(flux.Identifier("derivative"), flux.Object(
+			flux.Property("unit", unit),
+			flux.Property("nonNegative", flux.Bool(c.NonNegative)),
+		)),
+		flux.Call(flux.Member("v1", "fieldsAsCols"), flux.Object()),
+		Threshold{Base: c.Base, Thresholds: c.Thresholds}.generateFluxASTChecksCall(),
+	))
+}
+
+type rateAlias Rate
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			rateAlias
+			Type string `json:"type"`
+		}{
+			rateAlias: rateAlias(c),
+			Type:      c.Type(),
+		})
+}
@@ -0,0 +1,121 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRate_GenerateFlux(t *testing.T) {
+	base := check.Base{
+		ID:                    10,
+		Name:                  "moo",
+		Tags:                  []influxdb.Tag{{Key: "aaa", Value: "vaaa"}},
+		Every:                 mustDuration("1h"),
+		StatusMessageTemplate: "whoa! {r[\"usage_user\"]}",
+		Query: influxdb.DashboardQuery{
+			Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+		},
+	}
+	thresholds := []check.ThresholdConfig{
+		check.Greater{
+			ThresholdConfigBase: check.ThresholdConfigBase{
+				Level: notification.Critical,
+			},
+			Value: 100,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		rate   check.Rate
+		script string
+	}{
+		{
+			name: "rate per unit",
+			rate: check.Rate{
+				Base:        base,
+				Unit:        mustDuration("5m"),
+				NonNegative: true,
+				Thresholds:  thresholds,
+			},
+			script: `package main
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -2h)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1h, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "rate",
+	tags: {aaa: "vaaa"},
+}
+crit = (r) =>
+	(r["usage_user"] > 100.0)
+messageFn = (r) =>
+	("whoa! {r[\"usage_user\"]}")
+
+data
+	|> derivative(unit: 5m, nonNegative: true)
+	|> v1["fieldsAsCols"]()
+	|> monitor["check"](data: check, messageFn: messageFn, crit: crit)`,
+		},
+		{
+			name: "rate per second",
+			rate: check.Rate{
+				Base:       base,
+				Thresholds: thresholds,
+			},
+			script: `package main
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -2h)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1h, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "rate",
+	tags: {aaa: "vaaa"},
+}
+crit = (r) =>
+	(r["usage_user"] > 100.0)
+messageFn = (r) =>
+	("whoa! {r[\"usage_user\"]}")
+
+data
+	|> derivative(unit: 1s, nonNegative: false)
+	|> v1["fieldsAsCols"]()
+	|> monitor["check"](data: check, messageFn: messageFn, crit: crit)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := tt.rate.GenerateFluxAST(fluxlang.DefaultService)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assert.Equal(t, tt.script, ast.Format(p))
+		})
+	}
+}
@@ -59,20 +59,30 @@ func (t *Threshold) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	t.Base = tdRaws.Base
-	for _, tdRaw := range tdRaws.Thresholds {
+	thresholds, err := decodeThresholdConfigs(tdRaws.Thresholds)
+	if err != nil {
+		return err
+	}
+	t.Thresholds = thresholds
+	return nil
+}
+
+func decodeThresholdConfigs(tdRaws []thresholdConfigDecode) ([]ThresholdConfig, error) {
+	var thresholds []ThresholdConfig
+	for _, tdRaw := range tdRaws {
 		switch tdRaw.Type {
 		case "lesser":
 			td := &Lesser{
 				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
 				Value:               tdRaw.Value,
 			}
-			t.Thresholds = append(t.Thresholds, td)
+			thresholds = append(thresholds, td)
 		case "greater":
 			td := &Greater{
 				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
 				Value:               tdRaw.Value,
 			}
-			t.Thresholds = append(t.Thresholds, td)
+			thresholds = append(thresholds, td)
 		case "range":
 			td := &Range{
 				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
@@ -80,15 +90,14 @@ func (t *Threshold) UnmarshalJSON(b []byte) error {
 				Max:                 tdRaw.Max,
 				Within:              tdRaw.Within,
 			}
-			t.Thresholds = append(t.Thresholds, td)
+			thresholds = append(thresholds, td)
 		default:
-			return &influxdb.Error{
+			return nil, &influxdb.Error{
 				Msg: fmt.Sprintf("invalid threshold type %s", tdRaw.Type),
 			}
 		}
 	}
-
-	return nil
+	return thresholds, nil
 }
 
 func multiError(errs []error) error {
@@ -190,6 +199,26 @@ func replaceDurationsWithEvery(pkg *ast.Package, every *notification.Duration) {
 	})
 }
 
+// replaceStartWith sets the start of the range of the query to d before now,
+// for the checks reading more than their last interval.
+func replaceStartWith(pkg *ast.Package, d *notification.Duration) {
+	ast.Visit(pkg, func(n ast.Node) {
+		if e, ok := n.(*ast.Property); ok && e.Key.Key() == "start" {
+			newStart := (ast.DurationLiteral)(*d)
+			e.Value = flux.Negative(&newStart)
+		}
+	})
+}
+
+// multiplyDuration returns the duration d repeated n times.
+func multiplyDuration(d *notification.Duration, n int64) *notification.Duration {
+	m := &notification.Duration{}
+	for _, v := range d.Values {
+		m.Values = append(m.Values, ast.Duration{Magnitude: v.Magnitude * n, Unit: v.Unit})
+	}
+	return m
+}
+
 // TODO(desa): we'll likely want to remove all other arguments to range that are provided, but for now this should work.
 // When we decide to implement the full feature we'll have to do something more sophisticated.
 func removeStopFromRange(pkg *ast.Package) {
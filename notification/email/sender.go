@@ -0,0 +1,373 @@
+// Package email delivers the email notifications queued by the tasks of the
+// email notification rules.
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/monitoring"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/query"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultSendInterval is the default period between two reads of the
+	// queued email notifications.
+	DefaultSendInterval = 10 * time.Second
+
+	// DefaultLookback is how far back the queued email notifications are
+	// read, the ones which could not be delivered since are dropped.
+	DefaultLookback = time.Hour
+
+	// sendTimeout bounds the conversation with the SMTP server.
+	sendTimeout = 30 * time.Second
+)
+
+// DeliveryLog records the email notifications which have been delivered, so
+// that they are delivered once even though they are read more than once.
+type DeliveryLog interface {
+	EmailDelivered(ctx context.Context, key string) (bool, error)
+	RecordEmailDelivery(ctx context.Context, key string, t time.Time) error
+	DeleteEmailDeliveries(ctx context.Context, before time.Time) error
+}
+
+// Sender periodically reads the email notifications queued into the
+// _monitoring bucket of every organization by the tasks of the email
+// notification rules, and sends them to the SMTP server of their endpoint.
+type Sender struct {
+	log        *zap.Logger
+	qs         query.QueryService
+	rules      influxdb.NotificationRuleStore
+	endpoints  influxdb.NotificationEndpointService
+	secrets    influxdb.SecretService
+	deliveries DeliveryLog
+	poller     *monitoring.Poller
+	now        func() time.Time
+}
+
+// NewSender returns a sender that delivers the queued email notifications
+// every interval.
+func NewSender(log *zap.Logger, orgs influxdb.OrganizationService, buckets influxdb.BucketService, qs query.QueryService, rules influxdb.NotificationRuleStore, endpoints influxdb.NotificationEndpointService, secrets influxdb.SecretService, deliveries DeliveryLog, interval time.Duration) *Sender {
+	if interval <= 0 {
+		interval = DefaultSendInterval
+	}
+	s := &Sender{
+		log:        log,
+		qs:         qs,
+		rules:      rules,
+		endpoints:  endpoints,
+		secrets:    secrets,
+		deliveries: deliveries,
+		now:        time.Now,
+	}
+	s.poller = monitoring.NewPoller(log, orgs, buckets, s, interval, DefaultLookback)
+	return s
+}
+
+// Run delivers the queued email notifications every interval until ctx is
+// done.
+func (s *Sender) Run(ctx context.Context) error {
+	return s.poller.Run(ctx)
+}
+
+// Send delivers the email notifications queued since the last call by every
+// organization. The delivery log skips the notifications already delivered.
+func (s *Sender) Send(ctx context.Context) error {
+	return s.poller.Poll(ctx)
+}
+
+// Read delivers the notifications of the range queued by the organization.
+// The notifications which failed to be sent are retried until they are out
+// of the lookback.
+func (s *Sender) Read(ctx context.Context, orgID, bucketID influxdb.ID, start, stop time.Time) error {
+	notifications, err := s.readNotifications(ctx, orgID, bucketID, start, stop)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, n := range notifications {
+		if err := s.deliver(ctx, orgID, n); err != nil {
+			s.log.Error("Failed to send email notification", zap.Stringer("orgID", orgID), zap.String("rule", n.ruleID), zap.Error(err))
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to send %d email notifications", failed)
+	}
+	return nil
+}
+
+// Done drops the deliveries, which are only needed as long as their
+// notifications are read again.
+func (s *Sender) Done(ctx context.Context) error {
+	return s.deliveries.DeleteEmailDeliveries(ctx, s.now().Add(-2*DefaultLookback))
+}
+
+// deliver sends a single notification, unless it has been delivered already.
+// The notifications of a rule or an endpoint which is not an email one any
+// more are dropped.
+func (s *Sender) deliver(ctx context.Context, orgID influxdb.ID, n notification) error {
+	key := n.key(orgID)
+	delivered, err := s.deliveries.EmailDelivered(ctx, key)
+	if err != nil || delivered {
+		return err
+	}
+
+	msg, err := s.message(ctx, orgID, n)
+	if err != nil {
+		if influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+		s.log.Info("Dropping email notification", zap.Stringer("orgID", orgID), zap.String("rule", n.ruleID), zap.Error(err))
+	} else if err := msg.send(ctx); err != nil {
+		return err
+	}
+	return s.deliveries.RecordEmailDelivery(ctx, key, s.now())
+}
+
+func (s *Sender) message(ctx context.Context, orgID influxdb.ID, n notification) (*message, error) {
+	ruleID, err := influxdb.IDFromString(n.ruleID)
+	if err != nil {
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "invalid notification rule ID", Err: err}
+	}
+	endpointID, err := influxdb.IDFromString(n.endpointID)
+	if err != nil {
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "invalid notification endpoint ID", Err: err}
+	}
+
+	nr, err := s.rules.FindNotificationRuleByID(ctx, *ruleID)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := nr.(*rule.Email)
+	if !ok || nr.GetOrgID() != orgID {
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: fmt.Sprintf("notification rule %s is not an email rule", ruleID)}
+	}
+
+	ne, err := s.endpoints.FindNotificationEndpointByID(ctx, *endpointID)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := ne.(*endpoint.Email)
+	if !ok || ne.GetOrgID() != orgID {
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: fmt.Sprintf("notification endpoint %s is not an email endpoint", endpointID)}
+	}
+
+	msg := &message{
+		endpoint: e,
+		to:       r.To,
+		subject:  n.subject,
+		body:     n.body,
+	}
+	if e.Username.Key != "" {
+		if msg.username, err = s.secrets.LoadSecret(ctx, orgID, e.Username.Key); err != nil {
+			return nil, err
+		}
+	}
+	if e.Password.Key != "" {
+		if msg.password, err = s.secrets.LoadSecret(ctx, orgID, e.Password.Key); err != nil {
+			return nil, err
+		}
+	}
+	return msg, nil
+}
+
+func (s *Sender) readNotifications(ctx context.Context, orgID, bucketID influxdb.ID, start, stop time.Time) ([]notification, error) {
+	script := fmt.Sprintf(`from(bucketID: %q)
+	  |> range(start: %s, stop: %s)
+	  |> filter(fn: (r) => r._measurement == "notifications" and r._sent == %q and (r._field == %q or r._field == %q))
+	  |> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	  `, bucketID.String(), start.Format(time.RFC3339Nano), stop.Format(time.RFC3339Nano),
+		endpoint.EmailQueued, endpoint.EmailSubjectColumn, endpoint.EmailBodyColumn)
+
+	var nr notificationReader
+	if err := monitoring.QueryBucket(ctx, s.qs, orgID, bucketID, script, nr.readTable); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(nr.notifications, func(i, j int) bool {
+		return nr.notifications[i].time.Before(nr.notifications[j].time)
+	})
+	return nr.notifications, nil
+}
+
+// notification is an email notification queued by the task of a rule.
+type notification struct {
+	time       time.Time
+	ruleID     string
+	endpointID string
+	subject    string
+	body       string
+	// series are the tags of the notification, which tell it apart from
+	// the other notifications logged by the same run of the task.
+	series []string
+}
+
+// key identifies the notification in the delivery log.
+func (n notification) key(orgID influxdb.ID) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%d\n", orgID, n.time.UnixNano())
+	for _, s := range n.series {
+		fmt.Fprintf(h, "%s\n", s)
+	}
+	return orgID.String() + "/" + hex.EncodeToString(h.Sum(nil))
+}
+
+type notificationReader struct {
+	notifications []notification
+}
+
+func (nr *notificationReader) readTable(tbl flux.Table) error {
+	return tbl.Do(nr.readNotifications)
+}
+
+func (nr *notificationReader) readNotifications(cr flux.ColReader) error {
+	for i := 0; i < cr.Len(); i++ {
+		var n notification
+		for j, col := range cr.Cols() {
+			switch col.Type {
+			case flux.TTime:
+				if col.Label == "_time" && cr.Times(j).IsValid(i) {
+					n.time = values.Time(cr.Times(j).Value(i)).Time().UTC()
+				}
+				continue
+			case flux.TString:
+			default:
+				continue
+			}
+			if !cr.Strings(j).IsValid(i) {
+				continue
+			}
+
+			v := cr.Strings(j).ValueString(i)
+			switch col.Label {
+			case endpoint.EmailSubjectColumn:
+				n.subject = v
+			case endpoint.EmailBodyColumn:
+				n.body = v
+			case "_notification_rule_id":
+				n.ruleID = v
+				n.series = append(n.series, col.Label+"="+v)
+			case "_notification_endpoint_id":
+				n.endpointID = v
+				n.series = append(n.series, col.Label+"="+v)
+			case "result", "table":
+			default:
+				n.series = append(n.series, col.Label+"="+v)
+			}
+		}
+
+		// notifications missing their rule or endpoint can not be sent.
+		if n.ruleID != "" && n.endpointID != "" {
+			sort.Strings(n.series)
+			nr.notifications = append(nr.notifications, n)
+		}
+	}
+	return nil
+}
+
+// message is an email to send through the SMTP server of an endpoint.
+type message struct {
+	endpoint           *endpoint.Email
+	username, password string
+	to                 []string
+	subject, body      string
+}
+
+func (m *message) send(ctx context.Context) error {
+	e := m.endpoint
+	addr := net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+	tlsConfig := &tls.Config{ServerName: e.Host}
+
+	dialer := &net.Dialer{Timeout: sendTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(sendTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	if e.TLSMode == endpoint.EmailTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	c, err := smtp.NewClient(conn, e.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if e.TLSMode == endpoint.EmailTLSStartTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.username, m.password, e.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(e.From); err != nil {
+		return err
+	}
+	for _, to := range m.to {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.bytes(time.Now())); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// bytes returns the message in the RFC 5322 format, with its body quoted
+// printable.
+func (m *message) bytes(date time.Time) []byte {
+	// the subject is rendered from the statuses, it must not inject headers.
+	subject := strings.Join(strings.Fields(m.subject), " ")
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.endpoint.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(m.to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	w.Write([]byte(m.body))
+	w.Close()
+	return buf.Bytes()
+}
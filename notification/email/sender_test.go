@@ -0,0 +1,258 @@
+package email_test
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification/email"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/query"
+	qmock "github.com/influxdata/influxdb/v2/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+type deliveryLog struct {
+	delivered map[string]time.Time
+}
+
+func (l *deliveryLog) EmailDelivered(ctx context.Context, key string) (bool, error) {
+	_, ok := l.delivered[key]
+	return ok, nil
+}
+
+func (l *deliveryLog) RecordEmailDelivery(ctx context.Context, key string, t time.Time) error {
+	l.delivered[key] = t
+	return nil
+}
+
+func (l *deliveryLog) DeleteEmailDeliveries(ctx context.Context, before time.Time) error {
+	for k, t := range l.delivered {
+		if t.Before(before) {
+			delete(l.delivered, k)
+		}
+	}
+	return nil
+}
+
+// smtpServer is an SMTP server accepting any email.
+type smtpServer struct {
+	ln net.Listener
+
+	mu     sync.Mutex
+	from   []string
+	to     [][]string
+	emails []string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	var (
+		from string
+		to   []string
+	)
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<> ")
+			reply("250 OK")
+		case "RCPT":
+			to = append(to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<> "))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.from = append(s.from, from)
+			s.to = append(s.to, to)
+			s.emails = append(s.emails, data.String())
+			s.mu.Unlock()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestSender_Send(t *testing.T) {
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+	ruleID, endpointID := influxdb.ID(3), influxdb.ID(4)
+	t0 := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+
+	server := newSMTPServer(t)
+	defer server.ln.Close()
+	host, port, _ := net.SplitHostPort(server.ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationsF = func(ctx context.Context, filter influxdb.OrganizationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Organization, int, error) {
+		return []*influxdb.Organization{{ID: orgID, Name: "org"}}, 1, nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		if id != orgID || name != influxdb.MonitoringSystemBucketName {
+			t.Errorf("unexpected bucket lookup %s %q", id, name)
+		}
+		return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: name}, nil
+	}
+
+	rules := mock.NewNotificationRuleStore()
+	rules.FindNotificationRuleByIDF = func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+		if id != ruleID {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "notification rule not found"}
+		}
+		return &rule.Email{
+			Base:            rule.Base{ID: ruleID, OrgID: orgID, EndpointID: endpointID, Name: "cpu"},
+			To:              []string{"oncall@example.com", "ops@example.com"},
+			SubjectTemplate: "${r._check_name} is ${r._level}",
+			BodyTemplate:    "${r._message}",
+		}, nil
+	}
+	endpoints := mock.NewNotificationEndpointService()
+	endpoints.FindNotificationEndpointByIDF = func(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+		return &endpoint.Email{
+			Base:    endpoint.Base{ID: &endpointID, OrgID: &orgID, Name: "smtp"},
+			Host:    host,
+			Port:    portNum,
+			TLSMode: endpoint.EmailTLSNone,
+			From:    "alerts@example.com",
+		}, nil
+	}
+
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			q := req.Compiler.(lang.FluxCompiler).Query
+			if !strings.Contains(q, `from(bucketID: "0000000000000002")`) || !strings.Contains(q, `r._sent == "queued"`) {
+				t.Errorf("unexpected query:\n%s", q)
+			}
+			if req.OrganizationID != orgID {
+				t.Errorf("unexpected organization %s", req.OrganizationID)
+			}
+
+			tbl := &executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_start", Type: flux.TTime},
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_notification_rule_id", Type: flux.TString},
+					{Label: "_notification_endpoint_id", Type: flux.TString},
+					{Label: "_sent", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: endpoint.EmailSubjectColumn, Type: flux.TString},
+					{Label: endpoint.EmailBodyColumn, Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(0), execute.Time(t0.UnixNano()), "notifications", "0000000000000003", "0000000000000004", "queued", "db1", "cpu is crit", "high cpu on db1"},
+					{execute.Time(0), execute.Time(t0.UnixNano()), "notifications", "0000000000000003", "0000000000000004", "queued", "db2", "cpu is crit", "high cpu on db2"},
+					{execute.Time(0), execute.Time(t0.UnixNano()), "notifications", "0000000000000005", "0000000000000004", "queued", "db1", "deleted rule", "deleted rule"},
+				},
+			}
+			return flux.NewSliceResultIterator([]flux.Result{executetest.NewResult([]*executetest.Table{tbl})}), nil
+		},
+	}
+
+	deliveries := &deliveryLog{delivered: make(map[string]time.Time)}
+	sender := email.NewSender(zaptest.NewLogger(t), orgs, buckets, qs, rules, endpoints, mock.NewSecretService(), deliveries, time.Minute)
+
+	// the notifications are read twice, they must be sent once.
+	for i := 0; i < 2; i++ {
+		if err := sender.Send(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.emails) != 2 {
+		t.Fatalf("expected 2 emails, got %d:\n%s", len(server.emails), strings.Join(server.emails, "\n"))
+	}
+	if len(deliveries.delivered) != 3 {
+		t.Errorf("expected the 3 notifications to be delivered or dropped, got %d", len(deliveries.delivered))
+	}
+
+	for i, host := range []string{"db1", "db2"} {
+		if server.from[i] != "alerts@example.com" {
+			t.Errorf("unexpected sender %q", server.from[i])
+		}
+		if got := strings.Join(server.to[i], ","); got != "oncall@example.com,ops@example.com" {
+			t.Errorf("unexpected recipients %q", got)
+		}
+
+		msg, err := mail.ReadMessage(strings.NewReader(server.emails[i]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := msg.Header.Get("Subject"); got != "cpu is crit" {
+			t.Errorf("unexpected subject %q", got)
+		}
+		if got := msg.Header.Get("To"); got != "oncall@example.com, ops@example.com" {
+			t.Errorf("unexpected To header %q", got)
+		}
+		body, err := ioutil.ReadAll(quotedprintable.NewReader(msg.Body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := strings.TrimSpace(string(body)), "high cpu on "+host; got != want {
+			t.Errorf("unexpected body %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.NotificationEndpoint = &Email{}
+
+const (
+	emailUsernameSuffix = "-username"
+	emailPasswordSuffix = "-password"
+)
+
+// TLS modes of the connection to the SMTP server.
+const (
+	// EmailTLSNone sends email in plain text.
+	EmailTLSNone = "none"
+	// EmailTLSStartTLS upgrades the connection with STARTTLS.
+	EmailTLSStartTLS = "starttls"
+	// EmailTLS connects to the SMTP server over TLS.
+	EmailTLS = "tls"
+)
+
+// The Flux standard library can not send email, so the notification rules
+// of the email endpoints log their notifications into the _monitoring bucket
+// as queued, with the rendered subject and body of the email in these
+// columns, for influxd to deliver them.
+const (
+	// EmailQueued is the _sent column of the queued notifications.
+	EmailQueued = "queued"
+	// EmailSubjectColumn is the column of the subject of the email.
+	EmailSubjectColumn = "_email_subject"
+	// EmailBodyColumn is the column of the body of the email.
+	EmailBodyColumn = "_email_body"
+)
+
+var goodEmailTLSMode = map[string]bool{
+	EmailTLSNone:     true,
+	EmailTLSStartTLS: true,
+	EmailTLS:         true,
+}
+
+// Email is the notification endpoint config of an SMTP server.
+type Email struct {
+	Base
+	// Host is the host name of the SMTP server.
+	Host string `json:"host"`
+	// Port is the port of the SMTP server, usually 25, 465 or 587.
+	Port int `json:"port"`
+	// TLSMode is one of none, starttls or tls.
+	TLSMode string `json:"tlsMode"`
+	// From is the address email is sent from.
+	From string `json:"from"`
+	// Username and Password authenticate to the SMTP server, if it requires
+	// authentication.
+	Username influxdb.SecretField `json:"username,omitempty"`
+	Password influxdb.SecretField `json:"password,omitempty"`
+}
+
+// BackfillSecretKeys fill back fill the secret field key during the unmarshalling
+// if value of that secret field is not nil.
+func (s *Email) BackfillSecretKeys() {
+	if s.Username.Key == "" && s.Username.Value != nil {
+		s.Username.Key = s.idStr() + emailUsernameSuffix
+	}
+	if s.Password.Key == "" && s.Password.Value != nil {
+		s.Password.Key = s.idStr() + emailPasswordSuffix
+	}
+}
+
+// SecretFields return available secret fields.
+func (s Email) SecretFields() []influxdb.SecretField {
+	arr := []influxdb.SecretField{}
+	if s.Username.Key != "" {
+		arr = append(arr, s.Username)
+	}
+	if s.Password.Key != "" {
+		arr = append(arr, s.Password)
+	}
+	return arr
+}
+
+// Valid returns error if some configuration is invalid
+func (s Email) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.Host == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "email endpoint host must be provided",
+		}
+	}
+	if s.Port <= 0 || s.Port > 65535 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("email endpoint port %d is invalid", s.Port),
+		}
+	}
+	if !goodEmailTLSMode[s.TLSMode] {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid email tls mode",
+		}
+	}
+	if _, err := mail.ParseAddress(s.From); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("email endpoint from address is invalid: %s", err.Error()),
+		}
+	}
+	if (s.Username.Key == "") != (s.Password.Key == "") {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid email username/password, both or neither must be provided",
+		}
+	}
+	return nil
+}
+
+type emailAlias Email
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Email) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			emailAlias
+			Type string `json:"type"`
+		}{
+			emailAlias: emailAlias(s),
+			Type:       s.Type(),
+		})
+}
+
+// Type returns the type.
+func (s Email) Type() string {
+	return EmailType
+}
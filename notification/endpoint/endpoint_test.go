@@ -182,6 +182,114 @@ func TestValidEndpoint(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name: "empty email host",
+			src: &endpoint.Email{
+				Base:    goodBase,
+				Port:    587,
+				TLSMode: endpoint.EmailTLSStartTLS,
+				From:    "alerts@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "email endpoint host must be provided",
+			},
+		},
+		{
+			name: "invalid email port",
+			src: &endpoint.Email{
+				Base:    goodBase,
+				Host:    "smtp.example.com",
+				TLSMode: endpoint.EmailTLSStartTLS,
+				From:    "alerts@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "email endpoint port 0 is invalid",
+			},
+		},
+		{
+			name: "invalid email tls mode",
+			src: &endpoint.Email{
+				Base:    goodBase,
+				Host:    "smtp.example.com",
+				Port:    587,
+				TLSMode: "ssl",
+				From:    "alerts@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid email tls mode",
+			},
+		},
+		{
+			name: "email password without username",
+			src: &endpoint.Email{
+				Base:     goodBase,
+				Host:     "smtp.example.com",
+				Port:     587,
+				TLSMode:  endpoint.EmailTLSStartTLS,
+				From:     "alerts@example.com",
+				Password: influxdb.SecretField{Key: id1 + "-password"},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid email username/password, both or neither must be provided",
+			},
+		},
+		{
+			name: "valid email",
+			src: &endpoint.Email{
+				Base:     goodBase,
+				Host:     "smtp.example.com",
+				Port:     587,
+				TLSMode:  endpoint.EmailTLSStartTLS,
+				From:     "InfluxDB <alerts@example.com>",
+				Username: influxdb.SecretField{Key: id1 + "-username"},
+				Password: influxdb.SecretField{Key: id1 + "-password"},
+			},
+			err: nil,
+		},
+		{
+			name: "empty teams url",
+			src: &endpoint.Teams{
+				Base: goodBase,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "teams webhook URL is invalid",
+			},
+		},
+		{
+			name: "empty opsgenie api key",
+			src: &endpoint.Opsgenie{
+				Base: goodBase,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "opsgenie API key is invalid",
+			},
+		},
+		{
+			name: "invalid opsgenie url",
+			src: &endpoint.Opsgenie{
+				Base:   goodBase,
+				URL:    "posts://er:{DEf1=ghi@:5432/db?ssl",
+				APIKey: influxdb.SecretField{Key: id1 + "-api-key"},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "opsgenie endpoint URL is invalid: parse posts://er:{DEf1=ghi@:5432/db?ssl: net/url: invalid userinfo",
+			},
+		},
+		{
+			name: "valid opsgenie",
+			src: &endpoint.Opsgenie{
+				Base:   goodBase,
+				APIKey: influxdb.SecretField{Key: id1 + "-api-key"},
+			},
+			err: nil,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -288,6 +396,60 @@ func TestJSON(t *testing.T) {
 				Token: influxdb.SecretField{Key: "token-key-1"},
 			},
 		},
+		{
+			name: "simple Email",
+			src: &endpoint.Email{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "nameEmail",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Host:     "smtp.example.com",
+				Port:     465,
+				TLSMode:  endpoint.EmailTLS,
+				From:     "alerts@example.com",
+				Username: influxdb.SecretField{Key: "username-key"},
+				Password: influxdb.SecretField{Key: "password-key"},
+			},
+		},
+		{
+			name: "simple Teams",
+			src: &endpoint.Teams{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "nameTeams",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				URL: influxdb.SecretField{Key: "url-key"},
+			},
+		},
+		{
+			name: "simple Opsgenie",
+			src: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "nameOpsgenie",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				URL:    "https://api.eu.opsgenie.com/v2/alerts",
+				APIKey: influxdb.SecretField{Key: "api-key"},
+			},
+		},
 	}
 	for _, c := range cases {
 		b, err := json.Marshal(c.src)
@@ -382,6 +544,32 @@ func TestBackFill(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "simple opsgenie",
+			src: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "name1",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+				},
+				APIKey: influxdb.SecretField{
+					Value: strPtr("api-key-value"),
+				},
+			},
+			target: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "name1",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+				},
+				APIKey: influxdb.SecretField{
+					Key:   id1 + "-api-key",
+					Value: strPtr("api-key-value"),
+				},
+			},
+		},
 		{
 			name: "http with token",
 			src: &endpoint.HTTP{
@@ -588,6 +776,43 @@ func TestSecretFields(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "email with user and password",
+			src: &endpoint.Email{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "name1",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Host:    "smtp.example.com",
+				Port:    587,
+				TLSMode: endpoint.EmailTLSStartTLS,
+				From:    "alerts@example.com",
+				Username: influxdb.SecretField{
+					Key:   id1 + "-username",
+					Value: strPtr("user1"),
+				},
+				Password: influxdb.SecretField{
+					Key:   id1 + "-password",
+					Value: strPtr("password1"),
+				},
+			},
+			secrets: []influxdb.SecretField{
+				{
+					Key:   id1 + "-username",
+					Value: strPtr("user1"),
+				},
+				{
+					Key:   id1 + "-password",
+					Value: strPtr("password1"),
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		secretFields := c.src.SecretFields()
@@ -1,6 +1,11 @@
 package flux
 
-import "github.com/influxdata/flux/ast"
+import (
+	"regexp"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+)
 
 // File creates a new *ast.File.
 func File(name string, imports []*ast.ImportDeclaration, body []ast.Statement) *ast.File {
@@ -29,6 +34,15 @@ func LessThan(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	}
 }
 
+// GreaterThanEqual returns a greater than or equal to *ast.BinaryExpression.
+func GreaterThanEqual(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.GreaterThanEqualOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
 // Equal returns an equal to *ast.BinaryExpression.
 func Equal(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	return &ast.BinaryExpression{
@@ -38,6 +52,33 @@ func Equal(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	}
 }
 
+// NotEqual returns a not equal to *ast.BinaryExpression.
+func NotEqual(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.NotEqualOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// RegexpMatch returns a regular expression match *ast.BinaryExpression.
+func RegexpMatch(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.RegexpMatchOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// NotRegexpMatch returns a regular expression mismatch *ast.BinaryExpression.
+func NotRegexpMatch(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.NotRegexpMatchOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
 // Subtract returns a subtraction *ast.BinaryExpression.
 func Subtract(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	return &ast.BinaryExpression{
@@ -56,6 +97,33 @@ func Add(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	}
 }
 
+// Multiply returns a multiplication *ast.BinaryExpression.
+func Multiply(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.MultiplicationOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// Divide returns a division *ast.BinaryExpression.
+func Divide(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.DivisionOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// Modulo returns a modulo *ast.BinaryExpression.
+func Modulo(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.ModuloOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
 // Member returns an *ast.MemberExpression where the key is p and the values is c.
 func Member(p, c string) *ast.MemberExpression {
 	return &ast.MemberExpression{
@@ -82,6 +150,14 @@ func Or(lhs, rhs ast.Expression) *ast.LogicalExpression {
 	}
 }
 
+// Not returns a not *ast.UnaryExpression.
+func Not(e ast.Expression) *ast.UnaryExpression {
+	return &ast.UnaryExpression{
+		Operator: ast.NotOperator,
+		Argument: e,
+	}
+}
+
 // If returns an *ast.ConditionalExpression
 func If(test, consequent, alternate ast.Expression) *ast.ConditionalExpression {
 	return &ast.ConditionalExpression{
@@ -191,6 +267,20 @@ func Integer(i int64) *ast.IntegerLiteral {
 	}
 }
 
+// DateTime returns a *ast.DateTimeLiteral.
+func DateTime(t time.Time) *ast.DateTimeLiteral {
+	return &ast.DateTimeLiteral{
+		Value: t.UTC(),
+	}
+}
+
+// Regexp returns a *ast.RegexpLiteral.
+func Regexp(re *regexp.Regexp) *ast.RegexpLiteral {
+	return &ast.RegexpLiteral{
+		Value: re,
+	}
+}
+
 // Negative returns *ast.UnaryExpression for -(e).
 func Negative(e ast.Expression) *ast.UnaryExpression {
 	return &ast.UnaryExpression{
@@ -0,0 +1,174 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// Email is the notification rule config of email. The emails are not sent by
+// the task of the rule: its notifications are queued in the _monitoring bucket
+// with their rendered subject and body, and delivered by influxd.
+type Email struct {
+	Base
+	// To is the list of recipients of the email.
+	To []string `json:"to"`
+	// SubjectTemplate and BodyTemplate are the templates of the subject and
+	// body of the email, see parseTemplate for their placeholders.
+	SubjectTemplate string `json:"subjectTemplate"`
+	BodyTemplate    string `json:"bodyTemplate"`
+}
+
+// GenerateFlux generates a flux script for the email notification rule.
+func (s *Email) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	emailEndpoint, ok := e.(*endpoint.Email)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not an Email endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(emailEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the email notification rule.
+func (s *Email) GenerateFluxAST(e *endpoint.Email) (*ast.Package, error) {
+	escalations, err := s.generateEscalations(func(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+		emailEndpoint, ok := e.(*endpoint.Email)
+		if !ok {
+			return nil, fmt.Errorf("escalation endpoint provided is a %s, not an Email endpoint", e.Type())
+		}
+		return s.generateFluxASTBody(emailEndpoint), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	f := flux.File(
+		s.Name,
+		flux.Imports("influxdata/influxdb/monitor", "json", "experimental"),
+		append(s.generateFluxASTBody(e), escalations...),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *Email) generateFluxASTBody(e *endpoint.Email) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateFluxASTEndpoint())
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateLevelChecks()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+
+	return statements
+}
+
+// generateFluxASTEndpoint generates the endpoint queueing the notifications,
+// which renders the subject and body of their email with mapFn.
+func (s *Email) generateFluxASTEndpoint() ast.Statement {
+	email := flux.Call(flux.Identifier("mapFn"), flux.Object(flux.Property("r", flux.Identifier("r"))))
+	row := flux.ObjectWith("r",
+		flux.Property("_sent", flux.String(endpoint.EmailQueued)),
+		flux.Property(endpoint.EmailSubjectColumn, &ast.MemberExpression{Object: email, Property: &ast.Identifier{Name: "subject"}}),
+		flux.Property(endpoint.EmailBodyColumn, &ast.MemberExpression{Object: email, Property: &ast.Identifier{Name: "body"}}),
+	)
+	queue := flux.Pipe(
+		flux.Identifier("tables"),
+		flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", flux.Function(flux.FunctionParams("r"), row)))),
+	)
+	tables := &ast.Property{Key: &ast.Identifier{Name: "tables"}, Value: &ast.PipeLiteral{}}
+
+	return flux.DefineVariable("email_endpoint",
+		flux.Function(flux.FunctionParams("mapFn"), flux.Function([]*ast.Property{tables}, queue)))
+}
+
+func (s *Email) generateFluxASTNotifyPipe() ast.Statement {
+	// The templates are validated along with the rule.
+	subject, _ := parseTemplate(s.SubjectTemplate)
+	body, _ := parseTemplate(s.BodyTemplate)
+
+	endpointProps := []*ast.Property{}
+	endpointProps = append(endpointProps, flux.Property("subject", templateExpression(subject)))
+	endpointProps = append(endpointProps, flux.Property("body", templateExpression(body)))
+	endpointFn := flux.Function(flux.FunctionParams("r"), flux.Object(endpointProps...))
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("email_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+type emailAlias Email
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Email) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			emailAlias
+			Type string `json:"type"`
+		}{
+			emailAlias: emailAlias(s),
+			Type:       s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s Email) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if len(s.To) == 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "email rule must have at least one recipient",
+		}
+	}
+	for _, addr := range s.To {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("email rule recipient %q is invalid: %s", addr, err.Error()),
+			}
+		}
+	}
+	if s.SubjectTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "email subject template is empty",
+		}
+	}
+	if _, err := parseTemplate(s.SubjectTemplate); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("email subject template is invalid: %s", err.Error()),
+		}
+	}
+	if s.BodyTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "email body template is empty",
+		}
+	}
+	if _, err := parseTemplate(s.BodyTemplate); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("email body template is invalid: %s", err.Error()),
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s Email) Type() string {
+	return "email"
+}
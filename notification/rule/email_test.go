@@ -0,0 +1,322 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/andreyvit/diff"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	influxTesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+var _ influxdb.NotificationRule = &rule.Email{}
+
+func TestEmail_GenerateFlux(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     *rule.Email
+		endpoint influxdb.NotificationEndpoint
+		script   string
+	}{
+		{
+			name: "incompatible with endpoint",
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				URL: "http://whatever",
+			},
+			rule: &rule.Email{
+				To:              []string{"oncall@example.com"},
+				SubjectTemplate: "blah",
+				BodyTemplate:    "blah blah",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 3,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+				},
+			},
+			script: "", //no script generater, because of incompatible endpoint
+		},
+		{
+			name: "notify on crit",
+			endpoint: &endpoint.Email{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				Host:     "smtp.example.com",
+				Port:     587,
+				TLSMode:  endpoint.EmailTLSStartTLS,
+				From:     "alerts@example.com",
+				Username: influxdb.SecretField{Key: "3-username"},
+				Password: influxdb.SecretField{Key: "3-password"},
+			},
+			rule: &rule.Email{
+				To:              []string{"oncall@example.com", "ops@example.com"},
+				SubjectTemplate: "${r._check_name} is ${r._level}",
+				BodyTemplate:    "${r._message}",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 3,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+					TagRules: []notification.TagRule{
+						{
+							Tag: influxdb.Tag{
+								Key:   "foo",
+								Value: "bar",
+							},
+							Operator: influxdb.Equal,
+						},
+					},
+				},
+			},
+			script: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "json"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+email_endpoint = (mapFn) =>
+	((tables=<-) =>
+		(tables
+			|> map(fn: (r) =>
+				({r with _sent: "queued", _email_subject: mapFn(r: r).subject, _email_body: mapFn(r: r).body}))))
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar"))
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: email_endpoint(mapFn: (r) =>
+		({subject: string(v: r["_check_name"]) + " is " + string(v: r["_level"]), body: string(v: r["_message"])})))`,
+		},
+		{
+			name: "notify on any level",
+			endpoint: &endpoint.Email{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				Host:    "localhost",
+				Port:    25,
+				TLSMode: endpoint.EmailTLSNone,
+				From:    "alerts@example.com",
+			},
+			rule: &rule.Email{
+				To:              []string{"oncall@example.com"},
+				SubjectTemplate: "blah",
+				BodyTemplate:    "blah blah",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 3,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Any,
+						},
+					},
+					TagRules: []notification.TagRule{
+						{
+							Tag: influxdb.Tag{
+								Key:   "foo",
+								Value: "bar",
+							},
+							Operator: influxdb.Equal,
+						},
+					},
+				},
+			},
+			script: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "json"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+email_endpoint = (mapFn) =>
+	((tables=<-) =>
+		(tables
+			|> map(fn: (r) =>
+				({r with _sent: "queued", _email_subject: mapFn(r: r).subject, _email_body: mapFn(r: r).body}))))
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar"))
+any = statuses
+	|> filter(fn: (r) =>
+		(true))
+all_statuses = any
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: email_endpoint(mapFn: (r) =>
+		({subject: "blah", body: "blah blah"})))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := tt.rule.GenerateFlux(tt.endpoint)
+			if err != nil {
+				if script != "" {
+					t.Errorf("Failed to generate flux: %v", err)
+				}
+				return
+			}
+
+			if got, want := script, tt.script; got != want {
+				t.Errorf("\n\nStrings do not match:\n\n%s", diff.LineDiff(got, want))
+			}
+		})
+	}
+}
+
+func TestEmail_Valid(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		EndpointID: 3,
+		OwnerID:    4,
+		OrgID:      5,
+		Name:       "foo",
+		Every:      mustDuration("1h"),
+		StatusRules: []notification.StatusRule{
+			{
+				CurrentLevel: notification.Critical,
+			},
+		},
+		TagRules: []notification.TagRule{},
+	}
+
+	cases := []struct {
+		name string
+		rule *rule.Email
+		err  error
+	}{
+		{
+			name: "valid template",
+			rule: &rule.Email{
+				Base:            base,
+				To:              []string{"oncall@example.com"},
+				SubjectTemplate: "blah",
+				BodyTemplate:    "blah blah",
+			},
+			err: nil,
+		},
+		{
+			name: "missing recipients",
+			rule: &rule.Email{
+				Base:            base,
+				SubjectTemplate: "blah",
+				BodyTemplate:    "blah blah",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "email rule must have at least one recipient",
+			},
+		},
+		{
+			name: "invalid recipient",
+			rule: &rule.Email{
+				Base:            base,
+				To:              []string{"oncall"},
+				SubjectTemplate: "blah",
+				BodyTemplate:    "blah blah",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `email rule recipient "oncall" is invalid: mail: missing '@' or angle-addr`,
+			},
+		},
+		{
+			name: "missing subject template",
+			rule: &rule.Email{
+				Base:         base,
+				To:           []string{"oncall@example.com"},
+				BodyTemplate: "blah blah",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "email subject template is empty",
+			},
+		},
+		{
+			name: "missing body template",
+			rule: &rule.Email{
+				Base:            base,
+				To:              []string{"oncall@example.com"},
+				SubjectTemplate: "blah",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "email body template is empty",
+			},
+		},
+		{
+			name: "invalid subject template",
+			rule: &rule.Email{
+				Base:            base,
+				To:              []string{"oncall@example.com"},
+				SubjectTemplate: "${r._level",
+				BodyTemplate:    "blah blah",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `email subject template is invalid: placeholder "${r._level" is not closed`,
+			},
+		},
+		{
+			name: "invalid body template",
+			rule: &rule.Email{
+				Base:            base,
+				To:              []string{"oncall@example.com"},
+				SubjectTemplate: "blah",
+				BodyTemplate:    "${level}",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `email body template is invalid: placeholder "${level}" must be one of ${r}, ${r.column}, ${r["column"]}, ${json(r.column)} or ${json(r["column"])}`,
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.rule.Valid()
+			influxTesting.ErrorsEqual(t, got, c.err)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package rule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// EscalationStep is a step of the escalation policy of a notification rule.
+// Once a series of statuses has been crit for the delay of the step, its
+// statuses are notified to the endpoint of the step, in addition to the
+// endpoint of the rule. The endpoint of the step must be of the type of the
+// endpoint of the rule.
+type EscalationStep struct {
+	EndpointID influxdb.ID `json:"endpointID"`
+	// Delay is measured from the first status of the run of crit statuses
+	// of the series.
+	Delay *notification.Duration `json:"delay"`
+}
+
+// Valid returns an error if the step is invalid.
+func (s EscalationStep) Valid() error {
+	if !s.EndpointID.Valid() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Notification Rule escalation step EndpointID is invalid",
+		}
+	}
+	if s.Delay == nil || s.Delay.TimeDuration() <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Notification Rule escalation step delay must be a positive duration",
+		}
+	}
+	return nil
+}
+
+// escalationEndpointBody generates the statements of a rule for the endpoint
+// of one of its escalation steps.
+type escalationEndpointBody func(e influxdb.NotificationEndpoint) ([]ast.Statement, error)
+
+// generateEscalations generates the statements notifying the escalated
+// statuses to the endpoints of the escalation steps of the rule. Of the
+// statements of the rule for the endpoint of a step, the ones defining the
+// endpoint and its secrets are kept along with the notify pipe, and their
+// variables are suffixed with the number of the step.
+//
+// No state is kept by the task: the runs of crit statuses are found in the
+// statuses of the _monitoring bucket, and each of them is escalated once, by
+// the run of the task following the end of the delay of the step. The
+// notifications are logged there with the _escalation_step of the step.
+func (b *Base) generateEscalations(body escalationEndpointBody) ([]ast.Statement, error) {
+	if len(b.EscalationEndpoints) != len(b.Escalation) {
+		return nil, fmt.Errorf("expected %d escalation endpoints, got %d", len(b.Escalation), len(b.EscalationEndpoints))
+	}
+
+	var stmts []ast.Statement
+	for i, e := range b.EscalationEndpoints {
+		step := i + 1
+		endpointStmts, err := body(e)
+		if err != nil {
+			return nil, err
+		}
+
+		names := identifierRenamer{
+			"notification": fmt.Sprintf("notification_%d", step),
+			"all_statuses": fmt.Sprintf("escalation_%d", step),
+		}
+		statuses, err := b.generateFluxASTEscalationStatuses(b.Escalation[i], names["all_statuses"])
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, statuses)
+		start := len(stmts)
+		for _, stmt := range endpointStmts {
+			v, ok := stmt.(*ast.VariableAssignment)
+			if !ok {
+				continue
+			}
+			if v.ID.Name == "notification" {
+				break
+			}
+			names[v.ID.Name] = fmt.Sprintf("%s_%d", v.ID.Name, step)
+			stmts = append(stmts, v)
+		}
+		stmts = append(stmts, b.generateFluxASTEscalationNotificationDefinition(e, step, names["notification"]))
+		stmts = append(stmts, endpointStmts[len(endpointStmts)-1])
+
+		for _, stmt := range stmts[start:] {
+			ast.Walk(names, stmt)
+		}
+	}
+	return stmts, nil
+}
+
+// generateFluxASTEscalationStatuses generates the statuses escalated by the
+// step. They are the statuses at which a run of crit statuses of their series
+// has lasted for the delay of the step, written since the previous run of the
+// task, so that every run of crit statuses is escalated once.
+//
+// A run is measured from the first of its statuses read, so the statuses are
+// read back for twice the delay and the interval of the rule: a run which
+// started before is not mistaken for a run starting with the first status
+// read, as long as the check writes statuses more often than every half of
+// the delay and the interval of the rule.
+func (b *Base) generateFluxASTEscalationStatuses(s EscalationStep, name string) (ast.Statement, error) {
+	delay := s.Delay.TimeDuration()
+	lookback, err := notification.FromTimeDuration(2 * (delay + b.Every.TimeDuration()))
+	if err != nil {
+		return nil, err
+	}
+
+	props := []*ast.Property{flux.Property("start", flux.Negative((*ast.DurationLiteral)(&lookback)))}
+	if fn := b.generateTagRulesFn(); fn != nil {
+		props = append(props, flux.Property("fn", fn))
+	}
+
+	nanosecond := flux.Duration(1, "ns")
+	now := flux.Call(flux.Identifier("now"), flux.Object())
+	pipe := flux.Pipe(
+		flux.Call(flux.Member("monitor", "from"), flux.Object(props...)),
+		flux.Call(flux.Identifier("duplicate"), flux.Object(
+			flux.Property("column", flux.String("_level")),
+			flux.Property("as", flux.String("____temp_level____")),
+		)),
+		flux.Call(flux.Identifier("drop"), flux.Object(
+			flux.Property("columns", flux.Array(flux.String("_level"))),
+		)),
+		flux.Call(flux.Identifier("rename"), flux.Object(
+			flux.Property("columns", flux.Object(flux.Dictionary("____temp_level____", flux.String("_level")))),
+		)),
+		flux.Call(flux.Identifier("sort"), flux.Object(
+			flux.Property("columns", flux.Array(flux.String("_time"))),
+		)),
+		flux.Call(flux.Identifier("stateDuration"), flux.Object(
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"),
+				flux.Equal(flux.Member("r", "_level"), flux.String(strings.ToLower(notification.Critical.String()))),
+			)),
+			flux.Property("column", flux.String("_crit_duration")),
+			flux.Property("unit", nanosecond),
+		)),
+		flux.Call(flux.Identifier("elapsed"), flux.Object(
+			flux.Property("unit", nanosecond),
+			flux.Property("columnName", flux.String("_elapsed")),
+		)),
+		// the run lasted for the delay at this status, and not at the
+		// previous status of the series.
+		flux.Call(flux.Identifier("filter"), flux.Object(
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"),
+				flux.And(
+					flux.GreaterThanEqual(flux.Member("r", "_crit_duration"), flux.Integer(int64(delay))),
+					flux.LessThan(flux.Subtract(flux.Member("r", "_crit_duration"), flux.Member("r", "_elapsed")), flux.Integer(int64(delay))),
+				),
+			)),
+		)),
+		// the statuses before were read by the previous run of the task.
+		flux.Call(flux.Identifier("filter"), flux.Object(
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"),
+				flux.GreaterThanEqual(
+					flux.Member("r", "_time"),
+					flux.Call(flux.Member("experimental", "subDuration"), flux.Object(
+						flux.Property("from", now),
+						flux.Property("d", (*ast.DurationLiteral)(b.Every)),
+					)),
+				),
+			)),
+		)),
+		flux.Call(flux.Member("experimental", "group"), flux.Object(
+			flux.Property("mode", flux.String("extend")),
+			flux.Property("columns", flux.Array(flux.String("_level"))),
+		)),
+	)
+
+	if len(b.Silences) > 0 {
+		pipe = flux.Pipe(pipe, b.generateSilenceFilter())
+	}
+	if len(b.AcknowledgedIncidents) > 0 {
+		pipe = flux.Pipe(pipe, b.generateIncidentFilter())
+	}
+
+	return flux.DefineVariable(name, pipe), nil
+}
+
+func (b *Base) generateFluxASTEscalationNotificationDefinition(e influxdb.NotificationEndpoint, step int, name string) ast.Statement {
+	ruleID := flux.Property("_notification_rule_id", flux.String(b.ID.String()))
+	ruleName := flux.Property("_notification_rule_name", flux.String(b.Name))
+	endpointID := flux.Property("_notification_endpoint_id", flux.String(e.GetID().String()))
+	endpointName := flux.Property("_notification_endpoint_name", flux.String(e.GetName()))
+	escalationStep := flux.Property("_escalation_step", flux.String(strconv.Itoa(step)))
+
+	return flux.DefineVariable(name, flux.Object(ruleID, ruleName, endpointID, endpointName, escalationStep))
+}
+
+// identifierRenamer renames the identifiers referring to its variables. The
+// keys of the properties and of the member expressions are left untouched.
+type identifierRenamer map[string]string
+
+func (r identifierRenamer) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.Identifier:
+		if name, ok := r[n.Name]; ok {
+			n.Name = name
+		}
+	case *ast.Property:
+		ast.Walk(r, n.Value)
+		return nil
+	case *ast.MemberExpression:
+		ast.Walk(r, n.Object)
+		return nil
+	}
+	return r
+}
+
+func (r identifierRenamer) Done(ast.Node) {}
@@ -0,0 +1,202 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestGenerateFlux_escalation(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))
+
+escalation_1 = monitor["from"](start: -2h30m0s)
+	|> duplicate(column: "_level", as: "____temp_level____")
+	|> drop(columns: ["_level"])
+	|> rename(columns: {"____temp_level____": "_level"})
+	|> sort(columns: ["_time"])
+	|> stateDuration(fn: (r) =>
+		(r["_level"] == "crit"), column: "_crit_duration", unit: 1ns)
+	|> elapsed(unit: 1ns, columnName: "_elapsed")
+	|> filter(fn: (r) =>
+		(r["_crit_duration"] >= 900000000000 and r["_crit_duration"] - r["_elapsed"] < 900000000000))
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+	|> experimental["group"](mode: "extend", columns: ["_level"])
+slack_secret_1 = secrets["get"](key: "oncall_token")
+slack_endpoint_1 = slack["endpoint"](token: slack_secret_1)
+notification_1 = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "oncall",
+	_escalation_step: "1",
+}
+
+escalation_1
+	|> monitor["notify"](data: notification_1, endpoint: slack_endpoint_1(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))`
+
+	s := &rule.Slack{
+		Channel:         "bar",
+		MessageTemplate: "blah",
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+			Escalation: []rule.EscalationStep{
+				{
+					EndpointID: 3,
+					Delay:      mustDuration("15m"),
+				},
+			},
+		},
+	}
+	s.SetEscalationEndpoints([]influxdb.NotificationEndpoint{
+		&endpoint.Slack{
+			Base: endpoint.Base{
+				ID:   idPtr(3),
+				Name: "oncall",
+			},
+			Token: influxdb.SecretField{
+				Key: "oncall_token",
+			},
+		},
+	})
+
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			ID:   idPtr(2),
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+
+	s.SetEscalationEndpoints([]influxdb.NotificationEndpoint{
+		&endpoint.PagerDuty{
+			Base: endpoint.Base{
+				ID:   idPtr(3),
+				Name: "oncall",
+			},
+		},
+	})
+	if _, err := s.GenerateFlux(e); err == nil {
+		t.Error("expected an escalation endpoint of another type to fail")
+	}
+}
+
+func TestValidEscalation(t *testing.T) {
+	tests := []struct {
+		name       string
+		escalation []rule.EscalationStep
+		err        error
+	}{
+		{
+			name: "increasing delays",
+			escalation: []rule.EscalationStep{
+				{EndpointID: 3, Delay: mustDuration("15m")},
+				{EndpointID: 4, Delay: mustDuration("1h")},
+			},
+		},
+		{
+			name: "invalid endpoint ID",
+			escalation: []rule.EscalationStep{
+				{Delay: mustDuration("15m")},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Notification Rule escalation step EndpointID is invalid",
+			},
+		},
+		{
+			name: "missing delay",
+			escalation: []rule.EscalationStep{
+				{EndpointID: 3},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Notification Rule escalation step delay must be a positive duration",
+			},
+		},
+		{
+			name: "decreasing delays",
+			escalation: []rule.EscalationStep{
+				{EndpointID: 3, Delay: mustDuration("1h")},
+				{EndpointID: 4, Delay: mustDuration("15m")},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Notification Rule escalation steps must have increasing delays",
+			},
+		},
+	}
+	for _, c := range tests {
+		t.Run(c.name, func(t *testing.T) {
+			r := &rule.Slack{
+				MessageTemplate: "blah",
+				Base: rule.Base{
+					ID:         1,
+					Name:       "foo",
+					OwnerID:    2,
+					OrgID:      3,
+					EndpointID: 4,
+					Every:      mustDuration("1h"),
+					Escalation: c.escalation,
+				},
+			}
+			err := r.Valid()
+			if c.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != c.err.Error() {
+				t.Fatalf("expected error %v, got %v", c.err, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package rule
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/influxdb/v2"
@@ -13,6 +15,20 @@ import (
 // HTTP is the notification rule config of http.
 type HTTP struct {
 	Base
+	// BodyTemplate is the template of the body of the requests, see
+	// parseTemplate for its placeholders. The status row encoded as JSON is
+	// posted when it is empty.
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
+	// Headers are added to the headers of the endpoint, and replace them.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Method is the method of the requests, POST when empty.
+	Method string `json:"method,omitempty"`
+}
+
+var goodHTTPRuleMethod = map[string]bool{
+	"":              true,
+	http.MethodGet:  true,
+	http.MethodPost: true,
 }
 
 // GenerateFlux generates a flux script for the http notification rule.
@@ -30,10 +46,20 @@ func (s *HTTP) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
 
 // GenerateFluxAST generates a flux AST for the http notification rule.
 func (s *HTTP) GenerateFluxAST(e *endpoint.HTTP) (*ast.Package, error) {
+	escalations, err := s.generateEscalations(func(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+		httpEndpoint, ok := e.(*endpoint.HTTP)
+		if !ok {
+			return nil, fmt.Errorf("escalation endpoint provided is a %s, not an HTTP endpoint", e.Type())
+		}
+		return s.generateFluxASTBody(httpEndpoint), nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	f := flux.File(
 		s.Name,
 		s.imports(e),
-		s.generateFluxASTBody(e),
+		append(s.generateFluxASTBody(e), escalations...),
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
 }
@@ -46,11 +72,29 @@ func (s *HTTP) imports(e *endpoint.HTTP) []*ast.ImportDeclaration {
 		"experimental",
 	}
 
-	if e.AuthMethod == "bearer" || e.AuthMethod == "basic" {
+	if usesSecrets(e) {
 		packages = append(packages, "influxdata/influxdb/secrets")
+	} else {
+		// The endpoints of the escalation steps may use secrets as well.
+		for _, ee := range s.EscalationEndpoints {
+			if he, ok := ee.(*endpoint.HTTP); ok && usesSecrets(he) {
+				packages = append(packages, "influxdata/influxdb/secrets")
+				break
+			}
+		}
 	}
 
-	return flux.Imports(packages...)
+	imports := flux.Imports(packages...)
+	if s.Method == http.MethodGet {
+		get := flux.ImportDeclaration("experimental/http")
+		get.As = &ast.Identifier{Name: "experimental_http"}
+		imports = append(imports, get)
+	}
+	return imports
+}
+
+func usesSecrets(e *endpoint.HTTP) bool {
+	return e.AuthMethod == "bearer" || e.AuthMethod == "basic"
 }
 
 func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) []ast.Statement {
@@ -67,10 +111,31 @@ func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) []ast.Statement {
 }
 
 func (s *HTTP) generateHeaders(e *endpoint.HTTP) ast.Statement {
-	props := []*ast.Property{
-		flux.Dictionary(
-			"Content-Type", flux.String("application/json"),
-		),
+	headers := map[string]string{"Content-Type": "application/json"}
+	for k, v := range e.Headers {
+		headers[k] = v
+	}
+	for k, v := range s.Headers {
+		headers[k] = v
+	}
+	if e.AuthMethod == "bearer" || e.AuthMethod == "basic" {
+		delete(headers, "Authorization")
+	}
+
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		if k != "Content-Type" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if _, ok := headers["Content-Type"]; ok {
+		keys = append([]string{"Content-Type"}, keys...)
+	}
+
+	props := make([]*ast.Property, 0, len(keys))
+	for _, k := range keys {
+		props = append(props, flux.Dictionary(k, flux.String(headers[k])))
 	}
 
 	switch e.AuthMethod {
@@ -116,16 +181,58 @@ func (s *HTTP) generateHeaders(e *endpoint.HTTP) ast.Statement {
 }
 
 func (s *HTTP) generateFluxASTEndpoint(e *endpoint.HTTP) ast.Statement {
+	if s.Method == http.MethodGet {
+		return flux.DefineVariable("endpoint", s.generateFluxASTGetEndpoint(e))
+	}
+
 	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.String(e.URL))))
 
 	return flux.DefineVariable("endpoint", call)
 }
 
+// generateFluxASTGetEndpoint returns the equivalent of http.endpoint sending
+// GET requests, with experimental/http.get.
+func (s *HTTP) generateFluxASTGetEndpoint(e *endpoint.HTTP) ast.Expression {
+	get := flux.Call(flux.Member("experimental_http", "get"), flux.Object(
+		flux.Property("url", flux.String(e.URL)),
+		flux.Property("headers", flux.Member("obj", "headers")),
+	))
+	sent := flux.Call(flux.Identifier("string"), flux.Object(flux.Property("v",
+		flux.Equal(flux.Integer(200), flux.Member("response", "statusCode")),
+	)))
+	mapFn := flux.FuncBlock(flux.FunctionParams("r"),
+		flux.DefineVariable("obj", flux.Call(flux.Identifier("mapFn"), flux.Object(flux.Property("r", flux.Identifier("r"))))),
+		flux.DefineVariable("response", get),
+		&ast.ReturnStatement{
+			Argument: flux.ObjectWith("r", flux.Property("_sent", sent)),
+		},
+	)
+	send := flux.Pipe(
+		flux.Identifier("tables"),
+		flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", mapFn))),
+		flux.Call(flux.Member("experimental", "group"), flux.Object(
+			flux.Property("mode", flux.String("extend")),
+			flux.Property("columns", flux.Array(flux.String("_sent"))),
+		)),
+	)
+	tables := &ast.Property{
+		Key:   &ast.Identifier{Name: "tables"},
+		Value: &ast.PipeLiteral{},
+	}
+	return flux.Function(flux.FunctionParams("mapFn"), flux.Function([]*ast.Property{tables}, send))
+}
+
 func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
-	endpointBody := flux.Call(
+	var endpointBody ast.Expression = flux.Call(
 		flux.Member("json", "encode"),
 		flux.Object(flux.Property("v", flux.Identifier("body"))),
 	)
+	if s.BodyTemplate != "" {
+		endpointBody = flux.Call(
+			flux.Identifier("bytes"),
+			flux.Object(flux.Property("v", flux.Identifier("body"))),
+		)
+	}
 	headers := flux.Property("headers", flux.Identifier("headers"))
 
 	endpointProps := []*ast.Property{
@@ -150,6 +257,12 @@ func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
 }
 
 func (s *HTTP) generateBody() ast.Statement {
+	if s.BodyTemplate != "" {
+		// The template is validated along with the rule.
+		parts, _ := parseTemplate(s.BodyTemplate)
+		return flux.DefineVariable("body", templateExpression(parts))
+	}
+
 	// {r with "_version": 1}
 	props := []*ast.Property{
 		flux.Property(
@@ -180,6 +293,35 @@ func (s HTTP) Valid() error {
 	if err := s.Base.valid(); err != nil {
 		return err
 	}
+	if !goodHTTPRuleMethod[s.Method] {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "http rule method must be one of GET or POST",
+		}
+	}
+	for k := range s.Headers {
+		if k == "" {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "http rule header name is empty",
+			}
+		}
+	}
+	if s.BodyTemplate == "" {
+		return nil
+	}
+	if s.Method == http.MethodGet {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "http rule body template requires the POST method",
+		}
+	}
+	if _, err := parseTemplate(s.BodyTemplate); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("http rule body template is invalid: %s", err.Error()),
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,41 @@
+package rule
+
+import (
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// generateIncidentFilter generates the filter dropping the statuses of the
+// acknowledged incidents of the rule, so that they are not notified again.
+// The ok statuses are kept, so that the recovery of an acknowledged incident
+// is still notified.
+func (b *Base) generateIncidentFilter() *ast.CallExpression {
+	var acknowledged ast.Expression
+	for _, i := range b.AcknowledgedIncidents {
+		expr := generateIncidentExpr(i)
+		if acknowledged == nil {
+			acknowledged = expr
+			continue
+		}
+		acknowledged = flux.Or(acknowledged, expr)
+	}
+
+	return flux.Call(
+		flux.Identifier("filter"),
+		flux.Object(
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.Not(acknowledged))),
+		),
+	)
+}
+
+// generateIncidentExpr generates the expression matching the statuses of the
+// series of the incident which are not ok, since the incident started.
+func generateIncidentExpr(i influxdb.Incident) ast.Expression {
+	var expr ast.Expression = flux.Equal(flux.Member("r", "_check_id"), flux.String(i.CheckID.String()))
+	for _, t := range i.Tags {
+		expr = flux.And(expr, flux.Equal(flux.Member("r", t.Key), flux.String(t.Value)))
+	}
+	expr = flux.And(expr, flux.NotEqual(flux.Member("r", "_level"), flux.String(influxdb.CheckLevelOK)))
+	return flux.And(expr, flux.GreaterThanEqual(flux.Member("r", "_time"), flux.DateTime(i.Start)))
+}
@@ -0,0 +1,95 @@
+package rule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestGenerateFlux_acknowledgedIncidents(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "experimental"
+
+option task = {name: "foo", every: 1h, offset: 1s}
+
+headers = {"Content-Type": "application/json"}
+endpoint = http["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+	|> filter(fn: (r) =>
+		(not (r["_check_id"] == "0000000000000003" and r["host"] == "db1" and r["_level"] != "ok" and r["_time"] >= 2020-01-01T22:00:00Z or r["_check_id"] == "0000000000000004" and r["_level"] != "ok" and r["_time"] >= 2020-01-01T23:00:00Z)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: endpoint(mapFn: (r) => {
+		body = {r with _version: 1}
+
+		return {headers: headers, data: json["encode"](v: body)}
+	}))`
+
+	start := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+	s := &rule.HTTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			Offset:     mustDuration("1s"),
+			EndpointID: 2,
+			TagRules:   []notification.TagRule{},
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+	}
+	s.SetAcknowledgedIncidents([]influxdb.Incident{
+		{
+			CheckID: 3,
+			Tags:    []influxdb.Tag{{Key: "host", Value: "db1"}},
+			Status:  influxdb.IncidentAcknowledged,
+			Start:   start,
+		},
+		{
+			CheckID: 4,
+			Status:  influxdb.IncidentAcknowledged,
+			Start:   start.Add(time.Hour),
+		},
+	})
+
+	id := influxdb.ID(2)
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
@@ -0,0 +1,261 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// Opsgenie is the notification rule config of Opsgenie.
+type Opsgenie struct {
+	Base
+	MessageTemplate string `json:"messageTemplate"`
+	// Priorities map the levels of the statuses to the priorities of the
+	// alerts, they replace the default priorities of the levels.
+	Priorities []OpsgeniePriority `json:"priorities,omitempty"`
+}
+
+// OpsgeniePriority is the priority, from P1 to P5, of the alerts of the
+// statuses of a level.
+type OpsgeniePriority struct {
+	Level    notification.CheckLevel `json:"level"`
+	Priority string                  `json:"priority"`
+}
+
+// defaultOpsgeniePriorities are the priorities of the levels, which are
+// generated from the highest level to the lowest one. The ok statuses close
+// alerts rather than create them, they have no priority.
+var defaultOpsgeniePriorities = []OpsgeniePriority{
+	{Level: notification.Critical, Priority: "P1"},
+	{Level: notification.Warn, Priority: "P3"},
+	{Level: notification.Info, Priority: "P5"},
+}
+
+var goodOpsgeniePriority = map[string]bool{
+	"P1": true,
+	"P2": true,
+	"P3": true,
+	"P4": true,
+	"P5": true,
+}
+
+// GenerateFlux generates a flux script for the opsgenie notification rule.
+func (s *Opsgenie) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	opsgenieEndpoint, ok := e.(*endpoint.Opsgenie)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not an Opsgenie endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(opsgenieEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the opsgenie notification rule.
+// The alerts are posted to the alert API of the endpoint, and are aliased by
+// check, so that the statuses of a check update its open alert. The ok
+// statuses of a check close its alert instead.
+func (s *Opsgenie) GenerateFluxAST(e *endpoint.Opsgenie) (*ast.Package, error) {
+	escalations, err := s.generateEscalations(func(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+		opsgenieEndpoint, ok := e.(*endpoint.Opsgenie)
+		if !ok {
+			return nil, fmt.Errorf("escalation endpoint provided is a %s, not an Opsgenie endpoint", e.Type())
+		}
+		return s.generateFluxASTBody(opsgenieEndpoint), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	f := flux.File(
+		s.Name,
+		flux.Imports("influxdata/influxdb/monitor", "http", "json", "influxdata/influxdb/secrets", "experimental"),
+		append(s.generateFluxASTBody(e), escalations...),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *Opsgenie) generateFluxASTBody(e *endpoint.Opsgenie) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateFluxASTSecrets(e))
+	statements = append(statements, s.generateFluxASTEndpoint(e))
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateLevelChecks()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe(e))
+
+	return statements
+}
+
+func (s *Opsgenie) generateFluxASTSecrets(e *endpoint.Opsgenie) ast.Statement {
+	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.APIKey.Key))))
+
+	return flux.DefineVariable("opsgenie_secret", call)
+}
+
+// generateFluxASTEndpoint returns the equivalent of http.endpoint posting
+// each status to the url returned by mapFn along with its headers and data,
+// which is either the alert API or the close API of the alert of the status.
+// Opsgenie accepts requests with 202 Accepted.
+func (s *Opsgenie) generateFluxASTEndpoint(e *endpoint.Opsgenie) ast.Statement {
+	post := flux.Call(flux.Member("http", "post"), flux.Object(
+		flux.Property("url", flux.Member("obj", "url")),
+		flux.Property("headers", flux.Member("obj", "headers")),
+		flux.Property("data", flux.Member("obj", "data")),
+	))
+	sent := flux.Call(flux.Identifier("string"), flux.Object(flux.Property("v", flux.And(
+		flux.GreaterThanEqual(flux.Identifier("code"), flux.Integer(200)),
+		flux.LessThan(flux.Identifier("code"), flux.Integer(300)),
+	))))
+	mapFn := flux.FuncBlock(flux.FunctionParams("r"),
+		flux.DefineVariable("obj", flux.Call(flux.Identifier("mapFn"), flux.Object(flux.Property("r", flux.Identifier("r"))))),
+		flux.DefineVariable("code", post),
+		&ast.ReturnStatement{
+			Argument: flux.ObjectWith("r", flux.Property("_sent", sent)),
+		},
+	)
+	send := flux.Pipe(
+		flux.Identifier("tables"),
+		flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", mapFn))),
+		flux.Call(flux.Member("experimental", "group"), flux.Object(
+			flux.Property("mode", flux.String("extend")),
+			flux.Property("columns", flux.Array(flux.String("_sent"))),
+		)),
+	)
+	tables := &ast.Property{
+		Key:   &ast.Identifier{Name: "tables"},
+		Value: &ast.PipeLiteral{},
+	}
+	fn := flux.Function(flux.FunctionParams("mapFn"), flux.Function([]*ast.Property{tables}, send))
+
+	return flux.DefineVariable("opsgenie_endpoint", fn)
+}
+
+func (s *Opsgenie) generateFluxASTNotifyPipe(e *endpoint.Opsgenie) ast.Statement {
+	bodyProps := []*ast.Property{}
+	bodyProps = append(bodyProps, flux.Property("message", flux.String(s.MessageTemplate)))
+	bodyProps = append(bodyProps, flux.Property("alias", flux.Member("r", "_check_id")))
+	bodyProps = append(bodyProps, flux.Property("description", flux.Member("r", "_message")))
+	bodyProps = append(bodyProps, flux.Property("priority", s.generatePriority()))
+	bodyProps = append(bodyProps, flux.Property("source", flux.String("influxdb")))
+	bodyProps = append(bodyProps, flux.Property("entity", flux.Member("r", "_check_name")))
+
+	closeProps := []*ast.Property{}
+	closeProps = append(closeProps, flux.Property("source", flux.String("influxdb")))
+	closeProps = append(closeProps, flux.Property("note", flux.Member("r", "_message")))
+
+	headers := flux.Object(
+		flux.Dictionary("Content-Type", flux.String("application/json")),
+		flux.Dictionary("Authorization", flux.Add(flux.String("GenieKey "), flux.Identifier("opsgenie_secret"))),
+	)
+	encode := func(v string) ast.Expression {
+		return flux.Call(flux.Member("json", "encode"), flux.Object(flux.Property("v", flux.Identifier(v))))
+	}
+
+	// The alerts are closed by the alias they were created with.
+	ok := flux.Equal(flux.Member("r", "_level"), flux.String(strings.ToLower(notification.Ok.String())))
+	closeURL := flux.Add(
+		flux.Add(flux.String(e.AlertURL()+"/"), flux.Member("r", "_check_id")),
+		flux.String("/close?identifierType=alias"),
+	)
+
+	endpointFn := flux.FuncBlock(flux.FunctionParams("r"),
+		flux.DefineVariable("body", flux.Object(bodyProps...)),
+		flux.DefineVariable("close", flux.Object(closeProps...)),
+		&ast.ReturnStatement{
+			Argument: flux.Object(
+				flux.Property("url", flux.If(ok, closeURL, flux.String(e.AlertURL()))),
+				flux.Property("headers", headers),
+				flux.Property("data", flux.If(ok, encode("close"), encode("body"))),
+			),
+		},
+	)
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("opsgenie_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+// generatePriority returns the conditional expression of the priority of
+// the level of the status r, the lowest priority for unknown levels.
+func (s *Opsgenie) generatePriority() ast.Expression {
+	priorities := make(map[notification.CheckLevel]string, len(defaultOpsgeniePriorities))
+	for _, p := range defaultOpsgeniePriorities {
+		priorities[p.Level] = p.Priority
+	}
+	for _, p := range s.Priorities {
+		priorities[p.Level] = p.Priority
+	}
+
+	var expr ast.Expression = flux.String("P5")
+	for i := len(defaultOpsgeniePriorities) - 1; i >= 0; i-- {
+		lvl := defaultOpsgeniePriorities[i].Level
+		expr = flux.If(
+			flux.Equal(flux.Member("r", "_level"), flux.String(strings.ToLower(lvl.String()))),
+			flux.String(priorities[lvl]),
+			expr,
+		)
+	}
+	return expr
+}
+
+type opsgenieAlias Opsgenie
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Opsgenie) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			opsgenieAlias
+			Type string `json:"type"`
+		}{
+			opsgenieAlias: opsgenieAlias(s),
+			Type:          s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s Opsgenie) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.MessageTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "opsgenie message template is empty",
+		}
+	}
+	for _, p := range s.Priorities {
+		switch p.Level {
+		case notification.Info, notification.Warn, notification.Critical:
+		default:
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("opsgenie priority level %s is invalid", p.Level),
+			}
+		}
+		if !goodOpsgeniePriority[p.Priority] {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("opsgenie priority %q must be one of P1, P2, P3, P4 or P5", p.Priority),
+			}
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s Opsgenie) Type() string {
+	return "opsgenie"
+}
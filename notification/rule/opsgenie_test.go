@@ -0,0 +1,302 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/andreyvit/diff"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	influxTesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+var _ influxdb.NotificationRule = &rule.Opsgenie{}
+
+func TestOpsgenie_GenerateFlux(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		EndpointID: 3,
+		Name:       "foo",
+		Every:      mustDuration("1h"),
+		StatusRules: []notification.StatusRule{
+			{
+				CurrentLevel: notification.Any,
+			},
+		},
+		TagRules: []notification.TagRule{
+			{
+				Tag: influxdb.Tag{
+					Key:   "foo",
+					Value: "bar",
+				},
+				Operator: influxdb.Equal,
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		rule     *rule.Opsgenie
+		endpoint influxdb.NotificationEndpoint
+		script   string
+	}{
+		{
+			name: "incompatible with endpoint",
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				URL: "http://whatever",
+			},
+			rule: &rule.Opsgenie{
+				MessageTemplate: "blah",
+				Base:            base,
+			},
+			script: "", //no script generater, because of incompatible endpoint
+		},
+		{
+			name: "default priorities",
+			endpoint: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				APIKey: influxdb.SecretField{Key: "3-api-key"},
+			},
+			rule: &rule.Opsgenie{
+				MessageTemplate: "${r._check_name} is ${r._level}",
+				Base:            base,
+			},
+			script: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+opsgenie_secret = secrets["get"](key: "3-api-key")
+opsgenie_endpoint = (mapFn) =>
+	((tables=<-) =>
+		(tables
+			|> map(fn: (r) => {
+				obj = mapFn(r: r)
+				code = http["post"](url: obj["url"], headers: obj["headers"], data: obj["data"])
+
+				return {r with _sent: string(v: code >= 200 and code < 300)}
+			})
+			|> experimental["group"](mode: "extend", columns: ["_sent"])))
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar"))
+any = statuses
+	|> filter(fn: (r) =>
+		(true))
+all_statuses = any
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: opsgenie_endpoint(mapFn: (r) => {
+		body = {
+			message: "${r._check_name} is ${r._level}",
+			alias: r["_check_id"],
+			description: r["_message"],
+			priority: if r["_level"] == "crit" then "P1" else if r["_level"] == "warn" then "P3" else if r["_level"] == "info" then "P5" else "P5",
+			source: "influxdb",
+			entity: r["_check_name"],
+		}
+		close = {source: "influxdb", note: r["_message"]}
+
+		return {url: if r["_level"] == "ok" then "https://api.opsgenie.com/v2/alerts/" + r["_check_id"] + "/close?identifierType=alias" else "https://api.opsgenie.com/v2/alerts", headers: {"Content-Type": "application/json", "Authorization": "GenieKey " + opsgenie_secret}, data: if r["_level"] == "ok" then json["encode"](v: close) else json["encode"](v: body)}
+	}))`,
+		},
+		{
+			name: "priorities and local url",
+			endpoint: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				URL:    "http://localhost:7777/v2/alerts",
+				APIKey: influxdb.SecretField{Key: "3-api-key"},
+			},
+			rule: &rule.Opsgenie{
+				MessageTemplate: "blah",
+				Priorities: []rule.OpsgeniePriority{
+					{Level: notification.Critical, Priority: "P2"},
+					{Level: notification.Info, Priority: "P4"},
+				},
+				Base: base,
+			},
+			script: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+opsgenie_secret = secrets["get"](key: "3-api-key")
+opsgenie_endpoint = (mapFn) =>
+	((tables=<-) =>
+		(tables
+			|> map(fn: (r) => {
+				obj = mapFn(r: r)
+				code = http["post"](url: obj["url"], headers: obj["headers"], data: obj["data"])
+
+				return {r with _sent: string(v: code >= 200 and code < 300)}
+			})
+			|> experimental["group"](mode: "extend", columns: ["_sent"])))
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar"))
+any = statuses
+	|> filter(fn: (r) =>
+		(true))
+all_statuses = any
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: opsgenie_endpoint(mapFn: (r) => {
+		body = {
+			message: "blah",
+			alias: r["_check_id"],
+			description: r["_message"],
+			priority: if r["_level"] == "crit" then "P2" else if r["_level"] == "warn" then "P3" else if r["_level"] == "info" then "P4" else "P5",
+			source: "influxdb",
+			entity: r["_check_name"],
+		}
+		close = {source: "influxdb", note: r["_message"]}
+
+		return {url: if r["_level"] == "ok" then "http://localhost:7777/v2/alerts/" + r["_check_id"] + "/close?identifierType=alias" else "http://localhost:7777/v2/alerts", headers: {"Content-Type": "application/json", "Authorization": "GenieKey " + opsgenie_secret}, data: if r["_level"] == "ok" then json["encode"](v: close) else json["encode"](v: body)}
+	}))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := tt.rule.GenerateFlux(tt.endpoint)
+			if err != nil {
+				if script != "" {
+					t.Errorf("Failed to generate flux: %v", err)
+				}
+				return
+			}
+
+			if got, want := script, tt.script; got != want {
+				t.Errorf("\n\nStrings do not match:\n\n%s", diff.LineDiff(got, want))
+			}
+		})
+	}
+}
+
+func TestOpsgenie_Valid(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		EndpointID: 3,
+		OwnerID:    4,
+		OrgID:      5,
+		Name:       "foo",
+		Every:      mustDuration("1h"),
+		StatusRules: []notification.StatusRule{
+			{
+				CurrentLevel: notification.Critical,
+			},
+		},
+		TagRules: []notification.TagRule{},
+	}
+
+	cases := []struct {
+		name string
+		rule *rule.Opsgenie
+		err  error
+	}{
+		{
+			name: "valid priorities",
+			rule: &rule.Opsgenie{
+				MessageTemplate: "blah",
+				Priorities: []rule.OpsgeniePriority{
+					{Level: notification.Warn, Priority: "P2"},
+				},
+				Base: base,
+			},
+			err: nil,
+		},
+		{
+			name: "missing MessageTemplate",
+			rule: &rule.Opsgenie{
+				Base: base,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "opsgenie message template is empty",
+			},
+		},
+		{
+			name: "invalid priority level",
+			rule: &rule.Opsgenie{
+				MessageTemplate: "blah",
+				Priorities: []rule.OpsgeniePriority{
+					{Level: notification.Any, Priority: "P2"},
+				},
+				Base: base,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "opsgenie priority level ANY is invalid",
+			},
+		},
+		{
+			name: "ok priority level",
+			rule: &rule.Opsgenie{
+				MessageTemplate: "blah",
+				Priorities: []rule.OpsgeniePriority{
+					{Level: notification.Ok, Priority: "P5"},
+				},
+				Base: base,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "opsgenie priority level OK is invalid",
+			},
+		},
+		{
+			name: "invalid priority",
+			rule: &rule.Opsgenie{
+				MessageTemplate: "blah",
+				Priorities: []rule.OpsgeniePriority{
+					{Level: notification.Critical, Priority: "P0"},
+				},
+				Base: base,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `opsgenie priority "P0" must be one of P1, P2, P3, P4 or P5`,
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.rule.Valid()
+			influxTesting.ErrorsEqual(t, got, c.err)
+		})
+	}
+}
@@ -64,10 +64,20 @@ func (s *PagerDuty) GenerateFlux(e influxdb.NotificationEndpoint) (string, error
 
 // GenerateFluxAST generates a flux AST for the pagerduty notification rule.
 func (s *PagerDuty) GenerateFluxAST(e *endpoint.PagerDuty) (*ast.Package, error) {
+	escalations, err := s.generateEscalations(func(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+		pagerdutyEndpoint, ok := e.(*endpoint.PagerDuty)
+		if !ok {
+			return nil, fmt.Errorf("escalation endpoint provided is a %s, not a PagerDuty endpoint", e.Type())
+		}
+		return s.generateFluxASTBody(pagerdutyEndpoint), nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	f := flux.File(
 		s.Name,
 		flux.Imports("influxdata/influxdb/monitor", "pagerduty", "influxdata/influxdb/secrets", "experimental"),
-		s.generateFluxASTBody(e),
+		append(s.generateFluxASTBody(e), escalations...),
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
 }
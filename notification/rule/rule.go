@@ -17,6 +17,9 @@ var typeToRule = map[string](func() influxdb.NotificationRule){
 	"pagerduty": func() influxdb.NotificationRule { return &PagerDuty{} },
 	"http":      func() influxdb.NotificationRule { return &HTTP{} },
 	"telegram":  func() influxdb.NotificationRule { return &Telegram{} },
+	"email":     func() influxdb.NotificationRule { return &Email{} },
+	"teams":     func() influxdb.NotificationRule { return &Teams{} },
+	"opsgenie":  func() influxdb.NotificationRule { return &Opsgenie{} },
 }
 
 // UnmarshalJSON will convert
@@ -58,8 +61,23 @@ type Base struct {
 	RunbookLink string                    `json:"runbookLink"`
 	TagRules    []notification.TagRule    `json:"tagRules,omitempty"`
 	StatusRules []notification.StatusRule `json:"statusRules,omitempty"`
+	// Escalation is the escalation policy of the rule, its steps are ordered
+	// by increasing delays.
+	Escalation []EscalationStep `json:"escalation,omitempty"`
 	*influxdb.Limit
 	influxdb.CRUDLog
+
+	// Silences mute the statuses they match in the generated flux. They are
+	// set by the store when the task of the rule is generated, and are not
+	// part of the rule.
+	Silences []influxdb.Silence `json:"-"`
+	// AcknowledgedIncidents mute the statuses of their series in the
+	// generated flux until they are resolved. They are set by the store like
+	// the silences.
+	AcknowledgedIncidents []influxdb.Incident `json:"-"`
+	// EscalationEndpoints are the endpoints of the escalation steps, in the
+	// order of the steps. They are set by the store like the silences.
+	EscalationEndpoints []influxdb.NotificationEndpoint `json:"-"`
 }
 
 func (b Base) valid() error {
@@ -104,6 +122,19 @@ func (b Base) valid() error {
 			return err
 		}
 	}
+	var delay time.Duration
+	for _, step := range b.Escalation {
+		if err := step.Valid(); err != nil {
+			return err
+		}
+		if step.Delay.TimeDuration() <= delay {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Notification Rule escalation steps must have increasing delays",
+			}
+		}
+		delay = step.Delay.TimeDuration()
+	}
 	if b.Limit != nil {
 		if b.Limit.Every <= 0 || b.Limit.Rate <= 0 {
 			return &influxdb.Error{
@@ -182,6 +213,14 @@ func (b *Base) generateLevelChecks() []ast.Statement {
 		)
 	}
 
+	if len(b.Silences) > 0 {
+		pipe = flux.Pipe(pipe, b.generateSilenceFilter())
+	}
+
+	if len(b.AcknowledgedIncidents) > 0 {
+		pipe = flux.Pipe(pipe, b.generateIncidentFilter())
+	}
+
 	stmts = append(stmts, flux.DefineVariable("all_statuses", pipe))
 
 	return stmts
@@ -292,13 +331,8 @@ func (b *Base) generateFluxASTStatuses() ast.Statement {
 	dur := (*ast.DurationLiteral)(b.Every)
 	props = append(props, flux.Property("start", flux.Negative(increaseDur(dur))))
 
-	if len(b.TagRules) > 0 {
-		r := b.TagRules[0]
-		var body ast.Expression = r.GenerateFluxAST()
-		for _, r := range b.TagRules[1:] {
-			body = flux.And(body, r.GenerateFluxAST())
-		}
-		props = append(props, flux.Property("fn", flux.Function(flux.FunctionParams("r"), body)))
+	if fn := b.generateTagRulesFn(); fn != nil {
+		props = append(props, flux.Property("fn", fn))
 	}
 
 	base := flux.Call(flux.Member("monitor", "from"), flux.Object(props...))
@@ -306,6 +340,20 @@ func (b *Base) generateFluxASTStatuses() ast.Statement {
 	return flux.DefineVariable("statuses", base)
 }
 
+// generateTagRulesFn returns the function matching the statuses of the tag
+// rules, or nil if the rule has none.
+func (b *Base) generateTagRulesFn() *ast.FunctionExpression {
+	if len(b.TagRules) == 0 {
+		return nil
+	}
+	r := b.TagRules[0]
+	var body ast.Expression = r.GenerateFluxAST()
+	for _, r := range b.TagRules[1:] {
+		body = flux.And(body, r.GenerateFluxAST())
+	}
+	return flux.Function(flux.FunctionParams("r"), body)
+}
+
 // GetID implements influxdb.Getter interface.
 func (b Base) GetID() influxdb.ID {
 	return b.ID
@@ -331,6 +379,32 @@ func (b *Base) SetTaskID(id influxdb.ID) {
 	b.TaskID = id
 }
 
+// SetSilences sets the silences honored by the generated flux.
+func (b *Base) SetSilences(silences []influxdb.Silence) {
+	b.Silences = silences
+}
+
+// SetAcknowledgedIncidents sets the acknowledged incidents honored by the
+// generated flux.
+func (b *Base) SetAcknowledgedIncidents(incidents []influxdb.Incident) {
+	b.AcknowledgedIncidents = incidents
+}
+
+// GetEscalationEndpointIDs returns the endpoint IDs of the escalation steps.
+func (b Base) GetEscalationEndpointIDs() []influxdb.ID {
+	ids := make([]influxdb.ID, 0, len(b.Escalation))
+	for _, step := range b.Escalation {
+		ids = append(ids, step.EndpointID)
+	}
+	return ids
+}
+
+// SetEscalationEndpoints sets the endpoints of the escalation steps notified
+// by the generated flux.
+func (b *Base) SetEscalationEndpoints(endpoints []influxdb.NotificationEndpoint) {
+	b.EscalationEndpoints = endpoints
+}
+
 // ClearPrivateData clears the task ID from the base.
 func (b *Base) ClearPrivateData() {
 	b.TaskID = 0
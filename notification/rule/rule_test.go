@@ -195,6 +195,51 @@ func TestValidRule(t *testing.T) {
 				Msg:  `if limit is set, limit and limitEvery must be larger than 0`,
 			},
 		},
+		{
+			name: "bad http method",
+			src: &rule.HTTP{
+				Base:   goodBase,
+				Method: "DELETE",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "http rule method must be one of GET or POST",
+			},
+		},
+		{
+			name: "http body template with GET",
+			src: &rule.HTTP{
+				Base:         goodBase,
+				Method:       "GET",
+				BodyTemplate: "${r._level}",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "http rule body template requires the POST method",
+			},
+		},
+		{
+			name: "unclosed http body template placeholder",
+			src: &rule.HTTP{
+				Base:         goodBase,
+				BodyTemplate: "level: ${r._level",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `http rule body template is invalid: placeholder "${r._level" is not closed`,
+			},
+		},
+		{
+			name: "bad http body template placeholder",
+			src: &rule.HTTP{
+				Base:         goodBase,
+				BodyTemplate: "level: ${level}",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `http rule body template is invalid: placeholder "${level}" must be one of ${r}, ${r.column}, ${r["column"]}, ${json(r.column)} or ${json(r["column"])}`,
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -356,6 +401,48 @@ func TestJSON(t *testing.T) {
 				MessageTemplate: "blah",
 			},
 		},
+		{
+			name: "simple teams",
+			src: &rule.Teams{
+				Base: rule.Base{
+					ID:          influxTesting.MustIDBase16(id1),
+					OwnerID:     influxTesting.MustIDBase16(id2),
+					Name:        "name1",
+					OrgID:       influxTesting.MustIDBase16(id3),
+					RunbookLink: "runbooklink1",
+					SleepUntil:  &time3,
+					Every:       mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				TitleTemplate:   "title",
+				MessageTemplate: "blah",
+			},
+		},
+		{
+			name: "simple opsgenie",
+			src: &rule.Opsgenie{
+				Base: rule.Base{
+					ID:          influxTesting.MustIDBase16(id1),
+					OwnerID:     influxTesting.MustIDBase16(id2),
+					Name:        "name1",
+					OrgID:       influxTesting.MustIDBase16(id3),
+					RunbookLink: "runbooklink1",
+					SleepUntil:  &time3,
+					Every:       mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				MessageTemplate: "blah",
+				Priorities: []rule.OpsgeniePriority{
+					{Level: notification.Critical, Priority: "P2"},
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		b, err := json.Marshal(c.src)
@@ -0,0 +1,73 @@
+package rule
+
+import (
+	"regexp"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// generateSilenceFilter generates the filter dropping the statuses that fall
+// in a window of one of the silences of the rule, so that no notification is
+// sent for them.
+func (b *Base) generateSilenceFilter() *ast.CallExpression {
+	var silenced ast.Expression
+	for _, s := range b.Silences {
+		expr := generateSilenceExpr(s)
+		if silenced == nil {
+			silenced = expr
+			continue
+		}
+		silenced = flux.Or(silenced, expr)
+	}
+
+	return flux.Call(
+		flux.Identifier("filter"),
+		flux.Object(
+			flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.Not(silenced))),
+		),
+	)
+}
+
+// generateSilenceExpr generates the expression matching the statuses muted by
+// the silence. A recurring window is matched on the time elapsed since the
+// start of its first occurrence, modulo its period.
+func generateSilenceExpr(s influxdb.Silence) ast.Expression {
+	t := flux.Member("r", "_time")
+
+	var expr ast.Expression = flux.GreaterThanEqual(t, flux.DateTime(s.Start))
+	if s.Recurrence == nil {
+		expr = flux.And(expr, flux.LessThan(t, flux.DateTime(s.End)))
+	} else {
+		if s.Recurrence.Until != nil {
+			expr = flux.And(expr, flux.LessThan(t, flux.DateTime(*s.Recurrence.Until)))
+		}
+		elapsed := flux.Subtract(
+			flux.Call(flux.Identifier("int"), flux.Object(flux.Property("v", t))),
+			flux.Integer(s.Start.UnixNano()),
+		)
+		expr = flux.And(expr, flux.LessThan(
+			flux.Modulo(elapsed, flux.Integer(int64(s.Recurrence.Period()))),
+			flux.Integer(int64(s.End.Sub(s.Start))),
+		))
+	}
+
+	for _, tr := range s.TagRules {
+		expr = flux.And(expr, generateSilenceTagRuleExpr(tr))
+	}
+	return expr
+}
+
+func generateSilenceTagRuleExpr(tr influxdb.TagRule) ast.Expression {
+	k := flux.Member("r", tr.Key)
+	switch tr.Operator {
+	case influxdb.NotEqual:
+		return flux.NotEqual(k, flux.String(tr.Value))
+	case influxdb.RegexEqual:
+		return flux.RegexpMatch(k, flux.Regexp(regexp.MustCompile(tr.Value)))
+	case influxdb.NotRegexEqual:
+		return flux.NotRegexpMatch(k, flux.Regexp(regexp.MustCompile(tr.Value)))
+	}
+	return flux.Equal(k, flux.String(tr.Value))
+}
@@ -0,0 +1,103 @@
+package rule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestGenerateFlux_silences(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "experimental"
+
+option task = {name: "foo", every: 1h, offset: 1s}
+
+headers = {"Content-Type": "application/json"}
+endpoint = http["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+	|> filter(fn: (r) =>
+		(not (r["_time"] >= 2020-01-01T22:00:00Z and r["_time"] < 2020-01-02T00:00:00Z and r["host"] == "db1" or r["_time"] >= 2020-01-01T22:00:00Z and r["_time"] < 2020-02-01T00:00:00Z and (int(v: r["_time"]) - 1577916000000000000) % 86400000000000 < 7200000000000 and r["region"] =~ /^us-/)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: endpoint(mapFn: (r) => {
+		body = {r with _version: 1}
+
+		return {headers: headers, data: json["encode"](v: body)}
+	}))`
+
+	start := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+	until := time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+	s := &rule.HTTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			Offset:     mustDuration("1s"),
+			EndpointID: 2,
+			TagRules:   []notification.TagRule{},
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+	}
+	s.SetSilences([]influxdb.Silence{
+		{
+			Start: start,
+			End:   start.Add(2 * time.Hour),
+			TagRules: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "host", Value: "db1"}, Operator: influxdb.Equal},
+			},
+		},
+		{
+			Start: start,
+			End:   start.Add(2 * time.Hour),
+			Recurrence: &influxdb.SilenceRecurrence{
+				Every: influxdb.SilenceRecurDaily,
+				Until: &until,
+			},
+			TagRules: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "region", Value: "^us-"}, Operator: influxdb.RegexEqual},
+			},
+		},
+	})
+
+	id := influxdb.ID(2)
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
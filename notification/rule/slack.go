@@ -32,10 +32,20 @@ func (s *Slack) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
 
 // GenerateFluxAST generates a flux AST for the slack notification rule.
 func (s *Slack) GenerateFluxAST(e *endpoint.Slack) (*ast.Package, error) {
+	escalations, err := s.generateEscalations(func(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+		slackEndpoint, ok := e.(*endpoint.Slack)
+		if !ok {
+			return nil, fmt.Errorf("escalation endpoint provided is a %s, not a Slack endpoint", e.Type())
+		}
+		return s.generateFluxASTBody(slackEndpoint), nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	f := flux.File(
 		s.Name,
 		flux.Imports("influxdata/influxdb/monitor", "slack", "influxdata/influxdb/secrets", "experimental"),
-		s.generateFluxASTBody(e),
+		append(s.generateFluxASTBody(e), escalations...),
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
 }
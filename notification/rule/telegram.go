@@ -33,10 +33,20 @@ func (s *Telegram) GenerateFlux(e influxdb.NotificationEndpoint) (string, error)
 
 // GenerateFluxAST generates a flux AST for the telegram notification rule.
 func (s *Telegram) GenerateFluxAST(e *endpoint.Telegram) (*ast.Package, error) {
+	escalations, err := s.generateEscalations(func(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+		telegramEndpoint, ok := e.(*endpoint.Telegram)
+		if !ok {
+			return nil, fmt.Errorf("escalation endpoint provided is a %s, not a Telegram endpoint", e.Type())
+		}
+		return s.generateFluxASTBody(telegramEndpoint), nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	f := flux.File(
 		s.Name,
 		flux.Imports("influxdata/influxdb/monitor", "contrib/sranka/telegram", "influxdata/influxdb/secrets", "experimental"),
-		s.generateFluxASTBody(e),
+		append(s.generateFluxASTBody(e), escalations...),
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
 }
@@ -0,0 +1,110 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// templatePart is either the text of a template, or one of its placeholders,
+// which refer to a column of the status row or to the whole row.
+type templatePart struct {
+	text        string
+	column      string
+	placeholder bool
+	// json is set for the placeholders of a column encoded as JSON.
+	json bool
+}
+
+var (
+	templateColumnRe       = regexp.MustCompile(`^r\.([A-Za-z_][A-Za-z0-9_]*)$`)
+	templateQuotedColumnRe = regexp.MustCompile(`^r\["([^"\\]+)"\]$`)
+	templateJSONRe         = regexp.MustCompile(`^json\((.*)\)$`)
+)
+
+// parseTemplate splits a template into its text and its placeholders. The
+// placeholders are ${r} for the status row encoded as JSON, and ${r.column}
+// or ${r["column"]} for the value of a column of the status row, such as
+// ${r._check_name}, ${r._level}, ${r._message}, ${r._time} or the tags of
+// the status. ${json(r.column)} or ${json(r["column"])} is the value of the
+// column encoded as JSON, quoted and escaped if it is a string, to be used in
+// JSON documents.
+func parseTemplate(tmpl string) ([]templatePart, error) {
+	var parts []templatePart
+	for len(tmpl) > 0 {
+		start := strings.Index(tmpl, "${")
+		if start < 0 {
+			parts = append(parts, templatePart{text: tmpl})
+			break
+		}
+		if start > 0 {
+			parts = append(parts, templatePart{text: tmpl[:start]})
+		}
+		tmpl = tmpl[start+2:]
+
+		end := strings.Index(tmpl, "}")
+		if end < 0 {
+			return nil, fmt.Errorf("placeholder %q is not closed", "${"+tmpl)
+		}
+		ref := strings.TrimSpace(tmpl[:end])
+		tmpl = tmpl[end+1:]
+
+		part := templatePart{placeholder: true}
+		col := ref
+		if m := templateJSONRe.FindStringSubmatch(ref); m != nil {
+			col = strings.TrimSpace(m[1])
+			part.json = true
+		}
+		if col != "r" {
+			m := templateColumnRe.FindStringSubmatch(col)
+			if m == nil {
+				m = templateQuotedColumnRe.FindStringSubmatch(col)
+			}
+			if m == nil {
+				return nil, fmt.Errorf(`placeholder "${%s}" must be one of ${r}, ${r.column}, ${r["column"]}, ${json(r.column)} or ${json(r["column"])}`, ref)
+			}
+			part.column = m[1]
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// templateExpression returns the flux expression rendering the parts of a
+// template for the status row r.
+func templateExpression(parts []templatePart) ast.Expression {
+	var expr ast.Expression
+	for _, p := range parts {
+		var e ast.Expression
+		switch {
+		case !p.placeholder:
+			e = flux.String(p.text)
+		case p.column == "":
+			e = toStringCall(jsonEncodeCall(flux.Identifier("r")))
+		case p.json:
+			e = toStringCall(jsonEncodeCall(flux.Member("r", p.column)))
+		default:
+			e = toStringCall(flux.Member("r", p.column))
+		}
+		if expr == nil {
+			expr = e
+			continue
+		}
+		expr = flux.Add(expr, e)
+	}
+	if expr == nil {
+		return flux.String("")
+	}
+	return expr
+}
+
+func jsonEncodeCall(e ast.Expression) *ast.CallExpression {
+	return flux.Call(flux.Member("json", "encode"), flux.Object(flux.Property("v", e)))
+}
+
+func toStringCall(e ast.Expression) *ast.CallExpression {
+	return flux.Call(flux.Identifier("string"), flux.Object(flux.Property("v", e)))
+}
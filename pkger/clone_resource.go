@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	ierrors "github.com/influxdata/influxdb/v2/kit/errors"
@@ -50,17 +51,24 @@ var kindPriorities = map[Kind]int{
 	KindLabel:                         1,
 	KindBucket:                        2,
 	KindCheck:                         3,
-	KindCheckDeadman:                  4,
-	KindCheckThreshold:                5,
-	KindNotificationEndpoint:          6,
-	KindNotificationEndpointHTTP:      7,
-	KindNotificationEndpointPagerDuty: 8,
-	KindNotificationEndpointSlack:     9,
-	KindNotificationRule:              10,
-	KindTask:                          11,
-	KindVariable:                      12,
-	KindDashboard:                     13,
-	KindTelegraf:                      14,
+	KindCheckAnomaly:                  4,
+	KindCheckDeadman:                  5,
+	KindCheckRate:                     6,
+	KindCheckThreshold:                7,
+	KindNotificationEndpoint:          8,
+	KindNotificationEndpointEmail:     9,
+	KindNotificationEndpointHTTP:      10,
+	KindNotificationEndpointOpsgenie:  11,
+	KindNotificationEndpointPagerDuty: 12,
+	KindNotificationEndpointSlack:     13,
+	KindNotificationEndpointTeams:     14,
+	KindNotificationEndpointTelegram:  15,
+	KindNotificationRule:              16,
+	KindSilence:                       17,
+	KindTask:                          18,
+	KindVariable:                      19,
+	KindDashboard:                     20,
+	KindTelegraf:                      21,
 }
 
 type exportKey struct {
@@ -88,6 +96,7 @@ type resourceExporter struct {
 	labelSVC    influxdb.LabelService
 	endpointSVC influxdb.NotificationEndpointService
 	ruleSVC     influxdb.NotificationRuleStore
+	silenceSVC  influxdb.SilenceService
 	taskSVC     influxdb.TaskService
 	teleSVC     influxdb.TelegrafConfigStore
 	varSVC      influxdb.VariableService
@@ -106,6 +115,7 @@ func newResourceExporter(svc *Service) *resourceExporter {
 		labelSVC:        svc.labelSVC,
 		endpointSVC:     svc.endpointSVC,
 		ruleSVC:         svc.ruleSVC,
+		silenceSVC:      svc.silenceSVC,
 		taskSVC:         svc.taskSVC,
 		teleSVC:         svc.teleSVC,
 		varSVC:          svc.varSVC,
@@ -230,7 +240,9 @@ func (ex *resourceExporter) resourceCloneToKind(ctx context.Context, r ResourceT
 		}
 		mapResource(bkt.OrgID, uniqByNameResID, KindBucket, BucketToObject(r.Name, *bkt))
 	case r.Kind.is(KindCheck),
+		r.Kind.is(KindCheckAnomaly),
 		r.Kind.is(KindCheckDeadman),
+		r.Kind.is(KindCheckRate),
 		r.Kind.is(KindCheckThreshold):
 		ch, err := ex.checkSVC.FindCheckByID(ctx, r.ID)
 		if err != nil {
@@ -250,9 +262,13 @@ func (ex *resourceExporter) resourceCloneToKind(ctx context.Context, r ResourceT
 		}
 		mapResource(l.OrgID, uniqByNameResID, KindLabel, LabelToObject(r.Name, *l))
 	case r.Kind.is(KindNotificationEndpoint),
+		r.Kind.is(KindNotificationEndpointEmail),
 		r.Kind.is(KindNotificationEndpointHTTP),
+		r.Kind.is(KindNotificationEndpointOpsgenie),
 		r.Kind.is(KindNotificationEndpointPagerDuty),
-		r.Kind.is(KindNotificationEndpointSlack):
+		r.Kind.is(KindNotificationEndpointSlack),
+		r.Kind.is(KindNotificationEndpointTeams),
+		r.Kind.is(KindNotificationEndpointTelegram):
 		e, err := ex.endpointSVC.FindNotificationEndpointByID(ctx, r.ID)
 		if err != nil {
 			return err
@@ -273,6 +289,12 @@ func (ex *resourceExporter) resourceCloneToKind(ctx context.Context, r ResourceT
 		endpointObjectName := object.Name()
 
 		mapResource(rule.GetOrgID(), rule.GetID(), KindNotificationRule, NotificationRuleToObject(r.Name, endpointObjectName, rule))
+	case r.Kind.is(KindSilence):
+		sl, err := ex.silenceSVC.FindSilenceByID(ctx, r.ID)
+		if err != nil {
+			return err
+		}
+		mapResource(sl.OrgID, sl.ID, KindSilence, SilenceToObject(r.Name, *sl))
 	case r.Kind.is(KindTask):
 		t, err := ex.taskSVC.FindTaskByID(ctx, r.ID)
 		if err != nil {
@@ -318,6 +340,10 @@ func (ex *resourceExporter) resourceCloneAssociationsGen(ctx context.Context, la
 			shouldSkip := len(mLabelIDs) > 0 && !mLabelIDs[r.ID]
 			return nil, shouldSkip, nil
 		}
+		if r.Kind.is(KindSilence) {
+			// silences do not have labels
+			return nil, false, nil
+		}
 
 		labels, err := ex.labelSVC.FindResourceLabels(ctx, influxdb.LabelMappingFilter{
 			ResourceID:   r.ID,
@@ -517,6 +543,32 @@ func CheckToObject(name string, ch influxdb.Check) Object {
 			thresholds = append(thresholds, convertThreshold(th))
 		}
 		o.Spec[fieldCheckThresholds] = thresholds
+	case *icheck.Rate:
+		o.Kind = KindCheckRate
+		assignBase(cT.Base)
+		assignNonZeroFluxDurs(o.Spec, map[string]*notification.Duration{
+			fieldCheckUnit: cT.Unit,
+		})
+		assignNonZeroBools(o.Spec, map[string]bool{fieldCheckNonNegative: cT.NonNegative})
+		var thresholds []Resource
+		for _, th := range cT.Thresholds {
+			thresholds = append(thresholds, convertThreshold(th))
+		}
+		o.Spec[fieldCheckThresholds] = thresholds
+	case *icheck.Anomaly:
+		o.Kind = KindCheckAnomaly
+		assignBase(cT.Base)
+		assignNonZeroFluxDurs(o.Spec, map[string]*notification.Duration{
+			fieldCheckWindow: cT.Window,
+		})
+		var thresholds []Resource
+		for _, th := range cT.Thresholds {
+			thresholds = append(thresholds, Resource{
+				fieldLevel:           th.Level.String(),
+				fieldCheckDeviations: th.Deviations,
+			})
+		}
+		o.Spec[fieldCheckThresholds] = thresholds
 	}
 	return o
 }
@@ -910,6 +962,16 @@ func NotificationEndpointToObject(name string, e influxdb.NotificationEndpoint)
 	})
 
 	switch actual := e.(type) {
+	case *endpoint.Email:
+		o.Kind = KindNotificationEndpointEmail
+		o.Spec[fieldNotificationEndpointHost] = actual.Host
+		o.Spec[fieldNotificationEndpointPort] = actual.Port
+		o.Spec[fieldNotificationEndpointTLSMode] = actual.TLSMode
+		o.Spec[fieldNotificationEndpointFrom] = actual.From
+		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
+			fieldNotificationEndpointPassword: actual.Password,
+			fieldNotificationEndpointUsername: actual.Username,
+		})
 	case *endpoint.HTTP:
 		o.Kind = KindNotificationEndpointHTTP
 		o.Spec[fieldNotificationEndpointHTTPMethod] = actual.Method
@@ -920,6 +982,12 @@ func NotificationEndpointToObject(name string, e influxdb.NotificationEndpoint)
 			fieldNotificationEndpointToken:    actual.Token,
 			fieldNotificationEndpointUsername: actual.Username,
 		})
+	case *endpoint.Opsgenie:
+		o.Kind = KindNotificationEndpointOpsgenie
+		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationEndpointURL: actual.URL})
+		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
+			fieldNotificationEndpointAPIKey: actual.APIKey,
+		})
 	case *endpoint.PagerDuty:
 		o.Kind = KindNotificationEndpointPagerDuty
 		o.Spec[fieldNotificationEndpointURL] = actual.ClientURL
@@ -932,6 +1000,17 @@ func NotificationEndpointToObject(name string, e influxdb.NotificationEndpoint)
 		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
 			fieldNotificationEndpointToken: actual.Token,
 		})
+	case *endpoint.Teams:
+		o.Kind = KindNotificationEndpointTeams
+		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
+			fieldNotificationEndpointURL: actual.URL,
+		})
+	case *endpoint.Telegram:
+		o.Kind = KindNotificationEndpointTelegram
+		o.Spec[fieldNotificationEndpointChannel] = actual.Channel
+		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
+			fieldNotificationEndpointToken: actual.Token,
+		})
 	}
 
 	return o
@@ -983,8 +1062,27 @@ func NotificationRuleToObject(name, endpointPkgName string, iRule influxdb.Notif
 	}
 
 	switch t := iRule.(type) {
+	case *rule.Email:
+		assignBase(t.Base)
+		o.Spec[fieldNotificationRuleMessageTemplate] = t.BodyTemplate
+		o.Spec[fieldNotificationRuleSubjectTemplate] = t.SubjectTemplate
+		o.Spec[fieldNotificationRuleTo] = t.To
 	case *rule.HTTP:
 		assignBase(t.Base)
+		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleMessageTemplate: t.BodyTemplate})
+	case *rule.Opsgenie:
+		assignBase(t.Base)
+		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+		var priorityRes []Resource
+		for _, p := range t.Priorities {
+			priorityRes = append(priorityRes, Resource{
+				fieldLevel:                    p.Level.String(),
+				fieldNotificationRulePriority: p.Priority,
+			})
+		}
+		if len(priorityRes) > 0 {
+			o.Spec[fieldNotificationRulePriorities] = priorityRes
+		}
 	case *rule.PagerDuty:
 		assignBase(t.Base)
 		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
@@ -992,6 +1090,51 @@ func NotificationRuleToObject(name, endpointPkgName string, iRule influxdb.Notif
 		assignBase(t.Base)
 		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
 		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleChannel: t.Channel})
+	case *rule.Teams:
+		assignBase(t.Base)
+		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleTitleTemplate: t.TitleTemplate})
+	case *rule.Telegram:
+		assignBase(t.Base)
+		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleParseMode: t.ParseMode})
+		assignNonZeroBools(o.Spec, map[string]bool{fieldNotificationRuleDisablePreview: t.DisableWebPagePreview})
+	}
+
+	return o
+}
+
+// SilenceToObject converts an influxdb.Silence into a pkger.Object.
+func SilenceToObject(name string, s influxdb.Silence) Object {
+	if name == "" {
+		name = s.Name
+	}
+
+	o := newObject(KindSilence, name)
+	assignNonZeroStrings(o.Spec, map[string]string{
+		fieldDescription:  s.Description,
+		fieldSilenceStart: s.Start.UTC().Format(time.RFC3339),
+		fieldSilenceEnd:   s.End.UTC().Format(time.RFC3339),
+	})
+
+	if recur := s.Recurrence; recur != nil {
+		recurRes := Resource{fieldEvery: recur.Every}
+		if recur.Until != nil {
+			recurRes[fieldSilenceUntil] = recur.Until.UTC().Format(time.RFC3339)
+		}
+		o.Spec[fieldSilenceRecurrence] = recurRes
+	}
+
+	var tagRes []Resource
+	for _, tRule := range s.TagRules {
+		tagRes = append(tagRes, Resource{
+			fieldKey:      tRule.Key,
+			fieldValue:    tRule.Value,
+			fieldOperator: tRule.Operator.String(),
+		})
+	}
+	if len(tagRes) > 0 {
+		o.Spec[fieldSilenceTagRules] = tagRes
 	}
 
 	return o
@@ -416,19 +416,25 @@ func stackResLinks(r StackResource) RespStackResourceLinks {
 	switch r.Kind {
 	case KindBucket:
 		linkResource = "buckets"
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAnomaly, KindCheckDeadman, KindCheckRate, KindCheckThreshold:
 		linkResource = "checks"
 	case KindDashboard:
 		linkResource = "dashboards"
 	case KindLabel:
 		linkResource = "labels"
 	case KindNotificationEndpoint,
+		KindNotificationEndpointEmail,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointTelegram:
 		linkResource = "notificationEndpoints"
 	case KindNotificationRule:
 		linkResource = "notificationRules"
+	case KindSilence:
+		linkResource = "silences"
 	case KindTask:
 		linkResource = "tasks"
 	case KindTelegraf:
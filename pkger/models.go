@@ -16,16 +16,23 @@ const (
 	KindUnknown                       Kind = ""
 	KindBucket                        Kind = "Bucket"
 	KindCheck                         Kind = "Check"
+	KindCheckAnomaly                  Kind = "CheckAnomaly"
 	KindCheckDeadman                  Kind = "CheckDeadman"
+	KindCheckRate                     Kind = "CheckRate"
 	KindCheckThreshold                Kind = "CheckThreshold"
 	KindDashboard                     Kind = "Dashboard"
 	KindLabel                         Kind = "Label"
 	KindNotificationEndpoint          Kind = "NotificationEndpoint"
+	KindNotificationEndpointEmail     Kind = "NotificationEndpointEmail"
 	KindNotificationEndpointHTTP      Kind = "NotificationEndpointHTTP"
+	KindNotificationEndpointOpsgenie  Kind = "NotificationEndpointOpsgenie"
 	KindNotificationEndpointPagerDuty Kind = "NotificationEndpointPagerDuty"
 	KindNotificationEndpointSlack     Kind = "NotificationEndpointSlack"
+	KindNotificationEndpointTeams     Kind = "NotificationEndpointTeams"
+	KindNotificationEndpointTelegram  Kind = "NotificationEndpointTelegram"
 	KindNotificationRule              Kind = "NotificationRule"
 	KindPackage                       Kind = "Package"
+	KindSilence                       Kind = "Silence"
 	KindTask                          Kind = "Task"
 	KindTelegraf                      Kind = "Telegraf"
 	KindVariable                      Kind = "Variable"
@@ -43,15 +50,22 @@ func Kinds() []Kind {
 var kinds = map[Kind]bool{
 	KindBucket:                        true,
 	KindCheck:                         true,
+	KindCheckAnomaly:                  true,
 	KindCheckDeadman:                  true,
+	KindCheckRate:                     true,
 	KindCheckThreshold:                true,
 	KindDashboard:                     true,
 	KindLabel:                         true,
 	KindNotificationEndpoint:          true,
+	KindNotificationEndpointEmail:     true,
 	KindNotificationEndpointHTTP:      true,
+	KindNotificationEndpointOpsgenie:  true,
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
+	KindNotificationEndpointTeams:     true,
+	KindNotificationEndpointTelegram:  true,
 	KindNotificationRule:              true,
+	KindSilence:                       true,
 	KindTask:                          true,
 	KindTelegraf:                      true,
 	KindVariable:                      true,
@@ -87,18 +101,22 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 	switch k {
 	case KindBucket:
 		return influxdb.BucketsResourceType
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAnomaly, KindCheckDeadman, KindCheckRate, KindCheckThreshold:
 		return influxdb.ChecksResourceType
 	case KindDashboard:
 		return influxdb.DashboardsResourceType
 	case KindLabel:
 		return influxdb.LabelsResourceType
 	case KindNotificationEndpoint,
+		KindNotificationEndpointEmail,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointTelegram:
 		return influxdb.NotificationEndpointResourceType
-	case KindNotificationRule:
+	case KindNotificationRule, KindSilence:
 		return influxdb.NotificationRuleResourceType
 	case KindTask:
 		return influxdb.TasksResourceType
@@ -160,6 +178,7 @@ type Diff struct {
 	LabelMappings         []DiffLabelMapping         `json:"labelMappings"`
 	NotificationEndpoints []DiffNotificationEndpoint `json:"notificationEndpoints"`
 	NotificationRules     []DiffNotificationRule     `json:"notificationRules"`
+	Silences              []DiffSilence              `json:"silences"`
 	Tasks                 []DiffTask                 `json:"tasks"`
 	Telegrafs             []DiffTelegraf             `json:"telegrafConfigs"`
 	Variables             []DiffVariable             `json:"variables"`
@@ -402,6 +421,26 @@ type (
 	}
 )
 
+type (
+	// DiffSilence is a diff of an individual silence.
+	DiffSilence struct {
+		DiffIdentifier
+
+		New DiffSilenceValues  `json:"new"`
+		Old *DiffSilenceValues `json:"old"`
+	}
+
+	// DiffSilenceValues are the varying values for a silence.
+	DiffSilenceValues struct {
+		Name        string                      `json:"name"`
+		Description string                      `json:"description"`
+		Start       time.Time                   `json:"start"`
+		End         time.Time                   `json:"end"`
+		Recurrence  *influxdb.SilenceRecurrence `json:"recurrence"`
+		TagRules    []SummaryTagRule            `json:"tagRules"`
+	}
+)
+
 // DiffTelegraf is a diff of an individual telegraf. This resource is always new.
 type DiffTelegraf struct {
 	DiffIdentifier
@@ -443,6 +482,7 @@ type Summary struct {
 	LabelMappings         []SummaryLabelMapping         `json:"labelMappings"`
 	MissingEnvs           []string                      `json:"missingEnvRefs"`
 	MissingSecrets        []string                      `json:"missingSecrets"`
+	Silences              []SummarySilence              `json:"silences"`
 	Tasks                 []SummaryTask                 `json:"summaryTask"`
 	TelegrafConfigs       []SummaryTelegraf             `json:"telegrafConfigs"`
 	Variables             []SummaryVariable             `json:"variables"`
@@ -655,6 +695,19 @@ type SummaryReference struct {
 	DefaultValue interface{} `json:"defaultValue"`
 }
 
+// SummarySilence provides a summary of a silence.
+type SummarySilence struct {
+	SummaryIdentifier
+	ID          SafeID                      `json:"id,omitempty"`
+	OrgID       SafeID                      `json:"orgID,omitempty"`
+	Name        string                      `json:"name"`
+	Description string                      `json:"description"`
+	Start       time.Time                   `json:"start"`
+	End         time.Time                   `json:"end"`
+	Recurrence  *influxdb.SilenceRecurrence `json:"recurrence"`
+	TagRules    []SummaryTagRule            `json:"tagRules"`
+}
+
 // SummaryTask provides a summary of a task.
 type SummaryTask struct {
 	SummaryIdentifier
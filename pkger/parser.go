@@ -352,6 +352,7 @@ type Template struct {
 	mDashboards            map[string]*dashboard
 	mNotificationEndpoints map[string]*notificationEndpoint
 	mNotificationRules     map[string]*notificationRule
+	mSilences              map[string]*silence
 	mTasks                 map[string]*task
 	mTelegrafs             map[string]*telegraf
 	mVariables             map[string]*variable
@@ -415,6 +416,7 @@ func (p *Template) Summary() Summary {
 		Labels:                []SummaryLabel{},
 		MissingEnvs:           p.missingEnvRefs(),
 		MissingSecrets:        p.missingSecrets(),
+		Silences:              []SummarySilence{},
 		Tasks:                 []SummaryTask{},
 		TelegrafConfigs:       []SummaryTelegraf{},
 		Variables:             []SummaryVariable{},
@@ -446,6 +448,10 @@ func (p *Template) Summary() Summary {
 		sum.NotificationRules = append(sum.NotificationRules, r.summarize())
 	}
 
+	for _, s := range p.silences() {
+		sum.Silences = append(sum.Silences, s.summarize())
+	}
+
 	for _, t := range p.tasks() {
 		sum.Tasks = append(sum.Tasks, t.summarize())
 	}
@@ -490,21 +496,28 @@ func (p *Template) Contains(k Kind, pkgName string) bool {
 	case KindBucket:
 		_, ok := p.mBuckets[pkgName]
 		return ok
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAnomaly, KindCheckDeadman, KindCheckRate, KindCheckThreshold:
 		_, ok := p.mChecks[pkgName]
 		return ok
 	case KindLabel:
 		_, ok := p.mLabels[pkgName]
 		return ok
 	case KindNotificationEndpoint,
+		KindNotificationEndpointEmail,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointTelegram:
 		_, ok := p.mNotificationEndpoints[pkgName]
 		return ok
 	case KindNotificationRule:
 		_, ok := p.mNotificationRules[pkgName]
 		return ok
+	case KindSilence:
+		_, ok := p.mSilences[pkgName]
+		return ok
 	case KindTask:
 		_, ok := p.mTasks[pkgName]
 		return ok
@@ -692,6 +705,17 @@ func (p *Template) tasks() []*task {
 	return tasks
 }
 
+func (p *Template) silences() []*silence {
+	silences := make([]*silence, 0, len(p.mSilences))
+	for _, s := range p.mSilences {
+		silences = append(silences, s)
+	}
+
+	sort.Slice(silences, func(i, j int) bool { return silences[i].MetaName() < silences[j].MetaName() })
+
+	return silences
+}
+
 func (p *Template) telegrafs() []*telegraf {
 	teles := make([]*telegraf, 0, len(p.mTelegrafs))
 	for _, t := range p.mTelegrafs {
@@ -777,6 +801,7 @@ func (p *Template) graphResources() error {
 		p.graphDashboards,
 		p.graphNotificationEndpoints,
 		p.graphNotificationRules,
+		p.graphSilences,
 		p.graphTasks,
 		p.graphTelegrafs,
 	}
@@ -868,6 +893,8 @@ func (p *Template) graphChecks() *parseErr {
 	}{
 		{kind: KindCheckThreshold, checkKind: checkKindThreshold},
 		{kind: KindCheckDeadman, checkKind: checkKindDeadman},
+		{kind: KindCheckRate, checkKind: checkKindRate},
+		{kind: KindCheckAnomaly, checkKind: checkKindAnomaly},
 	}
 	var pErr parseErr
 	for _, checkKind := range checkKinds {
@@ -883,6 +910,7 @@ func (p *Template) graphChecks() *parseErr {
 				description:   o.Spec.stringShort(fieldDescription),
 				every:         o.Spec.durationShort(fieldEvery),
 				level:         o.Spec.stringShort(fieldLevel),
+				nonNegative:   o.Spec.boolShort(fieldCheckNonNegative),
 				offset:        o.Spec.durationShort(fieldOffset),
 				query:         strings.TrimSpace(o.Spec.stringShort(fieldQuery)),
 				reportZero:    o.Spec.boolShort(fieldCheckReportZero),
@@ -890,6 +918,8 @@ func (p *Template) graphChecks() *parseErr {
 				status:        normStr(o.Spec.stringShort(fieldStatus)),
 				statusMessage: o.Spec.stringShort(fieldCheckStatusMessageTemplate),
 				timeSince:     o.Spec.durationShort(fieldCheckTimeSince),
+				unit:          o.Spec.durationShort(fieldCheckUnit),
+				window:        o.Spec.durationShort(fieldCheckWindow),
 			}
 			for _, tagRes := range o.Spec.slcResource(fieldCheckTags) {
 				ch.tags = append(ch.tags, struct{ k, v string }{
@@ -898,6 +928,13 @@ func (p *Template) graphChecks() *parseErr {
 				})
 			}
 			for _, th := range o.Spec.slcResource(fieldCheckThresholds) {
+				if ch.kind == checkKindAnomaly {
+					ch.anomalyThresholds = append(ch.anomalyThresholds, anomalyThreshold{
+						level:      strings.TrimSpace(strings.ToUpper(th.stringShort(fieldLevel))),
+						deviations: th.float64Short(fieldCheckDeviations),
+					})
+					continue
+				}
 				ch.thresholds = append(ch.thresholds, threshold{
 					threshType: thresholdType(normStr(th.stringShort(fieldType))),
 					allVals:    th.boolShort(fieldCheckAllValues),
@@ -980,10 +1017,18 @@ func (p *Template) graphNotificationEndpoints() *parseErr {
 		kind             Kind
 		notificationKind notificationEndpointKind
 	}{
+		{
+			kind:             KindNotificationEndpointEmail,
+			notificationKind: notificationKindEmail,
+		},
 		{
 			kind:             KindNotificationEndpointHTTP,
 			notificationKind: notificationKindHTTP,
 		},
+		{
+			kind:             KindNotificationEndpointOpsgenie,
+			notificationKind: notificationKindOpsgenie,
+		},
 		{
 			kind:             KindNotificationEndpointPagerDuty,
 			notificationKind: notificationKindPagerDuty,
@@ -992,6 +1037,14 @@ func (p *Template) graphNotificationEndpoints() *parseErr {
 			kind:             KindNotificationEndpointSlack,
 			notificationKind: notificationKindSlack,
 		},
+		{
+			kind:             KindNotificationEndpointTeams,
+			notificationKind: notificationKindTeams,
+		},
+		{
+			kind:             KindNotificationEndpointTelegram,
+			notificationKind: notificationKindTelegram,
+		},
 	}
 
 	var pErr parseErr
@@ -1005,14 +1058,21 @@ func (p *Template) graphNotificationEndpoints() *parseErr {
 			endpoint := &notificationEndpoint{
 				kind:        nk.notificationKind,
 				identity:    ident,
+				apiKey:      o.Spec.references(fieldNotificationEndpointAPIKey),
+				channel:     o.Spec.stringShort(fieldNotificationEndpointChannel),
 				description: o.Spec.stringShort(fieldDescription),
+				from:        o.Spec.stringShort(fieldNotificationEndpointFrom),
+				host:        o.Spec.stringShort(fieldNotificationEndpointHost),
 				method:      strings.TrimSpace(strings.ToUpper(o.Spec.stringShort(fieldNotificationEndpointHTTPMethod))),
 				httpType:    normStr(o.Spec.stringShort(fieldType)),
 				password:    o.Spec.references(fieldNotificationEndpointPassword),
+				port:        o.Spec.intShort(fieldNotificationEndpointPort),
 				routingKey:  o.Spec.references(fieldNotificationEndpointRoutingKey),
 				status:      normStr(o.Spec.stringShort(fieldStatus)),
+				tlsMode:     normStr(o.Spec.stringShort(fieldNotificationEndpointTLSMode)),
 				token:       o.Spec.references(fieldNotificationEndpointToken),
 				url:         o.Spec.stringShort(fieldNotificationEndpointURL),
+				urlRef:      o.Spec.references(fieldNotificationEndpointURL),
 				username:    o.Spec.references(fieldNotificationEndpointUsername),
 			}
 			failures := p.parseNestedLabels(o.Spec, func(l *label) error {
@@ -1025,9 +1085,11 @@ func (p *Template) graphNotificationEndpoints() *parseErr {
 			p.setRefs(
 				endpoint.name,
 				endpoint.displayName,
+				endpoint.apiKey,
 				endpoint.password,
 				endpoint.routingKey,
 				endpoint.token,
+				endpoint.urlRef,
 				endpoint.username,
 			)
 
@@ -1054,14 +1116,26 @@ func (p *Template) graphNotificationRules() *parseErr {
 		}
 
 		rule := &notificationRule{
-			identity:     ident,
-			endpointName: p.getRefWithKnownEnvs(o.Spec, fieldNotificationRuleEndpointName),
-			description:  o.Spec.stringShort(fieldDescription),
-			channel:      o.Spec.stringShort(fieldNotificationRuleChannel),
-			every:        o.Spec.durationShort(fieldEvery),
-			msgTemplate:  o.Spec.stringShort(fieldNotificationRuleMessageTemplate),
-			offset:       o.Spec.durationShort(fieldOffset),
-			status:       normStr(o.Spec.stringShort(fieldStatus)),
+			identity:       ident,
+			endpointName:   p.getRefWithKnownEnvs(o.Spec, fieldNotificationRuleEndpointName),
+			description:    o.Spec.stringShort(fieldDescription),
+			channel:        o.Spec.stringShort(fieldNotificationRuleChannel),
+			disablePreview: o.Spec.boolShort(fieldNotificationRuleDisablePreview),
+			every:          o.Spec.durationShort(fieldEvery),
+			msgTemplate:    o.Spec.stringShort(fieldNotificationRuleMessageTemplate),
+			offset:         o.Spec.durationShort(fieldOffset),
+			parseMode:      o.Spec.stringShort(fieldNotificationRuleParseMode),
+			status:         normStr(o.Spec.stringShort(fieldStatus)),
+			subject:        o.Spec.stringShort(fieldNotificationRuleSubjectTemplate),
+			title:          o.Spec.stringShort(fieldNotificationRuleTitleTemplate),
+			to:             o.Spec.slcStr(fieldNotificationRuleTo),
+		}
+
+		for _, p := range o.Spec.slcResource(fieldNotificationRulePriorities) {
+			rule.priorities = append(rule.priorities, struct{ lvl, priority string }{
+				lvl:      strings.TrimSpace(strings.ToUpper(p.stringShort(fieldLevel))),
+				priority: strings.TrimSpace(strings.ToUpper(p.stringShort(fieldNotificationRulePriority))),
+			})
 		}
 
 		for _, sRule := range o.Spec.slcResource(fieldNotificationRuleStatusRules) {
@@ -1094,6 +1168,43 @@ func (p *Template) graphNotificationRules() *parseErr {
 	})
 }
 
+func (p *Template) graphSilences() *parseErr {
+	p.mSilences = make(map[string]*silence)
+	tracker := p.trackNames(false)
+	return p.eachResource(KindSilence, func(o Object) []validationErr {
+		ident, errs := tracker(o)
+		if len(errs) > 0 {
+			return errs
+		}
+
+		s := &silence{
+			identity:    ident,
+			description: o.Spec.stringShort(fieldDescription),
+			start:       o.Spec.timeShort(fieldSilenceStart),
+			end:         o.Spec.timeShort(fieldSilenceEnd),
+		}
+
+		if recur, ok := ifaceToResource(o.Spec[fieldSilenceRecurrence]); ok {
+			s.recurEvery = normStr(recur.stringShort(fieldEvery))
+			if until, ok := recur.time(fieldSilenceUntil); ok {
+				s.recurUntil = &until
+			}
+		}
+
+		for _, tRule := range o.Spec.slcResource(fieldSilenceTagRules) {
+			s.tagRules = append(s.tagRules, struct{ k, v, op string }{
+				k:  tRule.stringShort(fieldKey),
+				v:  tRule.stringShort(fieldValue),
+				op: normStr(tRule.stringShort(fieldOperator)),
+			})
+		}
+
+		p.mSilences[s.MetaName()] = s
+		p.setRefs(s.name, s.displayName)
+		return s.valid()
+	})
+}
+
 func (p *Template) graphTasks() *parseErr {
 	p.mTasks = make(map[string]*task)
 	tracker := p.trackNames(false)
@@ -1732,6 +1843,19 @@ func (r Resource) durationShort(key string) time.Duration {
 	return dur
 }
 
+func (r Resource) time(key string) (time.Time, bool) {
+	if t, ok := r[key].(time.Time); ok {
+		return t, true
+	}
+	t, err := time.Parse(time.RFC3339, r.stringShort(key))
+	return t, err == nil
+}
+
+func (r Resource) timeShort(key string) time.Time {
+	t, _ := r.time(key)
+	return t
+}
+
 func (r Resource) float64(key string) (float64, bool) {
 	f, ok := r[key].(float64)
 	if ok {
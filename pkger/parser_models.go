@@ -2,6 +2,7 @@ package pkger
 
 import (
 	"fmt"
+	"net/mail"
 	"net/url"
 	"regexp"
 	"sort"
@@ -191,16 +192,22 @@ type checkKind int
 const (
 	checkKindDeadman checkKind = iota + 1
 	checkKindThreshold
+	checkKindRate
+	checkKindAnomaly
 )
 
 const (
 	fieldCheckAllValues             = "allValues"
+	fieldCheckDeviations            = "deviations"
+	fieldCheckNonNegative           = "nonNegative"
 	fieldCheckReportZero            = "reportZero"
 	fieldCheckStaleTime             = "staleTime"
 	fieldCheckStatusMessageTemplate = "statusMessageTemplate"
 	fieldCheckTags                  = "tags"
 	fieldCheckThresholds            = "thresholds"
 	fieldCheckTimeSince             = "timeSince"
+	fieldCheckUnit                  = "unit"
+	fieldCheckWindow                = "window"
 )
 
 const checkNameMinLength = 1
@@ -208,19 +215,23 @@ const checkNameMinLength = 1
 type check struct {
 	identity
 
-	kind          checkKind
-	description   string
-	every         time.Duration
-	level         string
-	offset        time.Duration
-	query         string
-	reportZero    bool
-	staleTime     time.Duration
-	status        string
-	statusMessage string
-	tags          []struct{ k, v string }
-	timeSince     time.Duration
-	thresholds    []threshold
+	kind              checkKind
+	description       string
+	every             time.Duration
+	level             string
+	nonNegative       bool
+	offset            time.Duration
+	query             string
+	reportZero        bool
+	staleTime         time.Duration
+	status            string
+	statusMessage     string
+	tags              []struct{ k, v string }
+	timeSince         time.Duration
+	thresholds        []threshold
+	anomalyThresholds []anomalyThreshold
+	unit              time.Duration
+	window            time.Duration
 
 	labels sortedLabels
 }
@@ -278,6 +289,24 @@ func (c *check) summarize() SummaryCheck {
 			StaleTime:  toNotificationDuration(c.staleTime),
 			TimeSince:  toNotificationDuration(c.timeSince),
 		}
+	case checkKindRate:
+		sum.Kind = KindCheckRate
+		rate := &icheck.Rate{
+			Base:        base,
+			NonNegative: c.nonNegative,
+			Thresholds:  toInfluxThresholds(c.thresholds...),
+		}
+		if c.unit > 0 {
+			rate.Unit = toNotificationDuration(c.unit)
+		}
+		sum.Check = rate
+	case checkKindAnomaly:
+		sum.Kind = KindCheckAnomaly
+		sum.Check = &icheck.Anomaly{
+			Base:       base,
+			Window:     toNotificationDuration(c.window),
+			Thresholds: toInfluxAnomalyThresholds(c.anomalyThresholds...),
+		}
 	}
 	return sum
 }
@@ -313,7 +342,7 @@ func (c *check) valid() []validationErr {
 	}
 
 	switch c.kind {
-	case checkKindThreshold:
+	case checkKindThreshold, checkKindRate:
 		if len(c.thresholds) == 0 {
 			vErrs = append(vErrs, validationErr{
 				Field: fieldCheckThresholds,
@@ -326,6 +355,25 @@ func (c *check) valid() []validationErr {
 				vErrs = append(vErrs, fail)
 			}
 		}
+	case checkKindAnomaly:
+		if c.window < 2*c.every {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckWindow,
+				Msg:   "duration value must span at least 2 intervals of every",
+			})
+		}
+		if len(c.anomalyThresholds) == 0 {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckThresholds,
+				Msg:   "must provide at least 1 threshold entry",
+			})
+		}
+		for i, th := range c.anomalyThresholds {
+			for _, fail := range th.valid() {
+				fail.Index = intPtr(i)
+				vErrs = append(vErrs, fail)
+			}
+		}
 	}
 
 	if len(vErrs) > 0 {
@@ -414,6 +462,39 @@ func toInfluxThresholds(thresholds ...threshold) []icheck.ThresholdConfig {
 	return iThresh
 }
 
+type anomalyThreshold struct {
+	level      string
+	deviations float64
+}
+
+func (t anomalyThreshold) valid() []validationErr {
+	var vErrs []validationErr
+	if notification.ParseCheckLevel(t.level) == notification.Unknown {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldLevel,
+			Msg:   fmt.Sprintf("must be 1 in [CRIT, WARN, INFO, OK]; got=%q", t.level),
+		})
+	}
+	if t.deviations <= 0 {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldCheckDeviations,
+			Msg:   "must be greater than 0",
+		})
+	}
+	return vErrs
+}
+
+func toInfluxAnomalyThresholds(thresholds ...anomalyThreshold) []icheck.AnomalyThreshold {
+	var iThresh []icheck.AnomalyThreshold
+	for _, th := range thresholds {
+		iThresh = append(iThresh, icheck.AnomalyThreshold{
+			Level:      notification.ParseCheckLevel(th.level),
+			Deviations: th.deviations,
+		})
+	}
+	return iThresh
+}
+
 // chartKind identifies what kind of chart is eluded too. Each
 // chart kind has their own requirements for what constitutes
 // a chart.
@@ -1339,14 +1420,22 @@ const (
 	notificationKindHTTP notificationEndpointKind = iota + 1
 	notificationKindPagerDuty
 	notificationKindSlack
+	notificationKindEmail
+	notificationKindTeams
+	notificationKindOpsgenie
+	notificationKindTelegram
 )
 
 func (n notificationEndpointKind) String() string {
-	if n > 0 && n < 4 {
+	if n > 0 && n < 8 {
 		return [...]string{
 			endpoint.HTTPType,
 			endpoint.PagerDutyType,
 			endpoint.SlackType,
+			endpoint.EmailType,
+			endpoint.TeamsType,
+			endpoint.OpsgenieType,
+			endpoint.TelegramType,
 		}[n-1]
 	}
 	return ""
@@ -1359,9 +1448,15 @@ const (
 )
 
 const (
+	fieldNotificationEndpointAPIKey     = "apiKey"
+	fieldNotificationEndpointChannel    = "channel"
+	fieldNotificationEndpointFrom       = "from"
+	fieldNotificationEndpointHost       = "host"
 	fieldNotificationEndpointHTTPMethod = "method"
 	fieldNotificationEndpointPassword   = "password"
+	fieldNotificationEndpointPort       = "port"
 	fieldNotificationEndpointRoutingKey = "routingKey"
+	fieldNotificationEndpointTLSMode    = "tlsMode"
 	fieldNotificationEndpointToken      = "token"
 	fieldNotificationEndpointURL        = "url"
 	fieldNotificationEndpointUsername   = "username"
@@ -1371,14 +1466,21 @@ type notificationEndpoint struct {
 	identity
 
 	kind        notificationEndpointKind
+	apiKey      *references
+	channel     string
 	description string
+	from        string
+	host        string
 	method      string
 	password    *references
+	port        int
 	routingKey  *references
 	status      string
+	tlsMode     string
 	token       *references
 	httpType    string
 	url         string
+	urlRef      *references
 	username    *references
 
 	labels sortedLabels
@@ -1411,6 +1513,17 @@ func (n *notificationEndpoint) summarize() SummaryNotificationEndpoint {
 	}
 
 	switch n.kind {
+	case notificationKindEmail:
+		sum.Kind = KindNotificationEndpointEmail
+		sum.NotificationEndpoint = &endpoint.Email{
+			Base:     base,
+			Host:     n.host,
+			Port:     n.port,
+			TLSMode:  n.tlsMode,
+			From:     n.from,
+			Username: n.username.SecretField(),
+			Password: n.password.SecretField(),
+		}
 	case notificationKindHTTP:
 		sum.Kind = KindNotificationEndpointHTTP
 		e := &endpoint.HTTP{
@@ -1430,6 +1543,13 @@ func (n *notificationEndpoint) summarize() SummaryNotificationEndpoint {
 			e.AuthMethod = notificationHTTPAuthTypeNone
 		}
 		sum.NotificationEndpoint = e
+	case notificationKindOpsgenie:
+		sum.Kind = KindNotificationEndpointOpsgenie
+		sum.NotificationEndpoint = &endpoint.Opsgenie{
+			Base:   base,
+			URL:    n.url,
+			APIKey: n.apiKey.SecretField(),
+		}
 	case notificationKindPagerDuty:
 		sum.Kind = KindNotificationEndpointPagerDuty
 		sum.NotificationEndpoint = &endpoint.PagerDuty{
@@ -1444,6 +1564,19 @@ func (n *notificationEndpoint) summarize() SummaryNotificationEndpoint {
 			URL:   n.url,
 			Token: n.token.SecretField(),
 		}
+	case notificationKindTeams:
+		sum.Kind = KindNotificationEndpointTeams
+		sum.NotificationEndpoint = &endpoint.Teams{
+			Base: base,
+			URL:  n.urlRef.SecretField(),
+		}
+	case notificationKindTelegram:
+		sum.Kind = KindNotificationEndpointTelegram
+		sum.NotificationEndpoint = &endpoint.Telegram{
+			Base:    base,
+			Token:   n.token.SecretField(),
+			Channel: n.channel,
+		}
 	}
 	return sum
 }
@@ -1472,7 +1605,15 @@ func (n *notificationEndpoint) valid() []validationErr {
 		failures = append(failures, err)
 	}
 
-	if _, err := url.Parse(n.url); err != nil || n.url == "" {
+	// the url of email and telegram endpoints is unused, the one of opsgenie
+	// endpoints is optional, and the one of teams endpoints is a secret.
+	var urlRequired bool
+	switch n.kind {
+	case notificationKindEmail, notificationKindOpsgenie, notificationKindTeams, notificationKindTelegram:
+	default:
+		urlRequired = true
+	}
+	if _, err := url.Parse(n.url); err != nil || (urlRequired && n.url == "") {
 		failures = append(failures, validationErr{
 			Field: fieldNotificationEndpointURL,
 			Msg:   "must be valid url",
@@ -1488,6 +1629,70 @@ func (n *notificationEndpoint) valid() []validationErr {
 	}
 
 	switch n.kind {
+	case notificationKindEmail:
+		if n.host == "" {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointHost,
+				Msg:   "must provide non empty string",
+			})
+		}
+		if n.port <= 0 || n.port > 65535 {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPort,
+				Msg:   "must be a valid port",
+			})
+		}
+		if n.tlsMode != endpoint.EmailTLSNone && n.tlsMode != endpoint.EmailTLSStartTLS && n.tlsMode != endpoint.EmailTLS {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointTLSMode,
+				Msg: fmt.Sprintf(
+					"invalid tls mode provided %q; valid tls mode is 1 in [%s, %s, %s]",
+					n.tlsMode,
+					endpoint.EmailTLSNone,
+					endpoint.EmailTLSStartTLS,
+					endpoint.EmailTLS,
+				),
+			})
+		}
+		if _, err := mail.ParseAddress(n.from); err != nil {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointFrom,
+				Msg:   "must be a valid email address",
+			})
+		}
+		if n.username.hasValue() != n.password.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPassword,
+				Msg:   "username and password must be provided together",
+			})
+		}
+	case notificationKindOpsgenie:
+		if !n.apiKey.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointAPIKey,
+				Msg:   "must be provided",
+			})
+		}
+	case notificationKindTeams:
+		if !n.urlRef.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointURL,
+				Msg:   "must be provided",
+			})
+		}
+	case notificationKindTelegram:
+		if !n.token.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointToken,
+				Msg:   "must be provided",
+			})
+		}
+		if n.channel == "" {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointChannel,
+				Msg:   "must provide non empty string",
+			})
+		}
 	case notificationKindPagerDuty:
 		if !n.routingKey.hasValue() {
 			failures = append(failures, validationErr{
@@ -1551,24 +1756,37 @@ func (n *notificationEndpoint) valid() []validationErr {
 const (
 	fieldNotificationRuleChannel         = "channel"
 	fieldNotificationRuleCurrentLevel    = "currentLevel"
+	fieldNotificationRuleDisablePreview  = "disableWebPagePreview"
 	fieldNotificationRuleEndpointName    = "endpointName"
 	fieldNotificationRuleMessageTemplate = "messageTemplate"
+	fieldNotificationRuleParseMode       = "parseMode"
 	fieldNotificationRulePreviousLevel   = "previousLevel"
+	fieldNotificationRulePriorities      = "priorities"
+	fieldNotificationRulePriority        = "priority"
 	fieldNotificationRuleStatusRules     = "statusRules"
+	fieldNotificationRuleSubjectTemplate = "subjectTemplate"
 	fieldNotificationRuleTagRules        = "tagRules"
+	fieldNotificationRuleTitleTemplate   = "titleTemplate"
+	fieldNotificationRuleTo              = "to"
 )
 
 type notificationRule struct {
 	identity
 
-	channel     string
-	description string
-	every       time.Duration
-	msgTemplate string
-	offset      time.Duration
-	status      string
-	statusRules []struct{ curLvl, prevLvl string }
-	tagRules    []struct{ k, v, op string }
+	channel        string
+	description    string
+	disablePreview bool
+	every          time.Duration
+	msgTemplate    string
+	offset         time.Duration
+	parseMode      string
+	priorities     []struct{ lvl, priority string }
+	status         string
+	statusRules    []struct{ curLvl, prevLvl string }
+	subject        string
+	tagRules       []struct{ k, v, op string }
+	title          string
+	to             []string
 
 	associatedEndpoint *notificationEndpoint
 	endpointName       *references
@@ -1659,8 +1877,31 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 	}
 
 	switch r.associatedEndpoint.kind {
+	case notificationKindEmail:
+		return &rule.Email{
+			Base:            base,
+			To:              r.to,
+			SubjectTemplate: r.subject,
+			BodyTemplate:    r.msgTemplate,
+		}
 	case notificationKindHTTP:
-		return &rule.HTTP{Base: base}
+		return &rule.HTTP{
+			Base:         base,
+			BodyTemplate: r.msgTemplate,
+		}
+	case notificationKindOpsgenie:
+		var priorities []rule.OpsgeniePriority
+		for _, p := range r.priorities {
+			priorities = append(priorities, rule.OpsgeniePriority{
+				Level:    notification.ParseCheckLevel(p.lvl),
+				Priority: p.priority,
+			})
+		}
+		return &rule.Opsgenie{
+			Base:            base,
+			MessageTemplate: r.msgTemplate,
+			Priorities:      priorities,
+		}
 	case notificationKindPagerDuty:
 		return &rule.PagerDuty{
 			Base:            base,
@@ -1672,6 +1913,19 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 			Channel:         r.channel,
 			MessageTemplate: r.msgTemplate,
 		}
+	case notificationKindTeams:
+		return &rule.Teams{
+			Base:            base,
+			TitleTemplate:   r.title,
+			MessageTemplate: r.msgTemplate,
+		}
+	case notificationKindTelegram:
+		return &rule.Telegram{
+			Base:                  base,
+			MessageTemplate:       r.msgTemplate,
+			ParseMode:             r.parseMode,
+			DisableWebPagePreview: r.disablePreview,
+		}
 	}
 	return nil
 }
@@ -1754,6 +2008,34 @@ func (r *notificationRule) valid() []validationErr {
 		})
 	}
 
+	var priorityErrs []validationErr
+	for i, p := range r.priorities {
+		switch notification.ParseCheckLevel(p.lvl) {
+		case notification.Critical, notification.Warn, notification.Info, notification.Ok:
+		default:
+			priorityErrs = append(priorityErrs, validationErr{
+				Field: fieldLevel,
+				Msg:   fmt.Sprintf("must be 1 in [CRIT, WARN, INFO, OK]; got=%q", p.lvl),
+				Index: intPtr(i),
+			})
+		}
+		switch p.priority {
+		case "P1", "P2", "P3", "P4", "P5":
+		default:
+			priorityErrs = append(priorityErrs, validationErr{
+				Field: fieldNotificationRulePriority,
+				Msg:   fmt.Sprintf("must be 1 in [P1, P2, P3, P4, P5]; got=%q", p.priority),
+				Index: intPtr(i),
+			})
+		}
+	}
+	if len(priorityErrs) > 0 {
+		vErrs = append(vErrs, validationErr{
+			Field:  fieldNotificationRulePriorities,
+			Nested: priorityErrs,
+		})
+	}
+
 	if len(vErrs) > 0 {
 		return []validationErr{
 			objectValidationErr(fieldSpec, vErrs...),
@@ -1803,6 +2085,153 @@ func toSummaryTagRules(tagRules []struct{ k, v, op string }) []SummaryTagRule {
 	return out
 }
 
+const (
+	fieldSilenceEnd        = "end"
+	fieldSilenceRecurrence = "recurrence"
+	fieldSilenceStart      = "start"
+	fieldSilenceTagRules   = "tagRules"
+	fieldSilenceUntil      = "until"
+)
+
+type silence struct {
+	identity
+
+	description string
+	start       time.Time
+	end         time.Time
+	recurEvery  string
+	recurUntil  *time.Time
+	tagRules    []struct{ k, v, op string }
+}
+
+func (s *silence) ResourceType() influxdb.ResourceType {
+	return KindSilence.ResourceType()
+}
+
+func (s *silence) recurrence() *influxdb.SilenceRecurrence {
+	if s.recurEvery == "" {
+		return nil
+	}
+	return &influxdb.SilenceRecurrence{
+		Every: s.recurEvery,
+		Until: s.recurUntil,
+	}
+}
+
+func (s *silence) summarize() SummarySilence {
+	return SummarySilence{
+		SummaryIdentifier: SummaryIdentifier{
+			Kind:          KindSilence,
+			MetaName:      s.MetaName(),
+			EnvReferences: s.identity.summarizeReferences(),
+		},
+		Name:        s.Name(),
+		Description: s.description,
+		Start:       s.start,
+		End:         s.end,
+		Recurrence:  s.recurrence(),
+		TagRules:    toSummaryTagRules(s.tagRules),
+	}
+}
+
+func (s *silence) toInfluxSilence() influxdb.Silence {
+	sl := influxdb.Silence{
+		Name:        s.Name(),
+		Description: s.description,
+		Start:       s.start,
+		End:         s.end,
+		Recurrence:  s.recurrence(),
+	}
+	for _, tr := range s.tagRules {
+		op, _ := influxdb.ToOperator(tr.op)
+		sl.TagRules = append(sl.TagRules, influxdb.TagRule{
+			Tag: influxdb.Tag{
+				Key:   tr.k,
+				Value: tr.v,
+			},
+			Operator: op,
+		})
+	}
+	return sl
+}
+
+func (s *silence) valid() []validationErr {
+	var vErrs []validationErr
+	if err, ok := isValidName(s.Name(), 1); !ok {
+		vErrs = append(vErrs, err)
+	}
+
+	if s.start.IsZero() {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldSilenceStart,
+			Msg:   "must be provided as an RFC3339 time",
+		})
+	}
+	if s.end.IsZero() {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldSilenceEnd,
+			Msg:   "must be provided as an RFC3339 time",
+		})
+	} else if !s.end.After(s.start) {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldSilenceEnd,
+			Msg:   "must be after start",
+		})
+	}
+
+	if recur := s.recurrence(); recur != nil {
+		var recurErrs []validationErr
+		if period := recur.Period(); period == 0 {
+			recurErrs = append(recurErrs, validationErr{
+				Field: fieldEvery,
+				Msg:   fmt.Sprintf("must be 1 in [daily, weekly]; got=%q", recur.Every),
+			})
+		} else if s.end.Sub(s.start) >= period {
+			recurErrs = append(recurErrs, validationErr{
+				Field: fieldEvery,
+				Msg:   "must be longer than the window of the silence",
+			})
+		}
+		if recur.Until != nil && !recur.Until.After(s.start) {
+			recurErrs = append(recurErrs, validationErr{
+				Field: fieldSilenceUntil,
+				Msg:   "must be after start",
+			})
+		}
+		if len(recurErrs) > 0 {
+			vErrs = append(vErrs, validationErr{
+				Field:  fieldSilenceRecurrence,
+				Nested: recurErrs,
+			})
+		}
+	}
+
+	var tagErrs []validationErr
+	for i, tRule := range s.tagRules {
+		if _, ok := influxdb.ToOperator(tRule.op); !ok {
+			tagErrs = append(tagErrs, validationErr{
+				Field: fieldOperator,
+				Msg:   fmt.Sprintf("must be 1 in [equal, notequal, equalregex, notequalregex]; got=%q", tRule.op),
+				Index: intPtr(i),
+			})
+		}
+	}
+	if len(tagErrs) > 0 {
+		vErrs = append(vErrs, validationErr{
+			Field:  fieldSilenceTagRules,
+			Nested: tagErrs,
+		})
+	}
+
+	if len(vErrs) > 0 {
+		return []validationErr{
+			objectValidationErr(fieldSpec, vErrs...),
+		}
+	}
+
+	return nil
+}
+
 const (
 	fieldTaskCron = "cron"
 )
@@ -17,6 +17,7 @@ import (
 	"github.com/influxdata/influxdb/v2/notification"
 	icheck "github.com/influxdata/influxdb/v2/notification/check"
 	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -578,6 +579,38 @@ spec:
 			})
 		})
 
+		t.Run("rate and anomaly checks", func(t *testing.T) {
+			testfileRunner(t, "testdata/checks_rate_anomaly.yml", func(t *testing.T, template *Template) {
+				sum := template.Summary()
+				require.Len(t, sum.Checks, 2)
+
+				check1 := sum.Checks[0]
+				assert.Equal(t, KindCheckRate, check1.Kind)
+				rateCheck, ok := check1.Check.(*icheck.Rate)
+				require.Truef(t, ok, "got: %#v", check1)
+				assert.Equal(t, mustDuration(t, time.Minute), rateCheck.Unit)
+				assert.True(t, rateCheck.NonNegative)
+				expectedThresholds := []icheck.ThresholdConfig{
+					icheck.Greater{
+						ThresholdConfigBase: icheck.ThresholdConfigBase{Level: notification.Critical},
+						Value:               1000.0,
+					},
+				}
+				assert.Equal(t, expectedThresholds, rateCheck.Thresholds)
+
+				check2 := sum.Checks[1]
+				assert.Equal(t, KindCheckAnomaly, check2.Kind)
+				anomalyCheck, ok := check2.Check.(*icheck.Anomaly)
+				require.Truef(t, ok, "got: %#v", check2)
+				assert.Equal(t, mustDuration(t, 6*time.Hour), anomalyCheck.Window)
+				expectedAnomalyThresholds := []icheck.AnomalyThreshold{
+					{Level: notification.Warn, Deviations: 2.0},
+					{Level: notification.Critical, Deviations: 3.5},
+				}
+				assert.Equal(t, expectedAnomalyThresholds, anomalyCheck.Thresholds)
+			})
+		})
+
 		t.Run("with env refs should be successful", func(t *testing.T) {
 			testfileRunner(t, "testdata/checks_ref.yml", func(t *testing.T, template *Template) {
 				actual := template.Summary().Checks
@@ -2730,6 +2763,111 @@ spec:
 			})
 		})
 
+		t.Run("with email endpoint should be successful", func(t *testing.T) {
+			testfileRunner(t, "testdata/notification_endpoint_email.yml", func(t *testing.T, template *Template) {
+				endpoints := template.Summary().NotificationEndpoints
+				require.Len(t, endpoints, 1)
+
+				assert.Equal(t, KindNotificationEndpointEmail, endpoints[0].Kind)
+				assert.Equal(t, "email-notification-endpoint", endpoints[0].MetaName)
+				expected := &endpoint.Email{
+					Base: endpoint.Base{
+						Name:        "email name",
+						Description: "email desc",
+						Status:      influxdb.TaskStatusActive,
+					},
+					Host:     "smtp.example.com",
+					Port:     587,
+					TLSMode:  endpoint.EmailTLSStartTLS,
+					From:     "alerts@example.com",
+					Username: influxdb.SecretField{Value: strPtr("secret username")},
+					Password: influxdb.SecretField{Value: strPtr("secret password")},
+				}
+				assert.Equal(t, expected, endpoints[0].NotificationEndpoint)
+			})
+		})
+
+		t.Run("with teams and opsgenie endpoints should be successful", func(t *testing.T) {
+			testfileRunner(t, "testdata/notification_endpoint_teams_opsgenie.yml", func(t *testing.T, template *Template) {
+				endpoints := template.Summary().NotificationEndpoints
+				require.Len(t, endpoints, 2)
+
+				assert.Equal(t, KindNotificationEndpointTeams, endpoints[0].Kind)
+				assert.Equal(t, "teams-notification-endpoint", endpoints[0].MetaName)
+				expectedTeams := &endpoint.Teams{
+					Base: endpoint.Base{
+						Name:        "teams name",
+						Description: "teams desc",
+						Status:      influxdb.TaskStatusActive,
+					},
+					URL: influxdb.SecretField{Key: "teams-url"},
+				}
+				assert.Equal(t, expectedTeams, endpoints[0].NotificationEndpoint)
+
+				assert.Equal(t, KindNotificationEndpointOpsgenie, endpoints[1].Kind)
+				assert.Equal(t, "opsgenie-notification-endpoint", endpoints[1].MetaName)
+				expectedOpsgenie := &endpoint.Opsgenie{
+					Base: endpoint.Base{
+						Name:        "opsgenie name",
+						Description: "opsgenie desc",
+						Status:      influxdb.TaskStatusActive,
+					},
+					URL:    "http://localhost:7777/v2/alerts",
+					APIKey: influxdb.SecretField{Key: "opsgenie-api-key"},
+				}
+				assert.Equal(t, expectedOpsgenie, endpoints[1].NotificationEndpoint)
+
+				rules := template.notificationRules()
+				require.Len(t, rules, 2)
+
+				opsgenieRule, ok := rules[0].toInfluxRule().(*rule.Opsgenie)
+				require.True(t, ok)
+				assert.Equal(t, "${ r._message }", opsgenieRule.MessageTemplate)
+				expectedPriorities := []rule.OpsgeniePriority{
+					{Level: notification.Critical, Priority: "P2"},
+					{Level: notification.Warn, Priority: "P4"},
+				}
+				assert.Equal(t, expectedPriorities, opsgenieRule.Priorities)
+
+				teamsRule, ok := rules[1].toInfluxRule().(*rule.Teams)
+				require.True(t, ok)
+				assert.Equal(t, "${ r._check_name } is ${ r._level }", teamsRule.TitleTemplate)
+				assert.Equal(t, "${ r._message }", teamsRule.MessageTemplate)
+			})
+		})
+
+		t.Run("with telegram endpoint should be successful", func(t *testing.T) {
+			testfileRunner(t, "testdata/notification_endpoint_telegram.yml", func(t *testing.T, template *Template) {
+				sum := template.Summary()
+				endpoints := sum.NotificationEndpoints
+				require.Len(t, endpoints, 1)
+
+				assert.Equal(t, KindNotificationEndpointTelegram, endpoints[0].Kind)
+				assert.Equal(t, "telegram-notification-endpoint", endpoints[0].MetaName)
+				expected := &endpoint.Telegram{
+					Base: endpoint.Base{
+						Name:        "telegram name",
+						Description: "telegram desc",
+						Status:      influxdb.TaskStatusActive,
+					},
+					Token:   influxdb.SecretField{Key: "telegram-token"},
+					Channel: "-12345",
+				}
+				assert.Equal(t, expected, endpoints[0].NotificationEndpoint)
+
+				require.Len(t, sum.NotificationRules, 1)
+				assert.Equal(t, endpoint.TelegramType, sum.NotificationRules[0].EndpointType)
+
+				rules := template.notificationRules()
+				require.Len(t, rules, 1)
+				telegramRule, ok := rules[0].toInfluxRule().(*rule.Telegram)
+				require.True(t, ok)
+				assert.Equal(t, "${ r._message }", telegramRule.MessageTemplate)
+				assert.Equal(t, "HTML", telegramRule.ParseMode)
+				assert.True(t, telegramRule.DisableWebPagePreview)
+			})
+		})
+
 		t.Run("with env refs should be valid", func(t *testing.T) {
 			testfileRunner(t, "testdata/notification_endpoint_ref.yml", func(t *testing.T, template *Template) {
 				actual := template.Summary().NotificationEndpoints
@@ -2942,6 +3080,119 @@ spec:
   description: http none auth desc
   method: get
   url:  https://www.example.com/endpoint/noneauth
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointEmail,
+					resErr: testTemplateResourceError{
+						name:           "missing email host",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointHost},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointEmail
+metadata:
+  name: email-notification-endpoint
+spec:
+  port: 587
+  tlsMode: starttls
+  from: alerts@example.com
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointEmail,
+					resErr: testTemplateResourceError{
+						name:           "invalid email tls mode",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointTLSMode},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointEmail
+metadata:
+  name: email-notification-endpoint
+spec:
+  host: smtp.example.com
+  port: 587
+  tlsMode: ssl
+  from: alerts@example.com
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointEmail,
+					resErr: testTemplateResourceError{
+						name:           "missing email username",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointPassword},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointEmail
+metadata:
+  name: email-notification-endpoint
+spec:
+  host: smtp.example.com
+  port: 587
+  tlsMode: starttls
+  from: alerts@example.com
+  password: secret password
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointTeams,
+					resErr: testTemplateResourceError{
+						name:           "missing teams url",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointURL},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointTeams
+metadata:
+  name: teams-notification-endpoint
+spec:
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointOpsgenie,
+					resErr: testTemplateResourceError{
+						name:           "missing opsgenie api key",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointAPIKey},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointOpsgenie
+metadata:
+  name: opsgenie-notification-endpoint
+spec:
+  url: http://localhost:7777/v2/alerts
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointTelegram,
+					resErr: testTemplateResourceError{
+						name:           "missing telegram token",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointToken},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointTelegram
+metadata:
+  name: telegram-notification-endpoint
+spec:
+  channel: "-12345"
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointTelegram,
+					resErr: testTemplateResourceError{
+						name:           "missing telegram channel",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointChannel},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointTelegram
+metadata:
+  name: telegram-notification-endpoint
+spec:
+  token: secret token
 `,
 					},
 				},
@@ -3352,6 +3603,115 @@ spec:
 		})
 	})
 
+	t.Run("template with silences", func(t *testing.T) {
+		t.Run("should be successful", func(t *testing.T) {
+			testfileRunner(t, "testdata/silence.yml", func(t *testing.T, template *Template) {
+				sum := template.Summary()
+				require.Len(t, sum.Silences, 2)
+
+				until := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+				expected := SummarySilence{
+					SummaryIdentifier: SummaryIdentifier{
+						Kind:          KindSilence,
+						MetaName:      "migration",
+						EnvReferences: []SummaryReference{},
+					},
+					Name:     "migration",
+					Start:    time.Date(2020, 2, 1, 10, 0, 0, 0, time.UTC),
+					End:      time.Date(2020, 2, 1, 12, 0, 0, 0, time.UTC),
+					TagRules: []SummaryTagRule{},
+				}
+				assert.Equal(t, expected, sum.Silences[0])
+
+				expected = SummarySilence{
+					SummaryIdentifier: SummaryIdentifier{
+						Kind:          KindSilence,
+						MetaName:      "nightly-maintenance",
+						EnvReferences: []SummaryReference{},
+					},
+					Name:        "nightly maintenance",
+					Description: "db maintenance",
+					Start:       time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC),
+					End:         time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+					Recurrence: &influxdb.SilenceRecurrence{
+						Every: influxdb.SilenceRecurDaily,
+						Until: &until,
+					},
+					TagRules: []SummaryTagRule{
+						{Key: "host", Value: "db1", Operator: "equal"},
+						{Key: "region", Value: "^us-", Operator: "equalregex"},
+					},
+				}
+				assert.Equal(t, expected, sum.Silences[1])
+			})
+		})
+
+		t.Run("handles bad config", func(t *testing.T) {
+			tests := []testTemplateResourceError{
+				{
+					name:           "missing window",
+					validationErrs: 1,
+					valFields:      []string{fieldSpec, fieldSilenceStart, fieldSilenceEnd},
+					templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: Silence
+metadata:
+  name: silence-0
+spec:
+`,
+				},
+				{
+					name:           "end before start",
+					validationErrs: 1,
+					valFields:      []string{fieldSpec, fieldSilenceEnd},
+					templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: Silence
+metadata:
+  name: silence-0
+spec:
+  start: 2020-01-01T22:00:00Z
+  end: 2020-01-01T21:00:00Z
+`,
+				},
+				{
+					name:           "invalid recurrence",
+					validationErrs: 1,
+					valFields:      []string{fieldSpec, fieldSilenceRecurrence},
+					templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: Silence
+metadata:
+  name: silence-0
+spec:
+  start: 2020-01-01T22:00:00Z
+  end: 2020-01-02T00:00:00Z
+  recurrence:
+    every: monthly
+`,
+				},
+				{
+					name:           "invalid tag rule operator",
+					validationErrs: 1,
+					valFields:      []string{fieldSpec, fieldSilenceTagRules},
+					templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: Silence
+metadata:
+  name: silence-0
+spec:
+  start: 2020-01-01T22:00:00Z
+  end: 2020-01-02T00:00:00Z
+  tagRules:
+    - key: host
+      value: db1
+      operator: like
+`,
+				},
+			}
+
+			for _, tt := range tests {
+				testTemplateErrors(t, KindSilence, tt)
+			}
+		})
+	})
+
 	t.Run("template with tasks", func(t *testing.T) {
 		t.Run("happy path", func(t *testing.T) {
 			testfileRunner(t, "testdata/tasks", func(t *testing.T, template *Template) {
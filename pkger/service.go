@@ -156,6 +156,7 @@ type serviceOpt struct {
 	orgSVC      influxdb.OrganizationService
 	ruleSVC     influxdb.NotificationRuleStore
 	secretSVC   influxdb.SecretService
+	silenceSVC  influxdb.SilenceService
 	taskSVC     influxdb.TaskService
 	teleSVC     influxdb.TelegrafConfigStore
 	varSVC      influxdb.VariableService
@@ -254,6 +255,13 @@ func WithSecretSVC(secretSVC influxdb.SecretService) ServiceSetterFn {
 	}
 }
 
+// WithSilenceSVC sets the silence service.
+func WithSilenceSVC(silenceSVC influxdb.SilenceService) ServiceSetterFn {
+	return func(opt *serviceOpt) {
+		opt.silenceSVC = silenceSVC
+	}
+}
+
 // WithTaskSVC sets the task service.
 func WithTaskSVC(taskSVC influxdb.TaskService) ServiceSetterFn {
 	return func(opt *serviceOpt) {
@@ -305,6 +313,7 @@ type Service struct {
 	orgSVC      influxdb.OrganizationService
 	ruleSVC     influxdb.NotificationRuleStore
 	secretSVC   influxdb.SecretService
+	silenceSVC  influxdb.SilenceService
 	taskSVC     influxdb.TaskService
 	teleSVC     influxdb.TelegrafConfigStore
 	varSVC      influxdb.VariableService
@@ -342,6 +351,7 @@ func NewService(opts ...ServiceSetterFn) *Service {
 		orgSVC:      opt.orgSVC,
 		ruleSVC:     opt.ruleSVC,
 		secretSVC:   opt.secretSVC,
+		silenceSVC:  opt.silenceSVC,
 		taskSVC:     opt.taskSVC,
 		teleSVC:     opt.teleSVC,
 		varSVC:      opt.varSVC,
@@ -833,6 +843,22 @@ func (s *Service) cloneOrgTasks(ctx context.Context, orgID influxdb.ID) ([]Resou
 	return resources, nil
 }
 
+func (s *Service) cloneOrgSilences(ctx context.Context, orgID influxdb.ID) ([]ResourceToClone, error) {
+	silences, err := s.silenceSVC.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]ResourceToClone, 0, len(silences))
+	for _, sl := range silences {
+		resources = append(resources, ResourceToClone{
+			Kind: KindSilence,
+			ID:   sl.ID,
+		})
+	}
+	return resources, nil
+}
+
 func (s *Service) cloneOrgTelegrafs(ctx context.Context, orgID influxdb.ID) ([]ResourceToClone, error) {
 	teles, _, err := s.teleSVC.FindTelegrafConfigs(ctx, influxdb.TelegrafConfigFilter{OrgID: &orgID})
 	if err != nil {
@@ -881,6 +907,7 @@ func (s *Service) filterOrgResourceKinds(resourceKindFilters []Kind) []struct {
 		KindLabel:                s.cloneOrgLabels,
 		KindNotificationEndpoint: s.cloneOrgNotificationEndpoints,
 		KindNotificationRule:     s.cloneOrgNotificationRules,
+		KindSilence:              s.cloneOrgSilences,
 		KindTask:                 s.cloneOrgTasks,
 		KindTelegraf:             s.cloneOrgTelegrafs,
 		KindVariable:             s.cloneOrgVariables,
@@ -993,6 +1020,7 @@ func (s *Service) dryRun(ctx context.Context, orgID influxdb.ID, template *Templ
 	s.dryRunChecks(ctx, orgID, state.mChecks)
 	s.dryRunDashboards(ctx, orgID, state.mDashboards)
 	s.dryRunLabels(ctx, orgID, state.mLabels)
+	s.dryRunSilences(ctx, orgID, state.mSilences)
 	s.dryRunTasks(ctx, orgID, state.mTasks)
 	s.dryRunTelegrafConfigs(ctx, orgID, state.mTelegrafs)
 	s.dryRunVariables(ctx, orgID, state.mVariables)
@@ -1176,6 +1204,20 @@ func (s *Service) dryRunTasks(ctx context.Context, orgID influxdb.ID, tasks map[
 	}
 }
 
+func (s *Service) dryRunSilences(ctx context.Context, orgID influxdb.ID, silences map[string]*stateSilence) {
+	for _, stateSilence := range silences {
+		stateSilence.orgID = orgID
+		var existing *influxdb.Silence
+		if stateSilence.ID() != 0 {
+			existing, _ = s.silenceSVC.FindSilenceByID(ctx, stateSilence.ID())
+		}
+		if IsNew(stateSilence.stateStatus) && existing != nil {
+			stateSilence.stateStatus = StateStatusExists
+		}
+		stateSilence.existing = existing
+	}
+}
+
 func (s *Service) dryRunTelegrafConfigs(ctx context.Context, orgID influxdb.ID, teleConfigs map[string]*stateTelegraf) {
 	for _, stateTele := range teleConfigs {
 		stateTele.orgID = orgID
@@ -1431,11 +1473,15 @@ func ApplyWithResourceSkip(action ActionSkipResource) ApplyOptFn {
 			opt.ResourcesToSkip = make(map[ActionSkipResource]bool)
 		}
 		switch action.Kind {
-		case KindCheckDeadman, KindCheckThreshold:
+		case KindCheckAnomaly, KindCheckDeadman, KindCheckRate, KindCheckThreshold:
 			action.Kind = KindCheck
-		case KindNotificationEndpointHTTP,
+		case KindNotificationEndpointEmail,
+			KindNotificationEndpointHTTP,
+			KindNotificationEndpointOpsgenie,
 			KindNotificationEndpointPagerDuty,
-			KindNotificationEndpointSlack:
+			KindNotificationEndpointSlack,
+			KindNotificationEndpointTeams,
+			KindNotificationEndpointTelegram:
 			action.Kind = KindNotificationEndpoint
 		}
 		opt.ResourcesToSkip[action] = true
@@ -1449,11 +1495,15 @@ func ApplyWithKindSkip(action ActionSkipKind) ApplyOptFn {
 			opt.KindsToSkip = make(map[Kind]bool)
 		}
 		switch action.Kind {
-		case KindCheckDeadman, KindCheckThreshold:
+		case KindCheckAnomaly, KindCheckDeadman, KindCheckRate, KindCheckThreshold:
 			action.Kind = KindCheck
-		case KindNotificationEndpointHTTP,
+		case KindNotificationEndpointEmail,
+			KindNotificationEndpointHTTP,
+			KindNotificationEndpointOpsgenie,
 			KindNotificationEndpointPagerDuty,
-			KindNotificationEndpointSlack:
+			KindNotificationEndpointSlack,
+			KindNotificationEndpointTeams,
+			KindNotificationEndpointTelegram:
 			action.Kind = KindNotificationEndpoint
 		}
 		opt.KindsToSkip[action.Kind] = true
@@ -1583,6 +1633,7 @@ func (s *Service) applyState(ctx context.Context, coordinator *rollbackCoordinat
 			s.applyChecks(ctx, state.checks()),
 			s.applyDashboards(ctx, state.dashboards()),
 			endpointApp,
+			s.applySilences(ctx, state.silences()),
 			s.applyTasks(ctx, state.tasks()),
 			s.applyTelegrafs(ctx, userID, state.telegrafConfigs()),
 		},
@@ -2360,12 +2411,20 @@ func (s *Service) rollbackNotificationRules(ctx context.Context, userID influxdb
 
 		existingRuleFn := func(endpointID influxdb.ID) influxdb.NotificationRule {
 			switch rr := r.existing.(type) {
+			case *rule.Email:
+				rr.EndpointID = endpointID
 			case *rule.HTTP:
 				rr.EndpointID = endpointID
+			case *rule.Opsgenie:
+				rr.EndpointID = endpointID
 			case *rule.PagerDuty:
 				rr.EndpointID = endpointID
 			case *rule.Slack:
 				rr.EndpointID = endpointID
+			case *rule.Teams:
+				rr.EndpointID = endpointID
+			case *rule.Telegram:
+				rr.EndpointID = endpointID
 			}
 			return r.existing
 		}
@@ -2627,6 +2686,130 @@ func (s *Service) rollbackTasks(ctx context.Context, tasks []*stateTask) error {
 	return nil
 }
 
+func (s *Service) applySilences(ctx context.Context, silences []*stateSilence) applier {
+	const resource = "silences"
+
+	mutex := new(doMutex)
+	rollbackSilences := make([]*stateSilence, 0, len(silences))
+
+	createFn := func(ctx context.Context, i int, orgID, userID influxdb.ID) *applyErrBody {
+		var sl *stateSilence
+		mutex.Do(func() {
+			silences[i].orgID = orgID
+			sl = silences[i]
+		})
+
+		influxSilence, err := s.applySilence(ctx, sl)
+		if err != nil {
+			return &applyErrBody{
+				name: sl.parserSilence.MetaName(),
+				msg:  err.Error(),
+			}
+		}
+
+		mutex.Do(func() {
+			silences[i].id = influxSilence.ID
+			rollbackSilences = append(rollbackSilences, silences[i])
+		})
+
+		return nil
+	}
+
+	return applier{
+		creater: creater{
+			entries: len(silences),
+			fn:      createFn,
+		},
+		rollbacker: rollbacker{
+			resource: resource,
+			fn:       func(_ influxdb.ID) error { return s.rollbackSilences(ctx, rollbackSilences) },
+		},
+	}
+}
+
+func (s *Service) applySilence(ctx context.Context, sl *stateSilence) (influxdb.Silence, error) {
+	switch {
+	case IsRemoval(sl.stateStatus):
+		if err := s.silenceSVC.DeleteSilence(ctx, sl.ID()); err != nil {
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				return influxdb.Silence{}, nil
+			}
+			return influxdb.Silence{}, applyFailErr("delete", sl.stateIdentity(), err)
+		}
+		return *sl.existing, nil
+	case IsExisting(sl.stateStatus) && sl.existing != nil:
+		influxSilence := sl.parserSilence.toInfluxSilence()
+		recur := influxSilence.Recurrence
+		if recur == nil {
+			// an empty recurrence removes the recurrence of the existing silence
+			recur = &influxdb.SilenceRecurrence{}
+		}
+		updatedSilence, err := s.silenceSVC.UpdateSilence(ctx, sl.ID(), influxdb.SilenceUpdate{
+			Name:        &influxSilence.Name,
+			Description: &influxSilence.Description,
+			Start:       &influxSilence.Start,
+			End:         &influxSilence.End,
+			Recurrence:  recur,
+			TagRules:    &influxSilence.TagRules,
+		})
+		if err != nil {
+			return influxdb.Silence{}, applyFailErr("update", sl.stateIdentity(), err)
+		}
+		return *updatedSilence, nil
+	default:
+		influxSilence := sl.parserSilence.toInfluxSilence()
+		influxSilence.OrgID = sl.orgID
+		if err := s.silenceSVC.CreateSilence(ctx, &influxSilence); err != nil {
+			return influxdb.Silence{}, applyFailErr("create", sl.stateIdentity(), err)
+		}
+		return influxSilence, nil
+	}
+}
+
+func (s *Service) rollbackSilences(ctx context.Context, silences []*stateSilence) error {
+	rollbackFn := func(sl *stateSilence) error {
+		if !IsNew(sl.stateStatus) && sl.existing == nil {
+			return nil
+		}
+
+		var err error
+		switch sl.stateStatus {
+		case StateStatusRemove:
+			err = ierrors.Wrap(s.silenceSVC.CreateSilence(ctx, sl.existing), "rolling back removed silence")
+		case StateStatusExists:
+			recur := sl.existing.Recurrence
+			if recur == nil {
+				recur = &influxdb.SilenceRecurrence{}
+			}
+			_, err = s.silenceSVC.UpdateSilence(ctx, sl.ID(), influxdb.SilenceUpdate{
+				Name:        &sl.existing.Name,
+				Description: &sl.existing.Description,
+				Start:       &sl.existing.Start,
+				End:         &sl.existing.End,
+				Recurrence:  recur,
+				TagRules:    &sl.existing.TagRules,
+			})
+			err = ierrors.Wrap(err, "rolling back updated silence")
+		default:
+			err = ierrors.Wrap(s.silenceSVC.DeleteSilence(ctx, sl.ID()), "rolling back created silence")
+		}
+		return err
+	}
+
+	var errs []string
+	for _, sl := range silences {
+		if err := rollbackFn(sl); err != nil {
+			errs = append(errs, fmt.Sprintf("error for silence[%q]: %s", sl.ID(), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
 func (s *Service) applyTelegrafs(ctx context.Context, userID influxdb.ID, teles []*stateTelegraf) applier {
 	const resource = "telegrafs"
 
@@ -3127,6 +3310,17 @@ func (s *Service) updateStackAfterSuccess(ctx context.Context, stackID influxdb.
 			),
 		})
 	}
+	for _, sl := range state.mSilences {
+		if IsRemoval(sl.stateStatus) {
+			continue
+		}
+		stackResources = append(stackResources, StackResource{
+			APIVersion: APIVersion,
+			ID:         sl.ID(),
+			Kind:       KindSilence,
+			MetaName:   sl.parserSilence.MetaName(),
+		})
+	}
 	for _, t := range state.mTasks {
 		if IsRemoval(t.stateStatus) || isRestrictedTask(t.existing) {
 			continue
@@ -3260,6 +3454,13 @@ func (s *Service) updateStackAfterRollback(ctx context.Context, stackID influxdb
 				res.Associations = newAss
 			}
 		}
+		for _, sl := range state.mSilences {
+			res, ok := existingResources[newKey(KindSilence, sl.parserSilence.MetaName())]
+			if ok && res.ID != sl.ID() {
+				hasChanges = true
+				res.ID = sl.existing.ID
+			}
+		}
 		for _, t := range state.mTasks {
 			res, ok := existingResources[newKey(KindTask, t.parserTask.MetaName())]
 			if ok && res.ID != t.ID() {
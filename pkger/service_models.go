@@ -15,6 +15,7 @@ type stateCoordinator struct {
 	mEndpoints  map[string]*stateEndpoint
 	mLabels     map[string]*stateLabel
 	mRules      map[string]*stateRule
+	mSilences   map[string]*stateSilence
 	mTasks      map[string]*stateTask
 	mTelegrafs  map[string]*stateTelegraf
 	mVariables  map[string]*stateVariable
@@ -31,6 +32,7 @@ func newStateCoordinator(template *Template, acts resourceActions) *stateCoordin
 		mEndpoints:  make(map[string]*stateEndpoint),
 		mLabels:     make(map[string]*stateLabel),
 		mRules:      make(map[string]*stateRule),
+		mSilences:   make(map[string]*stateSilence),
 		mTasks:      make(map[string]*stateTask),
 		mTelegrafs:  make(map[string]*stateTelegraf),
 		mVariables:  make(map[string]*stateVariable),
@@ -99,6 +101,15 @@ func newStateCoordinator(template *Template, acts resourceActions) *stateCoordin
 			labelAssociations: state.templateToStateLabels(r.labels),
 		}
 	}
+	for _, sl := range template.silences() {
+		if acts.skipResource(KindSilence, sl.MetaName()) {
+			continue
+		}
+		state.mSilences[sl.MetaName()] = &stateSilence{
+			parserSilence: sl,
+			stateStatus:   StateStatusNew,
+		}
+	}
 	for _, task := range template.tasks() {
 		if acts.skipResource(KindTask, task.MetaName()) {
 			continue
@@ -181,6 +192,14 @@ func (s *stateCoordinator) rules() []*stateRule {
 	return out
 }
 
+func (s *stateCoordinator) silences() []*stateSilence {
+	out := make([]*stateSilence, 0, len(s.mSilences))
+	for _, sl := range s.mSilences {
+		out = append(out, sl)
+	}
+	return out
+}
+
 func (s *stateCoordinator) tasks() []*stateTask {
 	out := make([]*stateTask, 0, len(s.mTasks))
 	for _, t := range s.mTasks {
@@ -249,6 +268,13 @@ func (s *stateCoordinator) diff() Diff {
 		return diff.NotificationRules[i].MetaName < diff.NotificationRules[j].MetaName
 	})
 
+	for _, sl := range s.mSilences {
+		diff.Silences = append(diff.Silences, sl.diffSilence())
+	}
+	sort.Slice(diff.Silences, func(i, j int) bool {
+		return diff.Silences[i].MetaName < diff.Silences[j].MetaName
+	})
+
 	for _, t := range s.mTasks {
 		diff.Tasks = append(diff.Tasks, t.diffTask())
 	}
@@ -359,6 +385,16 @@ func (s *stateCoordinator) summary() Summary {
 		return sum.NotificationRules[i].MetaName < sum.NotificationRules[j].MetaName
 	})
 
+	for _, sl := range s.mSilences {
+		if IsRemoval(sl.stateStatus) {
+			continue
+		}
+		sum.Silences = append(sum.Silences, sl.summarize())
+	}
+	sort.Slice(sum.Silences, func(i, j int) bool {
+		return sum.Silences[i].MetaName < sum.Silences[j].MetaName
+	})
+
 	for _, t := range s.mTasks {
 		if IsRemoval(t.stateStatus) {
 			continue
@@ -508,7 +544,7 @@ func (s *stateCoordinator) get(k Kind, metaName string) (interface{}, bool) {
 	case KindBucket:
 		v, ok := s.mBuckets[metaName]
 		return v, ok
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAnomaly, KindCheckDeadman, KindCheckRate, KindCheckThreshold:
 		v, ok := s.mChecks[metaName]
 		return v, ok
 	case KindDashboard:
@@ -518,14 +554,21 @@ func (s *stateCoordinator) get(k Kind, metaName string) (interface{}, bool) {
 		v, ok := s.mLabels[metaName]
 		return v, ok
 	case KindNotificationEndpoint,
+		KindNotificationEndpointEmail,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointTelegram:
 		v, ok := s.mEndpoints[metaName]
 		return v, ok
 	case KindNotificationRule:
 		v, ok := s.mRules[metaName]
 		return v, ok
+	case KindSilence:
+		v, ok := s.mSilences[metaName]
+		return v, ok
 	case KindTask:
 		v, ok := s.mTasks[metaName]
 		return v, ok
@@ -582,7 +625,7 @@ func (s *stateCoordinator) addObjectForRemoval(k Kind, metaName string, id influ
 			parserBkt:   &bucket{identity: newIdentity},
 			stateStatus: StateStatusRemove,
 		}
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAnomaly, KindCheckDeadman, KindCheckRate, KindCheckThreshold:
 		s.mChecks[metaName] = &stateCheck{
 			id:          id,
 			parserCheck: &check{identity: newIdentity},
@@ -601,9 +644,13 @@ func (s *stateCoordinator) addObjectForRemoval(k Kind, metaName string, id influ
 			stateStatus: StateStatusRemove,
 		}
 	case KindNotificationEndpoint,
+		KindNotificationEndpointEmail,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointTelegram:
 		s.mEndpoints[metaName] = &stateEndpoint{
 			id:             id,
 			parserEndpoint: &notificationEndpoint{identity: newIdentity},
@@ -615,6 +662,12 @@ func (s *stateCoordinator) addObjectForRemoval(k Kind, metaName string, id influ
 			parserRule:  &notificationRule{identity: newIdentity},
 			stateStatus: StateStatusRemove,
 		}
+	case KindSilence:
+		s.mSilences[metaName] = &stateSilence{
+			id:            id,
+			parserSilence: &silence{identity: newIdentity},
+			stateStatus:   StateStatusRemove,
+		}
 	case KindTask:
 		s.mTasks[metaName] = &stateTask{
 			id:          id,
@@ -644,7 +697,7 @@ func (s *stateCoordinator) getObjectIDSetter(k Kind, metaName string) (func(infl
 			r.id = id
 			r.stateStatus = StateStatusExists
 		}, ok
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAnomaly, KindCheckDeadman, KindCheckRate, KindCheckThreshold:
 		r, ok := s.mChecks[metaName]
 		return func(id influxdb.ID) {
 			r.id = id
@@ -663,9 +716,13 @@ func (s *stateCoordinator) getObjectIDSetter(k Kind, metaName string) (func(infl
 			r.stateStatus = StateStatusExists
 		}, ok
 	case KindNotificationEndpoint,
+		KindNotificationEndpointEmail,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointTelegram:
 		r, ok := s.mEndpoints[metaName]
 		return func(id influxdb.ID) {
 			r.id = id
@@ -677,6 +734,12 @@ func (s *stateCoordinator) getObjectIDSetter(k Kind, metaName string) (func(infl
 			r.id = id
 			r.stateStatus = StateStatusExists
 		}, ok
+	case KindSilence:
+		r, ok := s.mSilences[metaName]
+		return func(id influxdb.ID) {
+			r.id = id
+			r.stateStatus = StateStatusExists
+		}, ok
 	case KindTask:
 		r, ok := s.mTasks[metaName]
 		return func(id influxdb.ID) {
@@ -1257,14 +1320,27 @@ func (r *stateRule) diffRule() DiffNotificationRule {
 	}
 
 	switch p := r.existing.(type) {
+	case *rule.Email:
+		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.BodyTemplate
 	case *rule.HTTP:
 		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.BodyTemplate
 	case *rule.Slack:
 		assignBase(p.Base)
 		sum.Old.MessageTemplate = p.MessageTemplate
 	case *rule.PagerDuty:
 		assignBase(p.Base)
 		sum.Old.MessageTemplate = p.MessageTemplate
+	case *rule.Opsgenie:
+		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.MessageTemplate
+	case *rule.Teams:
+		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.MessageTemplate
+	case *rule.Telegram:
+		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.MessageTemplate
 	}
 
 	return sum
@@ -1328,12 +1404,20 @@ func (r *stateRule) toInfluxRule() influxdb.NotificationRule {
 		influxRule.SetOrgID(r.orgID)
 	}
 	switch e := influxRule.(type) {
+	case *rule.Email:
+		e.EndpointID = r.associatedEndpoint.ID()
 	case *rule.HTTP:
 		e.EndpointID = r.associatedEndpoint.ID()
+	case *rule.Opsgenie:
+		e.EndpointID = r.associatedEndpoint.ID()
 	case *rule.PagerDuty:
 		e.EndpointID = r.associatedEndpoint.ID()
 	case *rule.Slack:
 		e.EndpointID = r.associatedEndpoint.ID()
+	case *rule.Teams:
+		e.EndpointID = r.associatedEndpoint.ID()
+	case *rule.Telegram:
+		e.EndpointID = r.associatedEndpoint.ID()
 	}
 
 	return influxRule
@@ -1416,6 +1500,79 @@ func (t *stateTask) summarize() SummaryTask {
 	return sum
 }
 
+type stateSilence struct {
+	id, orgID   influxdb.ID
+	stateStatus StateStatus
+
+	parserSilence *silence
+	existing      *influxdb.Silence
+}
+
+func (s *stateSilence) ID() influxdb.ID {
+	if !IsNew(s.stateStatus) && s.existing != nil {
+		return s.existing.ID
+	}
+	return s.id
+}
+
+func (s *stateSilence) diffSilence() DiffSilence {
+	sum := s.parserSilence.summarize()
+	diff := DiffSilence{
+		DiffIdentifier: DiffIdentifier{
+			Kind:        KindSilence,
+			ID:          SafeID(s.ID()),
+			StateStatus: s.stateStatus,
+			MetaName:    s.parserSilence.MetaName(),
+		},
+		New: DiffSilenceValues{
+			Name:        sum.Name,
+			Description: sum.Description,
+			Start:       sum.Start,
+			End:         sum.End,
+			Recurrence:  sum.Recurrence,
+			TagRules:    sum.TagRules,
+		},
+	}
+	if e := s.existing; e != nil {
+		diff.Old = &DiffSilenceValues{
+			Name:        e.Name,
+			Description: e.Description,
+			Start:       e.Start,
+			End:         e.End,
+			Recurrence:  e.Recurrence,
+		}
+		for _, tr := range e.TagRules {
+			diff.Old.TagRules = append(diff.Old.TagRules, SummaryTagRule{
+				Key:      tr.Key,
+				Value:    tr.Value,
+				Operator: tr.Operator.String(),
+			})
+		}
+	}
+	return diff
+}
+
+func (s *stateSilence) resourceType() influxdb.ResourceType {
+	return KindSilence.ResourceType()
+}
+
+func (s *stateSilence) stateIdentity() stateIdentity {
+	return stateIdentity{
+		id:           s.ID(),
+		name:         s.parserSilence.Name(),
+		metaName:     s.parserSilence.MetaName(),
+		resourceType: s.resourceType(),
+		stateStatus:  s.stateStatus,
+	}
+}
+
+func (s *stateSilence) summarize() SummarySilence {
+	sum := s.parserSilence.summarize()
+	sum.ID = SafeID(s.ID())
+	sum.OrgID = SafeID(s.orgID)
+	return sum
+}
+
 type stateTelegraf struct {
 	id, orgID         influxdb.ID
 	stateStatus       StateStatus
@@ -48,9 +48,10 @@ func TestService(t *testing.T) {
 					return nil
 				},
 			},
-			taskSVC: mock.NewTaskService(),
-			teleSVC: mock.NewTelegrafConfigStore(),
-			varSVC:  mock.NewVariableService(),
+			silenceSVC: mock.NewSilenceService(),
+			taskSVC:    mock.NewTaskService(),
+			teleSVC:    mock.NewTelegrafConfigStore(),
+			varSVC:     mock.NewVariableService(),
 		}
 		for _, o := range opts {
 			o(&opt)
@@ -66,6 +67,7 @@ func TestService(t *testing.T) {
 			WithNotificationRuleSVC(opt.ruleSVC),
 			WithOrganizationService(opt.orgSVC),
 			WithSecretSVC(opt.secretSVC),
+			WithSilenceSVC(opt.silenceSVC),
 			WithTaskSVC(opt.taskSVC),
 			WithTelegrafSVC(opt.teleSVC),
 			WithVariableSVC(opt.varSVC),
@@ -1604,6 +1606,62 @@ func TestService(t *testing.T) {
 			})
 		})
 
+		t.Run("silences", func(t *testing.T) {
+			t.Run("successfully creates", func(t *testing.T) {
+				testfileRunner(t, "testdata/silence.yml", func(t *testing.T, template *Template) {
+					orgID := influxdb.ID(9000)
+
+					fakeSilenceSVC := mock.NewSilenceService()
+					fakeSilenceSVC.CreateSilenceFn = func(_ context.Context, s *influxdb.Silence) error {
+						if s.OrgID != orgID {
+							return errors.New("wrong org id")
+						}
+						s.ID = 1
+						return nil
+					}
+
+					svc := newTestService(WithSilenceSVC(fakeSilenceSVC))
+
+					impact, err := svc.Apply(context.TODO(), orgID, 0, ApplyWithTemplate(template))
+					require.NoError(t, err)
+
+					sum := impact.Summary
+					require.Len(t, sum.Silences, 2)
+					assert.Equal(t, SafeID(1), sum.Silences[0].ID)
+					assert.Equal(t, SafeID(orgID), sum.Silences[0].OrgID)
+					assert.Equal(t, "migration", sum.Silences[0].Name)
+					assert.Equal(t, "nightly maintenance", sum.Silences[1].Name)
+				})
+			})
+
+			t.Run("rolls back all created silences on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/silence.yml", func(t *testing.T, template *Template) {
+					fakeSilenceSVC := mock.NewSilenceService()
+					fakeSilenceSVC.CreateSilenceFn = func(_ context.Context, s *influxdb.Silence) error {
+						if s.Name == "migration" {
+							return errors.New("limit hit")
+						}
+						s.ID = 1
+						return nil
+					}
+					var deleted []influxdb.ID
+					fakeSilenceSVC.DeleteSilenceFn = func(_ context.Context, id influxdb.ID) error {
+						deleted = append(deleted, id)
+						return nil
+					}
+
+					svc := newTestService(WithSilenceSVC(fakeSilenceSVC))
+
+					orgID := influxdb.ID(9000)
+
+					_, err := svc.Apply(context.TODO(), orgID, 0, ApplyWithTemplate(template))
+					require.Error(t, err)
+
+					assert.Equal(t, []influxdb.ID{1}, deleted)
+				})
+			})
+		})
+
 		t.Run("telegrafs", func(t *testing.T) {
 			t.Run("successfuly creates", func(t *testing.T) {
 				testfileRunner(t, "testdata/telegraf.yml", func(t *testing.T, template *Template) {
@@ -2588,6 +2646,18 @@ func TestService(t *testing.T) {
 							URL:        "http://example.com",
 						},
 					},
+					{
+						name: "telegram",
+						expected: &endpoint.Telegram{
+							Base: endpoint.Base{
+								Name:        "telegram-endpoint",
+								Description: "desc",
+								Status:      influxdb.TaskStatusActive,
+							},
+							Token:   influxdb.SecretField{Key: "token"},
+							Channel: "-12345",
+						},
+					},
 				}
 
 				for _, tt := range tests {
@@ -2711,6 +2781,25 @@ func TestService(t *testing.T) {
 								Base: newRuleBase(13),
 							},
 						},
+						{
+							name: "telegram",
+							endpoint: &endpoint.Telegram{
+								Base: endpoint.Base{
+									ID:          newTestIDPtr(13),
+									Name:        "endpoint_0",
+									Description: "desc",
+									Status:      influxdb.TaskStatusActive,
+								},
+								Token:   influxdb.SecretField{Key: "token"},
+								Channel: "-12345",
+							},
+							rule: &rule.Telegram{
+								Base:                  newRuleBase(13),
+								MessageTemplate:       "Telegram Template",
+								ParseMode:             "HTML",
+								DisableWebPagePreview: true,
+							},
+						},
 					}
 
 					for _, tt := range tests {
@@ -2788,6 +2877,13 @@ func TestService(t *testing.T) {
 							case *rule.Slack:
 								baseEqual(t, p.Base)
 								assert.Equal(t, p.MessageTemplate, actualRule.MessageTemplate)
+							case *rule.Telegram:
+								baseEqual(t, p.Base)
+								assert.Equal(t, p.MessageTemplate, actualRule.MessageTemplate)
+								actualTelegram, ok := newTemplate.notificationRules()[0].toInfluxRule().(*rule.Telegram)
+								require.True(t, ok)
+								assert.Equal(t, p.ParseMode, actualTelegram.ParseMode)
+								assert.Equal(t, p.DisableWebPagePreview, actualTelegram.DisableWebPagePreview)
 							}
 
 							require.Len(t, template.Summary().NotificationEndpoints, 1)
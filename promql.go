@@ -0,0 +1,77 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultPromQLLookback is how far back an instant vector selector looks for
+// the latest sample of a series, as the lookback delta of Prometheus.
+const DefaultPromQLLookback = 5 * time.Minute
+
+// PromQL result types, as in the responses of the Prometheus HTTP API.
+const (
+	PromQLVector = "vector"
+	PromQLMatrix = "matrix"
+)
+
+// PromQLQuery is a PromQL query evaluated at Stop, or at every Step from
+// Start to Stop when Step is not zero.
+type PromQLQuery struct {
+	Query string
+	Start time.Time
+	Stop  time.Time
+	Step  time.Duration
+}
+
+// PromQLResult is the result of a PromQL query, a vector of the series with
+// a single sample or a matrix of the series with their samples.
+type PromQLResult struct {
+	Type   string
+	Series []*PromSeries
+}
+
+// PromSeries is a series of Prometheus samples.
+type PromSeries struct {
+	// Labels holds the labels of the series, the metric name included as
+	// the __name__ label when the series has one.
+	Labels  map[string]string
+	Samples []PromSample
+}
+
+// PromSample is a sample of a Prometheus series.
+type PromSample struct {
+	Time  time.Time
+	Value float64
+}
+
+// PromMatchType is the type of a label matcher of a Prometheus remote read.
+type PromMatchType int
+
+// Label matcher types, numbered as in the remote read protocol.
+const (
+	PromMatchEqual PromMatchType = iota
+	PromMatchNotEqual
+	PromMatchRegexp
+	PromMatchNotRegexp
+)
+
+// PromLabelMatcher selects the series by the value of one of their labels.
+type PromLabelMatcher struct {
+	Type  PromMatchType
+	Name  string
+	Value string
+}
+
+// PromQLService evaluates PromQL queries and Prometheus remote reads against
+// the samples of a bucket, stored as written by Prometheus remote writes:
+// a measurement per metric with the labels as tags and a "value" field.
+// The data is read with the authorization of the context.
+type PromQLService interface {
+	// QueryPromQL evaluates the PromQL query against the samples of the bucket.
+	QueryPromQL(ctx context.Context, orgID, bucketID ID, q PromQLQuery) (*PromQLResult, error)
+
+	// ReadPromSeries returns the samples of the series of the bucket matching
+	// all the matchers between start and stop.
+	ReadPromSeries(ctx context.Context, orgID, bucketID ID, matchers []PromLabelMatcher, start, stop time.Time) ([]*PromSeries, error)
+}
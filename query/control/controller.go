@@ -23,6 +23,7 @@ import (
 	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
@@ -57,6 +58,7 @@ type Controller struct {
 	abortOnce  sync.Once
 	abort      chan struct{}
 	memory     *memoryManager
+	quotas     *quotaManager
 
 	metrics   *controllerMetrics
 	labelKeys []string
@@ -99,6 +101,11 @@ type Config struct {
 	MetricLabelKeys []string
 
 	ExecutorDependencies []flux.Dependency
+
+	// QuotaFinder finds the query quotas limiting the queries of each
+	// organization on top of the limits above. If this is unset, the
+	// queries of the organizations are not limited by query quotas.
+	QuotaFinder QuotaFinder
 }
 
 // complete will fill in the defaults, validate the configuration, and
@@ -179,6 +186,7 @@ func New(config Config) (*Controller, error) {
 		done:         make(chan struct{}),
 		abort:        make(chan struct{}),
 		memory:       mm,
+		quotas:       newQuotaManager(),
 		log:          logger,
 		metrics:      newControllerMetrics(c.MetricLabelKeys),
 		labelKeys:    c.MetricLabelKeys,
@@ -207,7 +215,11 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 	for _, dep := range c.dependencies {
 		ctx = dep.Inject(ctx)
 	}
-	q, err := c.query(ctx, req.Compiler)
+	quota, err := c.findQueryQuota(ctx, req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	q, err := c.query(ctx, req.Compiler, quota)
 	if err != nil {
 		return q, err
 	}
@@ -217,8 +229,8 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 
 // query submits a query for execution returning immediately.
 // Done must be called on any returned Query objects.
-func (c *Controller) query(ctx context.Context, compiler flux.Compiler) (flux.Query, error) {
-	q, err := c.createQuery(ctx, compiler.CompilerType())
+func (c *Controller) query(ctx context.Context, compiler flux.Compiler, quota *influxdb.QueryQuota) (flux.Query, error) {
+	q, err := c.createQuery(ctx, compiler.CompilerType(), quota)
 	if err != nil {
 		return nil, handleFluxError(err)
 	}
@@ -232,13 +244,17 @@ func (c *Controller) query(ctx context.Context, compiler flux.Compiler) (flux.Qu
 	if err := c.enqueueQuery(q); err != nil {
 		q.setErr(err)
 		c.finish(q)
-		c.countQueryRequest(q, labelQueueError)
+		if _, ok := IsQuotaExceeded(err); ok {
+			c.countQueryRequest(q, labelQuotaError)
+		} else {
+			c.countQueryRequest(q, labelQueueError)
+		}
 		return nil, q.Err()
 	}
 	return q, nil
 }
 
-func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Query, error) {
+func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType, quota *influxdb.QueryQuota) (*Query, error) {
 	c.queriesMu.RLock()
 	if c.shutdown {
 		c.queriesMu.RUnlock()
@@ -281,6 +297,8 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 		parentSpan:         parentSpan,
 		cancel:             cancel,
 		doneCh:             make(chan struct{}),
+		createdAt:          time.Now(),
+		quota:              quota,
 	}
 
 	// Lock the queries mutex for the rest of this method.
@@ -314,6 +332,14 @@ func (c *Controller) countQueryRequest(q *Query, result requestsLabel) {
 	c.metrics.requests.WithLabelValues(lvs...).Inc()
 }
 
+func (c *Controller) countQuotaRejection(q *Query, limit QuotaLimit) {
+	l := len(q.labelValues)
+	lvs := make([]string, l+1)
+	copy(lvs, q.labelValues)
+	lvs[l] = string(limit)
+	c.metrics.quotaRejections.WithLabelValues(lvs...).Inc()
+}
+
 func (c *Controller) compileQuery(q *Query, compiler flux.Compiler) (err error) {
 	log := c.log.With(influxlogger.TraceFields(q.parentCtx)...)
 
@@ -363,9 +389,14 @@ func (c *Controller) enqueueQuery(q *Query) error {
 		}
 	}
 
+	if !c.quotas.reserveQueue(q) {
+		return q.exceedQuota(QuotaQueueSize)
+	}
+
 	select {
 	case c.queryQueue <- q:
 	default:
+		c.quotas.releaseQueue(q)
 		return &flux.Error{
 			Code: codes.ResourceExhausted,
 			Msg:  "queue length exceeded",
@@ -381,7 +412,15 @@ func (c *Controller) processQueryQueue() {
 		case <-c.done:
 			return
 		case q := <-c.queryQueue:
-			c.executeQuery(q)
+			// A query of an organization executing as many queries
+			// as its query quota allows waits for one of them to
+			// finish, which then executes it in its place.
+			if !c.quotas.startExecuting(q) {
+				continue
+			}
+			for ; q != nil; q = c.quotas.finishExecuting(q) {
+				c.executeQuery(q)
+			}
 		}
 	}
 }
@@ -420,6 +459,7 @@ func (c *Controller) executeQuery(q *Query) {
 		return
 	}
 
+	q.startExecutionTimer()
 	q.c.createAllocator(q)
 	// Record unused memory before start.
 	q.recordUnusedMemory()
@@ -536,6 +576,7 @@ type Query struct {
 	parentCtx               context.Context
 	parentSpan, currentSpan *tracing.Span
 	stats                   flux.Statistics
+	createdAt               time.Time
 
 	done   sync.Once
 	doneCh chan struct{}
@@ -546,6 +587,13 @@ type Query struct {
 
 	memoryManager *queryMemoryManager
 	alloc         *memory.Allocator
+
+	// quota is the query quota of the organization of the query,
+	// or nil if its queries are unlimited. The stateMu protects
+	// access to the error and the timer enforcing it.
+	quota          *influxdb.QueryQuota
+	quotaErr       error
+	executionTimer *time.Timer
 }
 
 // ID reports an ephemeral unique ID for the query.
@@ -592,6 +640,9 @@ func (q *Query) Done() {
 		q.stateMu.Lock()
 		q.transitionTo(Finished)
 		q.cancel()
+		if q.executionTimer != nil {
+			q.executionTimer.Stop()
+		}
 		q.stateMu.Unlock()
 
 		// Ensure that all of the results have been drained.
@@ -635,7 +686,9 @@ func (q *Query) Done() {
 		}
 
 		// Count query request.
-		if q.err != nil || len(q.runtimeErrs) > 0 {
+		if q.quotaErr != nil {
+			q.c.countQueryRequest(q, labelQuotaError)
+		} else if q.err != nil || len(q.runtimeErrs) > 0 {
 			q.c.countQueryRequest(q, labelRuntimeError)
 		} else {
 			q.c.countQueryRequest(q, labelSuccess)
@@ -771,13 +824,62 @@ TRANSITION:
 }
 
 // Err reports any error the query may have encountered.
+// A query which exceeded its query quota reports the limit
+// it exceeded rather than the error it failed with.
 func (q *Query) Err() error {
 	q.stateMu.Lock()
 	err := q.err
+	if q.quotaErr != nil {
+		err = q.quotaErr
+	}
 	q.stateMu.Unlock()
 	return handleFluxError(err)
 }
 
+// exceedQuota records that the query exceeded a limit of its query
+// quota and returns the error reporting it. Only the first limit
+// exceeded is recorded and nothing is recorded once the query has
+// finished, in which case this returns nil.
+func (q *Query) exceedQuota(limit QuotaLimit) error {
+	q.stateMu.Lock()
+	defer q.stateMu.Unlock()
+
+	if q.quotaErr != nil {
+		return q.quotaErr
+	}
+	if isFinishedState(q.state) {
+		return nil
+	}
+	q.quotaErr = quotaExceededError(q.quota.OrgID, limit)
+	q.c.countQuotaRejection(q, limit)
+	return q.quotaErr
+}
+
+// startExecutionTimer cancels the query once it has executed for
+// longer than its query quota allows.
+func (q *Query) startExecutionTimer() {
+	if q.quota == nil || q.quota.MaxExecutionTime.Duration <= 0 {
+		return
+	}
+	q.stateMu.Lock()
+	defer q.stateMu.Unlock()
+
+	if isFinishedState(q.state) {
+		return
+	}
+	q.executionTimer = time.AfterFunc(q.quota.MaxExecutionTime.Duration, func() {
+		if q.exceedQuota(QuotaExecutionTime) != nil {
+			q.Cancel()
+		}
+	})
+}
+
+func (q *Query) quotaError() error {
+	q.stateMu.RLock()
+	defer q.stateMu.RUnlock()
+	return q.quotaErr
+}
+
 // setErr marks this query with an error. If the query was
 // canceled, then the error is ignored.
 //
@@ -910,6 +1012,10 @@ func (ti *errorCollectingTableIterator) Do(f func(t flux.Table) error) error {
 	if err != nil {
 		err = handleFluxError(err)
 		ti.q.addRuntimeError(err)
+		// The query likely failed because it exceeded its query quota.
+		if qerr := ti.q.quotaError(); qerr != nil {
+			err = qerr
+		}
 	}
 	return err
 }
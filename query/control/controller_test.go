@@ -20,6 +20,7 @@ import (
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/stdlib/universe"
+	platform "github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/query"
 	_ "github.com/influxdata/influxdb/v2/query/builtin"
 	"github.com/influxdata/influxdb/v2/query/control"
@@ -1320,3 +1321,448 @@ func makeRequest(c flux.Compiler) *query.Request {
 		Compiler: c,
 	}
 }
+
+type quotaFinder map[platform.ID]*platform.QueryQuota
+
+func (f quotaFinder) FindQueryQuota(ctx context.Context, orgID platform.ID) (*platform.QueryQuota, error) {
+	if q, ok := f[orgID]; ok {
+		return q, nil
+	}
+	return &platform.QueryQuota{OrgID: orgID}, nil
+}
+
+func makeOrgRequest(c flux.Compiler, orgID platform.ID) *query.Request {
+	return &query.Request{
+		OrganizationID: orgID,
+		Compiler:       c,
+	}
+}
+
+func validateQuotaExceeded(t testing.TB, err error, limit control.QuotaLimit) {
+	t.Helper()
+	if platform.ErrorCode(err) != platform.ETooManyRequests {
+		t.Errorf("unexpected error code: got %q want %q", platform.ErrorCode(err), platform.ETooManyRequests)
+	}
+	qerr, ok := control.IsQuotaExceeded(err)
+	if !ok {
+		t.Fatalf("expected a quota exceeded error, got: %v", err)
+	}
+	if qerr.Limit != limit {
+		t.Errorf("unexpected exceeded limit: got %s want %s", qerr.Limit, limit)
+	}
+}
+
+func validateQuotaRejections(t testing.TB, reg *prometheus.Registry, orgID platform.ID, limit control.QuotaLimit, want int) {
+	t.Helper()
+	metrics, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []struct {
+		name   string
+		labels map[string]string
+	}{
+		{name: "query_control_quota_rejections_total", labels: map[string]string{"quota": string(limit), "org": orgID.String()}},
+		{name: "query_control_requests_total", labels: map[string]string{"result": "quota_error", "org": orgID.String()}},
+	} {
+		var got int
+		if m := FindMetric(metrics, m.name, m.labels); m != nil {
+			got = int(*m.Counter.Value)
+		}
+		if got != want {
+			t.Errorf("unexpected %s: got %d want %d", m.name, got, want)
+		}
+	}
+}
+
+func TestController_QuotaQueueSize(t *testing.T) {
+	orgID, otherOrgID := platform.ID(1), platform.ID(2)
+
+	config := config
+	config.ConcurrencyQuota = 2
+	config.QueueSize = 10
+	config.QuotaFinder = quotaFinder{
+		orgID: {OrgID: orgID, MaxConcurrency: 1, MaxQueueSize: 1},
+	}
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+	reg := setupPromRegistry(ctrl)
+
+	// This channel blocks program execution until we are done
+	// with running the test.
+	done := make(chan struct{})
+	defer close(done)
+
+	executing := make(chan struct{}, 10)
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					executing <- struct{}{}
+					<-done
+				},
+			}, nil
+		},
+	}
+	start := func(orgID platform.ID) error {
+		q, err := ctrl.Query(context.Background(), makeOrgRequest(compiler, orgID))
+		if err != nil {
+			return err
+		}
+		go func() {
+			for range q.Results() {
+				// discard the results
+			}
+			q.Done()
+		}()
+		return nil
+	}
+
+	// The first query executes and the second one waits for it.
+	if err := start(orgID); err != nil {
+		t.Fatal(err)
+	}
+	<-executing
+	if err := start(orgID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The queue of the organization is full.
+	err = start(orgID)
+	if err == nil {
+		t.Fatal("expected an error about the query quota queue size")
+	}
+	validateQuotaExceeded(t, err, control.QuotaQueueSize)
+	validateQuotaRejections(t, reg, orgID, control.QuotaQueueSize, 1)
+
+	// The queries of other organizations are not held back.
+	if err := start(otherOrgID); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-executing:
+	case <-time.After(time.Second):
+		t.Fatal("expected the query of the other organization to execute")
+	}
+}
+
+func TestController_QuotaConcurrency(t *testing.T) {
+	const numQueries = 3
+	orgID := platform.ID(1)
+
+	config := config
+	config.ConcurrencyQuota = numQueries
+	config.QueueSize = numQueries
+	config.QuotaFinder = quotaFinder{
+		orgID: {OrgID: orgID, MaxConcurrency: 1},
+	}
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	var (
+		mu                  sync.Mutex
+		active, maxExecuted int
+	)
+	executing := make(chan struct{})
+	release := make(chan struct{})
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					mu.Lock()
+					active++
+					if active > maxExecuted {
+						maxExecuted = active
+					}
+					mu.Unlock()
+
+					executing <- struct{}{}
+					<-release
+
+					mu.Lock()
+					active--
+					mu.Unlock()
+				},
+			}, nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < numQueries; i++ {
+		q, err := ctrl.Query(context.Background(), makeOrgRequest(compiler, orgID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			consumeResults(t, q)
+		}()
+	}
+
+	// The queries execute one after the other although
+	// the controller could execute them all at once.
+	for i := 0; i < numQueries; i++ {
+		select {
+		case <-executing:
+		case <-time.After(time.Second):
+			t.Fatalf("expected query %d to execute", i)
+		}
+		select {
+		case <-executing:
+			t.Fatal("expected a single query to execute at once")
+		case <-time.After(50 * time.Millisecond):
+		}
+		release <- struct{}{}
+	}
+	wg.Wait()
+
+	if maxExecuted != 1 {
+		t.Errorf("expected a single query to execute at once, got %d", maxExecuted)
+	}
+}
+
+func TestController_QuotaMemory(t *testing.T) {
+	orgID := platform.ID(1)
+
+	config := config
+	config.InitialMemoryBytesQuotaPerQuery = config.MemoryBytesQuotaPerQuery / 4
+	config.QuotaFinder = quotaFinder{
+		orgID: {OrgID: orgID, MaxMemoryBytes: config.MemoryBytesQuotaPerQuery / 2},
+	}
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+	reg := setupPromRegistry(ctrl)
+
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					// Allocate memory up to the memory limit of a query,
+					// which is more than the organization is allowed.
+					for i := 0; i < 16; i++ {
+						size := config.MemoryBytesQuotaPerQuery / 16
+						if err := alloc.Account(int(size)); err != nil {
+							q.SetErr(err)
+							return
+						}
+					}
+				},
+			}, nil
+		},
+	}
+
+	q, err := ctrl.Query(context.Background(), makeOrgRequest(compiler, orgID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+
+	validateQuotaExceeded(t, q.Err(), control.QuotaMemoryBytes)
+	validateQuotaRejections(t, reg, orgID, control.QuotaMemoryBytes, 1)
+
+	// The memory of the query was given back to the organization.
+	q, err = ctrl.Query(context.Background(), makeOrgRequest(mockCompiler, orgID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumeResults(t, q)
+}
+
+func TestController_QuotaExecutionTime(t *testing.T) {
+	orgID := platform.ID(1)
+
+	config := config
+	config.QuotaFinder = quotaFinder{
+		orgID: {OrgID: orgID, MaxExecutionTime: platform.Duration{Duration: 50 * time.Millisecond}},
+	}
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+	reg := setupPromRegistry(ctrl)
+
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					// Execute until canceled.
+					<-q.Canceled
+				},
+			}, nil
+		},
+	}
+
+	q, err := ctrl.Query(context.Background(), makeOrgRequest(compiler, orgID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	timer := time.AfterFunc(time.Second, func() {
+		t.Error("expected the query to be canceled")
+		q.Cancel()
+	})
+	defer timer.Stop()
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+
+	validateQuotaExceeded(t, q.Err(), control.QuotaExecutionTime)
+	validateQuotaRejections(t, reg, orgID, control.QuotaExecutionTime, 1)
+}
+
+func TestController_FindRunningQueries(t *testing.T) {
+	orgID, otherOrgID := platform.ID(1), platform.ID(2)
+
+	config := config
+	config.ConcurrencyQuota = 2
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	executing := make(chan struct{}, 2)
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					executing <- struct{}{}
+					// Execute until canceled.
+					<-q.Canceled
+				},
+			}, nil
+		},
+	}
+
+	var queries []flux.Query
+	for _, id := range []platform.ID{orgID, otherOrgID} {
+		req := makeOrgRequest(compiler, id)
+		req.Authorization = &platform.Authorization{ID: 3, UserID: 4}
+		q, err := ctrl.Query(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		queries = append(queries, q)
+		<-executing
+	}
+
+	rqs, err := ctrl.FindRunningQueries(context.Background(), platform.RunningQueryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rqs) != 2 {
+		t.Fatalf("unexpected number of running queries: got %d want 2", len(rqs))
+	}
+	for i, rq := range rqs {
+		if rq.State != "executing" {
+			t.Errorf("unexpected state of query %s: got %s want executing", rq.ID, rq.State)
+		}
+		if rq.CompilerType != "mockCompiler" || rq.AuthorizationID != 3 || rq.UserID != 4 {
+			t.Errorf("unexpected query %+v", rq)
+		}
+		if i > 0 && rq.ID <= rqs[i-1].ID {
+			t.Errorf("expected the queries to be sorted by ID")
+		}
+	}
+
+	rqs, err = ctrl.FindRunningQueries(context.Background(), platform.RunningQueryFilter{OrgID: &otherOrgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rqs) != 1 || rqs[0].OrgID != otherOrgID {
+		t.Fatalf("unexpected running queries of org %s: %+v", otherOrgID, rqs)
+	}
+
+	rq, err := ctrl.FindRunningQueryByID(context.Background(), rqs[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rq.OrgID != otherOrgID {
+		t.Errorf("unexpected org of query %s: got %s want %s", rq.ID, rq.OrgID, otherOrgID)
+	}
+
+	// The queries are not running once they are done.
+	for _, q := range queries {
+		q.Cancel()
+		for range q.Results() {
+			// discard the results
+		}
+		q.Done()
+	}
+	rqs, err = ctrl.FindRunningQueries(context.Background(), platform.RunningQueryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rqs) != 0 {
+		t.Errorf("unexpected running queries: %+v", rqs)
+	}
+	if _, err := ctrl.FindRunningQueryByID(context.Background(), rq.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected the query to be not found, got: %v", err)
+	}
+}
+
+func TestController_CancelRunningQuery(t *testing.T) {
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	executing := make(chan struct{})
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					close(executing)
+					// Execute until canceled.
+					<-q.Canceled
+				},
+			}, nil
+		},
+	}
+
+	q, err := ctrl.Query(context.Background(), makeOrgRequest(compiler, platform.ID(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-executing
+
+	rqs, err := ctrl.FindRunningQueries(context.Background(), platform.RunningQueryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rqs) != 1 {
+		t.Fatalf("unexpected number of running queries: got %d want 1", len(rqs))
+	}
+	if err := ctrl.CancelRunningQuery(context.Background(), rqs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	timer := time.AfterFunc(time.Second, func() {
+		t.Error("expected the query to be canceled")
+		q.Cancel()
+	})
+	defer timer.Stop()
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+
+	if err := ctrl.CancelRunningQuery(context.Background(), rqs[0].ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected the canceled query to be not found, got: %v", err)
+	}
+}
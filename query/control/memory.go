@@ -43,22 +43,37 @@ func (m *memoryManager) addUnusedMemoryBytes(amount int64) int64 {
 // createAllocator will construct an allocator and memory manager
 // for the given query.
 func (c *Controller) createAllocator(q *Query) {
+	// The initial memory does not count against the memory pool,
+	// but it does against the memory quota of the organization.
+	limit := c.quotas.reserveInitialMemory(q, c.memory.initialBytesQuotaPerQuery)
 	q.memoryManager = &queryMemoryManager{
-		m:     c.memory,
-		limit: c.memory.initialBytesQuotaPerQuery,
+		m:        c.memory,
+		q:        q,
+		limit:    limit,
+		reserved: limit,
 	}
-	q.alloc = &memory.Allocator{
+	alloc := &memory.Allocator{
 		// Use an anonymous function to ensure the value is copied.
 		Limit:   func(v int64) *int64 { return &v }(q.memoryManager.limit),
 		Manager: q.memoryManager,
 	}
+	// The allocator is read concurrently to report the memory
+	// of the running queries.
+	q.stateMu.Lock()
+	q.alloc = alloc
+	q.stateMu.Unlock()
 }
 
 // queryMemoryManager is a memory manager for a specific query.
 type queryMemoryManager struct {
 	m     *memoryManager
+	q     *Query
 	limit int64
 	given int64
+
+	// reserved is the memory reserved against the query quota
+	// of the organization of the query.
+	reserved int64
 }
 
 // RequestMemory will determine if the query can be given more memory
@@ -77,6 +92,15 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 		return 0, errors.New("query hit hard limit")
 	}
 
+	// The memory quota of the organization is reserved first. A query
+	// limited by it is only given what it wants so the memory of the
+	// organization is not taken by a single query.
+	if !q.q.c.quotas.reserveMemory(q.q, want) {
+		q.q.exceedQuota(QuotaMemoryBytes)
+		return 0, errors.New("query hit organization memory quota")
+	}
+	orgLimited := q.q.quota != nil && q.q.quota.MaxMemoryBytes > 0
+
 	for {
 		unused := int64(math.MaxInt64)
 		if !q.m.unlimited {
@@ -84,6 +108,7 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 			if unused < want {
 				// We do not have the capacity for this query to
 				// be given more memory.
+				q.q.c.quotas.releaseMemory(q.q, want)
 				return 0, errors.New("not enough capacity")
 			}
 		}
@@ -92,7 +117,10 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 		// memory it needs, but it will probably ask for more memory
 		// so, if possible, give it more so it isn't repeatedly calling
 		// this method.
-		given := q.giveMemory(want, unused)
+		given := want
+		if !orgLimited {
+			given = q.giveMemory(want, unused)
+		}
 
 		// Reserve this memory for our own use.
 		if !q.m.unlimited {
@@ -107,6 +135,9 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 		// counter for the limit.
 		q.limit += given
 		q.given += given
+		if orgLimited {
+			q.reserved += given
+		}
 		return given, nil
 	}
 }
@@ -152,6 +183,8 @@ func (q *queryMemoryManager) Release() {
 	if !q.m.unlimited {
 		q.m.addUnusedMemoryBytes(q.given)
 	}
+	q.q.c.quotas.releaseMemory(q.q, q.reserved)
 	q.limit = q.m.initialBytesQuotaPerQuery
 	q.given = 0
+	q.reserved = 0
 }
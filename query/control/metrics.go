@@ -4,8 +4,9 @@ import "github.com/prometheus/client_golang/prometheus"
 
 // controllerMetrics holds metrics related to the query controller.
 type controllerMetrics struct {
-	requests  *prometheus.CounterVec
-	functions *prometheus.CounterVec
+	requests        *prometheus.CounterVec
+	functions       *prometheus.CounterVec
+	quotaRejections *prometheus.CounterVec
 
 	all          *prometheus.GaugeVec
 	compiling    *prometheus.GaugeVec
@@ -26,6 +27,7 @@ const (
 	labelCompileError = requestsLabel("compile_error")
 	labelRuntimeError = requestsLabel("runtime_error")
 	labelQueueError   = requestsLabel("queue_error")
+	labelQuotaError   = requestsLabel("quota_error")
 )
 
 func newControllerMetrics(labels []string) *controllerMetrics {
@@ -49,6 +51,13 @@ func newControllerMetrics(labels []string) *controllerMetrics {
 			Help:      "Count of functions in queries",
 		}, append(labels, "function")),
 
+		quotaRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "quota_rejections_total",
+			Help:      "Count of the queries rejected for exceeding the query quota of their organization",
+		}, append(labels, "quota")),
+
 		all: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
@@ -123,6 +132,7 @@ func (cm *controllerMetrics) PrometheusCollectors() []prometheus.Collector {
 	return []prometheus.Collector{
 		cm.requests,
 		cm.functions,
+		cm.quotaRejections,
 
 		cm.all,
 		cm.compiling,
@@ -0,0 +1,128 @@
+package control
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/query/influxql"
+)
+
+var _ influxdb.RunningQueryService = (*Controller)(nil)
+
+// ErrRunningQueryNotFound is used when the query is not running.
+var ErrRunningQueryNotFound = &influxdb.Error{
+	Code: influxdb.ENotFound,
+	Msg:  "running query not found",
+}
+
+// FindRunningQueries returns the queries compiling, queueing or executing
+// which match the filter, sorted by ID.
+func (c *Controller) FindRunningQueries(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+	now := time.Now()
+	rqs := make([]*influxdb.RunningQuery, 0)
+	for _, q := range c.Queries() {
+		rq, ok := q.runningQuery(now)
+		if !ok {
+			continue
+		}
+		if filter.OrgID != nil && rq.OrgID != *filter.OrgID {
+			continue
+		}
+		rqs = append(rqs, rq)
+	}
+	sort.Slice(rqs, func(i, j int) bool {
+		return rqs[i].ID < rqs[j].ID
+	})
+	return rqs, nil
+}
+
+// FindRunningQueryByID returns the query compiling, queueing or executing
+// with the ID.
+func (c *Controller) FindRunningQueryByID(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+	q, ok := c.findQuery(id)
+	if !ok {
+		return nil, ErrRunningQueryNotFound
+	}
+	rq, ok := q.runningQuery(time.Now())
+	if !ok {
+		return nil, ErrRunningQueryNotFound
+	}
+	return rq, nil
+}
+
+// CancelRunningQuery cancels the query compiling, queueing or executing
+// with the ID. The query still has to be released by whoever submitted it.
+func (c *Controller) CancelRunningQuery(ctx context.Context, id influxdb.ID) error {
+	q, ok := c.findQuery(id)
+	if !ok || isFinishedState(q.State()) {
+		return ErrRunningQueryNotFound
+	}
+	q.Cancel()
+	return nil
+}
+
+func (c *Controller) findQuery(id influxdb.ID) (*Query, bool) {
+	c.queriesMu.RLock()
+	defer c.queriesMu.RUnlock()
+	q, ok := c.queries[QueryID(id)]
+	return q, ok
+}
+
+// runningQuery describes the query, reporting false once it has finished.
+func (q *Query) runningQuery(now time.Time) (*influxdb.RunningQuery, bool) {
+	state := q.State()
+	if isFinishedState(state) {
+		return nil, false
+	}
+
+	rq := &influxdb.RunningQuery{
+		ID:          influxdb.ID(q.id),
+		State:       state.String(),
+		CreatedAt:   q.createdAt,
+		Elapsed:     influxdb.Duration{Duration: now.Sub(q.createdAt)},
+		MemoryBytes: q.allocatedMemory(),
+	}
+	if req := query.RequestFromContext(q.parentCtx); req != nil {
+		rq.OrgID = req.OrganizationID
+		if req.Authorization != nil {
+			rq.UserID = req.Authorization.UserID
+			rq.AuthorizationID = req.Authorization.ID
+		}
+		if req.Compiler != nil {
+			rq.CompilerType = string(req.Compiler.CompilerType())
+			rq.Query = queryText(req.Compiler)
+		}
+	}
+	return rq, true
+}
+
+// allocatedMemory returns the memory the query allocates while it executes.
+func (q *Query) allocatedMemory() int64 {
+	q.stateMu.RLock()
+	alloc := q.alloc
+	q.stateMu.RUnlock()
+	if alloc == nil {
+		return 0
+	}
+	return alloc.Allocated()
+}
+
+// queryText returns the text of the query the compiler compiles, or an empty
+// string if it does not compile a text.
+func queryText(compiler flux.Compiler) string {
+	switch c := compiler.(type) {
+	case lang.FluxCompiler:
+		return c.Query
+	case *lang.FluxCompiler:
+		return c.Query
+	case *influxql.Compiler:
+		return c.Query
+	default:
+		return ""
+	}
+}
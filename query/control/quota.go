@@ -0,0 +1,260 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// QuotaFinder finds the query quotas of the organizations.
+type QuotaFinder interface {
+	FindQueryQuota(ctx context.Context, orgID influxdb.ID) (*influxdb.QueryQuota, error)
+}
+
+// QuotaLimit is a limit of a query quota.
+type QuotaLimit string
+
+const (
+	// QuotaQueueSize is the limit of the queries of an organization
+	// waiting to execute.
+	QuotaQueueSize QuotaLimit = "queue_size"
+	// QuotaMemoryBytes is the limit of the memory of the executing queries
+	// of an organization.
+	QuotaMemoryBytes QuotaLimit = "memory_bytes"
+	// QuotaExecutionTime is the limit of the time a query of an
+	// organization executes for.
+	QuotaExecutionTime QuotaLimit = "execution_time"
+)
+
+// QuotaExceededError is the error of a query stopped or rejected because it
+// exceeded a limit of the query quota of its organization.
+type QuotaExceededError struct {
+	OrgID influxdb.ID
+	Limit QuotaLimit
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("query quota %s of organization %s exceeded", e.Limit, e.OrgID)
+}
+
+// IsQuotaExceeded returns the QuotaExceededError err wraps, if any.
+func IsQuotaExceeded(err error) (*QuotaExceededError, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *QuotaExceededError:
+			return e, true
+		case *influxdb.Error:
+			err = e.Err
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func quotaExceededError(orgID influxdb.ID, limit QuotaLimit) error {
+	qerr := &QuotaExceededError{OrgID: orgID, Limit: limit}
+	return &influxdb.Error{
+		Code: influxdb.ETooManyRequests,
+		Op:   "query/control",
+		Msg:  "too many queries",
+		Err:  qerr,
+	}
+}
+
+// findQueryQuota returns the query quota of the organization, or nil if its
+// queries are unlimited.
+func (c *Controller) findQueryQuota(ctx context.Context, orgID influxdb.ID) (*influxdb.QueryQuota, error) {
+	if c.config.QuotaFinder == nil || !orgID.Valid() {
+		return nil, nil
+	}
+	quota, err := c.config.QuotaFinder.FindQueryQuota(ctx, orgID)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.ErrorCode(err),
+			Op:   "query/control",
+			Msg:  "failed to find query quota",
+			Err:  err,
+		}
+	}
+	if quota.Unlimited() {
+		return nil, nil
+	}
+	return quota, nil
+}
+
+// orgQueries holds the queries of an organization limited by a query quota.
+type orgQueries struct {
+	// queued is the number of queries waiting to execute, including the
+	// waiting ones.
+	queued int
+	// executing is the number of queries executing.
+	executing int
+	// memoryBytes is the memory given to the executing queries.
+	memoryBytes int64
+	// waiting are the queries taken off the queue which wait for
+	// the executing queries to make room for them.
+	waiting []*Query
+}
+
+func (o *orgQueries) empty() bool {
+	return o.queued == 0 && o.executing == 0 && o.memoryBytes == 0 && len(o.waiting) == 0
+}
+
+// quotaManager enforces the query quotas of the organizations.
+type quotaManager struct {
+	mu   sync.Mutex
+	orgs map[influxdb.ID]*orgQueries
+}
+
+func newQuotaManager() *quotaManager {
+	return &quotaManager{
+		orgs: make(map[influxdb.ID]*orgQueries),
+	}
+}
+
+// org returns the queries of the organization. It must be called with a lock.
+func (m *quotaManager) org(orgID influxdb.ID) *orgQueries {
+	o, ok := m.orgs[orgID]
+	if !ok {
+		o = &orgQueries{}
+		m.orgs[orgID] = o
+	}
+	return o
+}
+
+// release forgets the organization once it has no queries left. It must be
+// called with a lock.
+func (m *quotaManager) release(orgID influxdb.ID, o *orgQueries) {
+	if o.empty() {
+		delete(m.orgs, orgID)
+	}
+}
+
+// reserveQueue reserves a place in the queue for the query, reporting
+// whether the queue of its organization has room for it.
+func (m *quotaManager) reserveQueue(q *Query) bool {
+	if q.quota == nil {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o := m.org(q.quota.OrgID)
+	if q.quota.MaxQueueSize > 0 && o.queued >= q.quota.MaxQueueSize {
+		m.release(q.quota.OrgID, o)
+		return false
+	}
+	o.queued++
+	return true
+}
+
+// releaseQueue gives back the place in the queue of a query which will not
+// execute.
+func (m *quotaManager) releaseQueue(q *Query) {
+	if q.quota == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o := m.org(q.quota.OrgID)
+	o.queued--
+	m.release(q.quota.OrgID, o)
+}
+
+// startExecuting reports whether the query taken off the queue may execute.
+// If its organization already executes as many queries as it is allowed to,
+// the query waits for one of them to finish.
+func (m *quotaManager) startExecuting(q *Query) bool {
+	if q.quota == nil {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o := m.org(q.quota.OrgID)
+	if q.quota.MaxConcurrency > 0 && o.executing >= q.quota.MaxConcurrency {
+		o.waiting = append(o.waiting, q)
+		return false
+	}
+	o.queued--
+	o.executing++
+	return true
+}
+
+// finishExecuting marks the query as finished executing and returns the
+// next query of its organization waiting to execute, if any.
+func (m *quotaManager) finishExecuting(q *Query) *Query {
+	if q.quota == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o := m.org(q.quota.OrgID)
+	o.executing--
+	if len(o.waiting) > 0 {
+		next := o.waiting[0]
+		o.waiting[0] = nil
+		o.waiting = o.waiting[1:]
+		o.queued--
+		o.executing++
+		return next
+	}
+	m.release(q.quota.OrgID, o)
+	return nil
+}
+
+// reserveInitialMemory reserves up to bytes of memory for the query to
+// start with and returns the memory reserved.
+func (m *quotaManager) reserveInitialMemory(q *Query, bytes int64) int64 {
+	if q.quota == nil || q.quota.MaxMemoryBytes == 0 {
+		return bytes
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o := m.org(q.quota.OrgID)
+	if available := q.quota.MaxMemoryBytes - o.memoryBytes; bytes > available {
+		bytes = available
+	}
+	if bytes < 0 {
+		bytes = 0
+	}
+	o.memoryBytes += bytes
+	return bytes
+}
+
+// reserveMemory reserves memory for the query, reporting whether its
+// organization may be given that much memory.
+func (m *quotaManager) reserveMemory(q *Query, bytes int64) bool {
+	if q.quota == nil || q.quota.MaxMemoryBytes == 0 {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o := m.org(q.quota.OrgID)
+	if o.memoryBytes+bytes > q.quota.MaxMemoryBytes {
+		m.release(q.quota.OrgID, o)
+		return false
+	}
+	o.memoryBytes += bytes
+	return true
+}
+
+// releaseMemory gives back the memory reserved for the query.
+func (m *quotaManager) releaseMemory(q *Query, bytes int64) {
+	if q.quota == nil || q.quota.MaxMemoryBytes == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o := m.org(q.quota.OrgID)
+	o.memoryBytes -= bytes
+	m.release(q.quota.OrgID, o)
+}
@@ -1,10 +1,13 @@
 package influxql
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/flux"
@@ -13,7 +16,12 @@ import (
 )
 
 // MultiResultEncoder encodes results as InfluxQL JSON format.
-type MultiResultEncoder struct{}
+type MultiResultEncoder struct {
+	// Encoding is the output format of the response; defaults to JSON.
+	Encoding EncodingFormat
+	// TimeFormat is the format of the timestamps; defaults to RFC3339Nano.
+	TimeFormat TimeFormat
+}
 
 // Encode writes a collection of results to the influxdb 1.X http response format.
 // Expectations/Assumptions:
@@ -28,9 +36,26 @@ type MultiResultEncoder struct{}
 //      TODO(jsternberg): This function currently requires the first column to be a time field, but this isn't
 //      a strict requirement and will be lifted when we begin to work on transpiling meta queries.
 func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
-	resp := Response{}
+	resp := e.response(results)
 	wc := &iocounter.Writer{Writer: w}
 
+	var err error
+	switch e.Encoding {
+	case JSONPretty:
+		enc := json.NewEncoder(wc)
+		enc.SetIndent("", "    ")
+		err = enc.Encode(resp)
+	case CSV:
+		err = encodeCSV(wc, resp)
+	default:
+		err = json.NewEncoder(wc).Encode(resp)
+	}
+	return wc.Count(), err
+}
+
+func (e *MultiResultEncoder) response(results flux.ResultIterator) Response {
+	resp := Response{}
+
 	for results.More() {
 		res := results.Next()
 		name := res.Name()
@@ -154,7 +179,7 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 						vs := cr.Times(idx)
 						for i := 0; i < vs.Len(); i++ {
 							if vs.IsValid(i) {
-								values[i][j] = execute.Time(vs.Value(i)).Time().Format(time.RFC3339Nano)
+								values[i][j] = e.formatTime(execute.Time(vs.Value(i)).Time())
 							}
 						}
 					default:
@@ -181,9 +206,123 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 	if err := results.Err(); err != nil && resp.Err == "" {
 		resp.error(err)
 	}
+	return resp
+}
 
-	err := json.NewEncoder(wc).Encode(resp)
-	return wc.Count(), err
+// formatTime converts a timestamp to the representation requested by the TimeFormat.
+func (e *MultiResultEncoder) formatTime(t time.Time) interface{} {
+	switch e.TimeFormat {
+	case Hour:
+		return t.UnixNano() / int64(time.Hour)
+	case Minute:
+		return t.UnixNano() / int64(time.Minute)
+	case Second:
+		return t.UnixNano() / int64(time.Second)
+	case Millisecond:
+		return t.UnixNano() / int64(time.Millisecond)
+	case Microsecond:
+		return t.UnixNano() / int64(time.Microsecond)
+	case Nanosecond:
+		return t.UnixNano()
+	default:
+		return t.Format(time.RFC3339Nano)
+	}
+}
+
+// encodeCSV writes the response in the influxdb 1.X CSV format.
+// Each series is preceded by a header of the form "name,tags,<columns...>"
+// whenever its columns differ from the previous series.
+func encodeCSV(w io.Writer, resp Response) error {
+	cw := csv.NewWriter(w)
+	if resp.Err != "" {
+		_ = cw.Write([]string{"error"})
+		_ = cw.Write([]string{resp.Err})
+		cw.Flush()
+		return cw.Error()
+	}
+
+	var columns []string
+	for _, result := range resp.Results {
+		if result.Err != "" {
+			_ = cw.Write([]string{"error"})
+			_ = cw.Write([]string{result.Err})
+			continue
+		}
+		for _, row := range result.Series {
+			if !stringsEqual(columns, row.Columns) {
+				if columns != nil {
+					// Separate blocks with different columns by an empty line.
+					_ = cw.Write(nil)
+				}
+				columns = row.Columns
+				_ = cw.Write(append([]string{"name", "tags"}, columns...))
+			}
+
+			tags := formatTags(row.Tags)
+			for _, values := range row.Values {
+				record := make([]string, 0, len(values)+2)
+				record = append(record, row.Name, tags)
+				for _, v := range values {
+					record = append(record, formatCSVValue(v))
+				}
+				if err := cw.Write(record); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(tags[k])
+	}
+	return b.String()
+}
+
+func formatCSVValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) || (a == nil) != (b == nil) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 func NewMultiResultEncoder() *MultiResultEncoder {
 	return new(MultiResultEncoder)
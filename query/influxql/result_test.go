@@ -106,6 +106,47 @@ func TestMultiResultEncoder_Encode(t *testing.T) {
 	}
 }
 
+func TestMultiResultEncoder_EncodeCSV(t *testing.T) {
+	in := flux.NewSliceResultIterator(
+		[]flux.Result{&executetest.Result{
+			Nm: "0",
+			Tbls: []*executetest.Table{{
+				KeyCols: []string{"_measurement", "host", "region"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "region", Type: flux.TString},
+					{Label: "value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{ts("2018-05-24T09:00:00Z"), "m0", "server01", "west", float64(2)},
+					{ts("2018-05-24T09:00:10Z"), "m0", "server01", "west", float64(2.5)},
+				},
+			}},
+		}},
+	)
+	out := "name,tags,time,value\n" +
+		"m0,\"host=server01,region=west\",1527152400,2\n" +
+		"m0,\"host=server01,region=west\",1527152410,2.5\n"
+
+	var buf bytes.Buffer
+	enc := &influxql.MultiResultEncoder{
+		Encoding:   influxql.CSV,
+		TimeFormat: influxql.Second,
+	}
+	n, err := enc.Encode(&buf, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, exp := buf.String(), out; got != exp {
+		t.Fatalf("unexpected output:\nexp=%s\ngot=%s", exp, got)
+	}
+	if g, w := n, int64(len(out)); g != w {
+		t.Errorf("unexpected encoding count -want/+got:\n%s", cmp.Diff(w, g))
+	}
+}
+
 type resultErrorIterator struct {
 	Error string
 }
@@ -0,0 +1,377 @@
+package promql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+const (
+	// metricNameLabel is the label of the metric name of a series, which is
+	// the measurement of its samples.
+	metricNameLabel = "__name__"
+	// valueField is the field of the samples.
+	valueField = "value"
+)
+
+// FluxOptions are the options of the transpilation of a PromQL query to a
+// Flux script.
+type FluxOptions struct {
+	// BucketID is the bucket of the samples.
+	BucketID influxdb.ID
+	// Start and Stop are the first and the last evaluation times of the
+	// query, it is evaluated at Stop only when Step is zero.
+	Start time.Time
+	Stop  time.Time
+	Step  time.Duration
+	// Lookback is how far back an instant vector selector looks for the
+	// latest sample of a series.
+	Lookback time.Duration
+}
+
+// Transpile parses the PromQL query and transpiles it to a Flux script reading
+// the samples written by Prometheus remote writes, a measurement per metric
+// with the labels as tags and a "value" field. It returns the script and the
+// type of its result, influxdb.PromQLVector or influxdb.PromQLMatrix.
+//
+// The results of a vector are timestamped with the time their series were
+// selected at, and the ones of a matrix evaluated at every step are
+// timestamped with their step.
+func Transpile(promql string, opts FluxOptions) (string, string, error) {
+	parsed, err := ParsePromQL(promql)
+	if err != nil {
+		return "", "", err
+	}
+	if opts.Lookback <= 0 {
+		opts.Lookback = influxdb.DefaultPromQLLookback
+	}
+	if opts.Step > 0 {
+		// Align the last evaluation on the steps from the start.
+		opts.Stop = opts.Start.Add(opts.Stop.Sub(opts.Start) / opts.Step * opts.Step)
+	}
+
+	switch q := parsed.(type) {
+	case *Selector:
+		return q.flux(opts)
+	case *AggregateExpr:
+		return q.flux(opts)
+	default:
+		return "", "", fmt.Errorf("unable to evaluate %q", promql)
+	}
+}
+
+// TranspileRead transpiles the read of the raw samples of the series of the
+// bucket matching all the matchers between start and stop to a Flux script.
+func TranspileRead(bucketID influxdb.ID, matchers []*LabelMatcher, start, stop time.Time) (string, error) {
+	var b strings.Builder
+	if err := fluxSelect(&b, bucketID, "", matchers, start, stop.Add(time.Nanosecond)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// fluxSelect writes the read of the samples of the series of the metric, any
+// metric when name is empty, matching the matchers between start and stop.
+func fluxSelect(b *strings.Builder, bucketID influxdb.ID, name string, matchers []*LabelMatcher, start, stop time.Time) error {
+	predicates := []string{fmt.Sprintf("r._field == %s", fluxString(valueField))}
+	if name != "" {
+		predicates = append(predicates, fmt.Sprintf("r._measurement == %s", fluxString(name)))
+	}
+	for _, m := range matchers {
+		p, err := m.fluxPredicate()
+		if err != nil {
+			return err
+		}
+		predicates = append(predicates, p)
+	}
+
+	fmt.Fprintf(b, "from(bucketID: %s)\n", fluxString(bucketID.String()))
+	fmt.Fprintf(b, "  |> range(start: %s, stop: %s)\n", start.UTC().Format(time.RFC3339Nano), stop.UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(b, "  |> filter(fn: (r) => %s)\n", strings.Join(predicates, " and "))
+	return nil
+}
+
+// fluxPredicate returns the predicate of the rows of the series matching m.
+func (m *LabelMatcher) fluxPredicate() (string, error) {
+	var value string
+	switch v := m.Value.Value().(type) {
+	case string:
+		value = v
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return "", fmt.Errorf("unsupported value of label matcher %s", m.Name)
+	}
+
+	column := m.Name
+	if column == metricNameLabel {
+		column = "_measurement"
+	}
+	ref := "r[" + fluxString(column) + "]"
+
+	switch m.Kind {
+	case Equal:
+		return ref + " == " + fluxString(value), nil
+	case NotEqual:
+		return ref + " != " + fluxString(value), nil
+	case RegexMatch, RegexNoMatch:
+		// Prometheus regular expressions are fully anchored.
+		re := "^(?:" + value + ")$"
+		if _, err := regexp.Compile(re); err != nil {
+			return "", fmt.Errorf("invalid regular expression of label matcher %s: %v", m.Name, err)
+		}
+		op := " =~ "
+		if m.Kind == RegexNoMatch {
+			op = " !~ "
+		}
+		return ref + op + fluxRegexp(re), nil
+	default:
+		return "", fmt.Errorf("unknown label match kind %d", m.Kind)
+	}
+}
+
+func (s *Selector) flux(opts FluxOptions) (string, string, error) {
+	var b strings.Builder
+	if s.Range > 0 {
+		// A range vector is the samples of the series in the range before the
+		// evaluation time, which Prometheus evaluates at a single time. The
+		// samples keep their own times, offset or not.
+		if opts.Step > 0 {
+			return "", "", fmt.Errorf("invalid expression type \"range vector\" for range query, must be instant vector")
+		}
+		stop := opts.Stop.Add(-s.Offset)
+		if err := fluxSelect(&b, opts.BucketID, s.Name, s.LabelMatchers, stop.Add(-s.Range+time.Nanosecond), stop.Add(time.Nanosecond)); err != nil {
+			return "", "", err
+		}
+		return b.String(), influxdb.PromQLMatrix, nil
+	}
+
+	if err := s.fluxInstant(&b, opts); err != nil {
+		return "", "", err
+	}
+	return b.String(), resultType(opts), nil
+}
+
+// fluxInstant writes the selection of the latest sample of every series
+// within the lookback of every evaluation time.
+func (s *Selector) fluxInstant(b *strings.Builder, opts FluxOptions) error {
+	start := opts.Start
+	if opts.Step == 0 {
+		start = opts.Stop
+	}
+	// The lookback of an evaluation time t is (t - lookback, t].
+	first := start.Add(-s.Offset - opts.Lookback + time.Nanosecond)
+	last := opts.Stop.Add(-s.Offset + time.Nanosecond)
+	if opts.Step == 0 {
+		if err := fluxSelect(b, opts.BucketID, s.Name, s.LabelMatchers, first, last); err != nil {
+			return err
+		}
+		b.WriteString("  |> last()\n")
+		b.WriteString("  |> drop(columns: [\"_time\"])\n")
+		return nil
+	}
+
+	// The range is read a lookback past the last evaluation so that the
+	// windows of the evaluations are not truncated by its bounds.
+	if err := fluxSelect(b, opts.BucketID, s.Name, s.LabelMatchers, first, last.Add(opts.Lookback)); err != nil {
+		return err
+	}
+	// Shift the samples so that the windows [t - lookback, t) stopping at the
+	// evaluation times hold the samples of their lookback, then keep the
+	// latest sample of every window at the time of its evaluation.
+	fmt.Fprintf(b, "  |> timeShift(duration: %s)\n", fluxDuration(s.Offset-time.Nanosecond))
+	offset := start.Add(-opts.Lookback).UnixNano() % int64(opts.Step)
+	if offset < 0 {
+		offset += int64(opts.Step)
+	}
+	fmt.Fprintf(b, "  |> window(every: %s, period: %s, offset: %s, createEmpty: false)\n",
+		fluxDuration(opts.Step), fluxDuration(opts.Lookback), fluxDuration(time.Duration(offset)))
+	b.WriteString("  |> last()\n")
+	b.WriteString("  |> duplicate(column: \"_stop\", as: \"_time\")\n")
+	fmt.Fprintf(b, "  |> filter(fn: (r) => r._time >= %s and r._time <= %s)\n",
+		start.UTC().Format(time.RFC3339Nano), opts.Stop.UTC().Format(time.RFC3339Nano))
+	b.WriteString("  |> window(every: inf)\n")
+	return nil
+}
+
+func (a *AggregateExpr) flux(opts FluxOptions) (string, string, error) {
+	if a.Selector.Range > 0 {
+		return "", "", fmt.Errorf("expected type instant vector in aggregation expression, got range vector")
+	}
+
+	var b strings.Builder
+	if err := a.Selector.fluxInstant(&b, opts); err != nil {
+		return "", "", err
+	}
+
+	// The samples are aggregated by evaluation time, the _time column of the
+	// range queries, and regrouped by series once aggregated.
+	var labels []string
+	if a.Aggregate != nil {
+		for _, l := range a.Aggregate.Labels {
+			if l.Name == metricNameLabel {
+				labels = append(labels, "_measurement")
+			} else {
+				labels = append(labels, l.Name)
+			}
+		}
+	}
+	group := func(byTime bool) string {
+		if a.Aggregate != nil && a.Aggregate.Without {
+			except := append([]string{"_value", "_start", "_stop", "_measurement", "_field"}, labels...)
+			if !byTime {
+				except = append(except, "_time")
+			}
+			return fmt.Sprintf("  |> group(columns: %s, mode: \"except\")\n", fluxStrings(except))
+		}
+		columns := labels
+		if byTime {
+			columns = append([]string{"_time"}, labels...)
+		}
+		return fmt.Sprintf("  |> group(columns: %s)\n", fluxStrings(columns))
+	}
+
+	var fn string
+	switch a.Op.Kind {
+	case TopKind, BottomKind:
+		k, ok := a.Op.number()
+		if !ok || k < 1 {
+			return "", "", fmt.Errorf("invalid parameter of %s", opName(a.Op.Kind))
+		}
+		fn = "top"
+		if a.Op.Kind == BottomKind {
+			fn = "bottom"
+		}
+		// topk and bottomk keep the series they select.
+		b.WriteString(group(opts.Step > 0))
+		fmt.Fprintf(&b, "  |> %s(n: %d)\n", fn, int64(k))
+		fmt.Fprintf(&b, "  |> group(columns: %s, mode: \"except\")\n", fluxStrings([]string{"_value", "_start", "_stop", "_time"}))
+		return b.String(), resultType(opts), nil
+	case SumKind:
+		fn = "sum()"
+	case CountKind:
+		fn = "count()"
+	case MinKind:
+		fn = "min()"
+	case MaxKind:
+		fn = "max()"
+	case AvgKind:
+		fn = "mean()"
+	case StdevKind, StdVarKind:
+		fn = `stddev(mode: "population")`
+	case QuantileKind:
+		q, ok := a.Op.number()
+		if !ok {
+			return "", "", fmt.Errorf("invalid parameter of quantile")
+		}
+		fn = fmt.Sprintf(`quantile(q: %s, method: "exact_mean")`, strconv.FormatFloat(q, 'f', -1, 64))
+	default:
+		return "", "", fmt.Errorf("unable to evaluate %s", opName(a.Op.Kind))
+	}
+
+	b.WriteString(group(opts.Step > 0))
+	fmt.Fprintf(&b, "  |> %s\n", fn)
+	if a.Op.Kind == StdVarKind {
+		b.WriteString("  |> map(fn: (r) => ({r with _value: r._value * r._value}))\n")
+	}
+	if opts.Step > 0 {
+		b.WriteString(group(false))
+	}
+	return b.String(), resultType(opts), nil
+}
+
+// number returns the number parameter of the operator, if any.
+func (o *Operator) number() (float64, bool) {
+	if o.Arg == nil {
+		return 0, false
+	}
+	n, ok := o.Arg.Value().(float64)
+	return n, ok
+}
+
+// resultType returns the type of the result of an instant vector expression.
+func resultType(opts FluxOptions) string {
+	if opts.Step > 0 {
+		return influxdb.PromQLMatrix
+	}
+	return influxdb.PromQLVector
+}
+
+func opName(kind OperatorKind) string {
+	switch kind {
+	case CountValuesKind:
+		return "count_values"
+	case TopKind:
+		return "topk"
+	case BottomKind:
+		return "bottomk"
+	case QuantileKind:
+		return "quantile"
+	case SumKind:
+		return "sum"
+	case MinKind:
+		return "min"
+	case MaxKind:
+		return "max"
+	case AvgKind:
+		return "avg"
+	case StdevKind:
+		return "stddev"
+	case StdVarKind:
+		return "stdvar"
+	case CountKind:
+		return "count"
+	default:
+		return "unknown operator"
+	}
+}
+
+// fluxString returns s as a Flux string literal.
+func fluxString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "${", `\${`)
+	return `"` + s + `"`
+}
+
+// fluxRegexp returns re as a Flux regular expression literal. Flux only
+// unescapes the slashes of the literal, so the slashes of re are escaped
+// unless they already are, and its other escapes are kept as they are.
+func fluxRegexp(re string) string {
+	var b strings.Builder
+	b.WriteByte('/')
+	for i := 0; i < len(re); i++ {
+		c := re[i]
+		if c == '\\' && i+1 < len(re) {
+			// an escaped slash is a slash, any other escape is kept.
+			i++
+			if c = re[i]; c != '/' {
+				b.WriteByte('\\')
+			}
+		}
+		if c == '/' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte('/')
+	return b.String()
+}
+
+// fluxStrings returns ss as a Flux array of strings.
+func fluxStrings(ss []string) string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = fluxString(s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// fluxDuration returns d as a Flux duration literal.
+func fluxDuration(d time.Duration) string {
+	return strconv.FormatInt(int64(d), 10) + "ns"
+}
@@ -0,0 +1,183 @@
+package promql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestTranspile(t *testing.T) {
+	bucketID := influxdb.ID(0x020f755c3c082000)
+	stop := time.Date(2020, 5, 20, 18, 40, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		promql   string
+		step     time.Duration
+		want     string
+		wantType string
+		wantErr  string
+	}{
+		{
+			name:     "instant vector",
+			promql:   `http_requests_total{job="api", code!="500"}`,
+			wantType: influxdb.PromQLVector,
+			want: `from(bucketID: "020f755c3c082000")
+  |> range(start: 2020-05-20T18:35:00.000000001Z, stop: 2020-05-20T18:40:00.000000001Z)
+  |> filter(fn: (r) => r._field == "value" and r._measurement == "http_requests_total" and r["job"] == "api" and r["code"] != "500")
+  |> last()
+  |> drop(columns: ["_time"])
+`,
+		},
+		{
+			name:     "instant vector over a range",
+			promql:   `http_requests_total{job=~"api|web"}`,
+			step:     time.Minute,
+			wantType: influxdb.PromQLMatrix,
+			want: `from(bucketID: "020f755c3c082000")
+  |> range(start: 2020-05-20T17:35:00.000000001Z, stop: 2020-05-20T18:45:00.000000001Z)
+  |> filter(fn: (r) => r._field == "value" and r._measurement == "http_requests_total" and r["job"] =~ /^(?:api|web)$/)
+  |> timeShift(duration: -1ns)
+  |> window(every: 60000000000ns, period: 300000000000ns, offset: 0ns, createEmpty: false)
+  |> last()
+  |> duplicate(column: "_stop", as: "_time")
+  |> filter(fn: (r) => r._time >= 2020-05-20T17:40:00Z and r._time <= 2020-05-20T18:40:00Z)
+  |> window(every: inf)
+`,
+		},
+		{
+			name:     "range vector",
+			promql:   `http_requests_total[5m] offset 1m`,
+			wantType: influxdb.PromQLMatrix,
+			want: `from(bucketID: "020f755c3c082000")
+  |> range(start: 2020-05-20T18:34:00.000000001Z, stop: 2020-05-20T18:39:00.000000001Z)
+  |> filter(fn: (r) => r._field == "value" and r._measurement == "http_requests_total")
+`,
+		},
+		{
+			name:    "range vector over a range",
+			promql:  `http_requests_total[5m]`,
+			step:    time.Minute,
+			wantErr: `invalid expression type "range vector" for range query, must be instant vector`,
+		},
+		{
+			name:     "sum by",
+			promql:   `sum by (job) (http_requests_total)`,
+			wantType: influxdb.PromQLVector,
+			want: `from(bucketID: "020f755c3c082000")
+  |> range(start: 2020-05-20T18:35:00.000000001Z, stop: 2020-05-20T18:40:00.000000001Z)
+  |> filter(fn: (r) => r._field == "value" and r._measurement == "http_requests_total")
+  |> last()
+  |> drop(columns: ["_time"])
+  |> group(columns: ["job"])
+  |> sum()
+`,
+		},
+		{
+			name:     "avg without over a range",
+			promql:   `avg without (instance) (up)`,
+			step:     time.Minute,
+			wantType: influxdb.PromQLMatrix,
+			want: `from(bucketID: "020f755c3c082000")
+  |> range(start: 2020-05-20T17:35:00.000000001Z, stop: 2020-05-20T18:45:00.000000001Z)
+  |> filter(fn: (r) => r._field == "value" and r._measurement == "up")
+  |> timeShift(duration: -1ns)
+  |> window(every: 60000000000ns, period: 300000000000ns, offset: 0ns, createEmpty: false)
+  |> last()
+  |> duplicate(column: "_stop", as: "_time")
+  |> filter(fn: (r) => r._time >= 2020-05-20T17:40:00Z and r._time <= 2020-05-20T18:40:00Z)
+  |> window(every: inf)
+  |> group(columns: ["_value", "_start", "_stop", "_measurement", "_field", "instance"], mode: "except")
+  |> mean()
+  |> group(columns: ["_value", "_start", "_stop", "_measurement", "_field", "instance", "_time"], mode: "except")
+`,
+		},
+		{
+			name:     "topk",
+			promql:   `topk(3, up)`,
+			wantType: influxdb.PromQLVector,
+			want: `from(bucketID: "020f755c3c082000")
+  |> range(start: 2020-05-20T18:35:00.000000001Z, stop: 2020-05-20T18:40:00.000000001Z)
+  |> filter(fn: (r) => r._field == "value" and r._measurement == "up")
+  |> last()
+  |> drop(columns: ["_time"])
+  |> group(columns: [])
+  |> top(n: 3)
+  |> group(columns: ["_value", "_start", "_stop", "_time"], mode: "except")
+`,
+		},
+		{
+			name:    "invalid regular expression",
+			promql:  `up{job=~"(api"}`,
+			wantErr: "invalid regular expression of label matcher job: error parsing regexp: missing closing ): `^(?:(api)$`",
+		},
+		{
+			name:    "comment",
+			promql:  "# http_requests_total",
+			wantErr: `unable to evaluate "# http_requests_total"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, typ, err := Transpile(tt.promql, FluxOptions{
+				BucketID: bucketID,
+				Start:    stop.Add(-time.Hour),
+				Stop:     stop,
+				Step:     tt.step,
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("unexpected error: got %v want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if typ != tt.wantType {
+				t.Errorf("unexpected result type: got %s want %s", typ, tt.wantType)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected script -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTranspileRead(t *testing.T) {
+	matchers := []*LabelMatcher{
+		{Name: "__name__", Kind: RegexMatch, Value: &StringLiteral{String: "http_.*"}},
+		{Name: "path", Kind: RegexNoMatch, Value: &StringLiteral{String: "/api/.*"}},
+	}
+	got, err := TranspileRead(influxdb.ID(0x020f755c3c082000), matchers,
+		time.Date(2020, 5, 20, 18, 0, 0, 0, time.UTC), time.Date(2020, 5, 20, 19, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `from(bucketID: "020f755c3c082000")
+  |> range(start: 2020-05-20T18:00:00Z, stop: 2020-05-20T19:00:00.000000001Z)
+  |> filter(fn: (r) => r._field == "value" and r["_measurement"] =~ /^(?:http_.*)$/ and r["path"] !~ /^(?:\/api\/.*)$/)
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected script -want/+got:\n%s", diff)
+	}
+}
+
+func TestFluxRegexp(t *testing.T) {
+	tests := []struct {
+		re   string
+		want string
+	}{
+		{re: `^(?:/api/.*)$`, want: `/^(?:\/api\/.*)$/`},
+		{re: `^(?:\/api\/.*)$`, want: `/^(?:\/api\/.*)$/`},
+		{re: `^(?:\d+\\)$`, want: `/^(?:\d+\\)$/`},
+		{re: `^(?:a\\/b)$`, want: `/^(?:a\\\/b)$/`},
+	}
+	for _, tt := range tests {
+		if got := fluxRegexp(tt.re); got != tt.want {
+			t.Errorf("fluxRegexp(%s) = %s, want %s", tt.re, got, tt.want)
+		}
+	}
+}
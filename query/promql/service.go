@@ -0,0 +1,243 @@
+package promql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb/v2"
+	pctx "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+var _ influxdb.PromQLService = (*Service)(nil)
+
+// Service evaluates PromQL queries and Prometheus remote reads by running
+// their transpiled Flux scripts.
+type Service struct {
+	qs query.QueryService
+}
+
+// NewService returns a service running the transpiled queries with qs.
+func NewService(qs query.QueryService) *Service {
+	return &Service{
+		qs: qs,
+	}
+}
+
+// QueryPromQL evaluates the PromQL query against the samples of the bucket.
+// The series of the result are sorted by labels.
+func (s *Service) QueryPromQL(ctx context.Context, orgID, bucketID influxdb.ID, q influxdb.PromQLQuery) (*influxdb.PromQLResult, error) {
+	script, typ, err := Transpile(q.Query, FluxOptions{
+		BucketID: bucketID,
+		Start:    q.Start,
+		Stop:     q.Stop,
+		Step:     q.Step,
+		Lookback: influxdb.DefaultPromQLLookback,
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to evaluate PromQL query",
+			Err:  err,
+		}
+	}
+
+	series, err := s.run(ctx, orgID, script)
+	if err != nil {
+		return nil, err
+	}
+	if typ == influxdb.PromQLVector {
+		// The series of a vector hold their latest sample at the evaluation time.
+		for _, ps := range series {
+			last := ps.Samples[len(ps.Samples)-1]
+			ps.Samples = []influxdb.PromSample{{Time: q.Stop, Value: last.Value}}
+		}
+	}
+	return &influxdb.PromQLResult{Type: typ, Series: series}, nil
+}
+
+// ReadPromSeries returns the samples of the series of the bucket matching all
+// the matchers between start and stop, sorted by labels.
+func (s *Service) ReadPromSeries(ctx context.Context, orgID, bucketID influxdb.ID, matchers []influxdb.PromLabelMatcher, start, stop time.Time) ([]*influxdb.PromSeries, error) {
+	lms := make([]*LabelMatcher, 0, len(matchers))
+	for _, m := range matchers {
+		lm := &LabelMatcher{Name: m.Name, Value: &StringLiteral{String: m.Value}}
+		switch m.Type {
+		case influxdb.PromMatchEqual:
+			lm.Kind = Equal
+		case influxdb.PromMatchNotEqual:
+			lm.Kind = NotEqual
+		case influxdb.PromMatchRegexp:
+			lm.Kind = RegexMatch
+		case influxdb.PromMatchNotRegexp:
+			lm.Kind = RegexNoMatch
+		default:
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("unknown type %d of label matcher %s", m.Type, m.Name),
+			}
+		}
+		lms = append(lms, lm)
+	}
+
+	script, err := TranspileRead(bucketID, lms, start, stop)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to read series",
+			Err:  err,
+		}
+	}
+	return s.run(ctx, orgID, script)
+}
+
+func (s *Service) run(ctx context.Context, orgID influxdb.ID, script string) ([]*influxdb.PromSeries, error) {
+	auth, err := pctx.GetAuthorization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	request := &query.Request{Authorization: auth, OrganizationID: orgID, Compiler: lang.FluxCompiler{Query: script}}
+
+	ittr, err := s.qs.Query(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer ittr.Release()
+
+	sr := seriesReader{series: make(map[string]*influxdb.PromSeries)}
+	for ittr.More() {
+		if err := ittr.Next().Tables().Do(sr.readTable); err != nil {
+			return nil, err
+		}
+	}
+	if err := ittr.Err(); err != nil {
+		return nil, fmt.Errorf("unexpected internal error while evaluating PromQL: %v", err)
+	}
+	return sr.sorted(), nil
+}
+
+// seriesReader reads the samples of the tables into series identified by
+// the labels of the group keys of the tables.
+type seriesReader struct {
+	series map[string]*influxdb.PromSeries
+}
+
+// keyColumns are the columns of the group keys which are not labels.
+var keyColumns = map[string]bool{
+	"_start": true,
+	"_stop":  true,
+	"_time":  true,
+	"_field": true,
+}
+
+func (sr *seriesReader) readTable(tbl flux.Table) error {
+	key := tbl.Key()
+	labels := make(map[string]string)
+	for j, c := range key.Cols() {
+		if c.Type != flux.TString || keyColumns[c.Label] || key.IsNull(j) {
+			continue
+		}
+		if c.Label == "_measurement" {
+			labels[metricNameLabel] = key.ValueString(j)
+		} else {
+			labels[c.Label] = key.ValueString(j)
+		}
+	}
+
+	id := seriesID(labels)
+	ps, ok := sr.series[id]
+	if !ok {
+		ps = &influxdb.PromSeries{Labels: labels}
+	}
+
+	timeIdx, valueIdx := -1, -1
+	for j, c := range tbl.Cols() {
+		switch c.Label {
+		case "_time":
+			timeIdx = j
+		case "_value":
+			valueIdx = j
+		}
+	}
+	if valueIdx < 0 {
+		return tbl.Do(func(flux.ColReader) error { return nil })
+	}
+
+	err := tbl.Do(func(cr flux.ColReader) error {
+		for i := 0; i < cr.Len(); i++ {
+			var sample influxdb.PromSample
+			if timeIdx >= 0 && cr.Times(timeIdx).IsValid(i) {
+				sample.Time = values.Time(cr.Times(timeIdx).Value(i)).Time().UTC()
+			}
+			switch cr.Cols()[valueIdx].Type {
+			case flux.TFloat:
+				if !cr.Floats(valueIdx).IsValid(i) {
+					continue
+				}
+				sample.Value = cr.Floats(valueIdx).Value(i)
+			case flux.TInt:
+				if !cr.Ints(valueIdx).IsValid(i) {
+					continue
+				}
+				sample.Value = float64(cr.Ints(valueIdx).Value(i))
+			case flux.TUInt:
+				if !cr.UInts(valueIdx).IsValid(i) {
+					continue
+				}
+				sample.Value = float64(cr.UInts(valueIdx).Value(i))
+			default:
+				continue
+			}
+			ps.Samples = append(ps.Samples, sample)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !ok && len(ps.Samples) > 0 {
+		sr.series[id] = ps
+	}
+	return nil
+}
+
+// sorted returns the series sorted by labels, with their samples sorted by
+// time.
+func (sr *seriesReader) sorted() []*influxdb.PromSeries {
+	ids := make([]string, 0, len(sr.series))
+	for id := range sr.series {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	series := make([]*influxdb.PromSeries, 0, len(ids))
+	for _, id := range ids {
+		ps := sr.series[id]
+		sort.SliceStable(ps.Samples, func(i, j int) bool {
+			return ps.Samples[i].Time.Before(ps.Samples[j].Time)
+		})
+		series = append(series, ps)
+	}
+	return series
+}
+
+// seriesID returns the identifier of the series of the labels.
+func seriesID(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%q=%q,", k, labels[k])
+	}
+	return b.String()
+}
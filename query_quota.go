@@ -0,0 +1,59 @@
+package influxdb
+
+import "context"
+
+// QueryQuota limits the queries of an organization run by the query
+// controller, on top of the limits the controller holds all the queries to.
+// A limit of 0 means the queries are unlimited.
+type QueryQuota struct {
+	OrgID ID `json:"orgID"`
+
+	// MaxConcurrency is the maximum number of queries of the organization
+	// executing at once, the others waiting in the queue.
+	MaxConcurrency int `json:"maxConcurrency"`
+	// MaxQueueSize is the maximum number of queries of the organization
+	// waiting to execute, the others being rejected.
+	MaxQueueSize int `json:"maxQueueSize"`
+	// MaxMemoryBytes is the maximum memory the executing queries of the
+	// organization allocate altogether.
+	MaxMemoryBytes int64 `json:"maxMemoryBytes"`
+	// MaxExecutionTime is the maximum time a query of the organization
+	// executes for before it is stopped.
+	MaxExecutionTime Duration `json:"maxExecutionTime"`
+}
+
+// Unlimited reports whether the quota sets no limit.
+func (q *QueryQuota) Unlimited() bool {
+	return q.MaxConcurrency == 0 && q.MaxQueueSize == 0 && q.MaxMemoryBytes == 0 && q.MaxExecutionTime.Duration == 0
+}
+
+// Valid returns an error if a limit of the quota is negative.
+func (q *QueryQuota) Valid() error {
+	if !q.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "query quota requires a valid orgID",
+		}
+	}
+	if q.MaxConcurrency < 0 || q.MaxQueueSize < 0 || q.MaxMemoryBytes < 0 || q.MaxExecutionTime.Duration < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "query quota limits must not be negative",
+		}
+	}
+	return nil
+}
+
+// QueryQuotaService manages the query quotas of the organizations.
+type QueryQuotaService interface {
+	// FindQueryQuota returns the query quota of the organization, without
+	// limits if none was set.
+	FindQueryQuota(ctx context.Context, orgID ID) (*QueryQuota, error)
+
+	// SetQueryQuota sets the query quota of the organization of q.
+	SetQueryQuota(ctx context.Context, q *QueryQuota) error
+
+	// DeleteQueryQuota removes the query quota of the organization, lifting
+	// its limits.
+	DeleteQueryQuota(ctx context.Context, orgID ID) error
+}
@@ -0,0 +1,152 @@
+package influxdb
+
+import (
+	"context"
+	"net/url"
+)
+
+// DefaultReplicationMaxQueueSizeBytes is the maximum size of the queue of a
+// replication that does not set one.
+const DefaultReplicationMaxQueueSizeBytes = 64 * 1024 * 1024
+
+// Replication forwards the data written to a local bucket to a bucket of a
+// remote InfluxDB instance. Writes to the local bucket are queued on disk and
+// sent to the remote instance in the background, so that they survive
+// restarts and outages of the remote instance.
+type Replication struct {
+	ID          ID     `json:"id,omitempty"`
+	OrgID       ID     `json:"orgID,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// LocalBucketID is the bucket whose writes are replicated.
+	LocalBucketID ID `json:"localBucketID"`
+
+	// RemoteURL is the base URL of the remote instance, and RemoteToken the
+	// token its writes are authorized with.
+	RemoteURL   string `json:"remoteURL"`
+	RemoteToken string `json:"remoteToken,omitempty"`
+
+	// RemoteOrgID and RemoteBucketID identify the bucket of the remote
+	// instance the writes are replicated to.
+	RemoteOrgID    ID `json:"remoteOrgID"`
+	RemoteBucketID ID `json:"remoteBucketID"`
+
+	// MaxQueueSizeBytes is the maximum size of the writes queued for the
+	// remote instance. Writes to the local bucket are rejected once the queue
+	// is full.
+	MaxQueueSizeBytes int64 `json:"maxQueueSizeBytes"`
+
+	// DropNonRetryableData drops the writes the remote instance rejects as
+	// invalid, rather than retrying them until the replication is updated.
+	DropNonRetryableData bool `json:"dropNonRetryableData"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the replication is missing a required field or
+// has an invalid remote URL.
+func (r *Replication) Valid() error {
+	if r.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication name is required",
+		}
+	}
+	if !r.OrgID.Valid() || !r.LocalBucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication requires a valid orgID and localBucketID",
+		}
+	}
+	if !r.RemoteOrgID.Valid() || !r.RemoteBucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication requires a valid remoteOrgID and remoteBucketID",
+		}
+	}
+	u, err := url.Parse(r.RemoteURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication remoteURL must be an http or https URL",
+			Err:  err,
+		}
+	}
+	if r.MaxQueueSizeBytes < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "replication maxQueueSizeBytes must not be negative",
+		}
+	}
+	return nil
+}
+
+// ReplicationUpdate is the set of fields of a replication to update. The
+// local bucket of a replication cannot change.
+type ReplicationUpdate struct {
+	Name                 *string `json:"name,omitempty"`
+	Description          *string `json:"description,omitempty"`
+	RemoteURL            *string `json:"remoteURL,omitempty"`
+	RemoteToken          *string `json:"remoteToken,omitempty"`
+	RemoteOrgID          *ID     `json:"remoteOrgID,omitempty"`
+	RemoteBucketID       *ID     `json:"remoteBucketID,omitempty"`
+	MaxQueueSizeBytes    *int64  `json:"maxQueueSizeBytes,omitempty"`
+	DropNonRetryableData *bool   `json:"dropNonRetryableData,omitempty"`
+}
+
+// Apply applies the update to the replication.
+func (u ReplicationUpdate) Apply(r *Replication) {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.RemoteURL != nil {
+		r.RemoteURL = *u.RemoteURL
+	}
+	if u.RemoteToken != nil {
+		r.RemoteToken = *u.RemoteToken
+	}
+	if u.RemoteOrgID != nil {
+		r.RemoteOrgID = *u.RemoteOrgID
+	}
+	if u.RemoteBucketID != nil {
+		r.RemoteBucketID = *u.RemoteBucketID
+	}
+	if u.MaxQueueSizeBytes != nil {
+		r.MaxQueueSizeBytes = *u.MaxQueueSizeBytes
+	}
+	if u.DropNonRetryableData != nil {
+		r.DropNonRetryableData = *u.DropNonRetryableData
+	}
+}
+
+// ReplicationFilter represents a set of filters that restrict the returned
+// replications.
+type ReplicationFilter struct {
+	OrgID         *ID
+	LocalBucketID *ID
+}
+
+// ReplicationService represents a service for managing the replications of
+// buckets to remote instances.
+type ReplicationService interface {
+	// FindReplicationByID returns a single replication by ID.
+	FindReplicationByID(ctx context.Context, id ID) (*Replication, error)
+
+	// FindReplications returns the replications matching the filter.
+	FindReplications(ctx context.Context, filter ReplicationFilter) ([]*Replication, error)
+
+	// CreateReplication creates a new replication and sets r.ID with the new
+	// identifier.
+	CreateReplication(ctx context.Context, r *Replication) error
+
+	// UpdateReplication updates a single replication with the changeset.
+	UpdateReplication(ctx context.Context, id ID, upd ReplicationUpdate) (*Replication, error)
+
+	// DeleteReplication removes a replication by ID, dropping the writes
+	// queued for it.
+	DeleteReplication(ctx context.Context, id ID) error
+}
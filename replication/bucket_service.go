@@ -0,0 +1,45 @@
+package replication
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"go.uber.org/zap"
+)
+
+// BucketService wraps an influxdb.BucketService to delete the replications of
+// deleted buckets.
+type BucketService struct {
+	influxdb.BucketService
+	Logger             *zap.Logger
+	ReplicationService influxdb.ReplicationService
+}
+
+func NewBucketService(logger *zap.Logger, bucketService influxdb.BucketService, replicationService influxdb.ReplicationService) *BucketService {
+	return &BucketService{
+		Logger:             logger,
+		BucketService:      bucketService,
+		ReplicationService: replicationService,
+	}
+}
+
+func (s *BucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error {
+	if err := s.BucketService.DeleteBucket(ctx, id); err != nil {
+		return err
+	}
+
+	logger := s.Logger.With(zap.String("bucket_id", id.String()))
+	rs, err := s.ReplicationService.FindReplications(ctx, influxdb.ReplicationFilter{
+		LocalBucketID: &id,
+	})
+	if err != nil {
+		logger.Error("Failed to lookup replications for Bucket.", zap.Error(err))
+		return nil
+	}
+	for _, r := range rs {
+		if err := s.ReplicationService.DeleteReplication(ctx, r.ID); err != nil {
+			logger.Error("Failed to delete replication for Bucket.", zap.Error(err))
+		}
+	}
+	return nil
+}
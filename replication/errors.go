@@ -0,0 +1,36 @@
+package replication
+
+import (
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	// ErrInvalidReplicationID is used when the ID of the replication cannot
+	// be encoded.
+	ErrInvalidReplicationID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "replication ID is invalid",
+	}
+
+	// ErrReplicationNotFound is used when the specified replication cannot be
+	// found.
+	ErrReplicationNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "replication not found",
+	}
+
+	// ErrQueueFull is used when a write cannot be queued because the queue of
+	// a replication of its bucket is full.
+	ErrQueueFull = &influxdb.Error{
+		Code: influxdb.ETooManyRequests,
+		Msg:  "replication queue is full",
+	}
+)
+
+// ErrInternalService is used when the error comes from an internal system.
+func ErrInternalService(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Err:  err,
+	}
+}
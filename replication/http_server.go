@@ -0,0 +1,203 @@
+package replication
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+const (
+	PrefixReplications = "/api/v2/replications"
+)
+
+type Handler struct {
+	chi.Router
+	api            *kithttp.API
+	log            *zap.Logger
+	replicationSvc influxdb.ReplicationService
+}
+
+// NewHTTPHandler constructs a new http server.
+func NewHTTPHandler(log *zap.Logger, replicationSvc influxdb.ReplicationService) *Handler {
+	h := &Handler{
+		api:            kithttp.NewAPI(kithttp.WithLog(log)),
+		log:            log,
+		replicationSvc: replicationSvc,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+	)
+
+	r.Route("/", func(r chi.Router) {
+		r.Post("/", h.handlePostReplication)
+		r.Get("/", h.handleGetReplications)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.handleGetReplication)
+			r.Patch("/", h.handlePatchReplication)
+			r.Delete("/", h.handleDeleteReplication)
+		})
+	})
+
+	h.Router = r
+	return h
+}
+
+// redact removes the remote token of a replication, which is never returned
+// once set.
+func redact(r *influxdb.Replication) *influxdb.Replication {
+	cp := *r
+	cp.RemoteToken = ""
+	return &cp
+}
+
+func (h *Handler) handlePostReplication(w http.ResponseWriter, r *http.Request) {
+	var rep influxdb.Replication
+	if err := json.NewDecoder(r.Body).Decode(&rep); err != nil {
+		h.api.Err(w, r, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		})
+		return
+	}
+	rep.ID = 0
+
+	if err := h.replicationSvc.CreateReplication(r.Context(), &rep); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.api.Respond(w, r, http.StatusCreated, redact(&rep))
+}
+
+type getReplicationsResponse struct {
+	Replications []*influxdb.Replication `json:"replications"`
+}
+
+func (h *Handler) handleGetReplications(w http.ResponseWriter, r *http.Request) {
+	filter, err := getFilterFromHTTPRequest(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	rs, err := h.replicationSvc.FindReplications(r.Context(), filter)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	resp := getReplicationsResponse{
+		Replications: make([]*influxdb.Replication, 0, len(rs)),
+	}
+	for _, rep := range rs {
+		resp.Replications = append(resp.Replications, redact(rep))
+	}
+	h.api.Respond(w, r, http.StatusOK, resp)
+}
+
+func (h *Handler) handleGetReplication(w http.ResponseWriter, r *http.Request) {
+	id, err := getReplicationIDFromURL(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	rep, err := h.replicationSvc.FindReplicationByID(r.Context(), id)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.api.Respond(w, r, http.StatusOK, redact(rep))
+}
+
+func (h *Handler) handlePatchReplication(w http.ResponseWriter, r *http.Request) {
+	id, err := getReplicationIDFromURL(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var upd influxdb.ReplicationUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.api.Err(w, r, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		})
+		return
+	}
+
+	rep, err := h.replicationSvc.UpdateReplication(r.Context(), id, upd)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.api.Respond(w, r, http.StatusOK, redact(rep))
+}
+
+func (h *Handler) handleDeleteReplication(w http.ResponseWriter, r *http.Request) {
+	id, err := getReplicationIDFromURL(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := h.replicationSvc.DeleteReplication(r.Context(), id); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func getReplicationIDFromURL(r *http.Request) (influxdb.ID, error) {
+	var id influxdb.ID
+	raw := chi.URLParam(r, "id")
+	if raw == "" {
+		return id, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+	if err := id.DecodeFromString(raw); err != nil {
+		return id, ErrInvalidReplicationID
+	}
+	return id, nil
+}
+
+func getFilterFromHTTPRequest(r *http.Request) (f influxdb.ReplicationFilter, err error) {
+	// Always provide OrgID.
+	f.OrgID, err = getIDFromHTTPRequest(r, "orgID")
+	if err != nil {
+		return f, err
+	}
+	if f.OrgID == nil {
+		return f, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	f.LocalBucketID, err = getIDFromHTTPRequest(r, "localBucketID")
+	return f, err
+}
+
+func getIDFromHTTPRequest(r *http.Request, key string) (*influxdb.ID, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	var id influxdb.ID
+	if err := id.DecodeFromString(raw); err != nil {
+		return nil, influxdb.ErrInvalidID
+	}
+	return &id, nil
+}
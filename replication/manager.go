@@ -0,0 +1,259 @@
+package replication
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// remoteWriteTimeout bounds the time a write to a remote instance may take.
+const remoteWriteTimeout = time.Minute
+
+var _ influxdb.ReplicationService = (*Manager)(nil)
+
+// Manager replicates the writes of local buckets to remote instances. It wraps
+// the ReplicationService storing the configuration of replications, and
+// starts, updates and stops the stream of a replication as the replication is
+// created, updated and deleted.
+//
+// The writes queued for each replication are stored in a directory named
+// after its ID under the path of the Manager.
+type Manager struct {
+	influxdb.ReplicationService
+
+	path    string
+	client  *http.Client
+	log     *zap.Logger
+	metrics *metrics
+
+	mu       sync.RWMutex
+	streams  map[influxdb.ID]*stream
+	byBucket map[influxdb.ID][]*stream
+}
+
+// NewManager returns a Manager of the replications of svc, queuing their
+// writes under path.
+func NewManager(svc influxdb.ReplicationService, path string, log *zap.Logger) *Manager {
+	return &Manager{
+		ReplicationService: svc,
+		path:               path,
+		client:             &http.Client{Timeout: remoteWriteTimeout},
+		log:                log,
+		metrics:            newMetrics(),
+		streams:            make(map[influxdb.ID]*stream),
+		byBucket:           make(map[influxdb.ID][]*stream),
+	}
+}
+
+// PrometheusCollectors returns the metrics of the queues of replications.
+func (m *Manager) PrometheusCollectors() []prometheus.Collector {
+	return m.metrics.PrometheusCollectors()
+}
+
+// Open starts the streams of all the replications, resuming with the writes
+// that were queued but not sent before the Manager was closed. The queues of
+// replications that no longer exist are removed.
+func (m *Manager) Open(ctx context.Context) error {
+	if err := os.MkdirAll(m.path, 0777); err != nil {
+		return err
+	}
+
+	rs, err := m.ReplicationService.FindReplications(ctx, influxdb.ReplicationFilter{})
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	exists := make(map[string]bool, len(rs))
+	for _, r := range rs {
+		exists[r.ID.String()] = true
+		if err := m.startStream(r); err != nil {
+			m.log.Error("Failed to start replication", zap.String("replication_id", r.ID.String()), zap.Error(err))
+		}
+	}
+
+	fis, err := ioutil.ReadDir(m.path)
+	if err != nil {
+		return err
+	}
+	for _, fi := range fis {
+		if fi.IsDir() && !exists[fi.Name()] {
+			if err := os.RemoveAll(filepath.Join(m.path, fi.Name())); err != nil {
+				m.log.Warn("Failed to remove queue of deleted replication", zap.String("path", fi.Name()), zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+// Close stops all the streams and closes their queues. Writes still queued
+// are sent once the Manager is opened again.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var err error
+	for id, s := range m.streams {
+		s.stop()
+		err = multierr.Append(err, s.queue.Close())
+		delete(m.streams, id)
+	}
+	m.byBucket = make(map[influxdb.ID][]*stream)
+	return err
+}
+
+// startStream opens the queue of a replication and starts its stream. It must
+// be called with m.mu held.
+func (m *Manager) startStream(r *influxdb.Replication) error {
+	q, err := openQueue(filepath.Join(m.path, r.ID.String()), r.MaxQueueSizeBytes)
+	if err != nil {
+		return err
+	}
+
+	s := newStream(r, q, m.client, m.log, m.metrics)
+	s.start()
+	m.streams[r.ID] = s
+	m.byBucket[r.LocalBucketID] = append(m.byBucket[r.LocalBucketID], s)
+	return nil
+}
+
+// removeStream stops the stream of a replication and removes its queue. It
+// must be called with m.mu held.
+func (m *Manager) removeStream(id influxdb.ID) error {
+	s, ok := m.streams[id]
+	if !ok {
+		return nil
+	}
+	s.stop()
+	delete(m.streams, id)
+
+	bucketID := s.replication().LocalBucketID
+	streams := m.byBucket[bucketID][:0]
+	for _, other := range m.byBucket[bucketID] {
+		if other != s {
+			streams = append(streams, other)
+		}
+	}
+	if len(streams) == 0 {
+		delete(m.byBucket, bucketID)
+	} else {
+		m.byBucket[bucketID] = streams
+	}
+
+	m.metrics.delete(s.id)
+	return s.queue.Remove()
+}
+
+// CreateReplication creates a replication and starts replicating the writes
+// to its local bucket.
+func (m *Manager) CreateReplication(ctx context.Context, r *influxdb.Replication) error {
+	if err := m.ReplicationService.CreateReplication(ctx, r); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.startStream(r); err != nil {
+		// Do not keep a replication that does not replicate anything.
+		if derr := m.ReplicationService.DeleteReplication(ctx, r.ID); derr != nil {
+			m.log.Error("Failed to delete replication", zap.String("replication_id", r.ID.String()), zap.Error(derr))
+		}
+		return ErrInternalService(err)
+	}
+	return nil
+}
+
+// UpdateReplication updates a replication. Writes already queued are sent
+// with the updated configuration.
+func (m *Manager) UpdateReplication(ctx context.Context, id influxdb.ID, upd influxdb.ReplicationUpdate) (*influxdb.Replication, error) {
+	r, err := m.ReplicationService.UpdateReplication(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if s, ok := m.streams[id]; ok {
+		s.update(r)
+	}
+	return r, nil
+}
+
+// DeleteReplication deletes a replication and drops the writes queued for
+// it.
+func (m *Manager) DeleteReplication(ctx context.Context, id influxdb.ID) error {
+	if err := m.ReplicationService.DeleteReplication(ctx, id); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.removeStream(id); err != nil {
+		m.log.Warn("Failed to remove replication queue", zap.String("replication_id", id.String()), zap.Error(err))
+	}
+	return nil
+}
+
+// replicated returns true if the bucket has replications.
+func (m *Manager) replicated(bucketID influxdb.ID) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.byBucket[bucketID]) > 0
+}
+
+// enqueue queues batches of line protocol, keyed by the bucket they are
+// written to, for each of the replications of their bucket. Either all the
+// batches are queued or, if the queue of a replication is full, none of them
+// and ErrQueueFull is returned.
+func (m *Manager) enqueue(batches map[influxdb.ID][]byte) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	type reservation struct {
+		s    *stream
+		size int
+	}
+	var reserved []reservation
+	for bucketID, data := range batches {
+		for _, s := range m.byBucket[bucketID] {
+			if err := s.queue.Reserve(len(data)); err != nil {
+				s.log.Warn("Failed to queue write for replication", zap.Error(err))
+				for _, r := range reserved {
+					r.s.queue.Release(r.size)
+				}
+				if err != ErrQueueFull {
+					return ErrInternalService(err)
+				}
+				return err
+			}
+			reserved = append(reserved, reservation{s: s, size: len(data)})
+		}
+	}
+
+	var err error
+	for bucketID, data := range batches {
+		for _, s := range m.byBucket[bucketID] {
+			if e := s.enqueue(data); e != nil {
+				s.log.Warn("Failed to queue write for replication", zap.Error(e))
+				if err == nil {
+					err = e
+				}
+			}
+		}
+	}
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package replication
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace is the leading part of all published metrics for replications.
+const namespace = "replications"
+
+const queueSubsystem = "queue" // sub-system associated with metrics for replication queues.
+
+// metrics is a set of metrics concerned with the queues of replications and
+// the writes sent to remote instances.
+type metrics struct {
+	QueueSizeBytes       *prometheus.GaugeVec
+	QueuedBytes          *prometheus.CounterVec
+	SentBytes            *prometheus.CounterVec
+	DroppedBytes         *prometheus.CounterVec
+	RemoteWriteResponses *prometheus.CounterVec
+	RemoteWriteErrors    *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	labels := []string{"replication_id"}
+	return &metrics{
+		QueueSizeBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: queueSubsystem,
+			Name:      "size_bytes",
+			Help:      "Size of the writes queued for the remote instance of a replication.",
+		}, labels),
+		QueuedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: queueSubsystem,
+			Name:      "queued_bytes_total",
+			Help:      "Number of bytes of writes added to the queue of a replication.",
+		}, labels),
+		SentBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: queueSubsystem,
+			Name:      "sent_bytes_total",
+			Help:      "Number of bytes of writes accepted by the remote instance of a replication.",
+		}, labels),
+		DroppedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: queueSubsystem,
+			Name:      "dropped_bytes_total",
+			Help:      "Number of bytes of writes dropped from the queue of a replication without being accepted by its remote instance.",
+		}, append(labels, "reason")),
+		RemoteWriteResponses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: queueSubsystem,
+			Name:      "remote_write_responses_total",
+			Help:      "Number of responses of the remote instance of a replication to writes, by status code.",
+		}, append(labels, "code")),
+		RemoteWriteErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: queueSubsystem,
+			Name:      "remote_write_errors_total",
+			Help:      "Number of writes to the remote instance of a replication that failed without a response.",
+		}, labels),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *metrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.QueueSizeBytes,
+		m.QueuedBytes,
+		m.SentBytes,
+		m.DroppedBytes,
+		m.RemoteWriteResponses,
+		m.RemoteWriteErrors,
+	}
+}
+
+// delete removes the metrics of a replication.
+func (m *metrics) delete(id string) {
+	l := prometheus.Labels{"replication_id": id}
+	m.QueueSizeBytes.Delete(l)
+	m.QueuedBytes.Delete(l)
+	m.SentBytes.Delete(l)
+	m.RemoteWriteErrors.Delete(l)
+}
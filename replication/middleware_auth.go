@@ -0,0 +1,72 @@
+package replication
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+)
+
+var _ influxdb.ReplicationService = (*AuthorizedService)(nil)
+
+// AuthorizedService authorizes the actions on replications against the local
+// bucket of the replication: reading a replication requires read access to
+// the bucket, and creating, updating or deleting it write access.
+type AuthorizedService struct {
+	influxdb.ReplicationService
+}
+
+// NewAuthorizedService returns a ReplicationService authorizing the actions
+// on the replications of s.
+func NewAuthorizedService(s influxdb.ReplicationService) *AuthorizedService {
+	return &AuthorizedService{ReplicationService: s}
+}
+
+func (svc AuthorizedService) FindReplicationByID(ctx context.Context, id influxdb.ID) (*influxdb.Replication, error) {
+	r, err := svc.ReplicationService.FindReplicationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeRead(ctx, influxdb.BucketsResourceType, r.LocalBucketID, r.OrgID); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (svc AuthorizedService) FindReplications(ctx context.Context, filter influxdb.ReplicationFilter) ([]*influxdb.Replication, error) {
+	rs, err := svc.ReplicationService.FindReplications(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	rs, _, err = authorizer.AuthorizeFindReplications(ctx, rs)
+	return rs, err
+}
+
+func (svc AuthorizedService) CreateReplication(ctx context.Context, r *influxdb.Replication) error {
+	if _, _, err := authorizer.AuthorizeWrite(ctx, influxdb.BucketsResourceType, r.LocalBucketID, r.OrgID); err != nil {
+		return err
+	}
+	return svc.ReplicationService.CreateReplication(ctx, r)
+}
+
+func (svc AuthorizedService) UpdateReplication(ctx context.Context, id influxdb.ID, upd influxdb.ReplicationUpdate) (*influxdb.Replication, error) {
+	r, err := svc.ReplicationService.FindReplicationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeWrite(ctx, influxdb.BucketsResourceType, r.LocalBucketID, r.OrgID); err != nil {
+		return nil, err
+	}
+	return svc.ReplicationService.UpdateReplication(ctx, id, upd)
+}
+
+func (svc AuthorizedService) DeleteReplication(ctx context.Context, id influxdb.ID) error {
+	r, err := svc.ReplicationService.FindReplicationByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, _, err := authorizer.AuthorizeWrite(ctx, influxdb.BucketsResourceType, r.LocalBucketID, r.OrgID); err != nil {
+		return err
+	}
+	return svc.ReplicationService.DeleteReplication(ctx, id)
+}
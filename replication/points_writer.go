@@ -0,0 +1,89 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+	"github.com/influxdata/influxdb/v2/tsdb"
+)
+
+var _ storage.PointsWriter = (*PointsWriter)(nil)
+
+// PointsWriter queues the points written to replicated buckets for their
+// remote instances, then writes them to an underlying storage.PointsWriter.
+//
+// Points are queued durably before they are written locally, so that points
+// stored locally are never lost to their remote instances, even by a crash
+// between the two. A write is rejected before it is written locally if the
+// queue of a replication of its buckets is full. The points of a partial
+// write, rejected by the underlying writer with an error with code
+// EUnprocessableEntity, are all replicated: the remote instance applies its
+// own validation to the points the local one rejected.
+type PointsWriter struct {
+	Underlying storage.PointsWriter
+	Manager    *Manager
+}
+
+// WritePoints queues points for the replications of their bucket and writes
+// them. It returns ErrQueueFull, without writing the points, if the queue of
+// a replication is full.
+func (w *PointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	batches := make(map[influxdb.ID][]byte)
+	for _, p := range points {
+		// The name of a point is its encoded org and bucket ID.
+		name := p.Name()
+		if len(name) != 16 {
+			continue
+		}
+		_, bucketID := tsdb.DecodeNameSlice(name)
+		buf, ok := batches[bucketID]
+		if !ok && !w.Manager.replicated(bucketID) {
+			continue
+		}
+		line, err := appendLine(buf, p)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "unable to replicate point",
+				Err:  err,
+			}
+		}
+		batches[bucketID] = line
+	}
+
+	if len(batches) > 0 {
+		if err := w.Manager.enqueue(batches); err != nil {
+			return err
+		}
+	}
+	return w.Underlying.WritePoints(ctx, points)
+}
+
+// appendLine appends the line protocol of a point, as it was written to its
+// bucket, to dst.
+func appendLine(dst []byte, p models.Point) ([]byte, error) {
+	tags := p.Tags()
+	measurement := tags.Get(models.MeasurementTagKeyBytes)
+
+	userTags := make(models.Tags, 0, len(tags))
+	for _, t := range tags {
+		if bytes.Equal(t.Key, models.MeasurementTagKeyBytes) || bytes.Equal(t.Key, models.FieldKeyTagKeyBytes) {
+			continue
+		}
+		userTags = append(userTags, t)
+	}
+
+	fields, err := p.Fields()
+	if err != nil {
+		return dst, err
+	}
+	pt, err := models.NewPoint(string(measurement), userTags, fields, p.Time())
+	if err != nil {
+		return dst, err
+	}
+	dst = pt.AppendString(dst)
+	return append(dst, '\n'), nil
+}
@@ -0,0 +1,224 @@
+package replication_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/replication"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+// remote is a remote instance accepting writes once it has failed the given
+// number of them.
+type remote struct {
+	mu       sync.Mutex
+	failures int
+	writes   []*http.Request
+	bodies   []string
+}
+
+func (rm *remote) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if rm.failures > 0 {
+		rm.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rm.writes = append(rm.writes, r)
+	rm.bodies = append(rm.bodies, string(body))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rm *remote) received() []string {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	return append([]string(nil), rm.bodies...)
+}
+
+func waitForWrites(t *testing.T, rm *remote, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if got := rm.received(); len(got) >= n {
+			return got
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d remote writes", n)
+	return nil
+}
+
+func storagePoint(bucketID influxdb.ID, host string) models.Point {
+	return models.MustNewPoint(
+		tsdb.EncodeNameString(orgID, bucketID),
+		models.NewTags(map[string]string{
+			models.MeasurementTagKey: "cpu",
+			models.FieldKeyTagKey:    "value",
+			"host":                   host,
+		}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(0, 10),
+	)
+}
+
+func TestPointsWriter_Replicates(t *testing.T) {
+	ctx := context.Background()
+
+	rm := &remote{failures: 1}
+	server := httptest.NewServer(rm)
+	defer server.Close()
+
+	svc, done := newTestService(t)
+	defer done()
+
+	dir, err := ioutil.TempDir("", "replication-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mgr := replication.NewManager(svc, dir, zaptest.NewLogger(t))
+	if err := mgr.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer mgr.Close()
+
+	if err := mgr.CreateReplication(ctx, newReplication(server.URL)); err != nil {
+		t.Fatal(err)
+	}
+
+	underlying := &mock.PointsWriter{}
+	w := &replication.PointsWriter{Underlying: underlying, Manager: mgr}
+	points := []models.Point{
+		storagePoint(bucketID, "a"),
+		// Points of buckets without replications are not queued.
+		storagePoint(remoteBktID, "b"),
+	}
+	if err := w.WritePoints(ctx, points); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(underlying.Points); got != 2 {
+		t.Fatalf("expected 2 points written locally, got %d", got)
+	}
+
+	// The first write fails, and is retried.
+	got := waitForWrites(t, rm, 1)
+	if want := "cpu,host=a value=1 10\n"; got[0] != want {
+		t.Fatalf("unexpected remote write -got/+want\n\t- %q\n\t+ %q", got[0], want)
+	}
+
+	rm.mu.Lock()
+	req := rm.writes[0]
+	rm.mu.Unlock()
+	if got, want := req.Header.Get("Authorization"), "Token secret"; got != want {
+		t.Fatalf("unexpected authorization -got/+want\n\t- %q\n\t+ %q", got, want)
+	}
+	q := req.URL.Query()
+	if q.Get("org") != remoteOrgID.String() || q.Get("bucket") != remoteBktID.String() {
+		t.Fatalf("unexpected remote org and bucket: %s", req.URL.RawQuery)
+	}
+}
+
+func TestPointsWriter_QueueFull(t *testing.T) {
+	ctx := context.Background()
+
+	// The remote instance is never reached.
+	svc, done := newTestService(t)
+	defer done()
+
+	dir, err := ioutil.TempDir("", "replication-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mgr := replication.NewManager(svc, dir, zaptest.NewLogger(t))
+	if err := mgr.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer mgr.Close()
+
+	r := newReplication("http://127.0.0.1:1")
+	r.MaxQueueSizeBytes = 1
+	if err := mgr.CreateReplication(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	underlying := &mock.PointsWriter{}
+	w := &replication.PointsWriter{Underlying: underlying, Manager: mgr}
+	err = w.WritePoints(ctx, []models.Point{storagePoint(bucketID, "a")})
+	if code := influxdb.ErrorCode(err); code != influxdb.ETooManyRequests {
+		t.Fatalf("expected error code %q, got %v", influxdb.ETooManyRequests, err)
+	}
+	if got := len(underlying.Points); got != 0 {
+		t.Fatalf("expected write to be rejected before it is written locally, got %d points", got)
+	}
+}
+
+func TestPointsWriter_PartialWrite(t *testing.T) {
+	ctx := context.Background()
+
+	rm := &remote{}
+	server := httptest.NewServer(rm)
+	defer server.Close()
+
+	svc, done := newTestService(t)
+	defer done()
+
+	dir, err := ioutil.TempDir("", "replication-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mgr := replication.NewManager(svc, dir, zaptest.NewLogger(t))
+	if err := mgr.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer mgr.Close()
+
+	if err := mgr.CreateReplication(ctx, newReplication(server.URL)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The local instance only accepts the first point.
+	var written []models.Point
+	underlying := &mock.PointsWriter{
+		WritePointsFn: func(ctx context.Context, points []models.Point) error {
+			written = append(written, points[0])
+			return &influxdb.Error{
+				Code: influxdb.EUnprocessableEntity,
+				Msg:  "partial write: max series per bucket (1) exceeded dropped=1",
+			}
+		},
+	}
+	w := &replication.PointsWriter{Underlying: underlying, Manager: mgr}
+
+	err = w.WritePoints(ctx, []models.Point{storagePoint(bucketID, "a"), storagePoint(bucketID, "b")})
+	if code := influxdb.ErrorCode(err); code != influxdb.EUnprocessableEntity {
+		t.Fatalf("expected error code %q, got %v", influxdb.EUnprocessableEntity, err)
+	}
+	if len(written) != 1 {
+		t.Fatalf("expected 1 point written locally, got %d", len(written))
+	}
+
+	// The points of a partial write are replicated, the remote instance
+	// validates them itself.
+	got := waitForWrites(t, rm, 1)
+	if want := "cpu,host=a value=1 10\ncpu,host=b value=1 10\n"; got[0] != want {
+		t.Fatalf("unexpected remote write -got/+want\n\t- %q\n\t+ %q", got[0], want)
+	}
+}
@@ -0,0 +1,503 @@
+package replication
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// defaultSegmentSize is the size past which the queue appends to a new
+	// segment file.
+	defaultSegmentSize = 16 * 1024 * 1024
+
+	// entryHeaderSize is the size of the length and checksum preceding the
+	// data of an entry.
+	entryHeaderSize = 8
+
+	segmentFileExt   = ".seg"
+	positionFileName = "position"
+)
+
+// errCorruptEntry is returned when an entry of a segment fails its checksum.
+var errCorruptEntry = errors.New("corrupt replication queue entry")
+
+// queue is a durable FIFO queue of entries, stored in a directory as a
+// sequence of segment files. Entries are appended to the last segment, synced
+// to disk before Append returns, and read from the first segment. A segment is
+// removed once all its entries have been read.
+//
+// The position of the next entry to read is persisted whenever the entry
+// before it is acknowledged with Advance, so that after a restart the queue
+// resumes with the first entry that was not acknowledged.
+//
+// Entries are encoded as a 4 byte length and a 4 byte CRC32 checksum of the
+// data, both big endian, followed by the data. A torn entry at the end of the
+// last segment, left by a crash during an append, is truncated when the queue
+// is opened.
+//
+// queue is safe for use by multiple goroutines, though entries are expected
+// to be read by a single one.
+type queue struct {
+	mu          sync.Mutex
+	dir         string
+	maxSize     int64
+	segmentSize int64
+
+	segments []uint64 // IDs of the segment files, oldest first
+
+	w     *os.File // last segment, opened for appending
+	wsize int64    // size of the last segment
+
+	r       *os.File // first segment, opened for reading
+	rsize   int64    // size of the first segment, if it is not the last
+	roffset int64    // offset of the next entry to read in the first segment
+	pending int64    // size of the entry returned by Peek and not yet acknowledged
+
+	size     int64 // size of the entries not yet acknowledged
+	reserved int64 // size of the entries reserved and not yet appended
+
+	// notify receives a value when an entry is appended.
+	notify chan struct{}
+}
+
+// openQueue opens the queue stored in dir, creating it if needed. Appends fail
+// with ErrQueueFull once the queue holds maxSize bytes, unless maxSize is 0.
+func openQueue(dir string, maxSize int64) (*queue, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, err
+	}
+
+	q := &queue{
+		dir:         dir,
+		maxSize:     maxSize,
+		segmentSize: defaultSegmentSize,
+		notify:      make(chan struct{}, 1),
+	}
+	if err := q.open(); err != nil {
+		q.close()
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *queue) open() error {
+	fis, err := ioutil.ReadDir(q.dir)
+	if err != nil {
+		return err
+	}
+	for _, fi := range fis {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), segmentFileExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(fi.Name(), segmentFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		q.segments = append(q.segments, id)
+	}
+	sort.Slice(q.segments, func(i, j int) bool { return q.segments[i] < q.segments[j] })
+
+	// Restore the read position, removing the segments that were read but
+	// not removed before the queue was closed.
+	segment, offset, err := q.readPosition()
+	if err != nil {
+		return err
+	}
+	for len(q.segments) > 1 && q.segments[0] < segment {
+		if err := os.Remove(q.segmentPath(q.segments[0])); err != nil {
+			return err
+		}
+		q.segments = q.segments[1:]
+	}
+	if len(q.segments) > 0 && q.segments[0] == segment {
+		q.roffset = offset
+	}
+
+	if len(q.segments) == 0 {
+		q.segments = []uint64{1}
+	}
+
+	// Open the last segment for appending, dropping any torn entry.
+	last := q.segments[len(q.segments)-1]
+	q.w, err = os.OpenFile(q.segmentPath(last), os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return err
+	}
+	start := int64(0)
+	if len(q.segments) == 1 {
+		start = q.roffset
+	}
+	q.wsize, err = validSize(q.w, start)
+	if err != nil {
+		return err
+	}
+	if err := q.w.Truncate(q.wsize); err != nil {
+		return err
+	}
+	if _, err := q.w.Seek(q.wsize, io.SeekStart); err != nil {
+		return err
+	}
+	if q.roffset > q.wsize && len(q.segments) == 1 {
+		q.roffset = q.wsize
+	}
+
+	if err := q.openReader(); err != nil {
+		return err
+	}
+
+	for _, id := range q.segments[:len(q.segments)-1] {
+		fi, err := os.Stat(q.segmentPath(id))
+		if err != nil {
+			return err
+		}
+		q.size += fi.Size()
+	}
+	q.size += q.wsize - q.roffset
+	return nil
+}
+
+// validSize returns the size of the entries of f that are complete, reading
+// from the entry at offset start.
+func validSize(f *os.File, start int64) (int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	var hdr [entryHeaderSize]byte
+	offset := start
+	for offset+entryHeaderSize <= fi.Size() {
+		if _, err := f.ReadAt(hdr[:], offset); err != nil {
+			return 0, err
+		}
+		n := int64(binary.BigEndian.Uint32(hdr[:4]))
+		if offset+entryHeaderSize+n > fi.Size() {
+			break
+		}
+		data := make([]byte, n)
+		if _, err := f.ReadAt(data, offset+entryHeaderSize); err != nil {
+			return 0, err
+		}
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(hdr[4:]) {
+			break
+		}
+		offset += entryHeaderSize + n
+	}
+	return offset, nil
+}
+
+// openReader opens the first segment for reading.
+func (q *queue) openReader() error {
+	if len(q.segments) == 1 {
+		q.r = q.w
+		return nil
+	}
+
+	var err error
+	q.r, err = os.Open(q.segmentPath(q.segments[0]))
+	if err != nil {
+		return err
+	}
+	fi, err := q.r.Stat()
+	if err != nil {
+		return err
+	}
+	q.rsize = fi.Size()
+	return nil
+}
+
+func (q *queue) segmentPath(id uint64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d%s", id, segmentFileExt))
+}
+
+// readPosition returns the persisted read position, or the start of the
+// queue if none was persisted.
+func (q *queue) readPosition() (segment uint64, offset int64, err error) {
+	b, err := ioutil.ReadFile(filepath.Join(q.dir, positionFileName))
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(b) != 16 {
+		return 0, 0, fmt.Errorf("invalid replication queue position file in %s", q.dir)
+	}
+	return binary.BigEndian.Uint64(b[:8]), int64(binary.BigEndian.Uint64(b[8:])), nil
+}
+
+// writePosition persists the read position.
+func (q *queue) writePosition() error {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], q.segments[0])
+	binary.BigEndian.PutUint64(b[8:], uint64(q.roffset))
+
+	path := filepath.Join(q.dir, positionFileName)
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b[:]); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Append adds an entry to the end of the queue. It returns ErrQueueFull if
+// the entry would take the queue past its maximum size.
+func (q *queue) Append(data []byte) error {
+	return q.append(data, false)
+}
+
+// Reserve reserves room for an entry of size bytes of data, so that
+// appending it with AppendReserved cannot fail with ErrQueueFull. It returns
+// ErrQueueFull if the entry would take the queue past its maximum size.
+func (q *queue) Reserve(size int) error {
+	n := int64(entryHeaderSize + size)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.w == nil {
+		return os.ErrClosed
+	}
+	if q.maxSize > 0 && q.size+q.reserved+n > q.maxSize {
+		return ErrQueueFull
+	}
+	q.reserved += n
+	return nil
+}
+
+// Release releases the room reserved for an entry of size bytes of data that
+// is not appended.
+func (q *queue) Release(size int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.reserved -= int64(entryHeaderSize + size)
+}
+
+// AppendReserved adds an entry, for which room was reserved with Reserve, to
+// the end of the queue. The reservation is released even if it fails.
+func (q *queue) AppendReserved(data []byte) error {
+	return q.append(data, true)
+}
+
+func (q *queue) append(data []byte, reserved bool) error {
+	n := int64(entryHeaderSize + len(data))
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if reserved {
+		q.reserved -= n
+	}
+	if q.w == nil {
+		return os.ErrClosed
+	}
+	if !reserved && q.maxSize > 0 && q.size+q.reserved+n > q.maxSize {
+		return ErrQueueFull
+	}
+
+	if q.wsize > 0 && q.wsize+n > q.segmentSize {
+		if err := q.roll(); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, n)
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(data)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(data))
+	copy(buf[entryHeaderSize:], data)
+
+	if _, err := q.w.Write(buf); err != nil {
+		// Drop whatever part of the entry was written.
+		_ = q.w.Truncate(q.wsize)
+		_, _ = q.w.Seek(q.wsize, io.SeekStart)
+		return err
+	}
+	if err := q.w.Sync(); err != nil {
+		return err
+	}
+	q.wsize += n
+	q.size += n
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// roll starts a new last segment.
+func (q *queue) roll() error {
+	id := q.segments[len(q.segments)-1] + 1
+	f, err := os.OpenFile(q.segmentPath(id), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	// The previous last segment stays open for reading if it is the first.
+	if q.r == q.w {
+		q.rsize = q.wsize
+	} else if err := q.w.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	q.w, q.wsize = f, 0
+	q.segments = append(q.segments, id)
+	return nil
+}
+
+// Peek returns the first entry of the queue that has not been acknowledged.
+// It returns io.EOF if the queue is empty.
+func (q *queue) Peek() ([]byte, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.r == nil {
+		return nil, os.ErrClosed
+	}
+
+	for {
+		end := q.rsize
+		if q.r == q.w {
+			end = q.wsize
+		}
+		if q.roffset+entryHeaderSize > end {
+			if q.r == q.w {
+				return nil, io.EOF
+			}
+			if err := q.nextSegment(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var hdr [entryHeaderSize]byte
+		if _, err := q.r.ReadAt(hdr[:], q.roffset); err != nil {
+			return nil, err
+		}
+		n := int64(binary.BigEndian.Uint32(hdr[:4]))
+		if q.roffset+entryHeaderSize+n > end {
+			return nil, errCorruptEntry
+		}
+		data := make([]byte, n)
+		if _, err := q.r.ReadAt(data, q.roffset+entryHeaderSize); err != nil {
+			return nil, err
+		}
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(hdr[4:]) {
+			return nil, errCorruptEntry
+		}
+		q.pending = entryHeaderSize + n
+		return data, nil
+	}
+}
+
+// nextSegment removes the first segment, which has been read entirely.
+func (q *queue) nextSegment() error {
+	if err := q.r.Close(); err != nil {
+		return err
+	}
+	if err := os.Remove(q.segmentPath(q.segments[0])); err != nil {
+		return err
+	}
+	q.size -= q.rsize - q.roffset
+	q.segments = q.segments[1:]
+	q.roffset, q.pending = 0, 0
+	if err := q.openReader(); err != nil {
+		return err
+	}
+	return q.writePosition()
+}
+
+// Advance acknowledges the entry returned by the last call to Peek, so that
+// it is not returned again, even after a restart.
+func (q *queue) Advance() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.pending == 0 {
+		return nil
+	}
+	q.roffset += q.pending
+	q.size -= q.pending
+	q.pending = 0
+	return q.writePosition()
+}
+
+// Skip drops the remainder of the first segment, after Peek returned
+// errCorruptEntry for one of its entries. The last segment cannot be skipped.
+func (q *queue) Skip() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.r == q.w {
+		return errCorruptEntry
+	}
+	q.roffset = q.rsize
+	return q.nextSegment()
+}
+
+// Size returns the size in bytes of the entries that have not been
+// acknowledged.
+func (q *queue) Size() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+// SetMaxSize sets the maximum size of the queue.
+func (q *queue) SetMaxSize(n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.maxSize = n
+}
+
+// Close closes the files of the queue.
+func (q *queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.close()
+}
+
+func (q *queue) close() error {
+	var err error
+	if q.r != nil && q.r != q.w {
+		err = q.r.Close()
+	}
+	if q.w != nil {
+		if e := q.w.Close(); err == nil {
+			err = e
+		}
+	}
+	q.r, q.w = nil, nil
+	return err
+}
+
+// Remove closes the queue and removes its directory.
+func (q *queue) Remove() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(q.dir)
+}
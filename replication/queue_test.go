@@ -0,0 +1,231 @@
+package replication
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func mustOpenQueue(t *testing.T, dir string, maxSize int64) *queue {
+	t.Helper()
+	q, err := openQueue(dir, maxSize)
+	if err != nil {
+		t.Fatalf("unexpected error opening queue: %v", err)
+	}
+	return q
+}
+
+func mustPeek(t *testing.T, q *queue, want string) {
+	t.Helper()
+	data, err := q.Peek()
+	if err != nil {
+		t.Fatalf("unexpected error peeking: %v", err)
+	}
+	if got := string(data); got != want {
+		t.Fatalf("unexpected entry -got/+want\n\t- %q\n\t+ %q", got, want)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-queue-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 0)
+	if _, err := q.Peek(); err != io.EOF {
+		t.Fatalf("expected io.EOF peeking empty queue, got %v", err)
+	}
+
+	for _, s := range []string{"a", "bb", "ccc"} {
+		if err := q.Append([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := q.Size(), int64(3*entryHeaderSize+6); got != want {
+		t.Fatalf("unexpected size -got/+want\n\t- %d\n\t+ %d", got, want)
+	}
+
+	// Peeking twice without acknowledging returns the same entry.
+	mustPeek(t, q, "a")
+	mustPeek(t, q, "a")
+	if err := q.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "bb")
+
+	// Entries not acknowledged before a restart are returned again.
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	q = mustOpenQueue(t, dir, 0)
+	defer q.Close()
+
+	mustPeek(t, q, "bb")
+	if err := q.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "ccc")
+	if err := q.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Peek(); err != io.EOF {
+		t.Fatalf("expected io.EOF peeking empty queue, got %v", err)
+	}
+	if got := q.Size(); got != 0 {
+		t.Fatalf("expected empty queue, got size %d", got)
+	}
+}
+
+func TestQueue_Segments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-queue-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 0)
+	defer q.Close()
+	q.segmentSize = 2 * (entryHeaderSize + 4)
+
+	want := []string{"0000", "1111", "2222", "3333", "4444"}
+	for _, s := range want {
+		if err := q.Append([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	segments, _ := filepath.Glob(filepath.Join(dir, "*"+segmentFileExt))
+	if len(segments) != 3 {
+		t.Fatalf("expected 3 segments, got %d", len(segments))
+	}
+
+	for _, s := range want {
+		mustPeek(t, q, s)
+		if err := q.Advance(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := q.Peek(); err != io.EOF {
+		t.Fatalf("expected io.EOF peeking empty queue, got %v", err)
+	}
+
+	// Segments read entirely are removed.
+	segments, _ = filepath.Glob(filepath.Join(dir, "*"+segmentFileExt))
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(segments))
+	}
+}
+
+func TestQueue_TornEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-queue-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 0)
+	if err := q.Append([]byte("whole")); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Append([]byte("torn")); err != nil {
+		t.Fatal(err)
+	}
+	path := q.segmentPath(q.segments[0])
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash in the middle of the second append.
+	if err := os.Truncate(path, entryHeaderSize+5+entryHeaderSize+2); err != nil {
+		t.Fatal(err)
+	}
+
+	q = mustOpenQueue(t, dir, 0)
+	defer q.Close()
+	if got, want := q.Size(), int64(entryHeaderSize+5); got != want {
+		t.Fatalf("unexpected size -got/+want\n\t- %d\n\t+ %d", got, want)
+	}
+	if err := q.Append([]byte("next")); err != nil {
+		t.Fatal(err)
+	}
+
+	mustPeek(t, q, "whole")
+	if err := q.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "next")
+}
+
+func TestQueue_Full(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-queue-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 2*(entryHeaderSize+4))
+	defer q.Close()
+
+	for _, s := range []string{"0000", "1111"} {
+		if err := q.Append([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Append([]byte("2222")); err != ErrQueueFull {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	// Acknowledging an entry makes room for another.
+	mustPeek(t, q, "0000")
+	if err := q.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Append([]byte("2222")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Raising the maximum size makes room as well.
+	q.SetMaxSize(0)
+	if err := q.Append([]byte("3333")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueue_Reserve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-queue-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 2*entryHeaderSize+2)
+	defer q.Close()
+
+	if err := q.Reserve(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Reserve(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// The reserved room is not available to other entries.
+	if err := q.Reserve(1); err != ErrQueueFull {
+		t.Fatalf("expected ErrQueueFull reserving room in a full queue, got %v", err)
+	}
+	if err := q.Append([]byte("a")); err != ErrQueueFull {
+		t.Fatalf("expected ErrQueueFull appending to a full queue, got %v", err)
+	}
+
+	// Reserved entries are appended even though the queue is full.
+	if err := q.AppendReserved([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	q.Release(1)
+	if err := q.Append([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	mustPeek(t, q, "a")
+}
@@ -0,0 +1,203 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/snowflake"
+)
+
+var replicationBucket = []byte("replicationsv1")
+
+var _ influxdb.ReplicationService = (*Service)(nil)
+
+// Service stores the configuration of replications in a kv.Store.
+type Service struct {
+	store     kv.Store
+	IDGen     influxdb.IDGenerator
+	TimeGen   influxdb.TimeGenerator
+	bucketSvc influxdb.BucketService
+}
+
+// NewService returns a Service storing replications in st. bucketSvc is used
+// to check the local bucket of new replications exists.
+func NewService(st kv.Store, bucketSvc influxdb.BucketService) *Service {
+	return &Service{
+		store:     st,
+		IDGen:     snowflake.NewDefaultIDGenerator(),
+		TimeGen:   influxdb.RealTimeGenerator{},
+		bucketSvc: bucketSvc,
+	}
+}
+
+// FindReplicationByID returns a single replication by ID.
+func (s *Service) FindReplicationByID(ctx context.Context, id influxdb.ID) (*influxdb.Replication, error) {
+	var r *influxdb.Replication
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		var err error
+		r, err = findReplicationByID(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func findReplicationByID(tx kv.Tx, id influxdb.ID) (*influxdb.Replication, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidReplicationID
+	}
+
+	b, err := tx.Bucket(replicationBucket)
+	if err != nil {
+		return nil, ErrInternalService(err)
+	}
+	v, err := b.Get(encodedID)
+	if kv.IsNotFound(err) {
+		return nil, ErrReplicationNotFound
+	}
+	if err != nil {
+		return nil, ErrInternalService(err)
+	}
+
+	r := &influxdb.Replication{}
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, ErrInternalService(err)
+	}
+	return r, nil
+}
+
+// FindReplications returns the replications matching the filter.
+func (s *Service) FindReplications(ctx context.Context, filter influxdb.ReplicationFilter) ([]*influxdb.Replication, error) {
+	rs := []*influxdb.Replication{}
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(replicationBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		cur, err := b.ForwardCursor(nil)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		defer cur.Close()
+
+		for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+			r := &influxdb.Replication{}
+			if err := json.Unmarshal(v, r); err != nil {
+				return ErrInternalService(err)
+			}
+			if filterFunc(r, filter) {
+				rs = append(rs, r)
+			}
+		}
+		return cur.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// CreateReplication creates a new replication and sets r.ID with the new
+// identifier.
+func (s *Service) CreateReplication(ctx context.Context, r *influxdb.Replication) error {
+	if r.MaxQueueSizeBytes == 0 {
+		r.MaxQueueSizeBytes = influxdb.DefaultReplicationMaxQueueSizeBytes
+	}
+	if err := r.Valid(); err != nil {
+		return err
+	}
+
+	bucket, err := s.bucketSvc.FindBucketByID(ctx, r.LocalBucketID)
+	if err != nil {
+		return err
+	}
+	if bucket.OrgID != r.OrgID {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "replication localBucketID must be a bucket of its organization",
+		}
+	}
+
+	r.ID = s.IDGen.ID()
+	now := s.TimeGen.Now()
+	r.SetCreatedAt(now)
+	r.SetUpdatedAt(now)
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		return putReplication(tx, r)
+	})
+}
+
+// UpdateReplication updates a single replication with the changeset.
+func (s *Service) UpdateReplication(ctx context.Context, id influxdb.ID, upd influxdb.ReplicationUpdate) (*influxdb.Replication, error) {
+	var r *influxdb.Replication
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		var err error
+		r, err = findReplicationByID(tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(r)
+		if err := r.Valid(); err != nil {
+			return err
+		}
+		r.SetUpdatedAt(s.TimeGen.Now())
+		return putReplication(tx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DeleteReplication removes a replication by ID.
+func (s *Service) DeleteReplication(ctx context.Context, id influxdb.ID) error {
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		if _, err := findReplicationByID(tx, id); err != nil {
+			return err
+		}
+
+		encodedID, err := id.Encode()
+		if err != nil {
+			return ErrInvalidReplicationID
+		}
+		b, err := tx.Bucket(replicationBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if err := b.Delete(encodedID); err != nil {
+			return ErrInternalService(err)
+		}
+		return nil
+	})
+}
+
+func putReplication(tx kv.Tx, r *influxdb.Replication) error {
+	encodedID, err := r.ID.Encode()
+	if err != nil {
+		return ErrInvalidReplicationID
+	}
+	v, err := json.Marshal(r)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+
+	b, err := tx.Bucket(replicationBucket)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	if err := b.Put(encodedID, v); err != nil {
+		return ErrInternalService(err)
+	}
+	return nil
+}
+
+func filterFunc(r *influxdb.Replication, filter influxdb.ReplicationFilter) bool {
+	return (filter.OrgID == nil || *filter.OrgID == r.OrgID) &&
+		(filter.LocalBucketID == nil || *filter.LocalBucketID == r.LocalBucketID)
+}
@@ -0,0 +1,201 @@
+package replication_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/bolt"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/replication"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	orgID       = influxdb.ID(0x3131313131313131)
+	otherOrgID  = influxdb.ID(0x3232323232323232)
+	bucketID    = influxdb.ID(0x3333333333333333)
+	remoteOrgID = influxdb.ID(0x3434343434343434)
+	remoteBktID = influxdb.ID(0x3535353535353535)
+)
+
+func NewTestBoltStore(t *testing.T) (kv.Store, func(), error) {
+	t.Helper()
+
+	f, err := ioutil.TempFile("", "influxdata-bolt-")
+	if err != nil {
+		return nil, nil, errors.New("unable to open temporary boltdb file")
+	}
+	f.Close()
+
+	ctx := context.Background()
+	logger := zaptest.NewLogger(t)
+	path := f.Name()
+	s := bolt.NewKVStore(logger, path)
+	if err := s.Open(context.Background()); err != nil {
+		return nil, nil, err
+	}
+
+	if err := all.Up(ctx, logger, s); err != nil {
+		return nil, nil, err
+	}
+
+	close := func() {
+		s.Close()
+		os.Remove(path)
+	}
+
+	return s, close, nil
+}
+
+func newTestService(t *testing.T) (*replication.Service, func()) {
+	t.Helper()
+
+	s, closeStore, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new bolt kv store: %v", err)
+	}
+
+	bucketSvc := &mock.BucketService{
+		FindBucketByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+			if id != bucketID {
+				return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+			}
+			return &influxdb.Bucket{ID: id, OrgID: orgID}, nil
+		},
+	}
+	return replication.NewService(s, bucketSvc), closeStore
+}
+
+func newReplication(remoteURL string) *influxdb.Replication {
+	return &influxdb.Replication{
+		OrgID:          orgID,
+		Name:           "edge",
+		LocalBucketID:  bucketID,
+		RemoteURL:      remoteURL,
+		RemoteToken:    "secret",
+		RemoteOrgID:    remoteOrgID,
+		RemoteBucketID: remoteBktID,
+	}
+}
+
+func TestService_CreateReplication(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  func(r *influxdb.Replication)
+		wantErr string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name:    "missing name",
+			update:  func(r *influxdb.Replication) { r.Name = "" },
+			wantErr: influxdb.EInvalid,
+		},
+		{
+			name:    "invalid remote URL",
+			update:  func(r *influxdb.Replication) { r.RemoteURL = "ftp://remote" },
+			wantErr: influxdb.EInvalid,
+		},
+		{
+			name:    "bucket of another org",
+			update:  func(r *influxdb.Replication) { r.OrgID = otherOrgID },
+			wantErr: influxdb.EInvalid,
+		},
+		{
+			name:    "bucket not found",
+			update:  func(r *influxdb.Replication) { r.LocalBucketID = remoteBktID },
+			wantErr: influxdb.ENotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, done := newTestService(t)
+			defer done()
+
+			r := newReplication("http://remote:8086")
+			if tt.update != nil {
+				tt.update(r)
+			}
+			err := svc.CreateReplication(context.Background(), r)
+			if tt.wantErr != "" {
+				if code := influxdb.ErrorCode(err); code != tt.wantErr {
+					t.Fatalf("unexpected error code -got/+want\n\t- %q\n\t+ %q", code, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !r.ID.Valid() {
+				t.Fatal("expected replication to have a valid ID")
+			}
+			if r.MaxQueueSizeBytes != influxdb.DefaultReplicationMaxQueueSizeBytes {
+				t.Fatalf("expected default max queue size, got %d", r.MaxQueueSizeBytes)
+			}
+
+			got, err := svc.FindReplicationByID(context.Background(), r.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(r, got); diff != "" {
+				t.Fatalf("unexpected replication -want/+got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_UpdateDeleteReplication(t *testing.T) {
+	ctx := context.Background()
+	svc, done := newTestService(t)
+	defer done()
+
+	r := newReplication("http://remote:8086")
+	if err := svc.CreateReplication(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	url := "https://other:8086"
+	got, err := svc.UpdateReplication(ctx, r.ID, influxdb.ReplicationUpdate{RemoteURL: &url})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.RemoteURL != url {
+		t.Fatalf("unexpected remote URL -got/+want\n\t- %q\n\t+ %q", got.RemoteURL, url)
+	}
+
+	invalid := ""
+	if _, err := svc.UpdateReplication(ctx, r.ID, influxdb.ReplicationUpdate{Name: &invalid}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid update to fail, got %v", err)
+	}
+
+	rs, err := svc.FindReplications(ctx, influxdb.ReplicationFilter{LocalBucketID: &r.LocalBucketID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 {
+		t.Fatalf("expected 1 replication, got %d", len(rs))
+	}
+	other := otherOrgID
+	if rs, err = svc.FindReplications(ctx, influxdb.ReplicationFilter{OrgID: &other}); err != nil || len(rs) != 0 {
+		t.Fatalf("expected no replication of other org, got %d, %v", len(rs), err)
+	}
+
+	if err := svc.DeleteReplication(ctx, r.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.FindReplicationByID(ctx, r.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected deleted replication to be not found, got %v", err)
+	}
+	if err := svc.DeleteReplication(ctx, r.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected deleting a deleted replication to fail, got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"go.uber.org/zap"
+)
+
+// Bounds of the delay between retries of a write the remote instance did not
+// accept.
+const (
+	minRetryDelay = time.Second
+	maxRetryDelay = 5 * time.Minute
+)
+
+// errNonRetryable is returned when the remote instance rejects a write as
+// invalid, so that retrying it cannot succeed.
+var errNonRetryable = errors.New("write rejected by the remote instance")
+
+// stream sends the writes queued for a replication to its remote instance,
+// oldest first. A write is only removed from the queue once the remote
+// instance accepts it; until then it is retried with an exponential backoff.
+type stream struct {
+	queue   *queue
+	client  *http.Client
+	log     *zap.Logger
+	metrics *metrics
+	id      string
+
+	mu     sync.Mutex
+	config influxdb.Replication
+
+	cancel func()
+	done   chan struct{}
+
+	// wake interrupts the wait before the next retry, when the configuration
+	// of the replication is updated.
+	wake chan struct{}
+}
+
+func newStream(r *influxdb.Replication, q *queue, client *http.Client, log *zap.Logger, m *metrics) *stream {
+	return &stream{
+		queue:   q,
+		client:  client,
+		log:     log.With(zap.String("replication_id", r.ID.String())),
+		metrics: m,
+		id:      r.ID.String(),
+		config:  *r,
+		done:    make(chan struct{}),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// start runs the stream in the background until stop is called.
+func (s *stream) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.metrics.QueueSizeBytes.WithLabelValues(s.id).Set(float64(s.queue.Size()))
+	go s.run(ctx)
+}
+
+// stop stops the stream and waits for it to return.
+func (s *stream) stop() {
+	s.cancel()
+	<-s.done
+}
+
+// update replaces the configuration of the replication and retries the
+// pending write without waiting.
+func (s *stream) update(r *influxdb.Replication) {
+	s.mu.Lock()
+	s.config = *r
+	s.mu.Unlock()
+	s.queue.SetMaxSize(r.MaxQueueSizeBytes)
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// replication returns the configuration of the replication.
+func (s *stream) replication() influxdb.Replication {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.config
+}
+
+// enqueue appends a batch of line protocol, for which room was reserved, to
+// the queue.
+func (s *stream) enqueue(data []byte) error {
+	if err := s.queue.AppendReserved(data); err != nil {
+		return err
+	}
+	s.metrics.QueuedBytes.WithLabelValues(s.id).Add(float64(len(data)))
+	s.metrics.QueueSizeBytes.WithLabelValues(s.id).Set(float64(s.queue.Size()))
+	return nil
+}
+
+func (s *stream) run(ctx context.Context) {
+	defer close(s.done)
+
+	delay := minRetryDelay
+	for {
+		data, err := s.queue.Peek()
+		if err == io.EOF {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.queue.notify:
+			}
+			continue
+		}
+		if err == errCorruptEntry {
+			s.log.Error("Dropping corrupt replication queue segment", zap.Error(err))
+			if err = s.queue.Skip(); err == nil {
+				continue
+			}
+		}
+		if err != nil {
+			s.log.Error("Failed to read replication queue", zap.Error(err))
+			if !s.wait(ctx, delay) {
+				return
+			}
+			delay = nextRetryDelay(delay)
+			continue
+		}
+
+		retryAfter, err := s.send(ctx, data)
+		switch {
+		case err == nil:
+			s.metrics.SentBytes.WithLabelValues(s.id).Add(float64(len(data)))
+		case errors.Is(err, errNonRetryable) && s.replication().DropNonRetryableData:
+			s.log.Warn("Dropping write rejected by the remote instance", zap.Error(err))
+			s.metrics.DroppedBytes.WithLabelValues(s.id, "non_retryable").Add(float64(len(data)))
+		default:
+			if ctx.Err() != nil {
+				return
+			}
+			s.log.Warn("Failed to replicate write, retrying", zap.Error(err), zap.Duration("delay", delay))
+			if retryAfter > delay {
+				delay = retryAfter
+			}
+			if !s.wait(ctx, delay) {
+				return
+			}
+			delay = nextRetryDelay(delay)
+			continue
+		}
+
+		if err := s.queue.Advance(); err != nil {
+			s.log.Error("Failed to acknowledge replicated write", zap.Error(err))
+		}
+		s.metrics.QueueSizeBytes.WithLabelValues(s.id).Set(float64(s.queue.Size()))
+		delay = minRetryDelay
+	}
+}
+
+// wait waits for d, or until the configuration of the replication is updated.
+// It returns false if ctx is done.
+func (s *stream) wait(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-s.wake:
+		return true
+	case <-t.C:
+		return true
+	}
+}
+
+func nextRetryDelay(d time.Duration) time.Duration {
+	if d *= 2; d > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return d
+}
+
+// send writes a batch of line protocol to the remote instance. When the write
+// fails, it returns the delay the remote instance asked to wait before
+// retrying, if any.
+func (s *stream) send(ctx context.Context, data []byte) (time.Duration, error) {
+	r := s.replication()
+
+	u, err := url.Parse(strings.TrimSuffix(r.RemoteURL, "/") + "/api/v2/write")
+	if err != nil {
+		return 0, err
+	}
+	params := u.Query()
+	params.Set("org", r.RemoteOrgID.String())
+	params.Set("bucket", r.RemoteBucketID.String())
+	params.Set("precision", "ns")
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if r.RemoteToken != "" {
+		req.Header.Set("Authorization", "Token "+r.RemoteToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.metrics.RemoteWriteErrors.WithLabelValues(s.id).Inc()
+		return 0, err
+	}
+	defer resp.Body.Close()
+	s.metrics.RemoteWriteResponses.WithLabelValues(s.id, strconv.Itoa(resp.StatusCode)).Inc()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return 0, nil
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("remote write failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	switch resp.StatusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return 0, fmt.Errorf("%w: %v", errNonRetryable, err)
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		if secs, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil && secs > 0 {
+			return time.Duration(secs) * time.Second, err
+		}
+	}
+	return 0, err
+}
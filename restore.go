@@ -0,0 +1,42 @@
+package influxdb
+
+import (
+	"context"
+	"io"
+)
+
+// RestoreService represents the online data restore functions of InfluxDB.
+type RestoreService interface {
+	// RestoreBucket writes the data of a backed up bucket held in a TSM file into
+	// a bucket of the running instance. The tombstone file of the TSM file is read
+	// from tombstone, which may be nil when the backup holds none.
+	RestoreBucket(ctx context.Context, req RestoreBucketRequest, tsm, tombstone io.Reader) error
+}
+
+// RestoreBucketRequest identifies the backed up bucket being restored and the
+// bucket its data is restored into.
+type RestoreBucketRequest struct {
+	// SourceOrgID and SourceBucketID identify the bucket in the backup.
+	SourceOrgID    ID
+	SourceBucketID ID
+	// OrgID and BucketID identify the bucket the data is restored into.
+	OrgID    ID
+	BucketID ID
+}
+
+// Validate returns an error if the request is invalid.
+func (r RestoreBucketRequest) Validate() error {
+	if !r.SourceOrgID.Valid() || !r.SourceBucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "source orgID and bucketID are required",
+		}
+	}
+	if !r.OrgID.Valid() || !r.BucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID and bucketID are required",
+		}
+	}
+	return nil
+}
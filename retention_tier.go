@@ -0,0 +1,94 @@
+package influxdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// Aggregates a retention tier can downsample data with.
+const (
+	RetentionAggregateMean  = "mean"
+	RetentionAggregateMin   = "min"
+	RetentionAggregateMax   = "max"
+	RetentionAggregateSum   = "sum"
+	RetentionAggregateFirst = "first"
+	RetentionAggregateLast  = "last"
+)
+
+// RetentionTier is a downsampled tier of a bucket. Once data is older than
+// the retention period of the bucket (or of the previous tier), it is
+// aggregated into windows of the tier and kept until it is older than the
+// retention period of the tier. A retention period of 0 keeps the tier forever.
+//
+// Downsampled data is stored in the series it was aggregated from, at the
+// start of each window, so it is queried like any other data of the bucket.
+type RetentionTier struct {
+	Window          time.Duration `json:"window"`
+	Aggregate       string        `json:"aggregate"`
+	RetentionPeriod time.Duration `json:"retentionPeriod"`
+}
+
+// ValidateRetentionTiers checks that the retention tiers of the bucket
+// follow each other: every tier must keep its data longer than the one
+// before it, over windows that are a multiple of the windows of the tier
+// before it.
+func (b *Bucket) ValidateRetentionTiers() error {
+	if len(b.RetentionTiers) == 0 {
+		return nil
+	}
+
+	if b.RetentionPeriod == InfiniteRetention {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "retention tiers require a bucket with a finite retention period",
+		}
+	}
+
+	var (
+		prevPeriod = b.RetentionPeriod
+		prevWindow time.Duration
+	)
+	for i, tier := range b.RetentionTiers {
+		if tier.Window <= 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("retention tier %d: window must be positive", i),
+			}
+		}
+
+		switch tier.Aggregate {
+		case RetentionAggregateMean, RetentionAggregateMin, RetentionAggregateMax,
+			RetentionAggregateSum, RetentionAggregateFirst, RetentionAggregateLast:
+		default:
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("retention tier %d: unknown aggregate %q", i, tier.Aggregate),
+			}
+		}
+
+		if prevWindow > 0 && tier.Window%prevWindow != 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("retention tier %d: window must be a multiple of %s", i, prevWindow),
+			}
+		}
+
+		if tier.RetentionPeriod == InfiniteRetention {
+			if i != len(b.RetentionTiers)-1 {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("retention tier %d: only the last tier can have an infinite retention period", i),
+				}
+			}
+		} else if tier.RetentionPeriod <= prevPeriod {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("retention tier %d: retention period must be longer than %s", i, prevPeriod),
+			}
+		}
+
+		prevPeriod, prevWindow = tier.RetentionPeriod, tier.Window
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestBucket_ValidateRetentionTiers(t *testing.T) {
+	const day = 24 * time.Hour
+
+	tests := []struct {
+		name            string
+		retentionPeriod time.Duration
+		tiers           []influxdb.RetentionTier
+		wantErr         bool
+	}{
+		{
+			name: "no tiers",
+		},
+		{
+			name:            "valid tiers",
+			retentionPeriod: 7 * day,
+			tiers: []influxdb.RetentionTier{
+				{Window: 5 * time.Minute, Aggregate: "mean", RetentionPeriod: 90 * day},
+				{Window: time.Hour, Aggregate: "mean", RetentionPeriod: 730 * day},
+			},
+		},
+		{
+			name:            "last tier kept forever",
+			retentionPeriod: 7 * day,
+			tiers: []influxdb.RetentionTier{
+				{Window: 5 * time.Minute, Aggregate: "max", RetentionPeriod: 90 * day},
+				{Window: time.Hour, Aggregate: "max"},
+			},
+		},
+		{
+			name: "tiers require a finite bucket retention",
+			tiers: []influxdb.RetentionTier{
+				{Window: time.Hour, Aggregate: "mean"},
+			},
+			wantErr: true,
+		},
+		{
+			name:            "window must be positive",
+			retentionPeriod: 7 * day,
+			tiers: []influxdb.RetentionTier{
+				{Aggregate: "mean", RetentionPeriod: 90 * day},
+			},
+			wantErr: true,
+		},
+		{
+			name:            "unknown aggregate",
+			retentionPeriod: 7 * day,
+			tiers: []influxdb.RetentionTier{
+				{Window: time.Hour, Aggregate: "count", RetentionPeriod: 90 * day},
+			},
+			wantErr: true,
+		},
+		{
+			name:            "window must be a multiple of the previous window",
+			retentionPeriod: 7 * day,
+			tiers: []influxdb.RetentionTier{
+				{Window: 5 * time.Minute, Aggregate: "mean", RetentionPeriod: 90 * day},
+				{Window: 7 * time.Minute, Aggregate: "mean", RetentionPeriod: 730 * day},
+			},
+			wantErr: true,
+		},
+		{
+			name:            "retention periods must increase",
+			retentionPeriod: 7 * day,
+			tiers: []influxdb.RetentionTier{
+				{Window: 5 * time.Minute, Aggregate: "mean", RetentionPeriod: 7 * day},
+			},
+			wantErr: true,
+		},
+		{
+			name:            "only the last tier can be kept forever",
+			retentionPeriod: 7 * day,
+			tiers: []influxdb.RetentionTier{
+				{Window: 5 * time.Minute, Aggregate: "mean"},
+				{Window: time.Hour, Aggregate: "mean", RetentionPeriod: 730 * day},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := influxdb.Bucket{
+				RetentionPeriod: tt.retentionPeriod,
+				RetentionTiers:  tt.tiers,
+			}
+			if err := b.ValidateRetentionTiers(); (err != nil) != tt.wantErr {
+				t.Errorf("Bucket.ValidateRetentionTiers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
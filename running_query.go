@@ -0,0 +1,45 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// RunningQuery is a query the query controller compiles, queues or executes.
+type RunningQuery struct {
+	// ID identifies the query until it finishes; it is not persisted.
+	ID    ID `json:"id"`
+	OrgID ID `json:"orgID"`
+	// UserID and AuthorizationID identify the user and the token which
+	// submitted the query, if known.
+	UserID          ID     `json:"userID,omitempty"`
+	AuthorizationID ID     `json:"authorizationID,omitempty"`
+	CompilerType    string `json:"compilerType"`
+	// Query is the text of the query, if its compiler has one.
+	Query string `json:"query,omitempty"`
+	// State is one of created, compiling, queueing or executing.
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"createdAt"`
+	Elapsed   Duration  `json:"elapsed"`
+	// MemoryBytes is the memory the query allocates while it executes.
+	MemoryBytes int64 `json:"memoryBytes"`
+}
+
+// RunningQueryFilter represents a set of filters that restrict the running
+// queries returned.
+type RunningQueryFilter struct {
+	OrgID *ID
+}
+
+// RunningQueryService inspects and cancels the running queries.
+type RunningQueryService interface {
+	// FindRunningQueries returns the running queries matching the filter,
+	// sorted by ID.
+	FindRunningQueries(ctx context.Context, filter RunningQueryFilter) ([]*RunningQuery, error)
+
+	// FindRunningQueryByID returns a single running query by ID.
+	FindRunningQueryByID(ctx context.Context, id ID) (*RunningQuery, error)
+
+	// CancelRunningQuery cancels a running query by ID.
+	CancelRunningQuery(ctx context.Context, id ID) error
+}
@@ -0,0 +1,203 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Recurrence periods of a silence.
+const (
+	SilenceRecurDaily  = "daily"
+	SilenceRecurWeekly = "weekly"
+)
+
+// Silence mutes the notifications of the statuses it matches during a
+// maintenance window. The statuses are still recorded by their checks, but
+// notification rules do not send them to their endpoints while the silence
+// is active.
+type Silence struct {
+	ID          ID     `json:"id,omitempty"`
+	OrgID       ID     `json:"orgID,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Start and End are the bounds of the window, or of its first occurrence
+	// when the silence recurs.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Recurrence repeats the window, it is nil for a single window.
+	Recurrence *SilenceRecurrence `json:"recurrence,omitempty"`
+
+	// TagRules restrict the silence to the statuses matching all of them.
+	// A silence without tag rules matches every status of its organization.
+	TagRules []TagRule `json:"tagRules,omitempty"`
+
+	CRUDLog
+}
+
+// SilenceRecurrence repeats the window of a silence every day or every week.
+type SilenceRecurrence struct {
+	Every string `json:"every"`
+	// Until is the optional end of the recurrence, the window repeats
+	// forever without it.
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// Period returns the duration between two occurrences.
+func (r SilenceRecurrence) Period() time.Duration {
+	switch r.Every {
+	case SilenceRecurDaily:
+		return 24 * time.Hour
+	case SilenceRecurWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// Valid returns an error if the silence is missing a required field or its
+// window is invalid.
+func (s *Silence) Valid() error {
+	if s.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence name is required",
+		}
+	}
+	if !s.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence requires a valid orgID",
+		}
+	}
+	if !s.End.After(s.Start) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence end must be after its start",
+		}
+	}
+	if r := s.Recurrence; r != nil {
+		period := r.Period()
+		if period == 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "silence recurrence must be one of daily or weekly",
+			}
+		}
+		if s.End.Sub(s.Start) >= period {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "silence window must be shorter than its recurrence",
+			}
+		}
+		if r.Until != nil && !r.Until.After(s.Start) {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "silence recurrence must end after the silence starts",
+			}
+		}
+	}
+	for _, tr := range s.TagRules {
+		if err := tr.Valid(); err != nil {
+			return err
+		}
+		if tr.Operator == RegexEqual || tr.Operator == NotRegexEqual {
+			if _, err := regexp.Compile(tr.Value); err != nil {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("silence tag rule %q has an invalid regular expression", tr.Key),
+					Err:  err,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Expired returns true if the silence has no window left after now.
+func (s *Silence) Expired(now time.Time) bool {
+	if s.Recurrence == nil {
+		return !s.End.After(now)
+	}
+	return s.Recurrence.Until != nil && !s.Recurrence.Until.After(now)
+}
+
+// ActiveAt returns true if t falls in a window of the silence.
+func (s *Silence) ActiveAt(t time.Time) bool {
+	if t.Before(s.Start) {
+		return false
+	}
+	if s.Recurrence == nil {
+		return t.Before(s.End)
+	}
+	if s.Recurrence.Until != nil && !t.Before(*s.Recurrence.Until) {
+		return false
+	}
+	return t.Sub(s.Start)%s.Recurrence.Period() < s.End.Sub(s.Start)
+}
+
+// SilenceUpdate is the set of fields of a silence to update.
+type SilenceUpdate struct {
+	Name        *string            `json:"name,omitempty"`
+	Description *string            `json:"description,omitempty"`
+	Start       *time.Time         `json:"start,omitempty"`
+	End         *time.Time         `json:"end,omitempty"`
+	Recurrence  *SilenceRecurrence `json:"recurrence,omitempty"`
+	TagRules    *[]TagRule         `json:"tagRules,omitempty"`
+}
+
+// Apply applies the update to the silence.
+func (u SilenceUpdate) Apply(s *Silence) {
+	if u.Name != nil {
+		s.Name = *u.Name
+	}
+	if u.Description != nil {
+		s.Description = *u.Description
+	}
+	if u.Start != nil {
+		s.Start = *u.Start
+	}
+	if u.End != nil {
+		s.End = *u.End
+	}
+	if u.Recurrence != nil {
+		s.Recurrence = u.Recurrence
+		// An empty recurrence removes the recurrence of the silence.
+		if u.Recurrence.Every == "" {
+			s.Recurrence = nil
+		}
+	}
+	if u.TagRules != nil {
+		s.TagRules = *u.TagRules
+	}
+}
+
+// SilenceFilter represents a set of filters that restrict the returned
+// silences.
+type SilenceFilter struct {
+	OrgID *ID
+	// Active restricts the silences to the ones active at the given time.
+	Active *time.Time
+}
+
+// SilenceService represents a service for managing the silences of the
+// notifications.
+type SilenceService interface {
+	// FindSilenceByID returns a single silence by ID.
+	FindSilenceByID(ctx context.Context, id ID) (*Silence, error)
+
+	// FindSilences returns the silences matching the filter.
+	FindSilences(ctx context.Context, filter SilenceFilter) ([]*Silence, error)
+
+	// CreateSilence creates a new silence and sets s.ID with the new
+	// identifier.
+	CreateSilence(ctx context.Context, s *Silence) error
+
+	// UpdateSilence updates a single silence with the changeset.
+	UpdateSilence(ctx context.Context, id ID, upd SilenceUpdate) (*Silence, error)
+
+	// DeleteSilence removes a silence by ID.
+	DeleteSilence(ctx context.Context, id ID) error
+}
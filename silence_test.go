@@ -0,0 +1,113 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestSilence_Valid(t *testing.T) {
+	start := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		silence influxdb.Silence
+		wantErr bool
+	}{
+		{
+			name: "valid window",
+			silence: influxdb.Silence{
+				Name: "maintenance", OrgID: 1, Start: start, End: start.Add(time.Hour),
+			},
+		},
+		{
+			name: "valid recurrence",
+			silence: influxdb.Silence{
+				Name: "maintenance", OrgID: 1, Start: start, End: start.Add(time.Hour),
+				Recurrence: &influxdb.SilenceRecurrence{Every: influxdb.SilenceRecurWeekly},
+			},
+		},
+		{
+			name: "missing name",
+			silence: influxdb.Silence{
+				OrgID: 1, Start: start, End: start.Add(time.Hour),
+			},
+			wantErr: true,
+		},
+		{
+			name: "end before start",
+			silence: influxdb.Silence{
+				Name: "maintenance", OrgID: 1, Start: start, End: start.Add(-time.Hour),
+			},
+			wantErr: true,
+		},
+		{
+			name: "window longer than its recurrence",
+			silence: influxdb.Silence{
+				Name: "maintenance", OrgID: 1, Start: start, End: start.Add(25 * time.Hour),
+				Recurrence: &influxdb.SilenceRecurrence{Every: influxdb.SilenceRecurDaily},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid recurrence",
+			silence: influxdb.Silence{
+				Name: "maintenance", OrgID: 1, Start: start, End: start.Add(time.Hour),
+				Recurrence: &influxdb.SilenceRecurrence{Every: "monthly"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid regular expression",
+			silence: influxdb.Silence{
+				Name: "maintenance", OrgID: 1, Start: start, End: start.Add(time.Hour),
+				TagRules: []influxdb.TagRule{
+					{Tag: influxdb.Tag{Key: "host", Value: "db("}, Operator: influxdb.RegexEqual},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.silence.Valid()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSilence_ActiveAt(t *testing.T) {
+	start := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+	until := start.Add(48 * time.Hour)
+	s := influxdb.Silence{
+		Start: start,
+		End:   start.Add(4 * time.Hour),
+		Recurrence: &influxdb.SilenceRecurrence{
+			Every: influxdb.SilenceRecurDaily,
+			Until: &until,
+		},
+	}
+
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{t: start.Add(-time.Minute), want: false},
+		{t: start, want: true},
+		{t: start.Add(3 * time.Hour), want: true},
+		{t: start.Add(4 * time.Hour), want: false},
+		{t: start.Add(25 * time.Hour), want: true},
+		{t: start.Add(47 * time.Hour), want: false},
+		{t: start.Add(49 * time.Hour), want: false},
+	}
+
+	for _, tt := range tests {
+		if got := s.ActiveAt(tt.t); got != tt.want {
+			t.Errorf("ActiveAt(%s) = %t, want %t", tt.t, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/tracing"
@@ -13,11 +14,18 @@ type BucketDeleter interface {
 	DeleteBucket(context.Context, influxdb.ID, influxdb.ID) error
 }
 
+// BucketPartitioner defines the behaviour of setting the duration of the time
+// partitions the data of a bucket is written to.
+type BucketPartitioner interface {
+	SetBucketPartitionDuration(orgID, bucketID influxdb.ID, d time.Duration)
+}
+
 // BucketService wraps an existing influxdb.BucketService implementation.
 //
 // BucketService ensures that when a bucket is deleted, all stored data
 // associated with the bucket is either removed, or marked to be removed via a
-// future compaction.
+// future compaction. If the engine is a BucketPartitioner, the time partitions
+// of buckets are kept up to date with their shard group duration.
 type BucketService struct {
 	inner  influxdb.BucketService
 	engine BucketDeleter
@@ -85,7 +93,11 @@ func (s *BucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) er
 	if s.inner == nil || s.engine == nil {
 		return errors.New("nil inner BucketService or Engine")
 	}
-	return s.inner.CreateBucket(ctx, b)
+	if err := s.inner.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+	s.setPartitionDuration(b.OrgID, b.ID, b.ShardGroupDuration)
+	return nil
 }
 
 // UpdateBucket updates a single bucket with changeset.
@@ -97,7 +109,12 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd in
 	if s.inner == nil || s.engine == nil {
 		return nil, errors.New("nil inner BucketService or Engine")
 	}
-	return s.inner.UpdateBucket(ctx, id, upd)
+	b, err := s.inner.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.setPartitionDuration(b.OrgID, b.ID, b.ShardGroupDuration)
+	return b, nil
 }
 
 // DeleteBucket removes a bucket by ID.
@@ -116,5 +133,14 @@ func (s *BucketService) DeleteBucket(ctx context.Context, bucketID influxdb.ID)
 	if err := s.engine.DeleteBucket(ctx, bucket.OrgID, bucketID); err != nil {
 		return err
 	}
+	s.setPartitionDuration(bucket.OrgID, bucketID, 0)
 	return s.inner.DeleteBucket(ctx, bucketID)
 }
+
+// setPartitionDuration sets the time partition duration of a bucket if the
+// engine supports partitioning.
+func (s *BucketService) setPartitionDuration(orgID, bucketID influxdb.ID, d time.Duration) {
+	if p, ok := s.engine.(BucketPartitioner); ok {
+		p.SetBucketPartitionDuration(orgID, bucketID, d)
+	}
+}
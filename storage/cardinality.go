@@ -0,0 +1,419 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/pkg/hll"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/seriesfile"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Precision of the sketches estimating the series of a bucket and the values
+// of a tag key of a bucket. A dense sketch of precision p takes 2^p bytes, and
+// estimates with a standard error of about 1.04/sqrt(2^p).
+const (
+	seriesSketchPrecision   = 14
+	tagValueSketchPrecision = 12
+)
+
+const cardinalitySubsystem = "cardinality" // sub-system associated with metrics for series cardinality.
+
+// The names of the series of a bucket are its encoded org ID followed by its
+// encoded bucket ID.
+const (
+	encodedOrgIDLen = 8
+	encodedNameLen  = 16
+)
+
+// sketch is a HyperLogLog++ sketch that caches its last estimate, as counting
+// a dense sketch visits every one of its registers.
+type sketch struct {
+	hll   *hll.Plus
+	count uint64 // estimate at the last count
+	added uint64 // values added since the last count
+}
+
+func newSketch(p uint8) *sketch {
+	h, err := hll.NewPlus(p)
+	if err != nil {
+		panic(err)
+	}
+	return &sketch{hll: h}
+}
+
+func (s *sketch) add(v []byte) {
+	s.hll.Add(v)
+	s.added++
+}
+
+// estimate returns the estimated number of distinct values added to s.
+func (s *sketch) estimate() uint64 {
+	if s.added > 0 {
+		s.count, s.added = s.hll.Count(), 0
+	}
+	return s.count
+}
+
+// full returns true if n more distinct values would take the estimate of s
+// past max. s is only counted if the values added since its last count could
+// have filled it.
+func (s *sketch) full(n, max uint64) bool {
+	if s.count+s.added+n <= max {
+		return false
+	}
+	return s.estimate()+n > max
+}
+
+// bucketCardinality holds the sketches of the series of a bucket and of the
+// values of each of its tag keys.
+type bucketCardinality struct {
+	series    *sketch
+	tagValues map[string]*sketch
+}
+
+// cardinalityEstimator estimates the number of series of buckets and the
+// number of values of their tag keys. The series of an organization are the
+// sum of the series of its buckets, as series never span buckets.
+//
+// Sketches cannot forget values, so the estimates of a bucket only decrease
+// when the whole bucket is deleted, or when the engine is reopened and the
+// estimator is seeded again from the series file.
+//
+// cardinalityEstimator is a prometheus.Collector of the estimates.
+type cardinalityEstimator struct {
+	mu      sync.Mutex
+	buckets map[string]*bucketCardinality // keyed by encoded org and bucket ID
+
+	seriesDesc    *prometheus.Desc
+	tagValuesDesc *prometheus.Desc
+}
+
+func newCardinalityEstimator(labels prometheus.Labels) *cardinalityEstimator {
+	names := []string{"org_id", "bucket_id"}
+	return &cardinalityEstimator{
+		buckets: make(map[string]*bucketCardinality),
+		seriesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, cardinalitySubsystem, "series"),
+			"Estimated number of series of a bucket.",
+			names, labels),
+		tagValuesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, cardinalitySubsystem, "max_tag_values"),
+			"Estimated number of values of the tag key of a bucket with the most values.",
+			names, labels),
+	}
+}
+
+// isSystemTag returns true if key is the tag key of the measurement or the
+// field of a series, whose values are not limited.
+func isSystemTag(key []byte) bool {
+	return bytes.Equal(key, models.MeasurementTagKeyBytes) || bytes.Equal(key, models.FieldKeyTagKeyBytes)
+}
+
+// bucket returns the sketches of the bucket name, creating them if create is
+// true. It must be called with c.mu held.
+func (c *cardinalityEstimator) bucket(name []byte, create bool) *bucketCardinality {
+	b := c.buckets[string(name)]
+	if b == nil && create {
+		b = &bucketCardinality{
+			series:    newSketch(seriesSketchPrecision),
+			tagValues: make(map[string]*sketch),
+		}
+		c.buckets[string(name)] = b
+	}
+	return b
+}
+
+// addSeriesLocked adds the series with the series file key key to the
+// sketches of its bucket. It must be called with c.mu held.
+func (c *cardinalityEstimator) addSeriesLocked(key []byte) {
+	name, tags := seriesfile.ParseSeriesKey(key)
+	b := c.bucket(name, true)
+	b.series.add(key)
+	for _, t := range tags {
+		if isSystemTag(t.Key) {
+			continue
+		}
+		s := b.tagValues[string(t.Key)]
+		if s == nil {
+			s = newSketch(tagValueSketchPrecision)
+			b.tagValues[string(t.Key)] = s
+		}
+		s.add(t.Value)
+	}
+}
+
+// add adds the series of a collection whose series have been created in the
+// series file.
+func (c *cardinalityEstimator) add(collection *tsdb.SeriesCollection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for iter := collection.Iterator(); iter.Next(); {
+		if key := iter.SeriesKey(); len(key) > 0 {
+			c.addSeriesLocked(key)
+		}
+	}
+}
+
+// seed replaces the sketches with sketches of the series of the series file.
+func (c *cardinalityEstimator) seed(sfile *seriesfile.SeriesFile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.buckets = make(map[string]*bucketCardinality)
+	for _, p := range sfile.Partitions() {
+		for _, id := range p.AppendSeriesIDs(nil) {
+			if p.IsDeleted(id) {
+				continue
+			}
+			if key := p.SeriesKey(id); len(key) > 0 {
+				c.addSeriesLocked(key)
+			}
+		}
+	}
+}
+
+// deleteBucket drops the sketches of the bucket name.
+func (c *cardinalityEstimator) deleteBucket(name []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.buckets, string(name))
+}
+
+// orgSeriesLocked returns the estimated number of series of the org of the
+// bucket name. It must be called with c.mu held.
+func (c *cardinalityEstimator) orgSeriesLocked(name []byte) uint64 {
+	var n uint64
+	for bname, b := range c.buckets {
+		if len(bname) == encodedNameLen && bname[:encodedOrgIDLen] == string(name[:encodedOrgIDLen]) {
+			n += b.series.estimate()
+		}
+	}
+	return n
+}
+
+// usage returns the estimated cardinality of a bucket of an org, or of all the
+// buckets of the org if bucketID is not valid.
+func (c *cardinalityEstimator) usage(orgID, bucketID influxdb.ID) *influxdb.CardinalityUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	u := &influxdb.CardinalityUsage{OrgID: orgID, BucketID: bucketID}
+	if !bucketID.Valid() {
+		u.BucketID = 0
+		org := tsdb.EncodeName(orgID, 0)
+		u.Series = c.orgSeriesLocked(org[:])
+		return u
+	}
+
+	name := tsdb.EncodeName(orgID, bucketID)
+	b := c.bucket(name[:], false)
+	if b == nil {
+		return u
+	}
+	u.Series = b.series.estimate()
+	u.TagValues = make(map[string]uint64, len(b.tagValues))
+	for k, s := range b.tagValues {
+		u.TagValues[k] = s.estimate()
+	}
+	return u
+}
+
+// Describe implements prometheus.Collector.
+func (c *cardinalityEstimator) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.seriesDesc
+	ch <- c.tagValuesDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *cardinalityEstimator) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, b := range c.buckets {
+		if len(name) != encodedNameLen {
+			continue
+		}
+		orgID, bucketID := tsdb.DecodeNameSlice([]byte(name))
+		org, bucket := orgID.String(), bucketID.String()
+
+		var maxValues uint64
+		for _, s := range b.tagValues {
+			if n := s.estimate(); n > maxValues {
+				maxValues = n
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(c.seriesDesc, prometheus.GaugeValue, float64(b.series.estimate()), org, bucket)
+		ch <- prometheus.MustNewConstMetric(c.tagValuesDesc, prometheus.GaugeValue, float64(maxValues), org, bucket)
+	}
+}
+
+// tagValue identifies a value of a tag key of a bucket.
+type tagValue struct {
+	name, key, value string
+}
+
+// limitCardinality drops the points of a collection that would create a series
+// taking a bucket or an org past its series limit, or a tag key of a bucket
+// past its limit of values. It returns an error describing the dropped points,
+// or nil if none was dropped. It must be called with e.mu held.
+//
+// Limits are checked against estimates, so a bucket may hold slightly more or
+// less series or tag values than its limits before writes are dropped.
+func (e *Engine) limitCardinality(collection *tsdb.SeriesCollection) error {
+	maxBucket, maxOrg, maxValues := e.config.MaxSeriesPerBucket, e.config.MaxSeriesPerOrg, e.config.MaxValuesPerTag
+	if maxBucket <= 0 && maxOrg <= 0 && maxValues <= 0 {
+		return nil
+	}
+
+	c := e.cardinality
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var (
+		buf     []byte
+		dropped int
+		reason  string
+		j       int
+
+		// The new series and tag values accepted so far, which are not yet
+		// in the series file, index or sketches.
+		newSeries  = make(map[string]bool)
+		newValues  = make(map[tagValue]bool)
+		bucketN    = make(map[string]uint64)
+		orgN       = make(map[string]uint64)
+		tagN       = make(map[string]uint64)
+		orgSeries  = make(map[string]uint64)
+		batchN     = uint64(collection.Length())
+		candidates []tagValue
+	)
+
+	orgEstimate := func(name []byte) uint64 {
+		org := string(name[:encodedOrgIDLen])
+		n, ok := orgSeries[org]
+		if !ok {
+			n = c.orgSeriesLocked(name)
+			orgSeries[org] = n
+		}
+		return n
+	}
+
+	for iter := collection.Iterator(); iter.Next(); {
+		name, tags := iter.Name(), iter.Tags()
+		if len(name) != encodedNameLen {
+			collection.Copy(j, iter.Index())
+			j++
+			continue
+		}
+		org := string(name[:encodedOrgIDLen])
+		b := c.bucket(name, true)
+
+		// Accept the point without looking up its series if the batch could
+		// not take any limit past its maximum even if all its series were new.
+		full := (maxBucket > 0 && b.series.full(batchN, uint64(maxBucket))) ||
+			(maxOrg > 0 && orgEstimate(name)+batchN > uint64(maxOrg))
+		for i := 0; !full && maxValues > 0 && i < len(tags); i++ {
+			if isSystemTag(tags[i].Key) {
+				continue
+			}
+			if s := b.tagValues[string(tags[i].Key)]; s != nil {
+				full = s.full(batchN, uint64(maxValues))
+			} else {
+				full = batchN > uint64(maxValues)
+			}
+		}
+		if !full {
+			collection.Copy(j, iter.Index())
+			j++
+			continue
+		}
+
+		buf = seriesfile.AppendSeriesKey(buf[:0], name, tags)
+		if newSeries[string(buf)] || e.sfile.HasSeries(name, tags, nil) {
+			collection.Copy(j, iter.Index())
+			j++
+			continue
+		}
+
+		var why string
+		switch {
+		case maxBucket > 0 && b.series.full(bucketN[string(name)]+1, uint64(maxBucket)):
+			why = fmt.Sprintf("max series per bucket (%d) exceeded", maxBucket)
+		case maxOrg > 0 && orgEstimate(name)+orgN[org]+1 > uint64(maxOrg):
+			why = fmt.Sprintf("max series per org (%d) exceeded", maxOrg)
+		}
+
+		candidates = candidates[:0]
+		for i := 0; why == "" && maxValues > 0 && i < len(tags); i++ {
+			t := tags[i]
+			if isSystemTag(t.Key) {
+				continue
+			}
+			tv := tagValue{name: string(name), key: string(t.Key), value: string(t.Value)}
+			if newValues[tv] {
+				continue
+			}
+			if ok, err := e.index.HasTagValue(name, t.Key, t.Value); err != nil || ok {
+				continue
+			}
+
+			s, n := b.tagValues[tv.key], tagN[tv.name+tv.key]+1
+			if (s != nil && s.full(n, uint64(maxValues))) || (s == nil && n > uint64(maxValues)) {
+				why = fmt.Sprintf("max values per tag (%d) exceeded for tag key %q", maxValues, t.Key)
+			}
+			candidates = append(candidates, tv)
+		}
+
+		if why != "" {
+			if reason == "" {
+				reason = why
+			}
+			dropped++
+			continue
+		}
+
+		newSeries[string(buf)] = true
+		bucketN[string(name)]++
+		orgN[org]++
+		for _, tv := range candidates {
+			newValues[tv] = true
+			tagN[tv.name+tv.key]++
+		}
+
+		collection.Copy(j, iter.Index())
+		j++
+	}
+	collection.Truncate(j)
+
+	if dropped == 0 {
+		return nil
+	}
+	return &influxdb.Error{
+		Code: influxdb.EUnprocessableEntity,
+		Msg:  fmt.Sprintf("partial write: %s dropped=%d", reason, dropped),
+	}
+}
+
+// CardinalityUsage returns the estimated cardinality of a bucket of an org, or
+// of all the buckets of the org if bucketID is not valid.
+func (e *Engine) CardinalityUsage(ctx context.Context, orgID, bucketID influxdb.ID) (*influxdb.CardinalityUsage, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	u := e.cardinality.usage(orgID, bucketID)
+	u.MaxValuesPerTag = e.config.MaxValuesPerTag
+	if bucketID.Valid() {
+		u.MaxSeries = e.config.MaxSeriesPerBucket
+	} else {
+		u.MaxSeries = e.config.MaxSeriesPerOrg
+	}
+	return u, nil
+}
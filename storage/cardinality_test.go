@@ -0,0 +1,199 @@
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+	"github.com/influxdata/influxdb/v2/tsdb"
+)
+
+// cardinalityPoint returns a point of the bucket of the org with the tags of
+// tagKV, as key/value pairs.
+func cardinalityPoint(orgID, bucketID influxdb.ID, tagKV ...string) models.Point {
+	tags := map[string]string{models.MeasurementTagKey: "cpu", models.FieldKeyTagKey: "value"}
+	for i := 0; i < len(tagKV); i += 2 {
+		tags[tagKV[i]] = tagKV[i+1]
+	}
+	return models.MustNewPoint(
+		tsdb.EncodeNameString(orgID, bucketID),
+		models.NewTags(tags),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+}
+
+func TestEngine_CardinalityLimits(t *testing.T) {
+	const (
+		orgID   = influxdb.ID(0x3131313131313131)
+		bucket1 = influxdb.ID(0x3232323232323232)
+		bucket2 = influxdb.ID(0x3333333333333333)
+	)
+
+	tests := []struct {
+		name    string
+		config  func(c *storage.Config)
+		points  []models.Point
+		dropped bool
+		series  map[influxdb.ID]uint64
+	}{
+		{
+			name: "no limits",
+			points: []models.Point{
+				cardinalityPoint(orgID, bucket1, "host", "a"),
+				cardinalityPoint(orgID, bucket1, "host", "b"),
+				cardinalityPoint(orgID, bucket1, "host", "c"),
+			},
+			series: map[influxdb.ID]uint64{bucket1: 3},
+		},
+		{
+			name:   "max series per bucket",
+			config: func(c *storage.Config) { c.MaxSeriesPerBucket = 2 },
+			points: []models.Point{
+				cardinalityPoint(orgID, bucket1, "host", "a"),
+				cardinalityPoint(orgID, bucket1, "host", "b"),
+				cardinalityPoint(orgID, bucket1, "host", "c"),
+				cardinalityPoint(orgID, bucket2, "host", "c"),
+			},
+			dropped: true,
+			series:  map[influxdb.ID]uint64{bucket1: 2, bucket2: 1},
+		},
+		{
+			name:   "points of existing series are accepted",
+			config: func(c *storage.Config) { c.MaxSeriesPerBucket = 2 },
+			points: []models.Point{
+				cardinalityPoint(orgID, bucket1, "host", "a"),
+				cardinalityPoint(orgID, bucket1, "host", "b"),
+				cardinalityPoint(orgID, bucket1, "host", "a"),
+				cardinalityPoint(orgID, bucket1, "host", "b"),
+			},
+			series: map[influxdb.ID]uint64{bucket1: 2},
+		},
+		{
+			name:   "max series per org",
+			config: func(c *storage.Config) { c.MaxSeriesPerOrg = 3 },
+			points: []models.Point{
+				cardinalityPoint(orgID, bucket1, "host", "a"),
+				cardinalityPoint(orgID, bucket1, "host", "b"),
+				cardinalityPoint(orgID, bucket2, "host", "a"),
+				cardinalityPoint(orgID, bucket2, "host", "b"),
+			},
+			dropped: true,
+			series:  map[influxdb.ID]uint64{bucket1: 2, bucket2: 1},
+		},
+		{
+			name:   "max values per tag",
+			config: func(c *storage.Config) { c.MaxValuesPerTag = 2 },
+			points: []models.Point{
+				cardinalityPoint(orgID, bucket1, "host", "a", "region", "west"),
+				cardinalityPoint(orgID, bucket1, "host", "b", "region", "west"),
+				cardinalityPoint(orgID, bucket1, "host", "c", "region", "west"),
+				cardinalityPoint(orgID, bucket1, "host", "a", "region", "east"),
+			},
+			dropped: true,
+			series:  map[influxdb.ID]uint64{bucket1: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := storage.NewConfig()
+			if tt.config != nil {
+				tt.config(&config)
+			}
+			engine := NewEngine(config, 0, 0)
+			defer engine.Close()
+			engine.MustOpen()
+
+			// Write the points one at a time, as limits are estimated per
+			// batch.
+			var dropped bool
+			for _, p := range tt.points {
+				err := engine.Engine.WritePoints(context.Background(), []models.Point{p})
+				if influxdb.ErrorCode(err) == influxdb.EUnprocessableEntity {
+					dropped = true
+				} else if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if dropped != tt.dropped {
+				t.Errorf("got dropped %v, want %v", dropped, tt.dropped)
+			}
+
+			for bucketID, want := range tt.series {
+				u, err := engine.CardinalityUsage(context.Background(), orgID, bucketID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if u.Series != want {
+					t.Errorf("bucket %s: got %d series, want %d", bucketID, u.Series, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEngine_CardinalityUsage(t *testing.T) {
+	const (
+		orgID    = influxdb.ID(0x3131313131313131)
+		bucketID = influxdb.ID(0x3232323232323232)
+	)
+
+	config := storage.NewConfig()
+	config.MaxSeriesPerBucket = 100
+	config.MaxValuesPerTag = 10
+	engine := NewEngine(config, 0, 0)
+	defer engine.Close()
+	engine.MustOpen()
+
+	var points []models.Point
+	for i := 0; i < 6; i++ {
+		points = append(points, cardinalityPoint(orgID, bucketID, "host", fmt.Sprint(i), "region", fmt.Sprint(i%2)))
+	}
+	if err := engine.Engine.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func() {
+		t.Helper()
+		u, err := engine.CardinalityUsage(context.Background(), orgID, bucketID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Series != 6 || u.MaxSeries != 100 || u.MaxValuesPerTag != 10 {
+			t.Errorf("unexpected bucket usage: %+v", u)
+		}
+		if u.TagValues["host"] != 6 || u.TagValues["region"] != 2 || len(u.TagValues) != 2 {
+			t.Errorf("unexpected tag values: %v", u.TagValues)
+		}
+
+		u, err = engine.CardinalityUsage(context.Background(), orgID, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Series != 6 || u.TagValues != nil {
+			t.Errorf("unexpected org usage: %+v", u)
+		}
+	}
+	check()
+
+	// The estimates are seeded from the series file when the engine opens.
+	engine.Engine.Close()
+	engine.MustOpen()
+	check()
+
+	if err := engine.DeleteBucket(context.Background(), orgID, bucketID); err != nil {
+		t.Fatal(err)
+	}
+	u, err := engine.CardinalityUsage(context.Background(), orgID, bucketID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Series != 0 {
+		t.Errorf("got %d series after deleting the bucket, want 0", u.Series)
+	}
+}
@@ -41,6 +41,12 @@ type Config struct {
 	// Index config.
 	Index     tsi1.Config `toml:"index"`
 	IndexPath string      `toml:"index-path"` // Overrides the default path.
+
+	// Cardinality limits. Writes creating series beyond a limit are dropped.
+	// A limit of 0 disables it.
+	MaxSeriesPerBucket int `toml:"max-series-per-bucket"`
+	MaxSeriesPerOrg    int `toml:"max-series-per-org"`
+	MaxValuesPerTag    int `toml:"max-values-per-tag"`
 }
 
 // NewConfig initialises a new config for an Engine.
@@ -0,0 +1,485 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage/wal"
+	"github.com/influxdata/influxdb/v2/tsdb/cursors"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+	"github.com/influxdata/influxdb/v2/tsdb/value"
+)
+
+// downsampleBatchWindows is the number of windows of a bucket downsampled at
+// once. The aggregated values of a batch are held in memory until they have
+// been written.
+const downsampleBatchWindows = 1000
+
+// downsampleJournalFile is the file, in the directory of the engine, the batch
+// being downsampled is journaled to until its data has been replaced.
+const downsampleJournalFile = "downsample.journal"
+
+// DownsampleBucketRange replaces the data of a bucket in [min, max) with one
+// value per window of the retention tier and series, aggregated with the
+// aggregate of the tier and timestamped at the start of its window. min and
+// max should be aligned to the window of the tier.
+//
+// Downsampling data that has already been downsampled to the same tier leaves
+// it unchanged. Each batch of windows is journaled with its aggregated values
+// before its data is deleted and replaced by them; a batch that was
+// interrupted is replayed from its journal before anything else is
+// downsampled, so that its data is neither lost nor aggregated twice.
+func (e *Engine) DownsampleBucketRange(ctx context.Context, orgID, bucketID influxdb.ID, tier influxdb.RetentionTier, min, max int64) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	window := int64(tier.Window)
+	if window <= 0 {
+		return fmt.Errorf("invalid downsampling window %s", tier.Window)
+	}
+
+	e.downsampleMu.Lock()
+	defer e.downsampleMu.Unlock()
+
+	if err := e.resumeDownsample(ctx); err != nil {
+		return err
+	}
+
+	// Start at the oldest data in range rather than at min, which may well
+	// be math.MinInt64.
+	first, err := e.firstTimestamp(ctx, orgID, bucketID, min, max)
+	if err != nil || first == math.MaxInt64 {
+		return err
+	}
+
+	for start := windowStart(first, window); start < max; start += window * downsampleBatchWindows {
+		end := start + window*downsampleBatchWindows
+		if end > max || end < start {
+			end = max
+		}
+
+		values, err := e.downsampleValues(ctx, orgID, bucketID, tier, start, end)
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		batch := downsampleBatch{OrgID: orgID, BucketID: bucketID, Min: start, Max: end - 1}
+		if err := e.journalDownsample(&batch, values); err != nil {
+			return err
+		}
+		if err := e.replaceDownsampled(ctx, &batch, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downsampleBatch is a journaled batch of downsampled windows. The data of the
+// bucket in [Min, Max] is replaced by Values, the encoded wal.WriteWALEntry of
+// the values aggregated from it.
+type downsampleBatch struct {
+	OrgID    influxdb.ID `json:"orgID"`
+	BucketID influxdb.ID `json:"bucketID"`
+	Min      int64       `json:"min"`
+	Max      int64       `json:"max"`
+	Values   []byte      `json:"values"`
+}
+
+func (e *Engine) downsampleJournalPath() string {
+	return filepath.Join(e.path, downsampleJournalFile)
+}
+
+// journalDownsample durably records batch with the values aggregated from its
+// data before any of the data is replaced.
+func (e *Engine) journalDownsample(batch *downsampleBatch, values map[string][]value.Value) error {
+	entry := wal.WriteWALEntry{Values: values}
+	data, err := entry.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	batch.Values = data
+
+	data, err = json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	return writeFileSynced(e.downsampleJournalPath(), data)
+}
+
+// resumeDownsample replaces the data of the batch left in the journal by an
+// interrupted downsampling, if any.
+func (e *Engine) resumeDownsample(ctx context.Context) error {
+	data, err := ioutil.ReadFile(e.downsampleJournalPath())
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var batch downsampleBatch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return err
+	}
+	entry := wal.WriteWALEntry{Values: make(map[string][]value.Value)}
+	if err := entry.UnmarshalBinary(batch.Values); err != nil {
+		return err
+	}
+	return e.replaceDownsampled(ctx, &batch, entry.Values)
+}
+
+// replaceDownsampled deletes the data of batch, writes the values aggregated
+// from it and removes the journal. Replacing the data of a batch again, after
+// it was interrupted at any point, has the same result.
+func (e *Engine) replaceDownsampled(ctx context.Context, batch *downsampleBatch, values map[string][]value.Value) error {
+	if err := e.DeleteBucketRange(ctx, batch.OrgID, batch.BucketID, batch.Min, batch.Max); err != nil {
+		return err
+	}
+	if err := e.writeValues(ctx, values); err != nil {
+		return err
+	}
+	return os.Remove(e.downsampleJournalPath())
+}
+
+// firstTimestamp returns the timestamp of the oldest value of the bucket in
+// [min, max), or math.MaxInt64 if there is none.
+func (e *Engine) firstTimestamp(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64) (int64, error) {
+	first := int64(math.MaxInt64)
+	err := e.forEachSeriesCursor(ctx, orgID, bucketID, min, max, func(_ string, cur cursors.Cursor) error {
+		var ts []int64
+		switch c := cur.(type) {
+		case cursors.FloatArrayCursor:
+			ts = c.Next().Timestamps
+		case cursors.IntegerArrayCursor:
+			ts = c.Next().Timestamps
+		case cursors.UnsignedArrayCursor:
+			ts = c.Next().Timestamps
+		case cursors.BooleanArrayCursor:
+			ts = c.Next().Timestamps
+		case cursors.StringArrayCursor:
+			ts = c.Next().Timestamps
+		}
+		if len(ts) > 0 && ts[0] < first {
+			first = ts[0]
+		}
+		return nil
+	})
+	return first, err
+}
+
+// downsampleValues reads the data of the bucket in [min, max) and returns it
+// aggregated over the windows of tier, keyed by series field key.
+func (e *Engine) downsampleValues(ctx context.Context, orgID, bucketID influxdb.ID, tier influxdb.RetentionTier, min, max int64) (map[string][]value.Value, error) {
+	values := make(map[string][]value.Value)
+	err := e.forEachSeriesCursor(ctx, orgID, bucketID, min, max, func(key string, cur cursors.Cursor) error {
+		var vals []value.Value
+		switch c := cur.(type) {
+		case cursors.FloatArrayCursor:
+			vals = downsampleFloats(c, int64(tier.Window), tier.Aggregate)
+		case cursors.IntegerArrayCursor:
+			vals = downsampleIntegers(c, int64(tier.Window), tier.Aggregate)
+		case cursors.UnsignedArrayCursor:
+			vals = downsampleUnsigneds(c, int64(tier.Window), tier.Aggregate)
+		case cursors.BooleanArrayCursor:
+			vals = downsampleBooleans(c, int64(tier.Window), tier.Aggregate)
+		case cursors.StringArrayCursor:
+			vals = downsampleStrings(c, int64(tier.Window), tier.Aggregate)
+		default:
+			return fmt.Errorf("unexpected cursor type %T", cur)
+		}
+		if err := cur.Err(); err != nil {
+			return err
+		}
+		if len(vals) > 0 {
+			values[key] = vals
+		}
+		return nil
+	})
+	return values, err
+}
+
+// forEachSeriesCursor calls fn with the series field key and an ascending
+// cursor over [min, max) of every series of the bucket.
+func (e *Engine) forEachSeriesCursor(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, fn func(key string, cur cursors.Cursor) error) error {
+	sc, err := e.CreateSeriesCursor(ctx, orgID, bucketID, nil)
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+
+	itr, err := e.CreateCursorIterator(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := cursors.CursorRequest{
+		Ascending: true,
+		StartTime: min,
+		EndTime:   max - 1,
+	}
+	for {
+		row, err := sc.Next()
+		if err != nil {
+			return err
+		} else if row == nil {
+			return nil
+		}
+
+		req.Name = row.Name
+		req.Tags = row.Tags
+		req.Field = string(row.Tags.Get(models.FieldKeyTagKeyBytes))
+
+		cur, err := itr.Next(ctx, &req)
+		if err != nil {
+			return err
+		} else if cur == nil {
+			continue
+		}
+
+		key := tsm1.SeriesFieldKey(string(models.MakeKey(row.Name, row.Tags)), req.Field)
+		err = fn(key, cur)
+		cur.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// windowStart returns the start of the window of size window that holds t.
+func windowStart(t, window int64) int64 {
+	mod := t % window
+	if mod < 0 {
+		mod += window
+	}
+	return t - mod
+}
+
+func downsampleFloats(c cursors.FloatArrayCursor, window int64, aggregate string) []value.Value {
+	var (
+		vals                       []value.Value
+		start, n                   int64
+		first, last, min, max, sum float64
+	)
+	flush := func() {
+		if n == 0 {
+			return
+		}
+		v := last
+		switch aggregate {
+		case influxdb.RetentionAggregateMean:
+			v = sum / float64(n)
+		case influxdb.RetentionAggregateMin:
+			v = min
+		case influxdb.RetentionAggregateMax:
+			v = max
+		case influxdb.RetentionAggregateSum:
+			v = sum
+		case influxdb.RetentionAggregateFirst:
+			v = first
+		}
+		vals = append(vals, value.NewFloatValue(start, v))
+	}
+
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if ws := windowStart(t, window); n == 0 || ws != start {
+				flush()
+				start, n = ws, 0
+				first, min, max, sum = v, v, v, 0
+			}
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+			sum += v
+			last = v
+			n++
+		}
+	}
+	flush()
+	return vals
+}
+
+func downsampleIntegers(c cursors.IntegerArrayCursor, window int64, aggregate string) []value.Value {
+	var (
+		vals                       []value.Value
+		start, n                   int64
+		first, last, min, max, sum int64
+	)
+	flush := func() {
+		if n == 0 {
+			return
+		}
+		v := last
+		switch aggregate {
+		case influxdb.RetentionAggregateMean:
+			v = sum / n
+		case influxdb.RetentionAggregateMin:
+			v = min
+		case influxdb.RetentionAggregateMax:
+			v = max
+		case influxdb.RetentionAggregateSum:
+			v = sum
+		case influxdb.RetentionAggregateFirst:
+			v = first
+		}
+		vals = append(vals, value.NewIntegerValue(start, v))
+	}
+
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if ws := windowStart(t, window); n == 0 || ws != start {
+				flush()
+				start, n = ws, 0
+				first, min, max, sum = v, v, v, 0
+			}
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+			sum += v
+			last = v
+			n++
+		}
+	}
+	flush()
+	return vals
+}
+
+func downsampleUnsigneds(c cursors.UnsignedArrayCursor, window int64, aggregate string) []value.Value {
+	var (
+		vals                       []value.Value
+		start, n                   int64
+		first, last, min, max, sum uint64
+	)
+	flush := func() {
+		if n == 0 {
+			return
+		}
+		v := last
+		switch aggregate {
+		case influxdb.RetentionAggregateMean:
+			v = sum / uint64(n)
+		case influxdb.RetentionAggregateMin:
+			v = min
+		case influxdb.RetentionAggregateMax:
+			v = max
+		case influxdb.RetentionAggregateSum:
+			v = sum
+		case influxdb.RetentionAggregateFirst:
+			v = first
+		}
+		vals = append(vals, value.NewUnsignedValue(start, v))
+	}
+
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if ws := windowStart(t, window); n == 0 || ws != start {
+				flush()
+				start, n = ws, 0
+				first, min, max, sum = v, v, v, 0
+			}
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+			sum += v
+			last = v
+			n++
+		}
+	}
+	flush()
+	return vals
+}
+
+// downsampleBooleans keeps the first value of each window with the first
+// aggregate and the last value with any other aggregate.
+func downsampleBooleans(c cursors.BooleanArrayCursor, window int64, aggregate string) []value.Value {
+	var (
+		vals        []value.Value
+		start, n    int64
+		first, last bool
+	)
+	flush := func() {
+		if n == 0 {
+			return
+		}
+		v := last
+		if aggregate == influxdb.RetentionAggregateFirst {
+			v = first
+		}
+		vals = append(vals, value.NewBooleanValue(start, v))
+	}
+
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if ws := windowStart(t, window); n == 0 || ws != start {
+				flush()
+				start, n = ws, 0
+				first = v
+			}
+			last = v
+			n++
+		}
+	}
+	flush()
+	return vals
+}
+
+// downsampleStrings keeps the first value of each window with the first
+// aggregate and the last value with any other aggregate.
+func downsampleStrings(c cursors.StringArrayCursor, window int64, aggregate string) []value.Value {
+	var (
+		vals        []value.Value
+		start, n    int64
+		first, last string
+	)
+	flush := func() {
+		if n == 0 {
+			return
+		}
+		v := last
+		if aggregate == influxdb.RetentionAggregateFirst {
+			v = first
+		}
+		vals = append(vals, value.NewStringValue(start, v))
+	}
+
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		for i, t := range a.Timestamps {
+			v := a.Values[i]
+			if ws := windowStart(t, window); n == 0 || ws != start {
+				flush()
+				start, n = ws, 0
+				first = v
+			}
+			last = v
+			n++
+		}
+		// The values of a string array may point into a decoding buffer
+		// that is reused by the next call to Next.
+		first, last = string([]byte(first)), string([]byte(last))
+	}
+	flush()
+	return vals
+}
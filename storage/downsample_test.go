@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/cursors"
+)
+
+func TestEngine_DownsampleBucketRange_Resume(t *testing.T) {
+	path, err := ioutil.TempDir("", "storage_downsample_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	org, bucket := influxdb.ID(1), influxdb.ID(2)
+	name := tsdb.EncodeNameString(org, bucket)
+	tags := models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"})
+	tier := influxdb.RetentionTier{Window: time.Minute, Aggregate: influxdb.RetentionAggregateSum}
+	min, max := int64(0), time.Unix(120, 0).UnixNano()
+
+	engine := NewEngine(path, NewConfig(), WithEngineID(0), WithNodeID(0))
+	if err := engine.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var points []models.Point
+	for i, v := range []float64{1, 2, 3, 5, 7, 9} {
+		points = append(points, models.MustNewPoint(name, tags, map[string]interface{}{"value": v}, time.Unix(int64(i*30), 0)))
+	}
+	if err := engine.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	// Interrupt downsampling after the data of the batch has been deleted but
+	// before its aggregated values have been written.
+	values, err := engine.downsampleValues(context.Background(), org, bucket, tier, min, max)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := downsampleBatch{OrgID: org, BucketID: bucket, Min: min, Max: max - 1}
+	if err := engine.journalDownsample(&batch, values); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.DeleteBucketRange(context.Background(), org, bucket, batch.Min, batch.Max); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	engine = NewEngine(path, NewConfig(), WithEngineID(0), WithNodeID(0))
+	if err := engine.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+
+	// Downsampling again completes the interrupted batch without aggregating
+	// its aggregated values a second time.
+	if err := engine.DownsampleBucketRange(context.Background(), org, bucket, tier, min, max); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(engine.downsampleJournalPath()); !os.IsNotExist(err) {
+		t.Fatalf("expected journal to be removed, got %v", err)
+	}
+
+	itr, err := engine.CreateCursorIterator(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := itr.Next(context.Background(), &cursors.CursorRequest{
+		Name:      []byte(name),
+		Tags:      tags,
+		Field:     "value",
+		Ascending: true,
+		StartTime: math.MinInt64,
+		EndTime:   math.MaxInt64,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cur.Close()
+
+	a := cur.(cursors.FloatArrayCursor).Next()
+	expTimes := []int64{0, time.Minute.Nanoseconds(), 2 * time.Minute.Nanoseconds(), 2*time.Minute.Nanoseconds() + 30*time.Second.Nanoseconds()}
+	expValues := []float64{3, 8, 7, 9}
+	if !reflect.DeepEqual(a.Timestamps, expTimes) || !reflect.DeepEqual(a.Values, expValues) {
+		t.Fatalf("got %v %v, expected %v %v", a.Timestamps, a.Values, expTimes, expValues)
+	}
+}
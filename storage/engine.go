@@ -63,6 +63,12 @@ type Engine struct {
 	retentionEnforcer        runner
 	retentionEnforcerLimiter runnable
 
+	// downsampleMu serializes downsampling, which journals its batches to a
+	// single file.
+	downsampleMu sync.Mutex
+
+	cardinality *cardinalityEstimator
+
 	defaultMetricLabels prometheus.Labels
 
 	writePointsValidationEnabled bool
@@ -114,7 +120,11 @@ func WithNodeID(id int) Option {
 // metrics are labelled correctly.
 func WithRetentionEnforcer(finder BucketFinder) Option {
 	return func(e *Engine) {
-		e.retentionEnforcer = newRetentionEnforcer(e, e.engine, finder)
+		r := newRetentionEnforcer(e, e.engine, finder)
+		r.Downsampler = e
+		r.Partitioner = e
+		r.downsampledPath = filepath.Join(e.path, downsampledFile)
+		e.retentionEnforcer = r
 	}
 }
 
@@ -217,6 +227,7 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	if r, ok := e.retentionEnforcer.(*retentionEnforcer); ok {
 		r.SetDefaultMetricLabels(e.defaultMetricLabels)
 	}
+	e.cardinality = newCardinalityEstimator(e.defaultMetricLabels)
 
 	return e
 }
@@ -252,6 +263,7 @@ func (e *Engine) PrometheusCollectors() []prometheus.Collector {
 	metrics = append(metrics, tsm1.PrometheusCollectors()...)
 	metrics = append(metrics, wal.PrometheusCollectors()...)
 	metrics = append(metrics, RetentionPrometheusCollectors()...)
+	metrics = append(metrics, e.cardinality)
 	return metrics
 }
 
@@ -278,6 +290,16 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 		return err
 	}
 
+	// Estimate the cardinality of buckets before any write is limited by it.
+	e.cardinality.seed(e.sfile)
+
+	// Restore the time partitions of buckets before any data is snapshotted.
+	if r, ok := e.retentionEnforcer.(*retentionEnforcer); ok {
+		if err := r.refreshPartitions(ctx); err != nil {
+			e.logger.Warn("Unable to determine bucket time partitions", zap.Error(err))
+		}
+	}
+
 	if err := e.replayWAL(); err != nil {
 		return err
 	}
@@ -560,6 +582,9 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return ErrEngineClosed
 	}
 
+	// Drop the points that would exceed a cardinality limit.
+	limitErr := e.limitCardinality(collection)
+
 	// Convert the collection to values for adding to the WAL/Cache.
 	values, err := tsm1.CollectionToValues(collection)
 	if err != nil {
@@ -571,7 +596,11 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return err
 	}
 
-	return e.writePointsLocked(ctx, collection, values)
+	err = e.writePointsLocked(ctx, collection, values)
+	if _, ok := err.(tsdb.PartialWriteError); (err == nil || ok) && limitErr != nil {
+		return limitErr
+	}
+	return err
 }
 
 // writePointsLocked does the work of writing points and must be called under some sort of lock.
@@ -602,6 +631,7 @@ func (e *Engine) writePointsLocked(ctx context.Context, collection *tsdb.SeriesC
 	if err := e.engine.WriteValues(values); err != nil {
 		return err
 	}
+	e.cardinality.add(collection)
 
 	return collection.PartialWriteError()
 }
@@ -644,7 +674,13 @@ func (e *Engine) CommitSegments(ctx context.Context, segs []string, fn func() er
 func (e *Engine) DeleteBucket(ctx context.Context, orgID, bucketID influxdb.ID) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
-	return e.DeleteBucketRange(ctx, orgID, bucketID, math.MinInt64, math.MaxInt64)
+	if err := e.DeleteBucketRange(ctx, orgID, bucketID, math.MinInt64, math.MaxInt64); err != nil {
+		return err
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	e.cardinality.deleteBucket(encoded[:])
+	return nil
 }
 
 // DeleteBucketRange deletes an entire bucket from the storage engine.
@@ -666,6 +702,26 @@ func (e *Engine) DeleteBucketRange(ctx context.Context, orgID, bucketID influxdb
 	return e.deleteBucketRangeLocked(ctx, orgID, bucketID, min, max, nil)
 }
 
+// SetBucketPartitionDuration sets the duration of the time partitions the data
+// of a bucket is written to. A duration of 0 disables partitioning.
+func (e *Engine) SetBucketPartitionDuration(orgID, bucketID influxdb.ID, d time.Duration) {
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	e.engine.Partitioner.SetDuration(encoded[:], d)
+}
+
+// SetPartitionDurations replaces the time partition durations of all buckets
+// with the shard group durations of buckets.
+func (e *Engine) SetPartitionDurations(buckets []*influxdb.Bucket) {
+	durations := make(map[string]time.Duration, len(buckets))
+	for _, b := range buckets {
+		if b.ShardGroupDuration > 0 {
+			encoded := tsdb.EncodeName(b.OrgID, b.ID)
+			durations[string(encoded[:])] = b.ShardGroupDuration
+		}
+	}
+	e.engine.Partitioner.SetDurations(durations)
+}
+
 // DeleteBucketRangePredicate deletes data within a bucket from the storage engine. Any data
 // deleted must be in [min, max], and the key must match the predicate if provided.
 func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
@@ -707,11 +763,14 @@ func (e *Engine) deleteBucketRangeLocked(ctx context.Context, orgID, bucketID in
 	return e.engine.DeletePrefixRange(ctx, name, min, max, pred)
 }
 
-// CreateBackup creates a "snapshot" of all TSM data in the Engine.
+// CreateBackup creates a "snapshot" of the TSM data in the Engine matching the filter.
 //   1) Snapshot the cache to ensure the backup includes all data written before now.
-//   2) Create hard links to all TSM files, in a new directory within the engine root directory.
+//      As the cache is flushed to TSM files, no WAL segments are needed to restore the backup.
+//   2) Create hard links to the TSM files, in a new directory within the engine root directory.
+//      Files modified before filter.Since are skipped and files holding data outside of the
+//      filtered org or bucket are rewritten to only contain the data of the org or bucket.
 //   3) Return a unique backup ID (invalid after the process terminates) and list of files.
-func (e *Engine) CreateBackup(ctx context.Context) (int, []string, error) {
+func (e *Engine) CreateBackup(ctx context.Context, filter influxdb.BackupFilter) (int, []string, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -719,11 +778,27 @@ func (e *Engine) CreateBackup(ctx context.Context) (int, []string, error) {
 		return 0, nil, ErrEngineClosed
 	}
 
+	if err := filter.Validate(); err != nil {
+		return 0, nil, err
+	}
+
 	if err := e.engine.WriteSnapshot(ctx, tsm1.CacheStatusBackup); err != nil {
 		return 0, nil, err
 	}
 
-	id, snapshotPath, err := e.engine.FileStore.CreateSnapshot(ctx)
+	var snapshotFilter tsm1.SnapshotFilter
+	if filter.BucketID != nil {
+		encoded := tsdb.EncodeName(*filter.OrgID, *filter.BucketID)
+		snapshotFilter.Prefix = models.EscapeMeasurement(encoded[:])
+	} else if filter.OrgID != nil {
+		encoded := tsdb.EncodeOrgName(*filter.OrgID)
+		snapshotFilter.Prefix = models.EscapeMeasurement(encoded[:])
+	}
+	if !filter.Since.IsZero() {
+		snapshotFilter.Since = filter.Since.UnixNano()
+	}
+
+	id, snapshotPath, err := e.engine.FileStore.CreateFilteredSnapshot(ctx, snapshotFilter)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -810,6 +885,130 @@ func (e *Engine) InternalBackupPath(backupID int) string {
 	return e.engine.FileStore.InternalBackupPath(backupID)
 }
 
+// restoreBatchSize is the number of values written to the engine at once
+// when restoring a bucket.
+const restoreBatchSize = 10000
+
+// RestoreBucket writes the data of the bucket identified by req.SourceOrgID and
+// req.SourceBucketID in a backed up TSM file into the bucket identified by
+// req.OrgID and req.BucketID. The data is written through the WAL and cache,
+// the same as any other write, so the engine keeps serving while it restores.
+// Data removed by the tombstone file of the backup, if provided, is not restored.
+func (e *Engine) RestoreBucket(ctx context.Context, req influxdb.RestoreBucketRequest, tsm, tombstone io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	dir, err := ioutil.TempDir(e.path, "restore")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// The tombstone file must sit beside the TSM file for the reader to apply it.
+	path := filepath.Join(dir, "restore."+tsm1.TSMFileExtension)
+	if err := copyRestoreFile(path, tsm); err != nil {
+		return errors.WithMessage(err, "failed to copy restore file")
+	}
+	if tombstone != nil {
+		if err := copyRestoreFile(filepath.Join(dir, "restore.tombstone"), tombstone); err != nil {
+			return errors.WithMessage(err, "failed to copy restore tombstone file")
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	r, err := tsm1.NewTSMReader(f, tsm1.WithTSMReaderLogger(e.logger))
+	if err != nil {
+		return multierr.Append(errors.WithMessage(err, "invalid restore file"), f.Close())
+	}
+	defer r.Close()
+
+	src := tsdb.EncodeName(req.SourceOrgID, req.SourceBucketID)
+	srcPrefix := models.EscapeMeasurement(src[:])
+	dst := tsdb.EncodeName(req.OrgID, req.BucketID)
+	dstPrefix := models.EscapeMeasurement(dst[:])
+
+	values := make(map[string][]value.Value)
+	n := 0
+	iter := r.Iterator(srcPrefix)
+	for iter.Next() {
+		key := iter.Key()
+		if !bytes.HasPrefix(key, srcPrefix) {
+			break
+		}
+
+		vals, err := r.ReadAll(key)
+		if err != nil {
+			return err
+		}
+		if len(vals) == 0 {
+			continue
+		}
+
+		newKey := make([]byte, 0, len(dstPrefix)+len(key)-len(srcPrefix))
+		newKey = append(newKey, dstPrefix...)
+		newKey = append(newKey, key[len(srcPrefix):]...)
+		values[string(newKey)] = vals
+
+		if n += len(vals); n >= restoreBatchSize {
+			if err := e.writeValues(ctx, values); err != nil {
+				return err
+			}
+			values, n = make(map[string][]value.Value), 0
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if len(values) > 0 {
+		return e.writeValues(ctx, values)
+	}
+	return nil
+}
+
+// writeValues writes a batch of values, keyed by series field key, to the WAL,
+// cache and index. The cache is snapshotted first if it has grown large enough,
+// so that restoring or downsampling a large bucket does not exceed the maximum
+// cache size.
+func (e *Engine) writeValues(ctx context.Context, values map[string][]value.Value) error {
+	if status := e.engine.ShouldCompactCache(time.Now()); status == tsm1.CacheStatusSizeExceeded {
+		// A failed snapshot is logged by WriteSnapshot; the write below reports
+		// an error if the cache is still too large to accept it.
+		_ = e.engine.WriteSnapshot(ctx, status)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	if _, err := e.wal.WriteMulti(ctx, values); err != nil {
+		return err
+	}
+
+	points := tsm1.ValuesToPoints(values)
+	return e.writePointsLocked(ctx, tsdb.NewSeriesCollection(points), values)
+}
+
+func copyRestoreFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		return multierr.Append(err, f.Close())
+	}
+	return f.Close()
+}
+
 // SeriesCardinality returns the number of series in the engine.
 func (e *Engine) SeriesCardinality() int64 {
 	e.mu.RLock()
@@ -1,12 +1,15 @@
 package storage_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
 	"math"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -16,6 +19,7 @@ import (
 	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/influxdata/influxdb/v2/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/cursors"
 	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -490,6 +494,123 @@ func TestEngine_WriteConflictingBatch(t *testing.T) {
 	}
 }
 
+func TestEngine_RestoreBucket(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		),
+		models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server2"}),
+			map[string]interface{}{"value": 2.0},
+			time.Unix(1, 2),
+		),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, files, err := engine.CreateBackup(context.Background(), influxdb.BackupFilter{OrgID: &engine.org, BucketID: &engine.bucket})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucketID, _ := influxdb.IDFromString("8888888888888888")
+	req := influxdb.RestoreBucketRequest{
+		SourceOrgID:    engine.org,
+		SourceBucketID: engine.bucket,
+		OrgID:          engine.org,
+		BucketID:       *bucketID,
+	}
+
+	var restored int
+	for _, file := range files {
+		if filepath.Ext(file) != "."+tsm1.TSMFileExtension {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := engine.FetchBackupFile(context.Background(), id, file, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.RestoreBucket(context.Background(), req, &buf, nil); err != nil {
+			t.Fatal(err)
+		}
+		restored++
+	}
+	if restored == 0 {
+		t.Fatal("expected backup to contain a TSM file")
+	}
+
+	// The restored bucket holds a copy of each series.
+	if got, exp := engine.SeriesCardinality(), int64(4); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	// An empty file is not a valid TSM file.
+	if err := engine.RestoreBucket(context.Background(), req, &bytes.Buffer{}, nil); err == nil {
+		t.Fatal("expected error restoring an invalid TSM file")
+	}
+}
+
+func TestEngine_DownsampleBucketRange(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	tags := models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"})
+	var points []models.Point
+	for i, v := range []float64{1, 2, 3, 5, 7, 9} {
+		points = append(points, models.MustNewPoint(name, tags, map[string]interface{}{"value": v}, time.Unix(int64(i*30), 0)))
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	tier := influxdb.RetentionTier{Window: time.Minute, Aggregate: influxdb.RetentionAggregateMean}
+	min, max := int64(0), time.Unix(120, 0).UnixNano()
+
+	// Downsampling twice leaves the downsampled data unchanged.
+	for i := 0; i < 2; i++ {
+		if err := engine.DownsampleBucketRange(context.Background(), engine.org, engine.bucket, tier, min, max); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	itr, err := engine.CreateCursorIterator(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := itr.Next(context.Background(), &cursors.CursorRequest{
+		Name:      []byte(name),
+		Tags:      tags,
+		Field:     "value",
+		Ascending: true,
+		StartTime: math.MinInt64,
+		EndTime:   math.MaxInt64,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cur.Close()
+
+	a := cur.(cursors.FloatArrayCursor).Next()
+	expTimes := []int64{0, time.Minute.Nanoseconds(), 2 * time.Minute.Nanoseconds(), 2*time.Minute.Nanoseconds() + 30*time.Second.Nanoseconds()}
+	expValues := []float64{1.5, 4, 7, 9}
+	if !reflect.DeepEqual(a.Timestamps, expTimes) || !reflect.DeepEqual(a.Values, expValues) {
+		t.Fatalf("got %v %v, expected %v %v", a.Timestamps, a.Values, expTimes, expValues)
+	}
+}
+
 // BenchmarkWritePoints_100K demonstrates the impact that batch size has on
 // writing a fixed number of points into storage. In this case 100K points are
 // written according to varying batch sizes.
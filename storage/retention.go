@@ -2,13 +2,17 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"math"
+	"os"
 	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/tracing"
 	"github.com/influxdata/influxdb/v2/logger"
+	"github.com/influxdata/influxdb/v2/pkg/fs"
 	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -17,6 +21,10 @@ import (
 
 const (
 	bucketAPITimeout = 10 * time.Second
+
+	// downsampledFile is the file, in the directory of the engine, the times
+	// up to which the retention tiers have been downsampled are persisted to.
+	downsampledFile = "downsampled.json"
 )
 
 // A Deleter implementation is capable of deleting data from a storage engine.
@@ -24,6 +32,18 @@ type Deleter interface {
 	DeleteBucketRange(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64) error
 }
 
+// A Downsampler implementation is capable of replacing the data of a bucket
+// with its aggregate over the windows of a retention tier.
+type Downsampler interface {
+	DownsampleBucketRange(ctx context.Context, orgID, bucketID influxdb.ID, tier influxdb.RetentionTier, min, max int64) error
+}
+
+// A Partitioner implementation can set the time partitions the data of
+// buckets is written to.
+type Partitioner interface {
+	SetPartitionDurations(buckets []*influxdb.Bucket)
+}
+
 // A Snapshotter implementation can take snapshots of the entire engine.
 type Snapshotter interface {
 	WriteSnapshot(ctx context.Context, status tsm1.CacheStatus) error
@@ -38,11 +58,21 @@ type BucketFinder interface {
 var ErrServiceClosed = errors.New("service is currently closed")
 
 // The retentionEnforcer periodically removes data that is outside of the retention
-// period of the bucket associated with the data.
+// period of the bucket associated with the data. Buckets with retention tiers
+// have their data downsampled into the tiers rather than removed, until it is
+// outside of the retention period of the last tier.
 type retentionEnforcer struct {
 	// Engine provides access to data stored on the engine
 	Engine Deleter
 
+	// Downsampler downsamples data into the retention tiers of buckets. Tiers
+	// are ignored if it is not set.
+	Downsampler Downsampler
+
+	// Partitioner is kept up to date with the shard group durations of
+	// buckets. Partitions are left unchanged if it is not set.
+	Partitioner Partitioner
+
 	Snapshotter Snapshotter
 
 	// BucketService provides an API for retrieving buckets associated with
@@ -52,6 +82,23 @@ type retentionEnforcer struct {
 	logger *zap.Logger
 
 	tracker *retentionTracker
+
+	// downsampled holds, for each tier of each bucket, the time up to which
+	// data has already been downsampled into the tier.
+	downsampled map[tierKey]int64
+
+	// downsampledPath is the file downsampled is persisted to, so that the
+	// tiers are not downsampled again from their start after a restart. It
+	// is only kept in memory if downsampledPath is empty.
+	downsampledPath string
+}
+
+// tierKey identifies a retention tier of a bucket. A changed tier is a new
+// tier whose data has to be downsampled again.
+type tierKey struct {
+	bucketID influxdb.ID
+	index    int
+	tier     influxdb.RetentionTier
 }
 
 // newRetentionEnforcer returns a new enforcer that ensures expired data is
@@ -108,6 +155,9 @@ func (s *retentionEnforcer) run() {
 	if err != nil {
 		log.Error("Unable to determine bucket information", zap.Error(err))
 	} else {
+		if s.Partitioner != nil {
+			s.Partitioner.SetPartitionDurations(buckets)
+		}
 		s.expireData(ctx, buckets, now)
 	}
 	s.tracker.CheckDuration(time.Since(now), err == nil)
@@ -127,7 +177,16 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 		logger.Warn("Unable to snapshot cache before retention", zap.Error(err))
 	}
 
+	if s.downsampled == nil && s.downsampledPath != "" {
+		downsampled, err := readDownsampled(s.downsampledPath)
+		if err != nil {
+			logger.Warn("Unable to read the downsampled retention tiers, downsampling them from their start", zap.Error(err))
+		}
+		s.downsampled = downsampled
+	}
+
 	var skipInf, skipInvalid int
+	downsampled := make(map[tierKey]int64)
 	for _, b := range buckets {
 		bucketFields := []zapcore.Field{
 			zap.String("org_id", b.OrgID.String()),
@@ -149,6 +208,22 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 		min := int64(math.MinInt64)
 		max := now.Add(-b.RetentionPeriod).UnixNano()
 
+		if len(b.RetentionTiers) > 0 && s.Downsampler != nil {
+			err := s.downsampleTiers(ctx, b, now, downsampled)
+			if err != nil {
+				logger.Info("Unable to downsample bucket", append(bucketFields, zap.Error(err))...)
+			}
+
+			// Data is kept in the tiers until it is outside of the retention
+			// period of the last tier.
+			last := b.RetentionTiers[len(b.RetentionTiers)-1]
+			if last.RetentionPeriod == influxdb.InfiniteRetention {
+				s.tracker.IncChecks(err == nil)
+				continue
+			}
+			max = now.Add(-last.RetentionPeriod).UnixNano()
+		}
+
 		span, ctx := tracing.StartSpanFromContext(ctx)
 		span.LogKV(
 			"bucket_id", b.ID,
@@ -170,11 +245,144 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 		span.Finish()
 	}
 
+	s.downsampled = downsampled
+	if s.downsampledPath != "" {
+		if err := writeDownsampled(s.downsampledPath, downsampled); err != nil {
+			logger.Warn("Unable to persist the downsampled retention tiers", zap.Error(err))
+		}
+	}
+
 	if skipInf > 0 || skipInvalid > 0 {
 		logger.Info("Skipped buckets", zap.Int("infinite_retention_total", skipInf), zap.Int("invalid_total", skipInvalid))
 	}
 }
 
+// downsampleTiers downsamples the data of b that has aged into each of its
+// retention tiers. Tier i holds the data between the retention period of the
+// tier before it (or of the bucket) and its own retention period, with the
+// boundaries aligned to the windows of the coarser tier so that no window
+// straddles two tiers. The time up to which each tier has been downsampled is
+// recorded in downsampled.
+func (s *retentionEnforcer) downsampleTiers(ctx context.Context, b *influxdb.Bucket, now time.Time, downsampled map[tierKey]int64) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	tiers := b.RetentionTiers
+	boundary := func(period, window time.Duration) int64 {
+		return windowStart(now.Add(-period).UnixNano(), int64(window))
+	}
+
+	for i, tier := range tiers {
+		key := tierKey{bucketID: b.ID, index: i, tier: tier}
+
+		period := b.RetentionPeriod
+		if i > 0 {
+			period = tiers[i-1].RetentionPeriod
+		}
+		max := boundary(period, tier.Window)
+
+		min := int64(math.MinInt64)
+		if i < len(tiers)-1 {
+			min = boundary(tier.RetentionPeriod, tiers[i+1].Window)
+		}
+		if done, ok := s.downsampled[key]; ok && done > min {
+			min = done
+		}
+
+		if min < max {
+			if err := s.Downsampler.DownsampleBucketRange(ctx, b.OrgID, b.ID, tier, min, max); err != nil {
+				return err
+			}
+			downsampled[key] = max
+		} else if done, ok := s.downsampled[key]; ok {
+			downsampled[key] = done
+		}
+	}
+	return nil
+}
+
+// downsampledTier is the persisted time up to which a retention tier of a
+// bucket has been downsampled.
+type downsampledTier struct {
+	BucketID influxdb.ID            `json:"bucketID"`
+	Index    int                    `json:"index"`
+	Tier     influxdb.RetentionTier `json:"tier"`
+	To       int64                  `json:"to"`
+}
+
+// readDownsampled reads the times up to which retention tiers have been
+// downsampled from path. A missing file holds no tier.
+func readDownsampled(path string) (map[tierKey]int64, error) {
+	downsampled := make(map[tierKey]int64)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return downsampled, nil
+	} else if err != nil {
+		return downsampled, err
+	}
+
+	var tiers []downsampledTier
+	if err := json.Unmarshal(data, &tiers); err != nil {
+		return downsampled, err
+	}
+	for _, t := range tiers {
+		downsampled[tierKey{bucketID: t.BucketID, index: t.Index, tier: t.Tier}] = t.To
+	}
+	return downsampled, nil
+}
+
+// writeDownsampled replaces the times up to which retention tiers have been
+// downsampled in path.
+func writeDownsampled(path string, downsampled map[tierKey]int64) error {
+	tiers := make([]downsampledTier, 0, len(downsampled))
+	for key, to := range downsampled {
+		tiers = append(tiers, downsampledTier{BucketID: key.bucketID, Index: key.index, Tier: key.tier, To: to})
+	}
+	data, err := json.Marshal(tiers)
+	if err != nil {
+		return err
+	}
+	return writeFileSynced(path, data)
+}
+
+// writeFileSynced replaces path with data. data is written to a temporary file
+// and synced before it is renamed over path, so that path holds either its
+// previous or its new contents after a crash.
+func writeFileSynced(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	f, err := fs.CreateFileWithReplacement(tmpPath)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return fs.RenameFileWithReplacement(tmpPath, path)
+}
+
+// refreshPartitions sets the time partitions of all buckets on the
+// Partitioner.
+func (s *retentionEnforcer) refreshPartitions(ctx context.Context) error {
+	if s == nil || s.Partitioner == nil {
+		return nil
+	}
+
+	buckets, err := s.getBucketInformation(ctx)
+	if err != nil {
+		return err
+	}
+	s.Partitioner.SetPartitionDurations(buckets)
+	return nil
+}
+
 // getBucketInformation returns a slice of buckets to run retention on.
 func (s *retentionEnforcer) getBucketInformation(ctx context.Context) ([]*influxdb.Bucket, error) {
 	ctx, cancel := context.WithTimeout(ctx, bucketAPITimeout)
@@ -8,6 +8,7 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sync"
 	"sync/atomic"
@@ -240,6 +241,87 @@ func TestRetentionService(t *testing.T) {
 	})
 }
 
+func TestRetentionService_Tiers(t *testing.T) {
+	t.Parallel()
+	engine := NewTestEngine()
+	downsampler := NewTestDownsampler()
+	dir, err := ioutil.TempDir("", "retention-tiers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	service := newRetentionEnforcer(engine, &TestSnapshotter{}, NewTestBucketFinder())
+	service.Downsampler = downsampler
+	service.downsampledPath = filepath.Join(dir, downsampledFile)
+	now := time.Date(2018, 4, 10, 23, 12, 33, 0, time.UTC)
+
+	tiers := []influxdb.RetentionTier{
+		{Window: 5 * time.Minute, Aggregate: influxdb.RetentionAggregateMean, RetentionPeriod: 90 * 24 * time.Hour},
+		{Window: time.Hour, Aggregate: influxdb.RetentionAggregateMean, RetentionPeriod: 730 * 24 * time.Hour},
+	}
+	bucket := &influxdb.Bucket{
+		OrgID:           1,
+		ID:              2,
+		RetentionPeriod: 7 * 24 * time.Hour,
+		RetentionTiers:  tiers,
+	}
+
+	type downsample struct {
+		tier     influxdb.RetentionTier
+		min, max int64
+	}
+	var got []downsample
+	downsampler.DownsampleBucketRangeFn = func(ctx context.Context, orgID, bucketID influxdb.ID, tier influxdb.RetentionTier, min, max int64) error {
+		got = append(got, downsample{tier: tier, min: min, max: max})
+		return nil
+	}
+
+	var deletedTo int64
+	engine.DeleteBucketRangeFn = func(ctx context.Context, orgID, bucketID influxdb.ID, from, to int64) error {
+		deletedTo = to
+		return nil
+	}
+
+	boundary := func(period, window time.Duration) int64 {
+		return now.Add(-period).Truncate(window).UnixNano()
+	}
+
+	service.expireData(context.Background(), []*influxdb.Bucket{bucket}, now)
+
+	exp := []downsample{
+		{tier: tiers[0], min: boundary(tiers[0].RetentionPeriod, tiers[1].Window), max: boundary(bucket.RetentionPeriod, tiers[0].Window)},
+		{tier: tiers[1], min: math.MinInt64, max: boundary(tiers[0].RetentionPeriod, tiers[1].Window)},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got downsamples\n%#v\nexpected\n%#v", got, exp)
+	}
+
+	// Only data outside of the last tier is deleted.
+	if exp := now.Add(-tiers[1].RetentionPeriod).UnixNano(); deletedTo != exp {
+		t.Fatalf("got delete to %d, expected %d", deletedTo, exp)
+	}
+
+	// The next run only downsamples the data that aged into the tiers since,
+	// even after a restart.
+	restarted := newRetentionEnforcer(engine, &TestSnapshotter{}, NewTestBucketFinder())
+	restarted.Downsampler = downsampler
+	restarted.downsampledPath = service.downsampledPath
+
+	got = nil
+	later := now.Add(time.Hour)
+	restarted.expireData(context.Background(), []*influxdb.Bucket{bucket}, later)
+
+	if len(got) != len(exp) {
+		t.Fatalf("got %d downsamples, expected %d", len(got), len(exp))
+	}
+	for i, d := range got {
+		if d.min != exp[i].max {
+			t.Fatalf("tier %d: got min %d, expected %d", i, d.min, exp[i].max)
+		}
+	}
+}
+
 func TestMetrics_Retention(t *testing.T) {
 	t.Parallel()
 	// metrics to be shared by multiple file stores.
@@ -327,6 +409,22 @@ func (e *TestEngine) DeleteBucketRange(ctx context.Context, orgID, bucketID infl
 	return e.DeleteBucketRangeFn(ctx, orgID, bucketID, min, max)
 }
 
+type TestDownsampler struct {
+	DownsampleBucketRangeFn func(context.Context, influxdb.ID, influxdb.ID, influxdb.RetentionTier, int64, int64) error
+}
+
+func NewTestDownsampler() *TestDownsampler {
+	return &TestDownsampler{
+		DownsampleBucketRangeFn: func(context.Context, influxdb.ID, influxdb.ID, influxdb.RetentionTier, int64, int64) error {
+			return nil
+		},
+	}
+}
+
+func (d *TestDownsampler) DownsampleBucketRange(ctx context.Context, orgID, bucketID influxdb.ID, tier influxdb.RetentionTier, min, max int64) error {
+	return d.DownsampleBucketRangeFn(ctx, orgID, bucketID, tier, min, max)
+}
+
 type TestSnapshotter struct{}
 
 func (s *TestSnapshotter) WriteSnapshot(ctx context.Context, status tsm1.CacheStatus) error {
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/tsdb"
+)
+
+// SchemaViolation is a point rejected by the schema of its bucket.
+type SchemaViolation struct {
+	// Index is the index of the point in the written points.
+	Index int
+
+	// Reason is why the point does not match the schema.
+	Reason string
+}
+
+// SchemaViolationError is returned by SchemaPointsWriter when points do not
+// match the schema of their bucket. The other points have been written.
+type SchemaViolationError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaViolationError) Error() string {
+	reasons := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		reasons = append(reasons, v.Reason)
+	}
+	return fmt.Sprintf("partial write: %d points rejected by bucket schema: %s", len(e.Violations), strings.Join(reasons, "; "))
+}
+
+// SchemaPointsWriter wraps an underlying points writer and only writes the
+// points which match the schema of their bucket. Points written to a bucket
+// without a schema are always written.
+type SchemaPointsWriter struct {
+	// Wrapped points writer.
+	Underlying PointsWriter
+
+	// Service used to look up the schemas of buckets.
+	SchemaService influxdb.BucketSchemaService
+}
+
+// WritePoints writes the points which match the schema of their bucket to the
+// underlying PointsWriter. It returns a *SchemaViolationError if any point
+// was rejected.
+func (w *SchemaPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if len(points) == 0 {
+		return nil
+	}
+
+	var (
+		schemas    = make(map[influxdb.ID]*influxdb.BucketSchema)
+		valid      = points[:0:0]
+		violations []SchemaViolation
+	)
+	for i, p := range points {
+		_, bucketID := tsdb.DecodeNameSlice(p.Name())
+		schema, ok := schemas[bucketID]
+		if !ok {
+			s, err := w.SchemaService.FindBucketSchema(ctx, bucketID)
+			if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+				return err
+			}
+			schema, schemas[bucketID] = s, s
+		}
+
+		if schema != nil {
+			if reason := validatePoint(schema, p); reason != "" {
+				violations = append(violations, SchemaViolation{Index: i, Reason: reason})
+				continue
+			}
+		}
+		valid = append(valid, p)
+	}
+
+	if len(violations) == 0 {
+		return w.Underlying.WritePoints(ctx, points)
+	}
+
+	if len(valid) > 0 {
+		if err := w.Underlying.WritePoints(ctx, valid); err != nil {
+			return err
+		}
+	}
+	return &SchemaViolationError{Violations: violations}
+}
+
+// validatePoint returns why the point does not match the schema, or an empty
+// string if it does.
+func validatePoint(schema *influxdb.BucketSchema, p models.Point) string {
+	tags := p.Tags()
+	name := string(tags.Get(models.MeasurementTagKeyBytes))
+	field := string(tags.Get(models.FieldKeyTagKeyBytes))
+
+	m := schema.Measurement(name)
+	if m == nil {
+		return fmt.Sprintf("measurement %q is not declared by the bucket schema", name)
+	}
+
+	for _, tag := range tags {
+		key := string(tag.Key)
+		if key == models.MeasurementTagKey || key == models.FieldKeyTagKey {
+			continue
+		}
+		if !m.HasTag(key) {
+			return fmt.Sprintf("tag key %q is not declared for measurement %q", key, name)
+		}
+	}
+
+	f := m.Field(field)
+	if f == nil {
+		return fmt.Sprintf("field %q is not declared for measurement %q", field, name)
+	}
+
+	itr := p.FieldIterator()
+	if !itr.Next() {
+		return fmt.Sprintf("field %q of measurement %q has no value", field, name)
+	}
+	if typ := schemaFieldType(itr.Type()); typ != f.Type {
+		return fmt.Sprintf("field %q of measurement %q is %s, schema requires %s", field, name, typ, f.Type)
+	}
+	return ""
+}
+
+func schemaFieldType(typ models.FieldType) influxdb.SchemaFieldType {
+	switch typ {
+	case models.Float:
+		return influxdb.SchemaFieldTypeFloat
+	case models.Integer:
+		return influxdb.SchemaFieldTypeInteger
+	case models.Unsigned:
+		return influxdb.SchemaFieldTypeUnsigned
+	case models.String:
+		return influxdb.SchemaFieldTypeString
+	case models.Boolean:
+		return influxdb.SchemaFieldTypeBoolean
+	default:
+		return "unknown"
+	}
+}
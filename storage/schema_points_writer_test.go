@@ -0,0 +1,128 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+	"github.com/influxdata/influxdb/v2/tsdb"
+)
+
+func TestSchemaPointsWriter(t *testing.T) {
+	const (
+		orgID         = influxdb.ID(1)
+		schemaBucket  = influxdb.ID(2)
+		defaultBucket = influxdb.ID(3)
+	)
+
+	schemaSvc := mock.NewBucketSchemaService()
+	schemaSvc.FindBucketSchemaFn = func(ctx context.Context, id influxdb.ID) (*influxdb.BucketSchema, error) {
+		if id != schemaBucket {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		}
+		return &influxdb.BucketSchema{
+			BucketID: schemaBucket,
+			Measurements: []influxdb.MeasurementSchema{
+				{
+					Name:   "cpu",
+					Tags:   []string{"host"},
+					Fields: []influxdb.FieldSchema{{Name: "usage", Type: influxdb.SchemaFieldTypeFloat}},
+				},
+			},
+		}, nil
+	}
+
+	point := func(bucketID influxdb.ID, measurement string, tags map[string]string, field string, value interface{}) models.Point {
+		tagsWithKeys := map[string]string{
+			models.MeasurementTagKey: measurement,
+			models.FieldKeyTagKey:    field,
+		}
+		for k, v := range tags {
+			tagsWithKeys[k] = v
+		}
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(orgID, bucketID),
+			models.NewTags(tagsWithKeys),
+			models.Fields{field: value},
+			time.Unix(0, 0),
+		)
+	}
+
+	tests := []struct {
+		name        string
+		points      []models.Point
+		wantWritten int
+		wantIndexes []int
+	}{
+		{
+			name: "matching points",
+			points: []models.Point{
+				point(schemaBucket, "cpu", map[string]string{"host": "a"}, "usage", 1.5),
+				point(schemaBucket, "cpu", nil, "usage", 2.5),
+			},
+			wantWritten: 2,
+		},
+		{
+			name: "bucket without schema",
+			points: []models.Point{
+				point(defaultBucket, "mem", map[string]string{"region": "west"}, "used", int64(1)),
+			},
+			wantWritten: 1,
+		},
+		{
+			name: "violations are rejected",
+			points: []models.Point{
+				point(schemaBucket, "cpu", map[string]string{"host": "a"}, "usage", 1.5),
+				point(schemaBucket, "mem", nil, "used", 1.5),
+				point(schemaBucket, "cpu", map[string]string{"region": "west"}, "usage", 1.5),
+				point(schemaBucket, "cpu", nil, "idle", 1.5),
+				point(schemaBucket, "cpu", nil, "usage", int64(1)),
+			},
+			wantWritten: 1,
+			wantIndexes: []int{1, 2, 3, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written int
+			w := &storage.SchemaPointsWriter{
+				Underlying: &mock.PointsWriter{
+					WritePointsFn: func(ctx context.Context, p []models.Point) error {
+						written += len(p)
+						return nil
+					},
+				},
+				SchemaService: schemaSvc,
+			}
+
+			err := w.WritePoints(context.Background(), tt.points)
+			if got, want := written, tt.wantWritten; got != want {
+				t.Errorf("written=%d, want %d", got, want)
+			}
+
+			var verr *storage.SchemaViolationError
+			if len(tt.wantIndexes) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected a schema violation error, got %v", err)
+			}
+			if got, want := len(verr.Violations), len(tt.wantIndexes); got != want {
+				t.Fatalf("violations=%d, want %d: %v", got, want, err)
+			}
+			for i, v := range verr.Violations {
+				if got, want := v.Index, tt.wantIndexes[i]; got != want {
+					t.Errorf("violation %d index=%d, want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -71,3 +71,13 @@ func ErrUnprocessableBucket(err error) *influxdb.Error {
 		Op:   "kv/MarshalBucket",
 	}
 }
+
+// InvalidBucketIDError is used when a service was provided an invalid bucket
+// ID.
+func InvalidBucketIDError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "bucket id provided is invalid",
+		Err:  err,
+	}
+}
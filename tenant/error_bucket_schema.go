@@ -0,0 +1,40 @@
+package tenant
+
+import (
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	// ErrBucketSchemaNotFound is used when a bucket has no schema.
+	ErrBucketSchemaNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "bucket schema not found",
+	}
+
+	errSchemaSystemBucket = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "system buckets cannot have a schema",
+	}
+)
+
+// ErrCorruptBucketSchema is used when the bucket schema cannot be unmarshalled
+// from the bytes stored in the kv.
+func ErrCorruptBucketSchema(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  "bucket schema could not be unmarshalled",
+		Err:  err,
+		Op:   "kv/UnmarshalBucketSchema",
+	}
+}
+
+// ErrUnprocessableBucketSchema is used when a bucket schema is not able to be
+// processed.
+func ErrUnprocessableBucketSchema(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EUnprocessableEntity,
+		Msg:  "bucket schema could not be marshalled",
+		Err:  err,
+		Op:   "kv/MarshalBucketSchema",
+	}
+}
@@ -0,0 +1,67 @@
+package tenant
+
+import (
+	"context"
+	"path"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+)
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaClientService)(nil)
+
+// BucketSchemaClientService connects to Influx via HTTP using tokens to manage
+// the schemas of buckets.
+type BucketSchemaClientService struct {
+	Client *httpc.Client
+}
+
+func bucketSchemaPath(bucketID influxdb.ID) string {
+	return path.Join(prefixBuckets, bucketID.String(), "schema")
+}
+
+// FindBucketSchema returns the schema of a bucket.
+func (s *BucketSchemaClientService) FindBucketSchema(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res bucketSchemaResponse
+	err := s.Client.
+		Get(bucketSchemaPath(bucketID)).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &res.BucketSchema, nil
+}
+
+// PutBucketSchema sets the schema of a bucket.
+func (s *BucketSchemaClientService) PutBucketSchema(ctx context.Context, schema *influxdb.BucketSchema) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	req := bucketSchemaRequest{Measurements: schema.Measurements}
+
+	var res bucketSchemaResponse
+	err := s.Client.
+		PutJSON(req, bucketSchemaPath(schema.BucketID)).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	*schema = res.BucketSchema
+	return nil
+}
+
+// DeleteBucketSchema removes the schema of a bucket.
+func (s *BucketSchemaClientService) DeleteBucketSchema(ctx context.Context, bucketID influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Delete(bucketSchemaPath(bucketID)).
+		Do(ctx)
+}
@@ -0,0 +1,138 @@
+package tenant
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+type bucketSchemaHandler struct {
+	log *zap.Logger
+	svc influxdb.BucketSchemaService
+	api *kithttp.API
+
+	idLookupKey string
+}
+
+// NewBucketSchemaHandler generates a mountable handler for the schema of a bucket. It looks up the bucket id
+// with chi.URLParam() and idLookupKey, e.g. `/buckets/{id}/schema`.
+func NewBucketSchemaHandler(log *zap.Logger, idLookupKey string, svc influxdb.BucketSchemaService) http.Handler {
+	h := &bucketSchemaHandler{
+		log: log,
+		svc: svc,
+		api: kithttp.NewAPI(kithttp.WithLog(log)),
+
+		idLookupKey: idLookupKey,
+	}
+
+	r := chi.NewRouter()
+	r.Get("/", h.handleGetBucketSchema)
+	r.Put("/", h.handlePutBucketSchema)
+	r.Delete("/", h.handleDeleteBucketSchema)
+	return r
+}
+
+type bucketSchemaRequest struct {
+	Measurements []influxdb.MeasurementSchema `json:"measurements"`
+}
+
+type bucketSchemaResponse struct {
+	influxdb.BucketSchema
+	Links map[string]string `json:"links"`
+}
+
+func newBucketSchemaResponse(s *influxdb.BucketSchema) *bucketSchemaResponse {
+	res := &bucketSchemaResponse{
+		BucketSchema: *s,
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/buckets/%s/schema", s.BucketID),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", s.BucketID),
+		},
+	}
+	if res.Measurements == nil {
+		res.Measurements = []influxdb.MeasurementSchema{}
+	}
+	return res
+}
+
+func (h *bucketSchemaHandler) bucketID(r *http.Request) (influxdb.ID, error) {
+	id := chi.URLParam(r, h.idLookupKey)
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+// handleGetBucketSchema is the HTTP handler for the GET /api/v2/buckets/:id/schema route.
+func (h *bucketSchemaHandler) handleGetBucketSchema(w http.ResponseWriter, r *http.Request) {
+	id, err := h.bucketID(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	s, err := h.svc.FindBucketSchema(r.Context(), id)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Bucket schema retrieved", zap.String("schema", fmt.Sprint(s)))
+
+	h.api.Respond(w, r, http.StatusOK, newBucketSchemaResponse(s))
+}
+
+// handlePutBucketSchema is the HTTP handler for the PUT /api/v2/buckets/:id/schema route.
+func (h *bucketSchemaHandler) handlePutBucketSchema(w http.ResponseWriter, r *http.Request) {
+	id, err := h.bucketID(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var req bucketSchemaRequest
+	if err := h.api.DecodeJSON(r.Body, &req); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	s := &influxdb.BucketSchema{
+		BucketID:     id,
+		Measurements: req.Measurements,
+	}
+	if err := h.svc.PutBucketSchema(r.Context(), s); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Bucket schema updated", zap.String("schema", fmt.Sprint(s)))
+
+	h.api.Respond(w, r, http.StatusOK, newBucketSchemaResponse(s))
+}
+
+// handleDeleteBucketSchema is the HTTP handler for the DELETE /api/v2/buckets/:id/schema route.
+func (h *bucketSchemaHandler) handleDeleteBucketSchema(w http.ResponseWriter, r *http.Request) {
+	id, err := h.bucketID(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := h.svc.DeleteBucketSchema(r.Context(), id); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Bucket schema deleted", zap.String("bucketID", id.String()))
+
+	h.api.Respond(w, r, http.StatusNoContent, nil)
+}
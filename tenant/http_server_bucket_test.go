@@ -4,11 +4,13 @@ import (
 	"context"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/influxdata/influxdb/v2"
 	ihttp "github.com/influxdata/influxdb/v2/http"
 	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/mock"
 	"github.com/influxdata/influxdb/v2/tenant"
 	itesting "github.com/influxdata/influxdb/v2/testing"
 	"go.uber.org/zap/zaptest"
@@ -56,7 +58,7 @@ func initBucketHttpService(f itesting.BucketFields, t *testing.T) (influxdb.Buck
 		t.Fatalf("failed to seed data: %s", err)
 	}
 
-	handler := tenant.NewHTTPBucketHandler(zaptest.NewLogger(t), tenant.NewService(store), nil, nil, nil)
+	handler := tenant.NewHTTPBucketHandler(zaptest.NewLogger(t), tenant.NewService(store), nil, nil, nil, nil)
 	r := chi.NewRouter()
 	r.Mount(handler.Prefix(), handler)
 	server := httptest.NewServer(r)
@@ -78,3 +80,52 @@ func initBucketHttpService(f itesting.BucketFields, t *testing.T) (influxdb.Buck
 func TestHTTPBucketService(t *testing.T) {
 	itesting.BucketService(initBucketHttpService, t)
 }
+
+func TestHTTPBucketService_UpdateShardGroupDuration(t *testing.T) {
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+	svc, _, done := initBucketHttpService(itesting.BucketFields{
+		OrgIDs:    mock.NewStaticIDGenerator(orgID),
+		BucketIDs: mock.NewStaticIDGenerator(bucketID),
+		Organizations: []*influxdb.Organization{
+			{Name: "org"},
+		},
+		Buckets: []*influxdb.Bucket{
+			{
+				OrgID:              orgID,
+				Name:               "bucket",
+				RetentionPeriod:    7 * 24 * time.Hour,
+				ShardGroupDuration: 24 * time.Hour,
+			},
+		},
+	}, t)
+	defer done()
+
+	ctx := context.Background()
+	rp := 7 * 24 * time.Hour
+
+	// an update without a shard group duration leaves it unchanged.
+	b, err := svc.UpdateBucket(ctx, bucketID, influxdb.BucketUpdate{RetentionPeriod: &rp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.ShardGroupDuration != 24*time.Hour {
+		t.Errorf("unexpected shard group duration %s, want 24h", b.ShardGroupDuration)
+	}
+
+	for _, sgd := range []time.Duration{-time.Hour, 30 * time.Minute} {
+		sgd := sgd
+		if _, err := svc.UpdateBucket(ctx, bucketID, influxdb.BucketUpdate{RetentionPeriod: &rp, ShardGroupDuration: &sgd}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected shard group duration %s to be invalid, got %v", sgd, err)
+		}
+	}
+
+	// an explicit 0 disables partitioning.
+	var sgd time.Duration
+	b, err = svc.UpdateBucket(ctx, bucketID, influxdb.BucketUpdate{RetentionPeriod: &rp, ShardGroupDuration: &sgd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.ShardGroupDuration != 0 {
+		t.Errorf("unexpected shard group duration %s, want 0", b.ShardGroupDuration)
+	}
+}
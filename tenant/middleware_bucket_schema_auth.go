@@ -0,0 +1,72 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.BucketSchemaService = (*AuthedBucketSchemaService)(nil)
+
+// AuthedBucketSchemaService wraps a influxdb.BucketSchemaService and authorizes
+// actions against it with the permissions of the bucket the schema belongs to.
+type AuthedBucketSchemaService struct {
+	s         influxdb.BucketSchemaService
+	bucketSvc influxdb.BucketService
+}
+
+// NewAuthedBucketSchemaService constructs an instance of an authorizing bucket
+// schema service.
+func NewAuthedBucketSchemaService(s influxdb.BucketSchemaService, bucketSvc influxdb.BucketService) *AuthedBucketSchemaService {
+	return &AuthedBucketSchemaService{
+		s:         s,
+		bucketSvc: bucketSvc,
+	}
+}
+
+// FindBucketSchema checks to see if the authorizer on context has read access to the bucket provided.
+func (s *AuthedBucketSchemaService) FindBucketSchema(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	b, err := s.bucketSvc.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeReadBucket(ctx, b.Type, b.ID, b.OrgID); err != nil {
+		return nil, err
+	}
+	return s.s.FindBucketSchema(ctx, bucketID)
+}
+
+// PutBucketSchema checks to see if the authorizer on context has write access to the bucket provided.
+func (s *AuthedBucketSchemaService) PutBucketSchema(ctx context.Context, schema *influxdb.BucketSchema) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	b, err := s.bucketSvc.FindBucketByID(ctx, schema.BucketID)
+	if err != nil {
+		return err
+	}
+	if _, _, err := authorizer.AuthorizeWrite(ctx, influxdb.BucketsResourceType, b.ID, b.OrgID); err != nil {
+		return err
+	}
+	return s.s.PutBucketSchema(ctx, schema)
+}
+
+// DeleteBucketSchema checks to see if the authorizer on context has write access to the bucket provided.
+func (s *AuthedBucketSchemaService) DeleteBucketSchema(ctx context.Context, bucketID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	b, err := s.bucketSvc.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return err
+	}
+	if _, _, err := authorizer.AuthorizeWrite(ctx, influxdb.BucketsResourceType, b.ID, b.OrgID); err != nil {
+		return err
+	}
+	return s.s.DeleteBucketSchema(ctx, bucketID)
+}
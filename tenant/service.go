@@ -33,6 +33,7 @@ type Service struct {
 	influxdb.UserResourceMappingService
 	influxdb.OrganizationService
 	influxdb.BucketService
+	influxdb.BucketSchemaService
 }
 
 // NewService creates a new base tenant service.
@@ -44,6 +45,7 @@ func NewService(st *Store) *Service {
 	svc.UserResourceMappingService = NewUserResourceMappingSvc(st, svc)
 	svc.OrganizationService = NewOrganizationSvc(st, svc)
 	svc.BucketService = NewBucketSvc(st, svc)
+	svc.BucketSchemaService = NewBucketSchemaSvc(st, svc)
 
 	return svc
 }
@@ -69,7 +71,8 @@ func (ts *Service) NewOrgHTTPHandler(log *zap.Logger, secretSvc influxdb.SecretS
 func (ts *Service) NewBucketHTTPHandler(log *zap.Logger, labelSvc influxdb.LabelService) *BucketHandler {
 	urmHandler := NewURMHandler(log.With(zap.String("handler", "urm")), influxdb.BucketsResourceType, "id", ts.UserService, NewAuthedURMService(ts.OrganizationService, ts.UserResourceMappingService))
 	labelHandler := label.NewHTTPEmbeddedHandler(log.With(zap.String("handler", "label")), influxdb.BucketsResourceType, labelSvc)
-	return NewHTTPBucketHandler(log.With(zap.String("handler", "bucket")), NewAuthedBucketService(ts.BucketService), labelSvc, urmHandler, labelHandler)
+	schemaHandler := NewBucketSchemaHandler(log.With(zap.String("handler", "bucket_schema")), "id", NewAuthedBucketSchemaService(ts.BucketSchemaService, ts.BucketService))
+	return NewHTTPBucketHandler(log.With(zap.String("handler", "bucket")), NewAuthedBucketService(ts.BucketService), labelSvc, urmHandler, labelHandler, schemaHandler)
 }
 
 func (ts *Service) NewUserHTTPHandler(log *zap.Logger) *UserHandler {
@@ -184,6 +184,14 @@ func (s *BucketSvc) CreateBucket(ctx context.Context, b *influxdb.Bucket) error
 		return err
 	}
 
+	if err := b.ValidateRetentionTiers(); err != nil {
+		return err
+	}
+
+	if err := b.ValidateShardGroupDuration(); err != nil {
+		return err
+	}
+
 	// make sure the org exists
 	if _, err := s.svc.FindOrganizationByID(ctx, b.OrgID); err != nil {
 		return err
@@ -229,7 +237,7 @@ func (s *BucketSvc) DeleteBucket(ctx context.Context, id influxdb.ID) error {
 		if err := s.store.DeleteBucket(ctx, tx, id); err != nil {
 			return err
 		}
-		return nil
+		return s.store.DeleteBucketSchema(ctx, tx, id)
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,69 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+type BucketSchemaSvc struct {
+	store *Store
+	svc   *Service
+}
+
+func NewBucketSchemaSvc(st *Store, svc *Service) *BucketSchemaSvc {
+	return &BucketSchemaSvc{
+		store: st,
+		svc:   svc,
+	}
+}
+
+// FindBucketSchema returns the schema of a bucket.
+func (s *BucketSchemaSvc) FindBucketSchema(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+	var schema *influxdb.BucketSchema
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		sch, err := s.store.GetBucketSchema(ctx, tx, bucketID)
+		if err != nil {
+			return err
+		}
+		schema = sch
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// PutBucketSchema sets the schema of an existing bucket.
+func (s *BucketSchemaSvc) PutBucketSchema(ctx context.Context, schema *influxdb.BucketSchema) error {
+	if err := schema.Valid(); err != nil {
+		return err
+	}
+
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		bucket, err := s.store.GetBucket(ctx, tx, schema.BucketID)
+		if err != nil {
+			return err
+		}
+		if bucket.Type == influxdb.BucketTypeSystem {
+			return errSchemaSystemBucket
+		}
+
+		schema.OrgID = bucket.OrgID
+		return s.store.PutBucketSchema(ctx, tx, schema)
+	})
+}
+
+// DeleteBucketSchema removes the schema of a bucket.
+func (s *BucketSchemaSvc) DeleteBucketSchema(ctx context.Context, bucketID influxdb.ID) error {
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		if _, err := s.store.GetBucketSchema(ctx, tx, bucketID); err != nil {
+			return err
+		}
+		return s.store.DeleteBucketSchema(ctx, tx, bucketID)
+	})
+}
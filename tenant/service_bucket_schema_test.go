@@ -0,0 +1,91 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketSchemaService(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	require.NoError(t, err)
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := tenant.NewService(tenant.NewStore(s))
+
+	org := &influxdb.Organization{Name: "org"}
+	require.NoError(t, svc.CreateOrganization(ctx, org))
+
+	bucket := &influxdb.Bucket{OrgID: org.ID, Name: "bucket"}
+	require.NoError(t, svc.CreateBucket(ctx, bucket))
+
+	_, err = svc.FindBucketSchema(ctx, bucket.ID)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+
+	schema := &influxdb.BucketSchema{
+		BucketID: bucket.ID,
+		Measurements: []influxdb.MeasurementSchema{
+			{
+				Name:   "cpu",
+				Tags:   []string{"host"},
+				Fields: []influxdb.FieldSchema{{Name: "usage", Type: influxdb.SchemaFieldTypeFloat}},
+			},
+		},
+	}
+	require.NoError(t, svc.PutBucketSchema(ctx, schema))
+	assert.Equal(t, org.ID, schema.OrgID)
+
+	got, err := svc.FindBucketSchema(ctx, bucket.ID)
+	require.NoError(t, err)
+	assert.Equal(t, schema.Measurements, got.Measurements)
+
+	t.Run("invalid schema is rejected", func(t *testing.T) {
+		err := svc.PutBucketSchema(ctx, &influxdb.BucketSchema{
+			BucketID: bucket.ID,
+			Measurements: []influxdb.MeasurementSchema{
+				{Name: "cpu", Fields: []influxdb.FieldSchema{{Name: "usage", Type: "decimal"}}},
+			},
+		})
+		assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+	})
+
+	t.Run("schema of missing bucket is rejected", func(t *testing.T) {
+		err := svc.PutBucketSchema(ctx, &influxdb.BucketSchema{BucketID: bucket.ID + 1})
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	})
+
+	t.Run("schema of invalid bucket id is rejected", func(t *testing.T) {
+		_, err := svc.FindBucketSchema(ctx, influxdb.InvalidID())
+		assert.Equal(t, tenant.InvalidBucketIDError(nil).Msg, influxdb.ErrorMessage(err))
+	})
+
+	t.Run("schema is replaced", func(t *testing.T) {
+		replaced := &influxdb.BucketSchema{
+			BucketID: bucket.ID,
+			Measurements: []influxdb.MeasurementSchema{
+				{Name: "mem", Fields: []influxdb.FieldSchema{{Name: "used", Type: influxdb.SchemaFieldTypeInteger}}},
+			},
+		}
+		require.NoError(t, svc.PutBucketSchema(ctx, replaced))
+
+		got, err := svc.FindBucketSchema(ctx, bucket.ID)
+		require.NoError(t, err)
+		assert.Equal(t, replaced.Measurements, got.Measurements)
+		assert.Equal(t, schema.CreatedAt, got.CreatedAt)
+	})
+
+	t.Run("schema is deleted with its bucket", func(t *testing.T) {
+		require.NoError(t, svc.DeleteBucket(ctx, bucket.ID))
+
+		_, err := svc.FindBucketSchema(ctx, bucket.ID)
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+
+		err = svc.DeleteBucketSchema(ctx, bucket.ID)
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	})
+}
@@ -79,7 +79,7 @@ func marshalBucket(u *influxdb.Bucket) ([]byte, error) {
 func (s *Store) GetBucket(ctx context.Context, tx kv.Tx, id influxdb.ID) (*influxdb.Bucket, error) {
 	encodedID, err := id.Encode()
 	if err != nil {
-		return nil, InvalidOrgIDError(err)
+		return nil, InvalidBucketIDError(err)
 	}
 
 	b, err := tx.Bucket(bucketBucket)
@@ -284,7 +284,7 @@ func (s *Store) CreateBucket(ctx context.Context, tx kv.Tx, bucket *influxdb.Buc
 
 	encodedID, err := bucket.ID.Encode()
 	if err != nil {
-		return InvalidOrgIDError(err)
+		return InvalidBucketIDError(err)
 	}
 
 	if err := s.uniqueBucketName(ctx, tx, bucket.OrgID, bucket.Name); err != nil {
@@ -383,6 +383,22 @@ func (s *Store) UpdateBucket(ctx context.Context, tx kv.Tx, id influxdb.ID, upd
 		bucket.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.RetentionTiers != nil {
+		bucket.RetentionTiers = *upd.RetentionTiers
+	}
+
+	if upd.ShardGroupDuration != nil {
+		bucket.ShardGroupDuration = *upd.ShardGroupDuration
+	}
+
+	if err := bucket.ValidateRetentionTiers(); err != nil {
+		return nil, err
+	}
+
+	if err := bucket.ValidateShardGroupDuration(); err != nil {
+		return nil, err
+	}
+
 	v, err := marshalBucket(bucket)
 	if err != nil {
 		return nil, err
@@ -407,7 +423,7 @@ func (s *Store) DeleteBucket(ctx context.Context, tx kv.Tx, id influxdb.ID) erro
 
 	encodedID, err := id.Encode()
 	if err != nil {
-		return InvalidOrgIDError(err)
+		return InvalidBucketIDError(err)
 	}
 
 	idx, err := tx.Bucket(bucketIndex)
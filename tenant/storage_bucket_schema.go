@@ -0,0 +1,106 @@
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+var bucketSchemaBucket = []byte("bucketschemasv1")
+
+func unmarshalBucketSchema(v []byte) (*influxdb.BucketSchema, error) {
+	s := &influxdb.BucketSchema{}
+	if err := json.Unmarshal(v, s); err != nil {
+		return nil, ErrCorruptBucketSchema(err)
+	}
+
+	return s, nil
+}
+
+func marshalBucketSchema(s *influxdb.BucketSchema) ([]byte, error) {
+	v, err := json.Marshal(s)
+	if err != nil {
+		return nil, ErrUnprocessableBucketSchema(err)
+	}
+
+	return v, nil
+}
+
+func (s *Store) GetBucketSchema(ctx context.Context, tx kv.Tx, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+	encodedID, err := bucketID.Encode()
+	if err != nil {
+		return nil, InvalidBucketIDError(err)
+	}
+
+	b, err := tx.Bucket(bucketSchemaBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if kv.IsNotFound(err) {
+		return nil, ErrBucketSchemaNotFound
+	}
+
+	if err != nil {
+		return nil, ErrInternalServiceError(err)
+	}
+
+	return unmarshalBucketSchema(v)
+}
+
+// PutBucketSchema creates or replaces the schema of the bucket schema.BucketID.
+func (s *Store) PutBucketSchema(ctx context.Context, tx kv.Tx, schema *influxdb.BucketSchema) error {
+	encodedID, err := schema.BucketID.Encode()
+	if err != nil {
+		return InvalidBucketIDError(err)
+	}
+
+	b, err := tx.Bucket(bucketSchemaBucket)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	if prev, err := s.GetBucketSchema(ctx, tx, schema.BucketID); err == nil {
+		schema.CreatedAt = prev.CreatedAt
+	} else if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		schema.SetCreatedAt(now)
+	} else {
+		return err
+	}
+	schema.SetUpdatedAt(now)
+
+	v, err := marshalBucketSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(encodedID, v); err != nil {
+		return ErrInternalServiceError(err)
+	}
+
+	return nil
+}
+
+// DeleteBucketSchema removes the schema of a bucket. It does not return an
+// error if the bucket has no schema.
+func (s *Store) DeleteBucketSchema(ctx context.Context, tx kv.Tx, bucketID influxdb.ID) error {
+	encodedID, err := bucketID.Encode()
+	if err != nil {
+		return InvalidBucketIDError(err)
+	}
+
+	b, err := tx.Bucket(bucketSchemaBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Delete(encodedID); err != nil && !kv.IsNotFound(err) {
+		return ErrInternalServiceError(err)
+	}
+
+	return nil
+}
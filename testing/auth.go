@@ -704,6 +704,8 @@ func FindAuthorizationByToken(
 		authorization *influxdb.Authorization
 	}
 
+	expired := time.Now().Add(-time.Hour)
+
 	tests := []struct {
 		name   string
 		fields AuthorizationFields
@@ -842,6 +844,42 @@ func FindAuthorizationByToken(
 				},
 			},
 		},
+		{
+			name: "expired authorization is not returned",
+			fields: AuthorizationFields{
+				OrgIDGenerator: mock.NewIncrementingIDGenerator(1),
+				Users: []*influxdb.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Orgs: []*influxdb.Organization{
+					{
+						Name: "o1",
+					},
+				},
+				Authorizations: []*influxdb.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       idOne,
+						Token:       "rand1",
+						Permissions: allUsersPermission(idOne),
+						ExpiresAt:   &expired,
+					},
+				},
+			},
+			args: args{
+				token: "rand1",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EUnauthorized,
+					Msg:  influxdb.ErrAuthorizationExpired,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
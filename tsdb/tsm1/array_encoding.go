@@ -111,6 +111,25 @@ func DecodeStringArrayBlock(block []byte, a *cursors.StringArray) error {
 	return err
 }
 
+// EncodeStringArrayBlockWithCodec encodes the string array into a block,
+// compressing the strings using codec.
+func EncodeStringArrayBlockWithCodec(a *cursors.StringArray, b []byte, codec StringCodec) ([]byte, error) {
+	if a.Len() == 0 {
+		return nil, nil
+	}
+
+	vb, err := StringArrayEncodeAllWithCodec(a.Values, nil, codec)
+	if err != nil {
+		return nil, err
+	}
+	tb, err := TimeArrayEncodeAll(a.Timestamps, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return packBlock(b, BlockString, tb, vb), nil
+}
+
 // DecodeTimestampArrayBlock decodes the timestamps from the specified
 // block, ignoring the block type and the values.
 func DecodeTimestampArrayBlock(block []byte, a *cursors.TimestampArray) error {
@@ -69,15 +69,57 @@ func StringArrayEncodeAll(src []string, b []byte) ([]byte, error) {
 	return dst[:len(res)+1], nil
 }
 
+// StringArrayEncodeAllWithCodec encodes src into b using codec, returning b
+// and any error encountered.  The returned slice may be of a different length
+// and capacity to b.
+func StringArrayEncodeAllWithCodec(src []string, b []byte, codec StringCodec) ([]byte, error) {
+	if len(src) == 0 {
+		return StringArrayEncodeAll(src, b)
+	}
+
+	var (
+		typ  byte
+		data []byte
+	)
+	switch codec {
+	case StringCodecSnappy:
+		return StringArrayEncodeAll(src, b)
+	case StringCodecZstd:
+		typ, data = stringCompressedZstd, appendStrings(nil, src)
+	case StringCodecZstdDictionary:
+		var ok bool
+		if data, ok = encodeStringDictionary(src, nil); ok {
+			typ = stringCompressedZstdDictionary
+		} else {
+			typ, data = stringCompressedZstd, appendStrings(data[:0], src)
+		}
+	default:
+		return b[:0], fmt.Errorf("StringArrayEncodeAll: unknown string codec %d", codec)
+	}
+
+	b = append(b[:0], typ<<4)
+	return zstdEncoder.EncodeAll(data, b), nil
+}
+
+// appendStrings appends each string of src to b, prefixed with its variable
+// byte length.
+func appendStrings(b []byte, src []string) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	for i := range src {
+		b = append(b, tmp[:binary.PutUvarint(tmp[:], uint64(len(src[i])))]...)
+		b = append(b, src[i]...)
+	}
+	return b
+}
+
 func StringArrayDecodeAll(b []byte, dst []string) ([]string, error) {
-	// First byte stores the encoding type, only have snappy format
-	// currently so ignore for now.
+	// First byte stores the encoding type.
 	if len(b) > 0 {
 		var err error
-		// it is important that to note that `snappy.Decode` always returns
-		// a newly allocated slice as the final strings reference this slice
-		// directly.
-		b, err = snappy.Decode(nil, b[1:])
+		// it is important that to note that `decodeStringPayload` always
+		// returns a newly allocated slice as the final strings reference this
+		// slice directly.
+		b, err = decodeStringPayload(b)
 		if err != nil {
 			return []string{}, fmt.Errorf("failed to decode string block: %v", err.Error())
 		}
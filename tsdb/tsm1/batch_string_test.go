@@ -182,6 +182,97 @@ func TestStringArrayEncodeAll_Quick(t *testing.T) {
 	}, nil)
 }
 
+func TestStringArrayEncodeAllWithCodec(t *testing.T) {
+	distinct := make([]string, 100)
+	for i := range distinct {
+		distinct[i] = fmt.Sprintf("GET /api/v2/query %d", i)
+	}
+	repeated := make([]string, 100)
+	for i := range repeated {
+		repeated[i] = fmt.Sprintf("level=%d", i%3)
+	}
+
+	tests := []struct {
+		name  string
+		codec StringCodec
+		src   []string
+		exp   byte
+	}{
+		{name: "snappy", codec: StringCodecSnappy, src: distinct, exp: stringCompressedSnappy},
+		{name: "zstd", codec: StringCodecZstd, src: repeated, exp: stringCompressedZstd},
+		{name: "zstd dictionary", codec: StringCodecZstdDictionary, src: repeated, exp: stringCompressedZstdDictionary},
+		{name: "zstd dictionary of distinct strings", codec: StringCodecZstdDictionary, src: distinct, exp: stringCompressedZstd},
+		{name: "empty strings", codec: StringCodecZstdDictionary, src: []string{"", "", ""}, exp: stringCompressedZstdDictionary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := StringArrayEncodeAllWithCodec(tt.src, nil, tt.codec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := b[0] >> 4; got != tt.exp {
+				t.Fatalf("unexpected encoding: got %v, exp %v", got, tt.exp)
+			}
+
+			got, err := StringArrayDecodeAll(b, nil)
+			if err != nil {
+				t.Fatalf("unexpected decode error: %v", err)
+			}
+			if !cmp.Equal(got, tt.src) {
+				t.Fatalf("unexpected values: -got/+exp\n%s", cmp.Diff(got, tt.src))
+			}
+
+			var dec StringDecoder
+			if err := dec.SetBytes(b); err != nil {
+				t.Fatalf("unexpected error creating string decoder: %v", err)
+			}
+			for i := 0; dec.Next(); i++ {
+				if got := dec.Read(); got != tt.src[i] {
+					t.Fatalf("read value %d mismatch: got %q, exp %q", i, got, tt.src[i])
+				}
+			}
+			if err := dec.Error(); err != nil {
+				t.Fatalf("unexpected decoder error: %v", err)
+			}
+		})
+	}
+}
+
+func TestStringArrayDecodeAll_CorruptDictionary(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "missing dictionary", data: []byte{}},
+		{name: "short dictionary", data: []byte{0x02, 0x01, 'a'}},
+		{name: "string too long", data: []byte{0x01, 0x05, 'a'}},
+		{name: "index out of range", data: []byte{0x01, 0x01, 'a', 0x00, 0x01}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := zstdEncoder.EncodeAll(tt.data, []byte{stringCompressedZstdDictionary << 4})
+			if _, err := StringArrayDecodeAll(b, nil); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestParseStringCodec(t *testing.T) {
+	for _, c := range []StringCodec{StringCodecSnappy, StringCodecZstd, StringCodecZstdDictionary} {
+		got, err := ParseStringCodec(c.String())
+		if err != nil {
+			t.Fatalf("unexpected error parsing %s: %v", c, err)
+		}
+		if got != c {
+			t.Fatalf("codec mismatch: got %v, exp %v", got, c)
+		}
+	}
+	if _, err := ParseStringCodec("gzip"); err == nil {
+		t.Fatalf("expected error parsing unknown codec")
+	}
+}
+
 func TestStringArrayDecodeAll_NoValues(t *testing.T) {
 	enc := NewStringEncoder(1024)
 	b, err := enc.Bytes()
@@ -1116,8 +1116,8 @@ func (k *tsmBatchKeyIterator) combineFloat(dedup bool) blocks {
 			i++
 			continue
 		}
-		// If we this block is already full, just add it as is
-		if BlockCount(k.blocks[i].b) >= k.size {
+		// If we this block is already full, just add it as is, unless it must be recoded
+		if BlockCount(k.blocks[i].b) >= k.size && !k.recode(k.blocks[i]) {
 			k.merged = append(k.merged, k.blocks[i])
 		} else {
 			break
@@ -1139,7 +1139,7 @@ func (k *tsmBatchKeyIterator) combineFloat(dedup bool) blocks {
 	}
 
 	// If we only have 1 blocks left, just append it as is and avoid decoding/recoding
-	if i == len(k.blocks)-1 {
+	if i == len(k.blocks)-1 && !k.recode(k.blocks[i]) {
 		if !k.blocks[i].read() {
 			k.merged = append(k.merged, k.blocks[i])
 		}
@@ -1335,8 +1335,8 @@ func (k *tsmBatchKeyIterator) combineInteger(dedup bool) blocks {
 			i++
 			continue
 		}
-		// If we this block is already full, just add it as is
-		if BlockCount(k.blocks[i].b) >= k.size {
+		// If we this block is already full, just add it as is, unless it must be recoded
+		if BlockCount(k.blocks[i].b) >= k.size && !k.recode(k.blocks[i]) {
 			k.merged = append(k.merged, k.blocks[i])
 		} else {
 			break
@@ -1358,7 +1358,7 @@ func (k *tsmBatchKeyIterator) combineInteger(dedup bool) blocks {
 	}
 
 	// If we only have 1 blocks left, just append it as is and avoid decoding/recoding
-	if i == len(k.blocks)-1 {
+	if i == len(k.blocks)-1 && !k.recode(k.blocks[i]) {
 		if !k.blocks[i].read() {
 			k.merged = append(k.merged, k.blocks[i])
 		}
@@ -1554,8 +1554,8 @@ func (k *tsmBatchKeyIterator) combineUnsigned(dedup bool) blocks {
 			i++
 			continue
 		}
-		// If we this block is already full, just add it as is
-		if BlockCount(k.blocks[i].b) >= k.size {
+		// If we this block is already full, just add it as is, unless it must be recoded
+		if BlockCount(k.blocks[i].b) >= k.size && !k.recode(k.blocks[i]) {
 			k.merged = append(k.merged, k.blocks[i])
 		} else {
 			break
@@ -1577,7 +1577,7 @@ func (k *tsmBatchKeyIterator) combineUnsigned(dedup bool) blocks {
 	}
 
 	// If we only have 1 blocks left, just append it as is and avoid decoding/recoding
-	if i == len(k.blocks)-1 {
+	if i == len(k.blocks)-1 && !k.recode(k.blocks[i]) {
 		if !k.blocks[i].read() {
 			k.merged = append(k.merged, k.blocks[i])
 		}
@@ -1773,8 +1773,8 @@ func (k *tsmBatchKeyIterator) combineString(dedup bool) blocks {
 			i++
 			continue
 		}
-		// If we this block is already full, just add it as is
-		if BlockCount(k.blocks[i].b) >= k.size {
+		// If we this block is already full, just add it as is, unless it must be recoded
+		if BlockCount(k.blocks[i].b) >= k.size && !k.recode(k.blocks[i]) {
 			k.merged = append(k.merged, k.blocks[i])
 		} else {
 			break
@@ -1796,7 +1796,7 @@ func (k *tsmBatchKeyIterator) combineString(dedup bool) blocks {
 	}
 
 	// If we only have 1 blocks left, just append it as is and avoid decoding/recoding
-	if i == len(k.blocks)-1 {
+	if i == len(k.blocks)-1 && !k.recode(k.blocks[i]) {
 		if !k.blocks[i].read() {
 			k.merged = append(k.merged, k.blocks[i])
 		}
@@ -1845,7 +1845,7 @@ func (k *tsmBatchKeyIterator) chunkString(dst blocks) blocks {
 		minTime, maxTime := values.Timestamps[0], values.Timestamps[len(values.Timestamps)-1]
 		values.Values = k.mergedStringValues.Values[:k.size]
 
-		cb, err := EncodeStringArrayBlock(&values, nil) // TODO(edd): pool this buffer
+		cb, err := EncodeStringArrayBlockWithCodec(&values, nil, k.stringCodec) // TODO(edd): pool this buffer
 		if err != nil {
 			k.err = err
 			return nil
@@ -1865,7 +1865,7 @@ func (k *tsmBatchKeyIterator) chunkString(dst blocks) blocks {
 	// Re-encode the remaining values into the last block
 	if k.mergedStringValues.Len() > 0 {
 		minTime, maxTime := k.mergedStringValues.Timestamps[0], k.mergedStringValues.Timestamps[len(k.mergedStringValues.Timestamps)-1]
-		cb, err := EncodeStringArrayBlock(k.mergedStringValues, nil) // TODO(edd): pool this buffer
+		cb, err := EncodeStringArrayBlockWithCodec(k.mergedStringValues, nil, k.stringCodec) // TODO(edd): pool this buffer
 		if err != nil {
 			k.err = err
 			return nil
@@ -1992,8 +1992,8 @@ func (k *tsmBatchKeyIterator) combineBoolean(dedup bool) blocks {
 			i++
 			continue
 		}
-		// If we this block is already full, just add it as is
-		if BlockCount(k.blocks[i].b) >= k.size {
+		// If we this block is already full, just add it as is, unless it must be recoded
+		if BlockCount(k.blocks[i].b) >= k.size && !k.recode(k.blocks[i]) {
 			k.merged = append(k.merged, k.blocks[i])
 		} else {
 			break
@@ -2015,7 +2015,7 @@ func (k *tsmBatchKeyIterator) combineBoolean(dedup bool) blocks {
 	}
 
 	// If we only have 1 blocks left, just append it as is and avoid decoding/recoding
-	if i == len(k.blocks)-1 {
+	if i == len(k.blocks)-1 && !k.recode(k.blocks[i]) {
 		if !k.blocks[i].read() {
 			k.merged = append(k.merged, k.blocks[i])
 		}
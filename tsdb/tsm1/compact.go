@@ -123,6 +123,9 @@ type DefaultPlanner struct {
 	// filesInUse is the set of files that have been returned as part of a plan and might
 	// be being compacted.  Two plans should not return the same file at any given time.
 	filesInUse map[string]struct{}
+
+	// Partitioner restricts plans to files of the same time partition.
+	Partitioner *Partitioner
 }
 
 type fileStore interface {
@@ -204,8 +207,12 @@ func (c *DefaultPlanner) ParseFileName(path string) (int, int, error) {
 
 // FullyCompacted returns true if the shard is fully compacted.
 func (c *DefaultPlanner) FullyCompacted() bool {
-	gens := c.findGenerations(false)
-	return len(gens) <= 1 && !gens.hasTombstones()
+	for _, gens := range c.Partitioner.partitionGenerations(c.findGenerations(false)) {
+		if len(gens) > 1 || gens.hasTombstones() {
+			return false
+		}
+	}
+	return true
 }
 
 // ForceFull causes the planner to return a full compaction plan the next time
@@ -233,6 +240,21 @@ func (c *DefaultPlanner) PlanLevel(level int) []CompactionGroup {
 	// split across several files in sequence.
 	generations := c.findGenerations(true)
 
+	// Files of different time partitions are never compacted together.
+	var cGroups []CompactionGroup
+	for _, generations := range c.Partitioner.partitionGenerations(generations) {
+		cGroups = append(cGroups, c.planLevel(generations, level)...)
+	}
+
+	if !c.acquire(cGroups) {
+		return nil
+	}
+
+	return cGroups
+}
+
+// planLevel returns the sets of generations to rewrite for a specific level.
+func (c *DefaultPlanner) planLevel(generations tsmGenerations, level int) []CompactionGroup {
 	// If there is only one generation and no tombstones, then there's nothing to
 	// do.
 	if len(generations) <= 1 && !generations.hasTombstones() {
@@ -304,10 +326,6 @@ func (c *DefaultPlanner) PlanLevel(level int) []CompactionGroup {
 		}
 	}
 
-	if !c.acquire(cGroups) {
-		return nil
-	}
-
 	return cGroups
 }
 
@@ -329,6 +347,21 @@ func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 	// split across several files in sequence.
 	generations := c.findGenerations(true)
 
+	// Files of different time partitions are never compacted together.
+	var cGroups []CompactionGroup
+	for _, generations := range c.Partitioner.partitionGenerations(generations) {
+		cGroups = append(cGroups, c.planOptimize(generations)...)
+	}
+
+	if !c.acquire(cGroups) {
+		return nil
+	}
+
+	return cGroups
+}
+
+// planOptimize returns the sets of level 4 generations to optimize.
+func (c *DefaultPlanner) planOptimize(generations tsmGenerations) []CompactionGroup {
 	// If there is only one generation and no tombstones, then there's nothing to
 	// do.
 	if len(generations) <= 1 && !generations.hasTombstones() {
@@ -396,10 +429,6 @@ func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 		cGroups = append(cGroups, cGroup)
 	}
 
-	if !c.acquire(cGroups) {
-		return nil
-	}
-
 	return cGroups
 }
 
@@ -408,6 +437,9 @@ func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 	generations := c.findGenerations(true)
 
+	// Files of different time partitions are never compacted together.
+	partitions := c.Partitioner.partitionGenerations(generations)
+
 	c.mu.RLock()
 	forceFull := c.forceFull
 	c.mu.RUnlock()
@@ -422,56 +454,81 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 			c.mu.Unlock()
 		}
 
-		var tsmFiles []string
-		var genCount int
-		for i, group := range generations {
-			var skip bool
-
-			// Skip the file if it's over the max size and contains a full block and it does not have any tombstones
-			if len(generations) > 2 && group.size() > uint64(maxTSMFileSize) && c.FileStore.BlockCount(group.files[0].Path, 1) == MaxPointsPerBlock && !group.hasTombstones() {
-				skip = true
+		var groups []CompactionGroup
+		for _, generations := range partitions {
+			if tsmFiles := c.planFull(generations); tsmFiles != nil {
+				groups = append(groups, tsmFiles)
 			}
+		}
 
-			// We need to look at the level of the next file because it may need to be combined with this generation
-			// but won't get picked up on it's own if this generation is skipped.  This allows the most recently
-			// created files to get picked up by the full compaction planner and avoids having a few less optimally
-			// compressed files.
-			if i < len(generations)-1 {
-				if generations[i+1].level() <= 3 {
-					skip = false
-				}
-			}
+		if len(groups) == 0 || !c.acquire(groups) {
+			return nil
+		}
+		return groups
+	}
 
-			if skip {
-				continue
-			}
+	// don't plan if nothing has changed in the filestore
+	if c.lastPlanCheck.After(c.FileStore.LastModified()) && !generations.hasTombstones() {
+		return nil
+	}
 
-			for _, f := range group.files {
-				tsmFiles = append(tsmFiles, f.Path)
+	c.lastPlanCheck = time.Now()
+
+	var tsmFiles []CompactionGroup
+	for _, generations := range partitions {
+		tsmFiles = append(tsmFiles, c.planLevel4(generations)...)
+	}
+
+	if !c.acquire(tsmFiles) {
+		return nil
+	}
+	return tsmFiles
+}
+
+// planFull returns the files of generations to rewrite in a full compaction,
+// or nil if they are already fully compacted.
+func (c *DefaultPlanner) planFull(generations tsmGenerations) CompactionGroup {
+	var tsmFiles []string
+	var genCount int
+	for i, group := range generations {
+		var skip bool
+
+		// Skip the file if it's over the max size and contains a full block and it does not have any tombstones
+		if len(generations) > 2 && group.size() > uint64(maxTSMFileSize) && c.FileStore.BlockCount(group.files[0].Path, 1) == MaxPointsPerBlock && !group.hasTombstones() {
+			skip = true
+		}
+
+		// We need to look at the level of the next file because it may need to be combined with this generation
+		// but won't get picked up on it's own if this generation is skipped.  This allows the most recently
+		// created files to get picked up by the full compaction planner and avoids having a few less optimally
+		// compressed files.
+		if i < len(generations)-1 {
+			if generations[i+1].level() <= 3 {
+				skip = false
 			}
-			genCount += 1
 		}
-		sort.Strings(tsmFiles)
 
-		// Make sure we have more than 1 file and more than 1 generation
-		if len(tsmFiles) <= 1 || genCount <= 1 {
-			return nil
+		if skip {
+			continue
 		}
 
-		group := []CompactionGroup{tsmFiles}
-		if !c.acquire(group) {
-			return nil
+		for _, f := range group.files {
+			tsmFiles = append(tsmFiles, f.Path)
 		}
-		return group
+		genCount += 1
 	}
+	sort.Strings(tsmFiles)
 
-	// don't plan if nothing has changed in the filestore
-	if c.lastPlanCheck.After(c.FileStore.LastModified()) && !generations.hasTombstones() {
+	// Make sure we have more than 1 file and more than 1 generation
+	if len(tsmFiles) <= 1 || genCount <= 1 {
 		return nil
 	}
 
-	c.lastPlanCheck = time.Now()
+	return tsmFiles
+}
 
+// planLevel4 returns the sets of level 4 generations to rewrite.
+func (c *DefaultPlanner) planLevel4(generations tsmGenerations) []CompactionGroup {
 	// If there is only one generation, return early to avoid re-compacting the same file
 	// over and over again.
 	if len(generations) <= 1 && !generations.hasTombstones() {
@@ -595,9 +652,6 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 		tsmFiles = append(tsmFiles, cGroup)
 	}
 
-	if !c.acquire(tsmFiles) {
-		return nil
-	}
 	return tsmFiles
 }
 
@@ -698,6 +752,14 @@ type Compactor struct {
 	// RateLimit is the limit for disk writes for all concurrent compactions.
 	RateLimit limiter.Rate
 
+	// Partitioner splits snapshots by time partition.
+	Partitioner *Partitioner
+
+	// StringCodec is the compression of the string blocks written by
+	// compactions.  Snapshots always use snappy, as it is cheaper, and full
+	// compactions encode the blocks written with another codec again.
+	StringCodec StringCodec
+
 	formatFileName FormatFileNameFunc
 	parseFileName  ParseFileNameFunc
 
@@ -842,26 +904,40 @@ func (c *Compactor) WriteSnapshot(ctx context.Context, cache *Cache) ([]string,
 		throttle = false
 	}
 
-	splits := cache.Split(concurrency)
+	// Data of partitioned buckets is written to a generation per partition.
+	var splits []*Cache
+	if c.Partitioner.enabled() {
+		var rest *Cache
+		splits, rest = c.Partitioner.splitCache(cache)
+		if rest != nil {
+			splits = append(splits, rest.Split(concurrency)...)
+		}
+	} else {
+		splits = cache.Split(concurrency)
+	}
 
 	type res struct {
 		files []string
 		err   error
 	}
 
-	resC := make(chan res, concurrency)
-	for i := 0; i < concurrency; i++ {
+	resC := make(chan res, len(splits))
+	sem := make(chan struct{}, concurrency)
+	for _, sp := range splits {
 		go func(sp *Cache) {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
 			iter := NewCacheKeyIterator(sp, MaxPointsPerBlock, intC)
 			files, err := c.writeNewFiles(c.FileStore.NextGeneration(), 0, nil, iter, throttle)
 			resC <- res{files: files, err: err}
 
-		}(splits[i])
+		}(sp)
 	}
 
 	var err error
-	files := make([]string, 0, concurrency)
-	for i := 0; i < concurrency; i++ {
+	files := make([]string, 0, len(splits))
+	for range splits {
 		result := <-resC
 		if result.err != nil {
 			err = result.err
@@ -941,7 +1017,7 @@ func (c *Compactor) compact(fast bool, tsmFiles []string) ([]string, error) {
 		return nil, nil
 	}
 
-	tsm, err := NewTSMBatchKeyIterator(size, fast, intC, trs...)
+	tsm, err := newTSMBatchKeyIterator(size, fast, c.StringCodec, intC, trs...)
 	if err != nil {
 		return nil, err
 	}
@@ -1601,6 +1677,10 @@ type tsmBatchKeyIterator struct {
 	// size is the maximum number of values to encode in a single block
 	size int
 
+	// stringCodec is the compression of the string blocks.  If fast is false,
+	// the blocks compressed with another codec are decoded and encoded again.
+	stringCodec StringCodec
+
 	// key is the current key lowest key across all readers that has not be fully exhausted
 	// of values.
 	key []byte
@@ -1627,6 +1707,10 @@ type tsmBatchKeyIterator struct {
 // NewTSMBatchKeyIterator returns a new TSM key iterator from readers.
 // size indicates the maximum number of values to encode in a single block.
 func NewTSMBatchKeyIterator(size int, fast bool, interrupt chan struct{}, readers ...*TSMReader) (KeyIterator, error) {
+	return newTSMBatchKeyIterator(size, fast, StringCodecSnappy, interrupt, readers...)
+}
+
+func newTSMBatchKeyIterator(size int, fast bool, stringCodec StringCodec, interrupt chan struct{}, readers ...*TSMReader) (KeyIterator, error) {
 	var iter []*BlockIterator
 	for _, r := range readers {
 		iter = append(iter, r.BlockIterator())
@@ -1637,6 +1721,7 @@ func NewTSMBatchKeyIterator(size int, fast bool, interrupt chan struct{}, reader
 		values:               map[string][]Value{},
 		pos:                  make([]int, len(readers)),
 		size:                 size,
+		stringCodec:          stringCodec,
 		iterators:            iter,
 		fast:                 fast,
 		buf:                  make([]blocks, len(iter)),
@@ -1810,6 +1895,12 @@ RETRY:
 	return len(k.merged) > 0
 }
 
+// recode returns true if the values of the block must be decoded and encoded
+// again, because the block is a string block compressed with another codec.
+func (k *tsmBatchKeyIterator) recode(b *block) bool {
+	return !k.fast && b.typ == BlockString && !k.stringCodec.encoded(b.b)
+}
+
 // merge combines the next set of blocks into merged blocks.
 func (k *tsmBatchKeyIterator) merge() {
 	switch k.typ {
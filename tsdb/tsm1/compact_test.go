@@ -3,6 +3,7 @@ package tsm1_test
 import (
 	"bufio"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"math"
 	"os"
@@ -92,6 +93,66 @@ func TestCompactor_Snapshot(t *testing.T) {
 	}
 }
 
+// Tests writing a Cache snapshot of partitioned data into a TSM file per partition
+func TestCompactor_Snapshot_Partitioned(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	points := map[string][]tsm1.Value{
+		"b1,host=A#!~#value": {tsm1.NewValue(1, 1.0), tsm1.NewValue(12, 2.0)},
+		"b1,host=B#!~#value": {tsm1.NewValue(5, 3.0)},
+		"b2,host=A#!~#value": {tsm1.NewValue(1, 4.0), tsm1.NewValue(12, 5.0)},
+	}
+
+	c := tsm1.NewCache(0)
+	for k, v := range points {
+		if err := c.Write([]byte(k), v); err != nil {
+			t.Fatalf("failed to write key foo to cache: %s", err.Error())
+		}
+	}
+
+	partitioner := tsm1.NewPartitioner()
+	partitioner.SetDuration([]byte("b1"), 10)
+
+	compactor := tsm1.NewCompactor()
+	compactor.Dir = dir
+	compactor.FileStore = tsm1.NewFileStore(dir)
+	compactor.Partitioner = partitioner
+	compactor.Open()
+
+	files, err := compactor.WriteSnapshot(context.Background(), c)
+	if err != nil {
+		t.Fatalf("unexpected error writing snapshot: %v", err)
+	}
+
+	type file struct {
+		keys             int
+		minTime, maxTime int64
+	}
+	var got []file
+	for _, f := range files {
+		r := MustOpenTSMReader(f)
+		minTime, maxTime := r.TimeRange()
+		got = append(got, file{keys: r.KeyCount(), minTime: minTime, maxTime: maxTime})
+		r.Close()
+	}
+	sort.Slice(got, func(i, j int) bool {
+		if got[i].minTime != got[j].minTime {
+			return got[i].minTime < got[j].minTime
+		}
+		return got[i].maxTime < got[j].maxTime
+	})
+
+	exp := []file{
+		{keys: 2, minTime: 1, maxTime: 5},   // b1 in [0, 10)
+		{keys: 1, minTime: 1, maxTime: 12},  // b2, unpartitioned
+		{keys: 1, minTime: 12, maxTime: 12}, // b1 in [10, 20)
+	}
+	if !cmp.Equal(got, exp, cmp.AllowUnexported(file{})) {
+		t.Fatalf("unexpected snapshot files: %s", cmp.Diff(got, exp, cmp.AllowUnexported(file{})))
+	}
+}
+
 func TestCompactor_CompactFullLastTimestamp(t *testing.T) {
 	dir := MustTempDir()
 	defer os.RemoveAll(dir)
@@ -262,6 +323,78 @@ func TestCompactor_CompactFull(t *testing.T) {
 	}
 }
 
+// Ensures that a full compaction encodes the string blocks of another codec again.
+func TestCompactor_CompactFull_StringCodec(t *testing.T) {
+	var values []tsm1.Value
+	for i := 0; i < 10; i++ {
+		values = append(values, tsm1.NewValue(int64(i), fmt.Sprintf("request %d", i%2)))
+	}
+
+	tests := []struct {
+		codec tsm1.StringCodec
+		exp   byte
+	}{
+		{codec: tsm1.StringCodecSnappy, exp: 1},
+		{codec: tsm1.StringCodecZstd, exp: 2},
+		{codec: tsm1.StringCodecZstdDictionary, exp: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.codec.String(), func(t *testing.T) {
+			dir := MustTempDir()
+			defer os.RemoveAll(dir)
+
+			// The file is written with the snappy codec.
+			f1 := MustWriteTSM(dir, 1, map[string][]tsm1.Value{
+				"log,host=A#!~#message": values,
+			})
+
+			fs := &fakeFileStore{}
+			defer fs.Close()
+			compactor := tsm1.NewCompactor()
+			compactor.Dir = dir
+			compactor.FileStore = fs
+			compactor.StringCodec = tt.codec
+			compactor.Open()
+
+			files, err := compactor.CompactFull([]string{f1})
+			if err != nil {
+				t.Fatalf("unexpected error compacting: %v", err)
+			}
+			if got, exp := len(files), 1; got != exp {
+				t.Fatalf("files length mismatch: got %v, exp %v", got, exp)
+			}
+
+			r := MustOpenTSMReader(files[0])
+			defer r.Close()
+
+			iter := r.BlockIterator()
+			if !iter.Next() {
+				t.Fatalf("expected a block")
+			}
+			_, _, _, _, _, b, err := iter.Read()
+			if err != nil {
+				t.Fatalf("unexpected error reading block: %v", err)
+			}
+			// The values follow the block type and the length prefixed timestamps.
+			tsLen, n := binary.Uvarint(b[1:])
+			if got := b[1+n+int(tsLen)] >> 4; got != tt.exp {
+				t.Fatalf("string encoding mismatch: got %v, exp %v", got, tt.exp)
+			}
+
+			got, err := r.ReadAll([]byte("log,host=A#!~#message"))
+			if err != nil {
+				t.Fatalf("unexpected error reading: %v", err)
+			}
+			if got, exp := len(got), len(values); got != exp {
+				t.Fatalf("values length mismatch: got %v, exp %v", got, exp)
+			}
+			for i, v := range values {
+				assertValueEqual(t, got[i], v)
+			}
+		})
+	}
+}
+
 // Ensures that a compaction will properly merge multiple TSM files
 func TestCompactor_Compact_OverlappingBlocks(t *testing.T) {
 	dir := MustTempDir()
@@ -1992,6 +2125,118 @@ func TestDefaultPlanner_PlanLevel_Tombstone(t *testing.T) {
 	}
 }
 
+func TestDefaultPlanner_PlanLevel_Partitioned(t *testing.T) {
+	unpartitioned := func(path string) tsm1.FileStat {
+		return tsm1.FileStat{
+			Path:    path,
+			Size:    1 * 1024 * 1024,
+			MinKey:  []byte("b2,host=A#!~#value"),
+			MaxKey:  []byte("b3,host=A#!~#value"),
+			MinTime: 1,
+			MaxTime: 9,
+		}
+	}
+	partitioned := func(path string) tsm1.FileStat {
+		return tsm1.FileStat{
+			Path:    path,
+			Size:    1 * 1024 * 1024,
+			MinKey:  []byte("b1,host=A#!~#value"),
+			MaxKey:  []byte("b1,host=B#!~#value"),
+			MinTime: 1,
+			MaxTime: 9,
+		}
+	}
+
+	data := []tsm1.FileStat{
+		unpartitioned("01-02.tsm1"),
+		partitioned("02-02.tsm1"),
+		unpartitioned("03-02.tsm1"),
+		partitioned("04-02.tsm1"),
+		unpartitioned("05-02.tsm1"),
+		partitioned("06-02.tsm1"),
+		unpartitioned("07-02.tsm1"),
+		partitioned("08-02.tsm1"),
+	}
+
+	cp := tsm1.NewDefaultPlanner(
+		&fakeFileStore{
+			PathsFn: func() []tsm1.FileStat {
+				return data
+			},
+		}, tsm1.DefaultCompactFullWriteColdDuration,
+	)
+	cp.Partitioner = tsm1.NewPartitioner()
+	cp.Partitioner.SetDuration([]byte("b1"), 10)
+
+	exp := []tsm1.CompactionGroup{
+		{"01-02.tsm1", "03-02.tsm1", "05-02.tsm1", "07-02.tsm1"},
+		{"02-02.tsm1", "04-02.tsm1", "06-02.tsm1", "08-02.tsm1"},
+	}
+	if got := cp.PlanLevel(2); !cmp.Equal(got, exp) {
+		t.Fatalf("unexpected plan: %s", cmp.Diff(got, exp))
+	}
+
+	if cp.FullyCompacted() {
+		t.Fatal("expected partitions not to be fully compacted")
+	}
+}
+
+// Ensure that the generations of a partition are not compacted with
+// generations of the unpartitioned data written after generations of the
+// partition that they overlap.
+func TestDefaultPlanner_PlanLevel_PartitionedOrdering(t *testing.T) {
+	unpartitioned := func(path string) tsm1.FileStat {
+		return tsm1.FileStat{
+			Path:    path,
+			Size:    1 * 1024 * 1024,
+			MinKey:  []byte("b1,host=A#!~#value"),
+			MaxKey:  []byte("b2,host=A#!~#value"),
+			MinTime: 1,
+			MaxTime: 9,
+		}
+	}
+	partitioned := func(path string) tsm1.FileStat {
+		return tsm1.FileStat{
+			Path:    path,
+			Size:    1 * 1024 * 1024,
+			MinKey:  []byte("b1,host=A#!~#value"),
+			MaxKey:  []byte("b1,host=B#!~#value"),
+			MinTime: 1,
+			MaxTime: 9,
+		}
+	}
+
+	data := []tsm1.FileStat{
+		unpartitioned("01-02.tsm1"),
+		unpartitioned("02-02.tsm1"),
+		partitioned("03-02.tsm1"),
+		partitioned("04-02.tsm1"),
+		partitioned("05-02.tsm1"),
+		partitioned("06-02.tsm1"),
+		unpartitioned("07-02.tsm1"),
+		unpartitioned("08-02.tsm1"),
+	}
+
+	cp := tsm1.NewDefaultPlanner(
+		&fakeFileStore{
+			PathsFn: func() []tsm1.FileStat {
+				return data
+			},
+		}, tsm1.DefaultCompactFullWriteColdDuration,
+	)
+	cp.Partitioner = tsm1.NewPartitioner()
+	cp.Partitioner.SetDuration([]byte("b1"), 10)
+
+	// The data of 01-02 may be overridden by the partition, whose data may be
+	// overridden by 07-08, so 01-02 and 07-08 are not compacted together.
+	exp := []tsm1.CompactionGroup{
+		{"03-02.tsm1", "04-02.tsm1", "05-02.tsm1", "06-02.tsm1"},
+	}
+	if got := cp.PlanLevel(2); !cmp.Equal(got, exp) {
+		t.Fatalf("unexpected plan: %s", cmp.Diff(got, exp))
+	}
+}
+
 func TestDefaultPlanner_PlanLevel_Multiple(t *testing.T) {
 	data := []tsm1.FileStat{
 		{
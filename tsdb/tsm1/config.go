@@ -82,6 +82,11 @@ type CompactionConfig struct {
 	// MaxConcurrent is the maximum number of concurrent full and level compactions that can
 	// run at one time.  A value of 0 results in 50% of runtime.GOMAXPROCS(0) used at runtime.
 	MaxConcurrent int `toml:"max-concurrent"`
+
+	// StringCodec is the compression of the string blocks written by compactions, one of
+	// snappy, zstd or zstd-dictionary.  Full compactions encode the string blocks compressed
+	// with another codec again.  Defaults to snappy.
+	StringCodec StringCodec `toml:"string-codec"`
 }
 
 // Default Cache configuration values.
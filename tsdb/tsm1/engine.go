@@ -141,6 +141,9 @@ type Engine struct {
 	CompactionPlan CompactionPlanner
 	FileStore      *FileStore
 
+	// Partitioner holds the time partition durations of buckets.
+	Partitioner *Partitioner
+
 	MaxPointsPerBlock int
 
 	// CacheFlushMemorySizeThreshold specifies the minimum size threshold for
@@ -185,9 +188,13 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 
 	cache := NewCache(uint64(config.Cache.MaxMemorySize))
 
+	partitioner := NewPartitioner()
+
 	c := NewCompactor()
 	c.Dir = path
 	c.FileStore = fs
+	c.Partitioner = partitioner
+	c.StringCodec = config.Compaction.StringCodec
 	c.RateLimit = limiter.NewRate(
 		int(config.Compaction.Throughput),
 		int(config.Compaction.ThroughputBurst))
@@ -212,6 +219,9 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 		maxCompactions = runtime.GOMAXPROCS(0)
 	}
 
+	planner := NewDefaultPlanner(fs, time.Duration(config.Compaction.FullWriteColdDuration))
+	planner.Partitioner = partitioner
+
 	logger := zap.NewNop()
 	e := &Engine{
 		path:   path,
@@ -221,10 +231,10 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 
 		Cache: cache,
 
-		FileStore: fs,
-		Compactor: c,
-		CompactionPlan: NewDefaultPlanner(fs,
-			time.Duration(config.Compaction.FullWriteColdDuration)),
+		FileStore:      fs,
+		Compactor:      c,
+		CompactionPlan: planner,
+		Partitioner:    partitioner,
 
 		CacheFlushMemorySizeThreshold:  uint64(config.Cache.SnapshotMemorySize),
 		CacheFlushWriteColdDuration:    time.Duration(config.Cache.SnapshotWriteColdDuration),
@@ -80,6 +80,14 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 	}
 	possiblyDead.keys = make(map[string]struct{})
 
+	// Files holding nothing but data to delete, such as the files of expired
+	// time partitions, are removed rather than tombstoned.
+	if pred == nil {
+		if err := e.removePrefixFiles(rootCtx, name, min, max, possiblyDead.keys); err != nil {
+			return err
+		}
+	}
+
 	if err := e.FileStore.Apply(func(r TSMFile) error {
 		var predClone Predicate // Apply executes concurrently across files.
 		if pred != nil {
@@ -301,3 +309,40 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 
 	return nil
 }
+
+// removePrefixFiles removes the TSM files whose keys all have the prefix name
+// and whose data all lies within [min, max]. The keys of the removed files are
+// added to possiblyDead.
+func (e *Engine) removePrefixFiles(ctx context.Context, name []byte, min, max int64, possiblyDead map[string]struct{}) error {
+	span, _ := tracing.StartSpanFromContextWithOperationName(ctx, "TSM remove files")
+	defer span.Finish()
+
+	var paths []string
+	for _, f := range e.FileStore.Stats() {
+		if f.MinTime < min || f.MaxTime > max {
+			continue
+		}
+		if !bytes.HasPrefix(f.MinKey, name) || !bytes.HasPrefix(f.MaxKey, name) {
+			continue
+		}
+
+		r := e.FileStore.TSMReader(f.Path)
+		if r == nil {
+			continue
+		}
+		iter := r.Iterator(name)
+		for iter.Next() {
+			possiblyDead[string(iter.Key())] = struct{}{}
+		}
+		err := iter.Err()
+		r.Unref()
+		if err != nil {
+			return err
+		}
+
+		paths = append(paths, f.Path)
+	}
+
+	span.LogKV("files_removed", len(paths))
+	return e.FileStore.Replace(paths, nil)
+}
@@ -121,6 +121,60 @@ func TestEngine_DeletePrefix(t *testing.T) {
 	}
 }
 
+func TestEngine_DeletePrefix_RemovesPartitions(t *testing.T) {
+	p1 := MustParsePointString("cpu,host=A value=1.1 1", "mm0")
+	p2 := MustParsePointString("cpu,host=B value=1.2 2", "mm0")
+	p3 := MustParsePointString("cpu,host=A value=1.3 11", "mm0")
+	p4 := MustParsePointString("mem,host=C value=1.4 1", "mm1")
+
+	e, err := NewEngine(tsm1.NewConfig(), t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	e.Partitioner.SetDuration([]byte("mm0"), 10)
+
+	if err := e.writePoints(p1, p2, p3, p4); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	if err := e.WriteSnapshot(context.Background(), tsm1.CacheStatusColdNoWrites); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+
+	// mm0 is written to a file for [0, 10) and one for [10, 20), mm1 to a third.
+	if exp, got := 3, len(e.FileStore.Files()); exp != got {
+		t.Fatalf("file count mismatch: exp %v, got %v", exp, got)
+	}
+
+	if err := e.DeletePrefixRange(context.Background(), []byte("mm0"), 0, 9, nil); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+
+	// The file of the expired partition is removed rather than tombstoned.
+	stats := e.FileStore.Stats()
+	if exp, got := 2, len(stats); exp != got {
+		t.Fatalf("file count mismatch: exp %v, got %v", exp, got)
+	}
+	for _, stat := range stats {
+		if stat.HasTombstone {
+			t.Fatalf("unexpected tombstone for %s", stat.Path)
+		}
+	}
+
+	exp := map[string]byte{
+		"mm0,\x00=cpu,host=A,\xff=value#!~#value": 0,
+		"mm1,\x00=mem,host=C,\xff=value#!~#value": 0,
+	}
+	if keys := e.FileStore.Keys(); !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("unexpected series in file store: %v != %v", keys, exp)
+	}
+}
+
 func BenchmarkEngine_DeletePrefixRange(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		b.StopTimer()
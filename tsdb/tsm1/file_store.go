@@ -24,6 +24,7 @@ import (
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/tsdb/cursors"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 )
@@ -1228,6 +1229,23 @@ func (f *FileStore) locations(key []byte, t int64, ascending bool) []*location {
 // CreateSnapshot creates hardlinks for all tsm and tombstone files
 // in the path provided.
 func (f *FileStore) CreateSnapshot(ctx context.Context) (backupID int, backupDirFullPath string, err error) {
+	return f.CreateFilteredSnapshot(ctx, SnapshotFilter{})
+}
+
+// SnapshotFilter restricts the files and keys included in a snapshot.
+type SnapshotFilter struct {
+	// Prefix restricts the snapshot to the keys beginning with Prefix. Files that
+	// also contain other keys are rewritten to only contain the matching keys.
+	Prefix []byte
+
+	// Since, when non-zero, restricts the snapshot to the files whose data or
+	// tombstones were modified after this time, in nanoseconds since the epoch.
+	Since int64
+}
+
+// CreateFilteredSnapshot creates hardlinks for the tsm and tombstone files
+// matching filter in the path provided.
+func (f *FileStore) CreateFilteredSnapshot(ctx context.Context, filter SnapshotFilter) (backupID int, backupDirFullPath string, err error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -1259,10 +1277,22 @@ func (f *FileStore) CreateSnapshot(ctx context.Context) (backupID int, backupDir
 		return 0, "", err
 	}
 	for _, tsmf := range files {
+		if filter.Since != 0 && tsmf.Stats().LastModified <= filter.Since {
+			continue
+		}
+
 		newpath := filepath.Join(backupDirFullPath, filepath.Base(tsmf.Path()))
-		if err := os.Link(tsmf.Path(), newpath); err != nil {
-			return 0, "", fmt.Errorf("error creating tsm hard link: %q", err)
+		if len(filter.Prefix) == 0 {
+			if err := os.Link(tsmf.Path(), newpath); err != nil {
+				return 0, "", fmt.Errorf("error creating tsm hard link: %q", err)
+			}
+		} else if ok, err := snapshotPrefix(tsmf, newpath, filter.Prefix); err != nil {
+			return 0, "", err
+		} else if !ok {
+			// the file does not contain any keys with the prefix.
+			continue
 		}
+
 		for _, tf := range tsmf.TombstoneFiles() {
 			newpath := filepath.Join(backupDirFullPath, filepath.Base(tf.Path))
 			if err := os.Link(tf.Path, newpath); err != nil {
@@ -1274,6 +1304,62 @@ func (f *FileStore) CreateSnapshot(ctx context.Context) (backupID int, backupDir
 	return backupID, backupDirFullPath, nil
 }
 
+// snapshotPrefix places the blocks of tsmf for keys beginning with prefix at
+// path. If all the keys of tsmf match the prefix, a hard link is created instead
+// of rewriting the file. It returns false if tsmf has no keys matching the prefix.
+func snapshotPrefix(tsmf TSMFile, path string, prefix []byte) (bool, error) {
+	if !tsmf.OverlapsKeyPrefixRange(prefix, prefix) {
+		return false, nil
+	}
+
+	if minKey, maxKey := tsmf.KeyRange(); bytes.HasPrefix(minKey, prefix) && bytes.HasPrefix(maxKey, prefix) {
+		if err := os.Link(tsmf.Path(), path); err != nil {
+			return false, fmt.Errorf("error creating tsm hard link: %q", err)
+		}
+		return true, nil
+	}
+
+	fd, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	if err != nil {
+		return false, err
+	}
+	w, err := NewTSMWriter(fd)
+	if err != nil {
+		return false, multierr.Append(err, fd.Close())
+	}
+
+	var n int
+	iter := tsmf.BlockIterator()
+	for iter.Next() {
+		key, minTime, maxTime, _, _, block, err := iter.Read()
+		if err != nil {
+			return false, multierr.Append(err, w.Remove())
+		}
+		if !bytes.HasPrefix(key, prefix) {
+			if bytes.Compare(key, prefix) > 0 {
+				// keys are sorted, so no further key can match.
+				break
+			}
+			continue
+		}
+		if err := w.WriteBlock(key, minTime, maxTime, block); err != nil {
+			return false, multierr.Append(err, w.Remove())
+		}
+		n++
+	}
+	if err := iter.Err(); err != nil {
+		return false, multierr.Append(err, w.Remove())
+	}
+
+	if n == 0 {
+		return false, multierr.Append(w.Close(), w.Remove())
+	}
+	if err := w.WriteIndex(); err != nil {
+		return false, multierr.Append(err, w.Remove())
+	}
+	return true, w.Close()
+}
+
 func (f *FileStore) InternalBackupPath(backupID int) string {
 	return filepath.Join(f.dir, fmt.Sprintf("%d.%s", backupID, TmpTSMFileExtension))
 }
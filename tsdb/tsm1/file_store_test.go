@@ -2754,6 +2754,82 @@ func TestFileStore_CreateSnapshot(t *testing.T) {
 	}
 }
 
+func TestFileStore_CreateFilteredSnapshot(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	store := tsm1.NewFileStore(dir)
+
+	// The first file holds keys of both prefixes, the second only "mem" keys.
+	writeFile := func(id int, keys ...string) string {
+		f := MustTempFile(dir)
+		w, err := tsm1.NewTSMWriter(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range keys {
+			if err := w.Write([]byte(key), []tsm1.Value{tsm1.NewValue(0, 1.0)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.WriteIndex(); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		name := filepath.Join(dir, tsm1.DefaultFormatFileName(id, 1)+".tsm")
+		if err := fs.RenameFile(f.Name(), name); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+	files := []string{
+		writeFile(1, "cpu,host=a#!~#value", "mem,host=a#!~#value"),
+		writeFile(2, "mem,host=b#!~#value"),
+	}
+	store.Replace(nil, files)
+
+	_, s, err := store.CreateFilteredSnapshot(context.Background(), tsm1.SnapshotFilter{Prefix: []byte("cpu")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := filepath.Join(s, filepath.Base(files[0]))
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatalf("unable to find file %q: %v", p, err)
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if got, exp := r.KeyCount(), 1; got != exp {
+		t.Fatalf("unexpected key count: got %d, exp %d", got, exp)
+	}
+	if !r.Contains([]byte("cpu,host=a#!~#value")) {
+		t.Fatal("expected snapshot file to contain the cpu key")
+	}
+
+	p = filepath.Join(s, filepath.Base(files[1]))
+	if _, err := os.Stat(p); !os.IsNotExist(err) {
+		t.Fatalf("expected file %q without matching keys to be excluded", p)
+	}
+
+	// No file was modified after now, so an incremental snapshot is empty.
+	_, s, err = store.CreateFilteredSnapshot(context.Background(), tsm1.SnapshotFilter{Since: time.Now().UnixNano()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tfs, err := ioutil.ReadDir(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tfs) != 0 {
+		t.Fatalf("expected no files in incremental snapshot, got %d", len(tfs))
+	}
+}
+
 type mockObserver struct {
 	fileFinishing func(path string) error
 	fileUnlinking func(path string) error
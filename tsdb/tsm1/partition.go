@@ -0,0 +1,256 @@
+package tsm1
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/models"
+)
+
+// Partitioner assigns the TSM data of buckets to time partitions. When a
+// bucket has a partition duration, snapshots write its data to separate TSM
+// files for each window of that duration, and compactions only merge files
+// belonging to the same partition. Every file of a partition holds the data
+// of a single bucket over a bounded window of time, so it can be unlinked once
+// the whole window is deleted rather than tombstoned and rewritten.
+//
+// Changing the partition duration of a bucket only affects data snapshotted
+// afterwards. Files written under a previous duration are compacted with the
+// unpartitioned files.
+//
+// A nil Partitioner partitions nothing. Partitioner is safe for use by
+// multiple goroutines.
+type Partitioner struct {
+	mu        sync.RWMutex
+	durations map[string]time.Duration
+}
+
+// NewPartitioner returns a Partitioner that partitions no bucket.
+func NewPartitioner() *Partitioner {
+	return &Partitioner{durations: make(map[string]time.Duration)}
+}
+
+// SetDuration sets the partition duration of the bucket with the given
+// unescaped name. A duration of 0 disables partitioning of the bucket.
+func (p *Partitioner) SetDuration(name []byte, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if d <= 0 {
+		delete(p.durations, string(name))
+		return
+	}
+	p.durations[string(name)] = d
+}
+
+// SetDurations replaces the partition durations of all buckets, keyed by
+// their unescaped name.
+func (p *Partitioner) SetDurations(durations map[string]time.Duration) {
+	m := make(map[string]time.Duration, len(durations))
+	for name, d := range durations {
+		if d > 0 {
+			m[name] = d
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.durations = m
+}
+
+// Duration returns the partition duration of the bucket with the given
+// unescaped name, or 0 if the bucket is not partitioned.
+func (p *Partitioner) Duration(name []byte) time.Duration {
+	if p == nil {
+		return 0
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.durations[string(name)]
+}
+
+// enabled returns true if any bucket is partitioned.
+func (p *Partitioner) enabled() bool {
+	if p == nil {
+		return false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.durations) > 0
+}
+
+// timePartition is the data of the bucket name in the window [min, max).
+type timePartition struct {
+	name     string
+	min, max int64
+}
+
+// partition returns the partition of the data of the bucket name at time t.
+// It returns false if the bucket is not partitioned.
+func (p *Partitioner) partition(name []byte, t int64) (timePartition, bool) {
+	d := int64(p.Duration(name))
+	if d <= 0 {
+		return timePartition{}, false
+	}
+
+	mod := t % d
+	if mod < 0 {
+		mod += d
+	}
+	min := t - mod
+	max := min + d
+	if max < min {
+		max = math.MaxInt64
+	}
+	return timePartition{name: string(name), min: min, max: max}, true
+}
+
+// filePartition returns the partition all the data of the file belongs to. It
+// returns false if the file holds data of more than one bucket or window.
+func (p *Partitioner) filePartition(f FileStat) (timePartition, bool) {
+	name := models.ParseName(f.MinKey)
+	if len(name) == 0 || !bytes.Equal(name, models.ParseName(f.MaxKey)) {
+		return timePartition{}, false
+	}
+	tp, ok := p.partition(name, f.MinTime)
+	if !ok || f.MaxTime >= tp.max {
+		return timePartition{}, false
+	}
+	return tp, true
+}
+
+// generationPartition returns the partition all the files of the generation
+// belong to.
+func (p *Partitioner) generationPartition(g *tsmGeneration) (timePartition, bool) {
+	if len(g.files) == 0 {
+		return timePartition{}, false
+	}
+	tp, ok := p.filePartition(g.files[0])
+	if !ok {
+		return timePartition{}, false
+	}
+	for _, f := range g.files[1:] {
+		if other, ok := p.filePartition(f); !ok || other != tp {
+			return timePartition{}, false
+		}
+	}
+	return tp, true
+}
+
+// splitCache splits the data of a deduplicated cache into one cache per time
+// partition, and a cache with the data of the buckets that are not
+// partitioned, which is nil if there is no such data. The returned caches
+// share their values with cache.
+func (p *Partitioner) splitCache(cache *Cache) (partitions []*Cache, rest *Cache) {
+	byPartition := make(map[timePartition]*Cache)
+
+	// ApplyEntryFn cannot return an error in this invocation.
+	_ = cache.ApplyEntryFn(func(key string, e *entry) error {
+		k := []byte(key)
+		name := models.ParseName(k)
+		if p.Duration(name) <= 0 {
+			if rest == nil {
+				rest = &Cache{store: newRing()}
+			}
+			rest.store.add(k, e)
+			return nil
+		}
+
+		e.mu.RLock()
+		values, vtype := e.values, e.vtype
+		e.mu.RUnlock()
+
+		for len(values) > 0 {
+			tp, _ := p.partition(name, values[0].UnixNano())
+			n := sort.Search(len(values), func(i int) bool {
+				return values[i].UnixNano() >= tp.max
+			})
+			if tp.max == math.MaxInt64 {
+				n = len(values)
+			}
+
+			c := byPartition[tp]
+			if c == nil {
+				c = &Cache{store: newRing()}
+				byPartition[tp] = c
+				partitions = append(partitions, c)
+			}
+			c.store.add(k, &entry{n: int64(n), values: values[:n], vtype: vtype})
+			values = values[n:]
+		}
+		return nil
+	})
+	return partitions, rest
+}
+
+// partitionGenerations splits generations into the sets of generations that
+// may be compacted together. The generations of a set belong to the same time
+// partition, or all to no single partition, and keep the order of
+// generations.
+//
+// The generation of a compacted file is that of the newest generation it was
+// compacted from, so a generation only joins the set of its partition if no
+// generation of another set that came after the set overlaps the set or the
+// generation. Its data would otherwise override newer data of that generation
+// once compacted; such a generation starts a new set of its partition instead.
+func (p *Partitioner) partitionGenerations(generations tsmGenerations) []tsmGenerations {
+	if !p.enabled() {
+		return []tsmGenerations{generations}
+	}
+
+	var (
+		sets []tsmGenerations
+		last []int // index in generations of the last generation of each set
+	)
+	open := make(map[timePartition]int)
+	for i, g := range generations {
+		// Generations that do not belong to a single partition share the zero
+		// partition, which no data belongs to.
+		tp, _ := p.generationPartition(g)
+
+		j, ok := open[tp]
+		if ok && sets[j].overlappedBy(g, generations[last[j]+1:i]) {
+			ok = false
+		}
+		if !ok {
+			j = len(sets)
+			open[tp] = j
+			sets = append(sets, tsmGenerations{})
+			last = append(last, 0)
+		}
+		sets[j] = append(sets[j], g)
+		last[j] = i
+	}
+	return sets
+}
+
+// overlappedBy returns true if any of others holds data of the keys and times
+// of the data of the generations or of g.
+func (a tsmGenerations) overlappedBy(g *tsmGeneration, others tsmGenerations) bool {
+	for _, o := range others {
+		if o.overlaps(g) {
+			return true
+		}
+		for _, gen := range a {
+			if o.overlaps(gen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// overlaps returns true if a file of g may hold data of the same key and time
+// as a file of other.
+func (g *tsmGeneration) overlaps(other *tsmGeneration) bool {
+	for _, f := range g.files {
+		for _, o := range other.files {
+			if f.OverlapsTimeRange(o.MinTime, o.MaxTime) && f.OverlapsKeyRange(o.MinKey, o.MaxKey) {
+				return true
+			}
+		}
+	}
+	return false
+}
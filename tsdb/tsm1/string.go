@@ -4,18 +4,206 @@ package tsm1
 // appended to byte slice prefixed with a variable byte length followed by the string
 // bytes.  The bytes are compressed using snappy compressor and a 1 byte header is used
 // to indicate the type of encoding.
+//
+// The bytes may instead be compressed using zstd, which compresses log-like strings
+// better at the cost of more CPU.  Blocks of few distinct strings may also store a
+// dictionary of the distinct strings followed by the variable byte index of each
+// string in the dictionary, compressed using zstd.
 
 import (
 	"encoding/binary"
 	"fmt"
+	"strings"
 
 	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 )
 
 // Note: an uncompressed format is not yet implemented.
+const (
+	// stringCompressedSnappy is a compressed encoding using Snappy compression
+	stringCompressedSnappy = 1
 
-// stringCompressedSnappy is a compressed encoding using Snappy compression
-const stringCompressedSnappy = 1
+	// stringCompressedZstd is a compressed encoding using zstd compression
+	stringCompressedZstd = 2
+
+	// stringCompressedZstdDictionary is a dictionary encoding compressed using
+	// zstd compression
+	stringCompressedZstdDictionary = 3
+)
+
+// StringCodec is the compression of the string blocks written to TSM files.
+type StringCodec byte
+
+const (
+	// StringCodecSnappy compresses the string blocks using snappy.
+	StringCodecSnappy StringCodec = iota
+
+	// StringCodecZstd compresses the string blocks using zstd.
+	StringCodecZstd
+
+	// StringCodecZstdDictionary compresses the string blocks using zstd, and
+	// dictionary encodes the blocks of few distinct strings first.
+	StringCodecZstdDictionary
+)
+
+var stringCodecNames = []string{
+	StringCodecSnappy:         "snappy",
+	StringCodecZstd:           "zstd",
+	StringCodecZstdDictionary: "zstd-dictionary",
+}
+
+// ParseStringCodec returns the codec named s.
+func ParseStringCodec(s string) (StringCodec, error) {
+	for c, name := range stringCodecNames {
+		if s == name {
+			return StringCodec(c), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown string codec %q, must be one of %s", s, strings.Join(stringCodecNames, ", "))
+}
+
+// String returns the name of the codec.
+func (c StringCodec) String() string {
+	if int(c) < len(stringCodecNames) {
+		return stringCodecNames[c]
+	}
+	return fmt.Sprintf("StringCodec(%d)", c)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c StringCodec) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *StringCodec) UnmarshalText(text []byte) error {
+	codec, err := ParseStringCodec(string(text))
+	if err != nil {
+		return err
+	}
+	*c = codec
+	return nil
+}
+
+// Set implements pflag.Value.
+func (c *StringCodec) Set(s string) error {
+	return c.UnmarshalText([]byte(s))
+}
+
+// Type implements pflag.Value.
+func (c *StringCodec) Type() string {
+	return "string-codec"
+}
+
+// encoded returns true if the values of the string block were encoded with
+// the codec.
+func (c StringCodec) encoded(block []byte) bool {
+	_, vb, err := unpackBlock(block[1:])
+	if err != nil || len(vb) == 0 {
+		return false
+	}
+
+	switch vb[0] >> 4 {
+	case stringCompressedSnappy:
+		return c == StringCodecSnappy
+	case stringCompressedZstd:
+		// Blocks of many distinct strings are not dictionary encoded.
+		return c == StringCodecZstd || c == StringCodecZstdDictionary
+	case stringCompressedZstdDictionary:
+		return c == StringCodecZstdDictionary
+	default:
+		return false
+	}
+}
+
+// The zstd encoder and decoder are safe for concurrent use of EncodeAll and
+// DecodeAll, which are the only methods used.
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// decodeStringPayload returns the variable byte length prefixed strings of
+// the encoded bytes b, whatever their encoding.  The returned slice is always
+// newly allocated.
+func decodeStringPayload(b []byte) ([]byte, error) {
+	switch b[0] >> 4 {
+	case stringCompressedSnappy:
+		return snappy.Decode(nil, b[1:])
+	case stringCompressedZstd:
+		return zstdDecoder.DecodeAll(b[1:], nil)
+	case stringCompressedZstdDictionary:
+		data, err := zstdDecoder.DecodeAll(b[1:], nil)
+		if err != nil {
+			return nil, err
+		}
+		return expandStringDictionary(data)
+	default:
+		return nil, fmt.Errorf("unknown string encoding %d", b[0]>>4)
+	}
+}
+
+// encodeStringDictionary returns the dictionary of the distinct strings of
+// src, followed by the index of each string in the dictionary.  It returns
+// false if the strings are too distinct for a dictionary to be worth it.
+func encodeStringDictionary(src []string, b []byte) ([]byte, bool) {
+	index := make(map[string]uint64)
+	var dict []string
+	for _, s := range src {
+		if _, ok := index[s]; !ok {
+			index[s] = uint64(len(dict))
+			dict = append(dict, s)
+		}
+		// Each string must repeat at least twice on average.
+		if 2*len(dict) > len(src) {
+			return b, false
+		}
+	}
+
+	var tmp [binary.MaxVarintLen64]byte
+	b = append(b, tmp[:binary.PutUvarint(tmp[:], uint64(len(dict)))]...)
+	for _, s := range dict {
+		b = append(b, tmp[:binary.PutUvarint(tmp[:], uint64(len(s)))]...)
+		b = append(b, s...)
+	}
+	for _, s := range src {
+		b = append(b, tmp[:binary.PutUvarint(tmp[:], index[s])]...)
+	}
+	return b, true
+}
+
+// expandStringDictionary returns the variable byte length prefixed strings
+// of the dictionary encoded bytes b.
+func expandStringDictionary(b []byte) ([]byte, error) {
+	n, i := binary.Uvarint(b)
+	if i <= 0 || n > uint64(len(b)) {
+		return nil, fmt.Errorf("stringDecoder: invalid dictionary length")
+	}
+	b = b[i:]
+
+	// Each entry of the dictionary keeps its length prefix.
+	dict := make([][]byte, n)
+	for j := range dict {
+		length, i := binary.Uvarint(b)
+		if i <= 0 || length > uint64(len(b)-i) {
+			return nil, fmt.Errorf("stringDecoder: invalid dictionary string length")
+		}
+		dict[j] = b[:i+int(length)]
+		b = b[i+int(length):]
+	}
+
+	var data []byte
+	for len(b) > 0 {
+		idx, i := binary.Uvarint(b)
+		if i <= 0 || idx >= n {
+			return nil, fmt.Errorf("stringDecoder: invalid dictionary index")
+		}
+		data = append(data, dict[idx]...)
+		b = b[i:]
+	}
+	return data, nil
+}
 
 // StringEncoder encodes multiple strings into a byte slice.
 type StringEncoder struct {
@@ -68,12 +256,11 @@ type StringDecoder struct {
 // SetBytes initializes the decoder with bytes to read from.
 // This must be called before calling any other method.
 func (e *StringDecoder) SetBytes(b []byte) error {
-	// First byte stores the encoding type, only have snappy format
-	// currently so ignore for now.
+	// First byte stores the encoding type.
 	var data []byte
 	if len(b) > 0 {
 		var err error
-		data, err = snappy.Decode(nil, b[1:])
+		data, err = decodeStringPayload(b)
 		if err != nil {
 			return fmt.Errorf("failed to decode string block: %v", err.Error())
 		}